@@ -8,7 +8,8 @@ import (
 
 // accountNetworksByID returns accountID->network mappings.
 // It is best-effort: lookup failures return nil so callers can continue.
-// Results are cached per client instance after the first successful lookup.
+// Results are cached per client instance after the first successful lookup,
+// and in the client's Cache when one is set.
 func (c *Client) accountNetworksByID(ctx context.Context) map[string]string {
 	c.accountNetworksMu.Lock()
 	defer c.accountNetworksMu.Unlock()
@@ -17,12 +18,20 @@ func (c *Client) accountNetworksByID(ctx context.Context) map[string]string {
 		return copyStringMap(c.accountNetworks)
 	}
 
+	var cached map[string]string
+	if c.cacheGet(cacheAccounts, "networks", &cached) {
+		c.accountNetworks = cached
+		c.accountNetworksLoaded = true
+		return copyStringMap(c.accountNetworks)
+	}
+
 	accounts, err := c.SDK.Accounts.List(ctx)
 	if err != nil {
 		return nil
 	}
 	c.accountNetworks = accountNetworkMap(accounts)
 	c.accountNetworksLoaded = true
+	c.cacheSet(cacheAccounts, "networks", c.accountNetworks)
 	return copyStringMap(c.accountNetworks)
 }
 
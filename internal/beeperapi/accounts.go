@@ -20,6 +20,11 @@ type Account struct {
 
 // List retrieves all accounts.
 func (s *AccountsService) List(ctx context.Context) ([]Account, error) {
+	var cached []Account
+	if s.client.cacheGet(cacheAccounts, "list", &cached) {
+		return cached, nil
+	}
+
 	ctx, cancel := s.client.contextWithTimeout(ctx)
 	defer cancel()
 
@@ -46,6 +51,7 @@ func (s *AccountsService) List(ctx context.Context) ([]Account, error) {
 		})
 	}
 
+	s.client.cacheSet(cacheAccounts, "list", accounts)
 	return accounts, nil
 }
 
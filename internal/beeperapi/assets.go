@@ -1,14 +1,19 @@
 package beeperapi
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"path/filepath"
 
 	beeperdesktopapi "github.com/beeper/desktop-api-go"
 	"github.com/beeper/desktop-api-go/option"
+
+	"github.com/johntheyoung/roadrunner/internal/media"
 )
 
 // AssetsService handles asset operations.
@@ -21,6 +26,10 @@ type AssetUploadParams struct {
 	FilePath string
 	FileName string
 	MimeType string
+	// StripMetadata removes EXIF (including GPS location), XMP, IPTC, and
+	// comments from JPEG files before upload. Other files are uploaded
+	// unchanged.
+	StripMetadata bool
 }
 
 // AssetUploadBase64Params configures base64 upload requests.
@@ -124,6 +133,24 @@ func (s *AssetsService) Upload(ctx context.Context, params AssetUploadParams) (A
 	sdkParams := beeperdesktopapi.AssetUploadParams{
 		File: f,
 	}
+	if params.StripMetadata {
+		data, err := io.ReadAll(f)
+		if err != nil {
+			return AssetUploadResult{}, fmt.Errorf("read %s: %w", params.FilePath, err)
+		}
+		stripped, err := media.StripJPEGMetadata(data)
+		switch {
+		case errors.Is(err, media.ErrNotJPEG):
+			stripped = data
+		case err != nil:
+			return AssetUploadResult{}, fmt.Errorf("strip metadata from %s: %w", params.FilePath, err)
+		}
+		sdkParams.File = bytes.NewReader(stripped)
+		// The upload is no longer a named file, so send its name.
+		if params.FileName == "" {
+			params.FileName = filepath.Base(params.FilePath)
+		}
+	}
 	if params.FileName != "" {
 		sdkParams.FileName = beeperdesktopapi.String(params.FileName)
 	}
@@ -0,0 +1,78 @@
+package beeperapi
+
+import "strings"
+
+// Cache resources used by the client.
+const (
+	cacheAccounts = "accounts"
+	cacheChats    = "chats"
+	cacheContacts = "contacts"
+)
+
+// Cache stores normalized responses between runs. Get decodes a fresh entry
+// into v and reports whether one was found; Invalidate drops every entry of
+// a resource. Resources are "accounts", "chats", and "contacts".
+type Cache interface {
+	Get(resource, key string, v any) bool
+	Set(resource, key string, v any)
+	Invalidate(resource string)
+}
+
+// WithCache serves account lists, chat name resolution, and contact searches
+// from c when it has fresh entries, and stores new results in it.
+func WithCache(c Cache) ClientOption {
+	return func(client *Client) {
+		client.cache = c
+	}
+}
+
+// WithScopedCache is like WithCache, but picks the cache with scope once the
+// client has resolved its base URL and token from arguments and environment.
+func WithScopedCache(scope func(baseURL, token string) Cache) ClientOption {
+	return func(client *Client) {
+		client.cache = scope(client.baseURL, client.token)
+	}
+}
+
+// cacheKey joins key parts with a separator that cannot appear in IDs or
+// queries typed on a command line.
+func cacheKey(parts ...string) string {
+	return strings.Join(parts, "\x00")
+}
+
+func (c *Client) cacheGet(resource, key string, v any) bool {
+	return c.cache != nil && c.cache.Get(resource, key, v)
+}
+
+func (c *Client) cacheSet(resource, key string, v any) {
+	if c.cache != nil {
+		c.cache.Set(resource, key, v)
+	}
+}
+
+// cacheInvalidate drops every cached entry of resources.
+func (c *Client) cacheInvalidate(resources ...string) {
+	if c == nil || c.cache == nil {
+		return
+	}
+	for _, resource := range resources {
+		c.cache.Invalidate(resource)
+	}
+}
+
+// invalidateAfterChatWrite drops the chat resolutions and contact searches
+// a created, started, or archived chat can make stale. Callers run it even
+// when the request failed, since the server may have applied it anyway.
+func (c *Client) invalidateAfterChatWrite() {
+	c.cacheInvalidate(cacheChats, cacheContacts)
+}
+
+// invalidateForEvent drops cached entries an event makes stale. Chat
+// changes can rename chats and add or rename participants, so both chat
+// resolutions and contact searches go.
+func (c *Client) invalidateForEvent(eventType string) {
+	switch eventType {
+	case EventTypeChatUpserted, EventTypeChatDeleted:
+		c.cacheInvalidate(cacheChats, cacheContacts)
+	}
+}
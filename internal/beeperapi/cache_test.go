@@ -0,0 +1,196 @@
+package beeperapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// memCache is an in-memory Cache without expiry.
+type memCache struct {
+	mu      sync.Mutex
+	entries map[string]map[string][]byte
+}
+
+func newMemCache() *memCache {
+	return &memCache{entries: map[string]map[string][]byte{}}
+}
+
+func (m *memCache) Get(resource, key string, v any) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.entries[resource][key]
+	return ok && json.Unmarshal(data, v) == nil
+}
+
+func (m *memCache) Set(resource, key string, v any) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	if m.entries[resource] == nil {
+		m.entries[resource] = map[string][]byte{}
+	}
+	m.entries[resource][key] = data
+}
+
+func (m *memCache) Invalidate(resource string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, resource)
+}
+
+func (m *memCache) len(resource string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.entries[resource])
+}
+
+func TestAccountsListServedFromCache(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"accountID":"acc1","network":"WhatsApp","user":{"id":"u1","fullName":"Me"}}]`))
+	}))
+	defer server.Close()
+
+	cache := newMemCache()
+	for i := 0; i < 2; i++ {
+		client, err := NewClient("test-token", server.URL, 5*time.Second, WithCache(cache))
+		if err != nil {
+			t.Fatalf("NewClient() error = %v", err)
+		}
+		accounts, err := client.Accounts().List(context.Background())
+		if err != nil {
+			t.Fatalf("List(%d) error = %v", i, err)
+		}
+		if len(accounts) != 1 || accounts[0].ID != "acc1" || accounts[0].Network != "WhatsApp" {
+			t.Fatalf("List(%d) = %#v", i, accounts)
+		}
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("API calls = %d, want 1", got)
+	}
+}
+
+func TestWithScopedCacheUsesResolvedEndpoint(t *testing.T) {
+	t.Parallel()
+
+	var gotURL, gotToken string
+	_, err := NewClient("test-token", "http://127.0.0.1:1", time.Second, WithScopedCache(func(baseURL, token string) Cache {
+		gotURL, gotToken = baseURL, token
+		return newMemCache()
+	}))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if gotURL != "http://127.0.0.1:1" || gotToken != "test-token" {
+		t.Fatalf("scope = (%q, %q)", gotURL, gotToken)
+	}
+}
+
+func TestResolveIDCachesMatchesOnly(t *testing.T) {
+	t.Parallel()
+
+	var searches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/v1/chats/search" {
+			_, _ = w.Write([]byte(`[]`))
+			return
+		}
+		searches.Add(1)
+		_, _ = w.Write([]byte(`{"items":[{"id":"!team:beeper.local","accountID":"acc1","title":"Team","type":"group","participants":{"hasMore":false,"items":[],"total":0}}],"hasMore":false}`))
+	}))
+	defer server.Close()
+
+	cache := newMemCache()
+	client, err := NewClient("test-token", server.URL, 5*time.Second, WithCache(cache))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		id, err := client.Chats().ResolveID(context.Background(), "team", nil)
+		if err != nil {
+			t.Fatalf("ResolveID(%d) error = %v", i, err)
+		}
+		if id != "!team:beeper.local" {
+			t.Fatalf("ResolveID(%d) = %q", i, id)
+		}
+	}
+	if got := searches.Load(); got != 1 {
+		t.Fatalf("searches after hits = %d, want 1", got)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Chats().ResolveID(context.Background(), "nobody", nil); !IsNoMatch(err) {
+			t.Fatalf("ResolveID(nobody) error = %v, want no match", err)
+		}
+	}
+	if got := searches.Load(); got != 3 {
+		t.Fatalf("searches after misses = %d, want 3", got)
+	}
+}
+
+func TestReadEventInvalidatesChatCaches(t *testing.T) {
+	t.Parallel()
+
+	upgrader := websocket.Upgrader{
+		CheckOrigin: func(r *http.Request) bool { return true },
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		_ = conn.WriteJSON(map[string]any{"type": "message.upserted", "chatID": "!team:beeper.local"})
+		_ = conn.WriteJSON(map[string]any{"type": "chat.upserted", "chatID": "!team:beeper.local"})
+		_, _, _ = conn.ReadMessage()
+	}))
+	defer server.Close()
+
+	cache := newMemCache()
+	cache.Set(cacheChats, cacheKey("team"), "!team:beeper.local")
+	cache.Set(cacheContacts, cacheKey("acc1", "bob"), []Contact{{ID: "@bob:beeper.local"}})
+	cache.Set(cacheAccounts, "list", []Account{{ID: "acc1"}})
+
+	client, err := NewClient("test-token", server.URL, 5*time.Second, WithCache(cache))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	ws, err := client.Events().Connect(context.Background())
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer func() { _ = ws.Close() }()
+
+	if _, err := ws.ReadEvent(context.Background()); err != nil {
+		t.Fatalf("ReadEvent(1) error = %v", err)
+	}
+	if cache.len(cacheChats) != 1 {
+		t.Fatal("message event dropped cached chats")
+	}
+	if _, err := ws.ReadEvent(context.Background()); err != nil {
+		t.Fatalf("ReadEvent(2) error = %v", err)
+	}
+	if cache.len(cacheChats) != 0 || cache.len(cacheContacts) != 0 {
+		t.Fatal("chat event kept cached chats or contacts")
+	}
+	if cache.len(cacheAccounts) != 1 {
+		t.Fatal("chat event dropped cached accounts")
+	}
+}
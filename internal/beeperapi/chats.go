@@ -35,6 +35,11 @@ type ChatListItem struct {
 	AccountID    string `json:"account_id"`
 	LastActivity string `json:"last_activity,omitempty"`
 	Preview      string `json:"preview,omitempty"`
+
+	// Tags and Note are local chat metadata (rr chats tag/note) filled in
+	// by rr; the API never sets them.
+	Tags []string `json:"tags,omitempty"`
+	Note string   `json:"note,omitempty"`
 }
 
 // ChatSearchParams configures chat search queries.
@@ -72,6 +77,16 @@ type ChatSearchItem struct {
 	UnreadCount int64  `json:"unread_count"`
 	IsArchived  bool   `json:"is_archived"`
 	IsMuted     bool   `json:"is_muted"`
+	// LastActivity is RFC 3339, when known.
+	LastActivity string `json:"last_activity,omitempty"`
+	// Tags and Note are local chat metadata filled in by rr.
+	Tags []string `json:"tags,omitempty"`
+	Note string   `json:"note,omitempty"`
+
+	// participants holds the other participants' names for ranking.
+	participants []string
+	// participantIDs holds the other participants' user IDs.
+	participantIDs []string
 }
 
 // ChatDetail represents a chat detail response.
@@ -93,6 +108,9 @@ type ChatDetail struct {
 	ParticipantsTotal      int64  `json:"participants_total"`
 	ParticipantsReturned   int    `json:"participants_returned"`
 	ParticipantsHasMore    bool   `json:"participants_has_more"`
+	// Tags and Note are local chat metadata filled in by rr.
+	Tags []string `json:"tags,omitempty"`
+	Note string   `json:"note,omitempty"`
 }
 
 // ChatGetParams configures chat detail retrieval.
@@ -164,19 +182,7 @@ func (s *ChatsService) List(ctx context.Context, params ChatListParams) (ChatLis
 	}
 
 	for _, chat := range page.Items {
-		item := ChatListItem{
-			ID:        chat.ID,
-			Title:     chat.Title,
-			AccountID: chat.AccountID,
-		}
-		item.DisplayName = displayNameForChat(string(chat.Type), chat.Title, chat.Participants.Items)
-		if !chat.LastActivity.IsZero() {
-			item.LastActivity = chat.LastActivity.Format(time.RFC3339)
-		}
-		if chat.JSON.Preview.Valid() {
-			item.Preview = chat.Preview.Text
-		}
-		result.Items = append(result.Items, item)
+		result.Items = append(result.Items, chatListItemFromSDK(chat))
 	}
 
 	return result, nil
@@ -256,17 +262,23 @@ func (s *ChatsService) Search(ctx context.Context, params ChatSearchParams) (Cha
 
 	for _, chat := range page.Items {
 		displayName := displayNameForChat(string(chat.Type), chat.Title, chat.Participants.Items)
-		result.Items = append(result.Items, ChatSearchItem{
-			ID:          chat.ID,
-			Title:       chat.Title,
-			DisplayName: displayName,
-			AccountID:   chat.AccountID,
-			Type:        string(chat.Type),
-			Network:     networkForAccount(accountNetworks, chat.AccountID),
-			UnreadCount: chat.UnreadCount,
-			IsArchived:  chat.IsArchived,
-			IsMuted:     chat.IsMuted,
-		})
+		item := ChatSearchItem{
+			ID:             chat.ID,
+			Title:          chat.Title,
+			DisplayName:    displayName,
+			AccountID:      chat.AccountID,
+			Type:           string(chat.Type),
+			Network:        networkForAccount(accountNetworks, chat.AccountID),
+			UnreadCount:    chat.UnreadCount,
+			IsArchived:     chat.IsArchived,
+			IsMuted:        chat.IsMuted,
+			participants:   participantNames(chat.Participants.Items),
+			participantIDs: participantIDs(chat.Participants.Items),
+		}
+		if !chat.LastActivity.IsZero() {
+			item.LastActivity = chat.LastActivity.Format(time.RFC3339)
+		}
+		result.Items = append(result.Items, item)
 	}
 
 	return result, nil
@@ -338,6 +350,7 @@ func (s *ChatsService) Create(ctx context.Context, params ChatCreateParams) (Cha
 	}
 
 	resp, err := s.client.SDK.Chats.New(ctx, sdkParams)
+	s.client.invalidateAfterChatWrite()
 	if err != nil {
 		return ChatCreateResult{}, err
 	}
@@ -393,7 +406,9 @@ func (s *ChatsService) Start(ctx context.Context, params ChatStartParams) (ChatS
 	}
 
 	var resp startResponse
-	if err := s.client.SDK.Post(ctx, "v1/chats", body, &resp); err != nil {
+	err := s.client.SDK.Post(ctx, "v1/chats", body, &resp)
+	s.client.invalidateAfterChatWrite()
+	if err != nil {
 		return ChatStartResult{}, err
 	}
 
@@ -413,5 +428,23 @@ func (s *ChatsService) Archive(ctx context.Context, chatID string, archived bool
 	}
 
 	err := s.client.SDK.Chats.Archive(ctx, chatID, sdkParams)
+	s.client.invalidateAfterChatWrite()
 	return err
 }
+
+// chatListItemFromSDK flattens an SDK chat into list output form.
+func chatListItemFromSDK(chat beeperdesktopapi.ChatListResponse) ChatListItem {
+	item := ChatListItem{
+		ID:        chat.ID,
+		Title:     chat.Title,
+		AccountID: chat.AccountID,
+	}
+	item.DisplayName = displayNameForChat(string(chat.Type), chat.Title, chat.Participants.Items)
+	if !chat.LastActivity.IsZero() {
+		item.LastActivity = chat.LastActivity.Format(time.RFC3339)
+	}
+	if chat.JSON.Preview.Valid() {
+		item.Preview = chat.Preview.Text
+	}
+	return item
+}
@@ -3,6 +3,7 @@ package beeperapi
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"sync"
 	"time"
@@ -18,15 +19,38 @@ type Client struct {
 	baseURL string
 	timeout time.Duration
 
+	httpClient   *http.Client
+	eventsDialer EventsDialer
+	cache        Cache
+
 	accountNetworksMu     sync.Mutex
 	accountNetworks       map[string]string
 	accountNetworksLoaded bool
 }
 
+// ClientOption customizes a Client created by NewClient.
+type ClientOption func(*Client)
+
+// WithHTTPClient routes SDK requests through hc instead of the default
+// HTTP client (used by cassettes to record or replay traffic).
+func WithHTTPClient(hc *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = hc
+	}
+}
+
+// WithEventsDialer opens /v1/ws connections through d instead of the
+// default websocket dialer.
+func WithEventsDialer(d EventsDialer) ClientOption {
+	return func(c *Client) {
+		c.eventsDialer = d
+	}
+}
+
 // NewClient creates a new Beeper API client.
 // Token precedence: BEEPER_TOKEN > BEEPER_ACCESS_TOKEN > provided token
 // URL precedence: BEEPER_URL > BEEPER_DESKTOP_BASE_URL > provided baseURL
-func NewClient(token string, baseURL string, timeout time.Duration) (*Client, error) {
+func NewClient(token string, baseURL string, timeout time.Duration, options ...ClientOption) (*Client, error) {
 	// Token precedence
 	if t := os.Getenv("BEEPER_TOKEN"); t != "" {
 		token = t
@@ -45,6 +69,15 @@ func NewClient(token string, baseURL string, timeout time.Duration) (*Client, er
 		baseURL = u
 	}
 
+	c := &Client{
+		token:   token,
+		baseURL: baseURL,
+		timeout: timeout,
+	}
+	for _, apply := range options {
+		apply(c)
+	}
+
 	opts := []option.RequestOption{
 		option.WithAccessToken(token),
 	}
@@ -52,15 +85,14 @@ func NewClient(token string, baseURL string, timeout time.Duration) (*Client, er
 	if baseURL != "" {
 		opts = append(opts, option.WithBaseURL(baseURL))
 	}
+	if c.httpClient != nil {
+		opts = append(opts, option.WithHTTPClient(c.httpClient))
+	}
 
 	sdk := beeperdesktopapi.NewClient(opts...)
+	c.SDK = &sdk
 
-	return &Client{
-		SDK:     &sdk,
-		token:   token,
-		baseURL: baseURL,
-		timeout: timeout,
-	}, nil
+	return c, nil
 }
 
 // contextWithTimeout returns a context with the client's timeout.
@@ -69,6 +69,12 @@ type contactsListResponse struct {
 
 // SearchContacts finds contacts on a specific account.
 func (s *AccountsService) SearchContacts(ctx context.Context, accountID string, query string) ([]Contact, error) {
+	key := cacheKey(accountID, query)
+	var cached []Contact
+	if s.client.cacheGet(cacheContacts, key, &cached) {
+		return cached, nil
+	}
+
 	ctx, cancel := s.client.contextWithTimeout(ctx)
 	defer cancel()
 
@@ -92,6 +98,7 @@ func (s *AccountsService) SearchContacts(ctx context.Context, accountID string,
 		})
 	}
 
+	s.client.cacheSet(cacheContacts, key, contacts)
 	return contacts, nil
 }
 
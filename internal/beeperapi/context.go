@@ -0,0 +1,154 @@
+package beeperapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultContextMaxPages bounds how many pages of chat history Context
+// scans to locate an anchor given by message ID or time.
+const DefaultContextMaxPages = 25
+
+// MessageContextParams selects the anchor message and window size. Exactly
+// one of SortKey, MessageID, and At identifies the anchor.
+type MessageContextParams struct {
+	SortKey   string
+	MessageID string
+	At        time.Time // newest message at or before this time
+	Before    int       // messages before the anchor
+	After     int       // messages after the anchor
+	MaxPages  int       // history pages to scan for MessageID/At (default DefaultContextMaxPages)
+}
+
+// MessageContext is a window of messages around an anchor. Before and
+// After are oldest first, so Before, Anchor, After read in chat order.
+type MessageContext struct {
+	ChatID  string        `json:"chat_id"`
+	SortKey string        `json:"sort_key"`
+	Anchor  *MessageItem  `json:"anchor"`
+	Before  []MessageItem `json:"before"`
+	After   []MessageItem `json:"after"`
+}
+
+// Context returns the messages around an anchor. A MessageID or At anchor
+// is located by paging the chat's history back from the newest message;
+// ErrMessageNotFound is returned when it is not within params.MaxPages
+// pages. A SortKey anchor needs no scan; Anchor is nil when the message
+// with that sort key cannot be read back.
+func (s *MessagesService) Context(ctx context.Context, chatID string, params MessageContextParams) (MessageContext, error) {
+	set := 0
+	for _, ok := range []bool{params.SortKey != "", params.MessageID != "", !params.At.IsZero()} {
+		if ok {
+			set++
+		}
+	}
+	if set != 1 {
+		return MessageContext{}, errors.New("exactly one of sort key, message ID, or time is required")
+	}
+
+	result := MessageContext{ChatID: chatID, SortKey: params.SortKey}
+	if params.SortKey == "" {
+		anchor, err := s.locateAnchor(ctx, chatID, params)
+		if err != nil {
+			return MessageContext{}, err
+		}
+		result.Anchor = &anchor
+		result.SortKey = anchor.SortKey
+	}
+
+	// Before is always fetched (at least one message) when the anchor
+	// still has to be read back: the anchor is the first message after
+	// its older neighbor.
+	beforeLimit := params.Before
+	if result.Anchor == nil && beforeLimit == 0 {
+		beforeLimit = 1
+	}
+	before, err := s.collect(ctx, chatID, result.SortKey, "before", beforeLimit)
+	if err != nil {
+		return MessageContext{}, err
+	}
+	if result.Anchor == nil {
+		neighbor := ""
+		if len(before) > 0 {
+			neighbor = before[0].SortKey
+		}
+		page, err := s.List(ctx, chatID, MessageListParams{Cursor: neighbor, Direction: "after"})
+		if err != nil {
+			return MessageContext{}, err
+		}
+		if len(page.Items) > 0 && page.Items[0].SortKey == result.SortKey {
+			anchor := page.Items[0]
+			result.Anchor = &anchor
+		}
+	}
+	before = before[:min(len(before), params.Before)]
+	for i, j := 0, len(before)-1; i < j; i, j = i+1, j-1 {
+		before[i], before[j] = before[j], before[i]
+	}
+	result.Before = before
+
+	result.After, err = s.collect(ctx, chatID, result.SortKey, "after", params.After)
+	if err != nil {
+		return MessageContext{}, err
+	}
+	return result, nil
+}
+
+// locateAnchor pages history back from the newest message until it finds
+// params.MessageID, or the newest message at or before params.At.
+func (s *MessagesService) locateAnchor(ctx context.Context, chatID string, params MessageContextParams) (MessageItem, error) {
+	maxPages := params.MaxPages
+	if maxPages <= 0 {
+		maxPages = DefaultContextMaxPages
+	}
+
+	pages := 0
+	cursor := ""
+	for pages < maxPages {
+		page, err := s.List(ctx, chatID, MessageListParams{Cursor: cursor, Direction: "before"})
+		if err != nil {
+			return MessageItem{}, err
+		}
+		pages++
+		for _, item := range page.Items {
+			if params.MessageID != "" {
+				if item.ID == params.MessageID {
+					return item, nil
+				}
+				continue
+			}
+			if ts, err := time.Parse(time.RFC3339, item.Timestamp); err == nil && !ts.After(params.At) {
+				return item, nil
+			}
+		}
+		if !page.HasMore || page.NextCursor == "" || page.NextCursor == cursor {
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	if params.MessageID != "" {
+		return MessageItem{}, fmt.Errorf("%w: %s in %s (scanned %d pages)", ErrMessageNotFound, params.MessageID, chatID, pages)
+	}
+	return MessageItem{}, fmt.Errorf("%w: no message at or before %s in %s (scanned %d pages)", ErrMessageNotFound, params.At.Format(time.RFC3339), chatID, pages)
+}
+
+// collect pages from cursor in direction until it has limit messages,
+// nearest to the cursor first.
+func (s *MessagesService) collect(ctx context.Context, chatID, cursor, direction string, limit int) ([]MessageItem, error) {
+	items := []MessageItem{}
+	for len(items) < limit {
+		page, err := s.List(ctx, chatID, MessageListParams{Cursor: cursor, Direction: direction})
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page.Items...)
+		if !page.HasMore || page.NextCursor == "" || page.NextCursor == cursor {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	return items[:min(len(items), limit)], nil
+}
@@ -24,3 +24,31 @@ func displayNameForChat(chatType string, title string, participants []shared.Use
 
 	return ""
 }
+
+// participantNames returns the names of the non-self participants.
+func participantNames(participants []shared.User) []string {
+	names := make([]string, 0, len(participants))
+	for _, p := range participants {
+		if p.IsSelf {
+			continue
+		}
+		if p.FullName != "" {
+			names = append(names, p.FullName)
+		}
+		if p.Username != "" && p.Username != p.FullName {
+			names = append(names, p.Username)
+		}
+	}
+	return names
+}
+
+// participantIDs returns the user IDs of participants other than self.
+func participantIDs(participants []shared.User) []string {
+	ids := make([]string, 0, len(participants))
+	for _, p := range participants {
+		if !p.IsSelf && p.ID != "" {
+			ids = append(ids, p.ID)
+		}
+	}
+	return ids
+}
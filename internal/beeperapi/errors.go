@@ -103,8 +103,11 @@ func formatAPIError(err *beeperdesktopapi.Error) string {
 	}
 }
 
-// IsNotFound returns true if the error is a 404.
+// IsNotFound returns true if the error is a 404 or ErrMessageNotFound.
 func IsNotFound(err error) bool {
+	if errors.Is(err, ErrMessageNotFound) {
+		return true
+	}
 	var apiErr *beeperdesktopapi.Error
 	if errors.As(err, &apiErr) {
 		return apiErr.StatusCode == 404
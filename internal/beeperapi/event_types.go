@@ -0,0 +1,162 @@
+package beeperapi
+
+import (
+	"encoding/json"
+	"time"
+
+	beeperdesktopapi "github.com/beeper/desktop-api-go"
+)
+
+// Known websocket domain event types.
+const (
+	EventTypeMessageUpserted = "message.upserted"
+	EventTypeMessageDeleted  = "message.deleted"
+	EventTypeChatUpserted    = "chat.upserted"
+	EventTypeChatDeleted     = "chat.deleted"
+
+	// EventTypeMessageBackfill is emitted by the CLI (not the API) for
+	// messages recovered after a reconnect. Entries have the same shape as
+	// message.upserted entries.
+	EventTypeMessageBackfill = "message.backfill"
+)
+
+// DecodedEvent is an Event with its entries decoded into typed items.
+type DecodedEvent struct {
+	Event
+	Messages   []MessageItem
+	Chats      []ChatListItem
+	DeletedIDs []string
+	// Unknown holds entries of unknown event types, or entries that could
+	// not be decoded, as raw maps.
+	Unknown []map[string]any
+}
+
+// Decode converts raw entries into typed items based on the event type.
+// It never fails: entries that do not decode are kept in Unknown.
+func (e Event) Decode() DecodedEvent {
+	decoded := DecodedEvent{Event: e}
+
+	switch e.Type {
+	case EventTypeMessageUpserted, EventTypeMessageBackfill:
+		for _, entry := range e.Entries {
+			var msg beeperdesktopapi.Message
+			if err := decodeEntry(entry, &msg); err != nil || msg.ID == "" {
+				decoded.Unknown = append(decoded.Unknown, entry)
+				continue
+			}
+			item := messageItemFromSDK(msg)
+			if item.ChatID == "" {
+				item.ChatID = e.ChatID
+			}
+			decoded.Messages = append(decoded.Messages, item)
+		}
+	case EventTypeChatUpserted:
+		for _, entry := range e.Entries {
+			var chat beeperdesktopapi.ChatListResponse
+			if err := decodeEntry(entry, &chat); err != nil || chat.ID == "" {
+				decoded.Unknown = append(decoded.Unknown, entry)
+				continue
+			}
+			decoded.Chats = append(decoded.Chats, chatListItemFromSDK(chat))
+		}
+	case EventTypeMessageDeleted, EventTypeChatDeleted:
+		decoded.DeletedIDs = deletedEventIDs(e)
+	default:
+		decoded.Unknown = e.Entries
+	}
+
+	return decoded
+}
+
+// NewBackfillEvent wraps a message recovered after a reconnect in a
+// message.backfill event whose entry has the shape of a live
+// message.upserted entry.
+func NewBackfillEvent(chatID string, item MessageItem) Event {
+	evt := Event{
+		Type:    EventTypeMessageBackfill,
+		ChatID:  chatID,
+		IDs:     []string{item.ID},
+		Entries: []map[string]any{messageEntry(item)},
+	}
+	if ts, err := time.Parse(time.RFC3339, item.Timestamp); err == nil {
+		evt.TS = ts.UnixMilli()
+	}
+	return evt
+}
+
+// messageEntry converts item back to the API's message JSON, the inverse
+// of messageItemFromSDK.
+func messageEntry(item MessageItem) map[string]any {
+	entry := map[string]any{
+		"id":        item.ID,
+		"accountID": item.AccountID,
+		"chatID":    item.ChatID,
+		"senderID":  item.SenderID,
+		"sortKey":   item.SortKey,
+	}
+	setIf := func(m map[string]any, key string, value any, ok bool) {
+		if ok {
+			m[key] = value
+		}
+	}
+	setIf(entry, "timestamp", item.Timestamp, item.Timestamp != "")
+	setIf(entry, "senderName", item.SenderName, item.SenderName != "")
+	setIf(entry, "text", item.Text, item.Text != "")
+	setIf(entry, "type", item.MessageType, item.MessageType != "")
+	setIf(entry, "linkedMessageID", item.LinkedMessageID, item.LinkedMessageID != "")
+	setIf(entry, "isSender", true, item.IsSender)
+	setIf(entry, "isUnread", true, item.IsUnread)
+	if len(item.Attachments) > 0 {
+		attachments := make([]map[string]any, 0, len(item.Attachments))
+		for _, att := range item.Attachments {
+			a := map[string]any{"type": att.Type}
+			setIf(a, "fileName", att.FileName, att.FileName != "")
+			setIf(a, "fileSize", att.FileSize, att.FileSize != 0)
+			setIf(a, "mimeType", att.MimeType, att.MimeType != "")
+			setIf(a, "srcURL", att.SrcURL, att.SrcURL != "")
+			setIf(a, "duration", att.Duration, att.Duration != 0)
+			setIf(a, "isGif", true, att.IsGif)
+			setIf(a, "isSticker", true, att.IsSticker)
+			setIf(a, "isVoiceNote", true, att.IsVoiceNote)
+			setIf(a, "posterImg", att.PosterImg, att.PosterImg != "")
+			setIf(a, "size", map[string]any{"width": att.Width, "height": att.Height}, att.Width != 0 || att.Height != 0)
+			attachments = append(attachments, a)
+		}
+		entry["attachments"] = attachments
+	}
+	if len(item.Reactions) > 0 {
+		reactions := make([]map[string]any, 0, len(item.Reactions))
+		for _, r := range item.Reactions {
+			reaction := map[string]any{"id": r.ID, "participantID": r.ParticipantID, "reactionKey": r.ReactionKey}
+			setIf(reaction, "emoji", true, r.Emoji)
+			setIf(reaction, "imgURL", r.ImgURL, r.ImgURL != "")
+			reactions = append(reactions, reaction)
+		}
+		entry["reactions"] = reactions
+	}
+	return entry
+}
+
+func decodeEntry(entry map[string]any, dst any) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dst)
+}
+
+func deletedEventIDs(e Event) []string {
+	if len(e.IDs) > 0 {
+		return e.IDs
+	}
+	if e.Type == EventTypeChatDeleted && e.ChatID != "" && len(e.Entries) == 0 {
+		return []string{e.ChatID}
+	}
+	ids := make([]string, 0, len(e.Entries))
+	for _, entry := range e.Entries {
+		if id, ok := entry["id"].(string); ok && id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
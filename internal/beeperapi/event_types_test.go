@@ -0,0 +1,112 @@
+package beeperapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestEventDecodeMessageUpserted(t *testing.T) {
+	t.Parallel()
+
+	var evt Event
+	if err := json.Unmarshal([]byte(`{
+		"type":"message.upserted",
+		"chatID":"chat_a",
+		"ids":["m1"],
+		"entries":[
+			{"id":"m1","accountID":"acc1","chatID":"chat_a","senderID":"u1","senderName":"Alice","sortKey":"s1","timestamp":"2026-02-11T00:00:00Z","text":"hello","type":"TEXT"},
+			{"unexpected":true}
+		]
+	}`), &evt); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	decoded := evt.Decode()
+	if len(decoded.Messages) != 1 {
+		t.Fatalf("messages = %d, want 1", len(decoded.Messages))
+	}
+	msg := decoded.Messages[0]
+	if msg.ID != "m1" || msg.SenderName != "Alice" || msg.SortKey != "s1" || msg.Text != "hello" {
+		t.Fatalf("unexpected message: %#v", msg)
+	}
+	if msg.Timestamp != "2026-02-11T00:00:00Z" {
+		t.Fatalf("timestamp = %q", msg.Timestamp)
+	}
+	if len(decoded.Unknown) != 1 {
+		t.Fatalf("unknown = %d, want 1 undecodable entry", len(decoded.Unknown))
+	}
+}
+
+func TestEventDecodeChatUpsertedAndDeleted(t *testing.T) {
+	t.Parallel()
+
+	chat := Event{
+		Type:   EventTypeChatUpserted,
+		ChatID: "chat_a",
+		Entries: []map[string]any{{
+			"id":           "chat_a",
+			"accountID":    "acc1",
+			"title":        "Team",
+			"type":         "group",
+			"participants": map[string]any{"hasMore": false, "items": []any{}, "total": 0},
+			"unreadCount":  0,
+		}},
+	}.Decode()
+	if len(chat.Chats) != 1 || chat.Chats[0].Title != "Team" || chat.Chats[0].AccountID != "acc1" {
+		t.Fatalf("unexpected chats: %#v", chat.Chats)
+	}
+
+	deleted := Event{Type: EventTypeMessageDeleted, ChatID: "chat_a", IDs: []string{"m1", "m2"}}.Decode()
+	if len(deleted.DeletedIDs) != 2 || deleted.DeletedIDs[1] != "m2" {
+		t.Fatalf("deleted IDs = %#v", deleted.DeletedIDs)
+	}
+}
+
+func TestEventDecodeUnknownTypeKeepsRawEntries(t *testing.T) {
+	t.Parallel()
+
+	decoded := Event{
+		Type:    "presence.updated",
+		Entries: []map[string]any{{"userID": "u1"}},
+	}.Decode()
+	if len(decoded.Unknown) != 1 || decoded.Unknown[0]["userID"] != "u1" {
+		t.Fatalf("unknown = %#v", decoded.Unknown)
+	}
+	if len(decoded.Messages) != 0 || len(decoded.Chats) != 0 {
+		t.Fatalf("unexpected typed items: %#v", decoded)
+	}
+}
+
+func TestEventDecodeBackfillEntries(t *testing.T) {
+	t.Parallel()
+
+	item := MessageItem{
+		ID: "m2", AccountID: "acc1", ChatID: "chat_a", SenderID: "u1", SenderName: "User One",
+		Text: "two", MessageType: "TEXT", Timestamp: "2026-02-11T00:01:00Z", SortKey: "s2", IsUnread: true, HasMedia: true,
+		Attachments:  []MessageAttachment{{Type: "img", FileName: "a.png", MimeType: "image/png", SrcURL: "mxc://a", Width: 3, Height: 2}},
+		Reactions:    []MessageReaction{{ID: "r1", ParticipantID: "u2", ReactionKey: "👍", Emoji: true}},
+		ReactionKeys: []string{"👍"},
+	}
+	evt := NewBackfillEvent("chat_a", item)
+	if evt.Type != EventTypeMessageBackfill || evt.TS != 1770768060000 || len(evt.IDs) != 1 || evt.IDs[0] != "m2" {
+		t.Fatalf("event = %#v", evt)
+	}
+	if _, ok := evt.Entries[0]["sortKey"]; !ok {
+		t.Fatalf("entry is not in the API shape: %#v", evt.Entries[0])
+	}
+
+	// Entries round-trip through the JSON a consumer would read.
+	raw, err := json.Marshal(evt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var read Event
+	if err := json.Unmarshal(raw, &read); err != nil {
+		t.Fatal(err)
+	}
+	decoded := read.Decode()
+	if len(decoded.Messages) != 1 || !reflect.DeepEqual(decoded.Messages[0], item) {
+		t.Fatalf("messages = %#v, want %#v", decoded.Messages, item)
+	}
+}
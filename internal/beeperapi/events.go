@@ -2,6 +2,7 @@ package beeperapi
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -44,10 +45,20 @@ func (e Event) IsControlMessage() bool {
 	}
 }
 
+// EventsDialer opens /v1/ws connections in place of the default dialer.
+// ObserveEventsFrame is called with every raw frame read from a connection
+// it returned, so implementations can record sessions.
+type EventsDialer interface {
+	DialEvents(ctx context.Context, dialer *websocket.Dialer, url string, header http.Header) (*websocket.Conn, *http.Response, error)
+	ObserveEventsFrame(conn *websocket.Conn, frame []byte)
+}
+
 // EventsConnection wraps an active websocket connection.
 type EventsConnection struct {
-	conn *websocket.Conn
-	mu   sync.Mutex
+	conn     *websocket.Conn
+	mu       sync.Mutex
+	observer EventsDialer
+	client   *Client
 }
 
 // EventsHandshakeError is returned when opening /v1/ws fails at HTTP handshake time.
@@ -114,7 +125,13 @@ func (s *EventsService) Connect(ctx context.Context) (*EventsConnection, error)
 	headers := http.Header{}
 	headers.Set("Authorization", "Bearer "+s.client.token)
 
-	conn, resp, err := dialer.DialContext(ctx, wsURL, headers)
+	var conn *websocket.Conn
+	var resp *http.Response
+	if s.client.eventsDialer != nil {
+		conn, resp, err = s.client.eventsDialer.DialEvents(ctx, &dialer, wsURL, headers)
+	} else {
+		conn, resp, err = dialer.DialContext(ctx, wsURL, headers)
+	}
 	if err != nil {
 		statusCode := 0
 		statusText := ""
@@ -133,7 +150,7 @@ func (s *EventsService) Connect(ctx context.Context) (*EventsConnection, error)
 		return nil, err
 	}
 
-	return &EventsConnection{conn: conn}, nil
+	return &EventsConnection{conn: conn, observer: s.client.eventsDialer, client: s.client}, nil
 }
 
 // SetSubscriptions replaces current websocket subscriptions.
@@ -173,13 +190,43 @@ func (c *EventsConnection) ReadEvent(ctx context.Context) (Event, error) {
 		_ = c.conn.SetReadDeadline(deadline)
 	}
 
+	_, data, err := c.conn.ReadMessage()
+	if err != nil {
+		return Event{}, err
+	}
+	if c.observer != nil {
+		c.observer.ObserveEventsFrame(c.conn, data)
+	}
+
 	var evt Event
-	if err := c.conn.ReadJSON(&evt); err != nil {
+	if err := json.Unmarshal(data, &evt); err != nil {
 		return Event{}, err
 	}
+	// Chat events make cached chat resolutions stale for every rr process.
+	c.client.invalidateForEvent(evt.Type)
 	return evt, nil
 }
 
+// ReadRaw reads one websocket frame without decoding it.
+func (c *EventsConnection) ReadRaw(ctx context.Context) ([]byte, error) {
+	if c == nil || c.conn == nil {
+		return nil, fmt.Errorf("events connection is not initialized")
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = c.conn.SetReadDeadline(deadline)
+	}
+
+	_, data, err := c.conn.ReadMessage()
+	if err != nil {
+		return nil, err
+	}
+	if c.observer != nil {
+		c.observer.ObserveEventsFrame(c.conn, data)
+	}
+	return data, nil
+}
+
 // Close closes the websocket connection.
 func (c *EventsConnection) Close() error {
 	if c == nil || c.conn == nil {
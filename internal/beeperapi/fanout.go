@@ -0,0 +1,124 @@
+package beeperapi
+
+import (
+	"context"
+	"sync"
+)
+
+// MaxConcurrency bounds how many API requests FanOut keeps in flight.
+const MaxConcurrency = 16
+
+// FanOut calls fn for every key with at most concurrency calls in flight.
+// Results keep the order of keys regardless of completion order. The first
+// error cancels the remaining calls and is returned.
+func FanOut[K, V any](ctx context.Context, concurrency int, keys []K, fn func(ctx context.Context, key K) (V, error)) ([]V, error) {
+	concurrency = min(max(concurrency, 1), MaxConcurrency, max(len(keys), 1))
+	results := make([]V, len(keys))
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	sem := make(chan struct{}, concurrency)
+	for i, key := range keys {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			v, err := fn(ctx, key)
+			if err != nil {
+				errOnce.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+			results[i] = v
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// Prefetch is a request running in the background, typically the next page
+// of a listing fetched while the current page is processed.
+type Prefetch[T any] struct {
+	done chan struct{}
+	val  T
+	err  error
+}
+
+// StartPrefetch runs fn in a new goroutine.
+func StartPrefetch[T any](fn func() (T, error)) *Prefetch[T] {
+	p := &Prefetch[T]{done: make(chan struct{})}
+	go func() {
+		defer close(p.done)
+		p.val, p.err = fn()
+	}()
+	return p
+}
+
+// Wait blocks until the prefetch finishes and returns its result.
+func (p *Prefetch[T]) Wait() (T, error) {
+	<-p.done
+	return p.val, p.err
+}
+
+// SearchEach pages through chat search results in order, calling fn for each
+// page. With prefetch set, the next page is requested while fn handles the
+// current one. Paging follows OldestCursor, so params.Direction should be
+// empty or "before".
+func (s *ChatsService) SearchEach(ctx context.Context, params ChatSearchParams, prefetch bool, fn func(page ChatSearchResult) error) error {
+	page, err := s.Search(ctx, params)
+	if err != nil {
+		return err
+	}
+	for {
+		more := page.HasMore && page.OldestCursor != "" && page.OldestCursor != params.Cursor
+		if more {
+			params.Cursor = page.OldestCursor
+		}
+		nextParams := params
+		fetch := func() (ChatSearchResult, error) { return s.Search(ctx, nextParams) }
+
+		var next *Prefetch[ChatSearchResult]
+		if more && prefetch {
+			next = StartPrefetch(fetch)
+		}
+		if err := fn(page); err != nil {
+			if next != nil {
+				_, _ = next.Wait()
+			}
+			return err
+		}
+		if !more {
+			return nil
+		}
+		if next != nil {
+			page, err = next.Wait()
+		} else {
+			page, err = fetch()
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
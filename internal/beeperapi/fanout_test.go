@@ -0,0 +1,111 @@
+package beeperapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFanOutKeepsKeyOrderAndBoundsConcurrency(t *testing.T) {
+	t.Parallel()
+
+	var inFlight, peak atomic.Int32
+	keys := []int{5, 1, 4, 2, 3, 0}
+	got, err := FanOut(context.Background(), 2, keys, func(_ context.Context, key int) (string, error) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Duration(key) * time.Millisecond)
+		return fmt.Sprintf("k%d", key), nil
+	})
+	if err != nil {
+		t.Fatalf("FanOut() error = %v", err)
+	}
+	want := []string{"k5", "k1", "k4", "k2", "k3", "k0"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("FanOut() = %v, want %v", got, want)
+	}
+	if peak.Load() > 2 {
+		t.Fatalf("peak concurrency = %d, want <= 2", peak.Load())
+	}
+}
+
+func TestFanOutReturnsFirstError(t *testing.T) {
+	t.Parallel()
+
+	boom := errors.New("boom")
+	var calls atomic.Int32
+	_, err := FanOut(context.Background(), 1, []string{"a", "b", "c"}, func(ctx context.Context, key string) (int, error) {
+		calls.Add(1)
+		if key == "a" {
+			return 0, boom
+		}
+		return 1, ctx.Err()
+	})
+	if !errors.Is(err, boom) {
+		t.Fatalf("FanOut() error = %v, want boom", err)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("calls = %d, want remaining keys skipped after error", calls.Load())
+	}
+}
+
+func TestChatsSearchEachPrefetchesInOrder(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chats/search" {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`[]`))
+			return
+		}
+		requests.Add(1)
+		start := 0
+		if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+			start, _ = strconv.Atoi(strings.TrimPrefix(cursor, "c"))
+		}
+		end := min(start+2, 5)
+		items := make([]string, 0, 2)
+		for i := start; i < end; i++ {
+			items = append(items, fmt.Sprintf(`{"id":"!chat%d:beeper.local","accountID":"acc1","participants":{"hasMore":false,"items":[],"total":0},"title":"Chat %d","type":"group","unreadCount":0}`, i, i))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"items":[%s],"hasMore":%t,"oldestCursor":"c%d","newestCursor":""}`, strings.Join(items, ","), end < 5, end)
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-token", server.URL, 0)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	var ids []string
+	err = client.Chats().SearchEach(context.Background(), ChatSearchParams{Limit: 2}, true, func(page ChatSearchResult) error {
+		for _, item := range page.Items {
+			ids = append(ids, item.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("SearchEach() error = %v", err)
+	}
+	if len(ids) != 5 || ids[0] != "!chat0:beeper.local" || ids[4] != "!chat4:beeper.local" {
+		t.Fatalf("ids = %v", ids)
+	}
+	if requests.Load() != 3 {
+		t.Fatalf("requests = %d, want 3", requests.Load())
+	}
+}
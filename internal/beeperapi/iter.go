@@ -0,0 +1,104 @@
+package beeperapi
+
+import (
+	"context"
+	"iter"
+)
+
+// NextCursor returns the cursor that continues a listing in direction: the
+// newest cursor when paging "after", otherwise the oldest. It falls back to
+// the other cursor when the preferred one is empty.
+func NextCursor(direction, oldestCursor, newestCursor string) string {
+	if direction == "after" {
+		if newestCursor != "" {
+			return newestCursor
+		}
+		return oldestCursor
+	}
+	if oldestCursor != "" {
+		return oldestCursor
+	}
+	return newestCursor
+}
+
+// pageFunc fetches the page at cursor and returns its items, whether more
+// pages follow, and the cursor of the next page.
+type pageFunc[T any] func(ctx context.Context, cursor string) (items []T, hasMore bool, next string, err error)
+
+// paginate yields every item from cursor onward, fetching pages lazily. It
+// stops after the last page, when the API repeats a cursor, when the
+// consumer stops ranging, or when ctx is done. Errors are yielded once with
+// a zero item and end the sequence.
+func paginate[T any](ctx context.Context, cursor string, fetch pageFunc[T]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+			items, hasMore, next, err := fetch(ctx, cursor)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			if !hasMore || next == "" || next == cursor {
+				return
+			}
+			cursor = next
+		}
+	}
+}
+
+// All iterates over every chat matching params, starting at params.Cursor.
+func (s *ChatsService) All(ctx context.Context, params ChatListParams) iter.Seq2[ChatListItem, error] {
+	return paginate(ctx, params.Cursor, func(ctx context.Context, cursor string) ([]ChatListItem, bool, string, error) {
+		params.Cursor = cursor
+		page, err := s.List(ctx, params)
+		return page.Items, page.HasMore, NextCursor(params.Direction, page.OldestCursor, page.NewestCursor), err
+	})
+}
+
+// SearchAll iterates over every chat search result, starting at params.Cursor.
+func (s *ChatsService) SearchAll(ctx context.Context, params ChatSearchParams) iter.Seq2[ChatSearchItem, error] {
+	return paginate(ctx, params.Cursor, func(ctx context.Context, cursor string) ([]ChatSearchItem, bool, string, error) {
+		params.Cursor = cursor
+		page, err := s.Search(ctx, params)
+		return page.Items, page.HasMore, NextCursor(params.Direction, page.OldestCursor, page.NewestCursor), err
+	})
+}
+
+// All iterates over the messages of a chat in params.Direction, starting at
+// params.Cursor.
+func (s *MessagesService) All(ctx context.Context, chatID string, params MessageListParams) iter.Seq2[MessageItem, error] {
+	return paginate(ctx, params.Cursor, func(ctx context.Context, cursor string) ([]MessageItem, bool, string, error) {
+		params.Cursor = cursor
+		page, err := s.List(ctx, chatID, params)
+		return page.Items, page.HasMore, page.NextCursor, err
+	})
+}
+
+// SearchAll iterates over every message search result, starting at
+// params.Cursor.
+func (s *MessagesService) SearchAll(ctx context.Context, params MessageSearchParams) iter.Seq2[MessageItem, error] {
+	return paginate(ctx, params.Cursor, func(ctx context.Context, cursor string) ([]MessageItem, bool, string, error) {
+		params.Cursor = cursor
+		page, err := s.Search(ctx, params)
+		return page.Items, page.HasMore, NextCursor(params.Direction, page.OldestCursor, page.NewestCursor), err
+	})
+}
+
+// AllContacts iterates over every contact of an account, starting at
+// params.Cursor.
+func (s *AccountsService) AllContacts(ctx context.Context, accountID string, params ContactListParams) iter.Seq2[Contact, error] {
+	return paginate(ctx, params.Cursor, func(ctx context.Context, cursor string) ([]Contact, bool, string, error) {
+		params.Cursor = cursor
+		page, err := s.ListContacts(ctx, accountID, params)
+		return page.Items, page.HasMore, NextCursor(params.Direction, page.OldestCursor, page.NewestCursor), err
+	})
+}
@@ -0,0 +1,145 @@
+package beeperapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// pagedChatListServer serves total chats two per page on /v1/chats, paging
+// with oldestCursor "cN".
+func pagedChatListServer(t *testing.T, total int, requests *atomic.Int32) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chats" {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`[]`))
+			return
+		}
+		requests.Add(1)
+		start := 0
+		if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+			start, _ = strconv.Atoi(strings.TrimPrefix(cursor, "c"))
+		}
+		end := min(start+2, total)
+		items := make([]string, 0, 2)
+		for i := start; i < end; i++ {
+			items = append(items, fmt.Sprintf(`{"id":"!chat%d:beeper.local","accountID":"acc1","participants":{"hasMore":false,"items":[],"total":0},"title":"Chat %d","type":"group","unreadCount":0}`, i, i))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"items":[%s],"hasMore":%t,"oldestCursor":"c%d","newestCursor":"n%d"}`, strings.Join(items, ","), end < total, end, start)
+	}))
+}
+
+func TestChatsAllIteratesEveryPage(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := pagedChatListServer(t, 5, &requests)
+	defer server.Close()
+
+	client, err := NewClient("test-token", server.URL, 0)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	var ids []string
+	for chat, err := range client.Chats().All(context.Background(), ChatListParams{Direction: "before"}) {
+		if err != nil {
+			t.Fatalf("All() error = %v", err)
+		}
+		ids = append(ids, chat.ID)
+	}
+	if len(ids) != 5 || ids[0] != "!chat0:beeper.local" || ids[4] != "!chat4:beeper.local" {
+		t.Fatalf("ids = %v", ids)
+	}
+	if requests.Load() != 3 {
+		t.Fatalf("requests = %d, want 3", requests.Load())
+	}
+}
+
+func TestChatsAllStopsWhenConsumerBreaks(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := pagedChatListServer(t, 10, &requests)
+	defer server.Close()
+
+	client, err := NewClient("test-token", server.URL, 0)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	count := 0
+	for _, err := range client.Chats().All(context.Background(), ChatListParams{}) {
+		if err != nil {
+			t.Fatalf("All() error = %v", err)
+		}
+		count++
+		if count == 3 {
+			break
+		}
+	}
+	if requests.Load() != 2 {
+		t.Fatalf("requests = %d, want 2 (no fetch after break)", requests.Load())
+	}
+}
+
+func TestChatsAllYieldsContextError(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := pagedChatListServer(t, 10, &requests)
+	defer server.Close()
+
+	client, err := NewClient("test-token", server.URL, 0)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var gotErr error
+	count := 0
+	for _, err := range client.Chats().All(ctx, ChatListParams{}) {
+		if err != nil {
+			gotErr = err
+			break
+		}
+		count++
+		if count == 2 {
+			cancel()
+		}
+	}
+	if !errors.Is(gotErr, context.Canceled) {
+		t.Fatalf("error = %v, want context.Canceled", gotErr)
+	}
+	if count != 2 || requests.Load() != 1 {
+		t.Fatalf("count = %d, requests = %d; want 2 items from 1 request", count, requests.Load())
+	}
+}
+
+func TestNextCursor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		direction, oldest, newest, want string
+	}{
+		{"", "o", "n", "o"},
+		{"before", "o", "n", "o"},
+		{"before", "", "n", "n"},
+		{"after", "o", "n", "n"},
+		{"after", "o", "", "o"},
+	}
+	for _, tt := range tests {
+		if got := NextCursor(tt.direction, tt.oldest, tt.newest); got != tt.want {
+			t.Errorf("NextCursor(%q, %q, %q) = %q, want %q", tt.direction, tt.oldest, tt.newest, got, tt.want)
+		}
+	}
+}
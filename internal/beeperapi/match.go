@@ -0,0 +1,260 @@
+package beeperapi
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Match modes for chat and contact resolution.
+const (
+	MatchExact  = "exact"
+	MatchPrefix = "prefix"
+	MatchFuzzy  = "fuzzy"
+)
+
+// MatchModes lists the supported match modes, strictest first.
+var MatchModes = []string{MatchExact, MatchPrefix, MatchFuzzy}
+
+const (
+	// fuzzyThreshold is the lowest score a fuzzy candidate needs.
+	fuzzyThreshold = 0.5
+	// fuzzyMargin is how far the best fuzzy candidate must lead the
+	// runner-up to be picked without asking.
+	fuzzyMargin = 0.15
+	// maxCandidates caps the candidates reported for ambiguous queries.
+	maxCandidates = 10
+)
+
+// MatchCandidate is a chat or contact ranked against a resolution query.
+type MatchCandidate struct {
+	ID           string  `json:"id"`
+	Name         string  `json:"name"`
+	AccountID    string  `json:"account_id,omitempty"`
+	Score        float64 `json:"score"`
+	MatchedOn    string  `json:"matched_on"` // title|display_name|participant|full_name|username|email|phone_number|id
+	LastActivity string  `json:"last_activity,omitempty"`
+
+	exact bool
+}
+
+// ValidateMatchMode returns an error unless mode is empty or one of
+// MatchModes.
+func ValidateMatchMode(mode string) error {
+	if mode == "" || slices.Contains(MatchModes, mode) {
+		return nil
+	}
+	return fmt.Errorf("invalid match mode %q (expected exact|prefix|fuzzy)", mode)
+}
+
+// matchField is one named value of a chat or contact that queries are
+// scored against. Participant names weigh less than the chat's own names.
+type matchField struct {
+	name   string
+	value  string
+	weight float64
+	// exactOnly fields (IDs, phone numbers) never match partially.
+	exactOnly bool
+}
+
+// rankCandidate scores fields against query in mode and returns the best
+// match, or false when nothing matched.
+func rankCandidate(query, mode string, fields []matchField) (score float64, matchedOn string, exact bool, ok bool) {
+	for _, f := range fields {
+		fieldMode := mode
+		if f.exactOnly {
+			fieldMode = MatchExact
+		}
+		s := scoreName(query, f.value, fieldMode) * f.weight
+		if s <= score {
+			continue
+		}
+		score, matchedOn = s, f.name
+		exact = s >= 1
+	}
+	if mode == MatchFuzzy && score < fuzzyThreshold {
+		return 0, "", false, false
+	}
+	return score, matchedOn, exact, score > 0
+}
+
+// scoreName scores how well name matches query, from 0 (no match) to 1
+// (equal ignoring case and spacing). Prefix mode also accepts names or
+// words starting with query; fuzzy mode adds substring, edit distance,
+// and word overlap scoring.
+func scoreName(query, name, mode string) float64 {
+	q := normalizeName(query)
+	n := normalizeName(name)
+	if q == "" || n == "" {
+		return 0
+	}
+	if q == n {
+		return 1
+	}
+	if mode == MatchExact {
+		return 0
+	}
+
+	if strings.HasPrefix(n, q) {
+		return 0.9
+	}
+	nameTokens := strings.Fields(n)
+	for _, token := range nameTokens {
+		if strings.HasPrefix(token, q) {
+			return 0.85
+		}
+	}
+	if mode == MatchPrefix {
+		return 0
+	}
+
+	if strings.Contains(n, q) {
+		return 0.8
+	}
+	similarity := 1 - float64(levenshtein(q, n))/float64(max(len([]rune(q)), len([]rune(n))))
+
+	queryTokens := strings.Fields(q)
+	matched := 0
+	for _, qt := range queryTokens {
+		if slices.ContainsFunc(nameTokens, func(nt string) bool { return tokensMatch(qt, nt) }) {
+			matched++
+		}
+	}
+	overlap := 0.8 * float64(matched) / float64(max(len(queryTokens), len(nameTokens)))
+
+	return math.Max(similarity, overlap)
+}
+
+// tokensMatch reports whether query token q matches name token n: equal,
+// a prefix of at least 3 characters, or within a typo or two of it.
+func tokensMatch(q, n string) bool {
+	if q == n || (len([]rune(q)) >= 3 && strings.HasPrefix(n, q)) {
+		return true
+	}
+	size := len([]rune(q))
+	switch {
+	case size >= 8:
+		return levenshtein(q, n) <= 2
+	case size >= 4:
+		return levenshtein(q, n) <= 1
+	}
+	return false
+}
+
+// normalizeName lowercases s and collapses whitespace.
+func normalizeName(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
+// levenshtein returns the edit distance between a and b in runes.
+func levenshtein(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	prev := make([]int, len(br)+1)
+	cur := make([]int, len(br)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		cur[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(br)]
+}
+
+// recencyBonus favors recently active chats when scores are otherwise
+// close. lastActivity is RFC 3339; unknown times get no bonus.
+func recencyBonus(lastActivity string, now time.Time) float64 {
+	t, err := time.Parse(time.RFC3339, lastActivity)
+	if err != nil {
+		return 0
+	}
+	switch age := now.Sub(t); {
+	case age <= 24*time.Hour:
+		return 0.05
+	case age <= 7*24*time.Hour:
+		return 0.03
+	case age <= 30*24*time.Hour:
+		return 0.01
+	}
+	return 0
+}
+
+// pickMatch chooses the candidate a query resolves to. A single exact match
+// always wins. Otherwise exact mode needs one exact match, prefix mode one
+// prefix match, and fuzzy mode a best candidate that clearly leads the
+// rest. It returns a *MatchError listing the top candidates when the
+// choice is ambiguous.
+func pickMatch(resource, query, mode string, candidates []MatchCandidate) (MatchCandidate, error) {
+	candidates = dedupeCandidates(candidates)
+	slices.SortStableFunc(candidates, compareCandidates)
+
+	exact := slices.DeleteFunc(slices.Clone(candidates), func(c MatchCandidate) bool { return !c.exact })
+	switch {
+	case len(exact) == 1:
+		return exact[0], nil
+	case len(exact) > 1:
+		return MatchCandidate{}, ambiguous(resource, query, mode, exact)
+	case mode == MatchExact || len(candidates) == 0:
+		return MatchCandidate{}, &MatchError{Resource: resource, Query: query, Mode: mode}
+	case len(candidates) == 1:
+		return candidates[0], nil
+	case mode == MatchFuzzy && candidates[0].Score-candidates[1].Score >= fuzzyMargin:
+		return candidates[0], nil
+	}
+	return MatchCandidate{}, ambiguous(resource, query, mode, candidates)
+}
+
+func ambiguous(resource, query, mode string, candidates []MatchCandidate) *MatchError {
+	if len(candidates) > maxCandidates {
+		candidates = candidates[:maxCandidates]
+	}
+	return &MatchError{Resource: resource, Query: query, Mode: mode, Ambiguous: true, Candidates: candidates}
+}
+
+// dedupeCandidates keeps the best-scoring entry per ID.
+func dedupeCandidates(candidates []MatchCandidate) []MatchCandidate {
+	best := make(map[string]int, len(candidates))
+	out := make([]MatchCandidate, 0, len(candidates))
+	for _, c := range candidates {
+		i, ok := best[c.ID]
+		if !ok {
+			best[c.ID] = len(out)
+			out = append(out, c)
+			continue
+		}
+		if c.Score > out[i].Score {
+			out[i] = c
+		}
+	}
+	return out
+}
+
+// compareCandidates orders by score, then recency, then name and ID.
+func compareCandidates(a, b MatchCandidate) int {
+	switch {
+	case a.Score != b.Score:
+		if a.Score > b.Score {
+			return -1
+		}
+		return 1
+	case a.LastActivity != b.LastActivity:
+		return strings.Compare(b.LastActivity, a.LastActivity)
+	case a.Name != b.Name:
+		return strings.Compare(a.Name, b.Name)
+	}
+	return strings.Compare(a.ID, b.ID)
+}
+
+// roundScore keeps reported scores readable.
+func roundScore(score float64) float64 {
+	return math.Round(score*1000) / 1000
+}
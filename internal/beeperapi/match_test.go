@@ -0,0 +1,140 @@
+package beeperapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestScoreName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		query, name, mode string
+		want              func(float64) bool
+	}{
+		{"team", "Team", MatchExact, func(s float64) bool { return s == 1 }},
+		{"  project   team ", "Project Team", MatchExact, func(s float64) bool { return s == 1 }},
+		{"tea", "Team", MatchExact, func(s float64) bool { return s == 0 }},
+		{"tea", "Team", MatchPrefix, func(s float64) bool { return s == 0.9 }},
+		{"team", "Project Team", MatchPrefix, func(s float64) bool { return s == 0.85 }},
+		{"eam", "Team", MatchPrefix, func(s float64) bool { return s == 0 }},
+		{"eam", "Team", MatchFuzzy, func(s float64) bool { return s == 0.8 }},
+		{"taem", "Team", MatchFuzzy, func(s float64) bool { return s >= fuzzyThreshold && s < 0.8 }},
+		{"smith jon", "John Smith", MatchFuzzy, func(s float64) bool { return s >= 0.4 }},
+		{"xyz", "Team", MatchFuzzy, func(s float64) bool { return s < fuzzyThreshold }},
+		{"team", "", MatchFuzzy, func(s float64) bool { return s == 0 }},
+	}
+	for _, tt := range tests {
+		if got := scoreName(tt.query, tt.name, tt.mode); !tt.want(got) {
+			t.Errorf("scoreName(%q, %q, %s) = %v", tt.query, tt.name, tt.mode, got)
+		}
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"team", "team", 0},
+		{"taem", "team", 2},
+		{"kitten", "sitting", 3},
+		{"zoë", "zoe", 1},
+	}
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestPickMatch(t *testing.T) {
+	t.Parallel()
+
+	exactTeam := MatchCandidate{ID: "!team", Name: "Team", Score: 1, exact: true}
+	teamster := MatchCandidate{ID: "!teamster", Name: "Teamster", Score: 0.9}
+	teal := MatchCandidate{ID: "!teal", Name: "Teal", Score: 0.6}
+
+	if got, err := pickMatch("chat", "team", MatchPrefix, []MatchCandidate{teamster, exactTeam}); err != nil || got.ID != "!team" {
+		t.Fatalf("exact among prefix = %v, %v", got, err)
+	}
+	if _, err := pickMatch("chat", "tea", MatchExact, []MatchCandidate{teamster}); !IsNoMatch(err) {
+		t.Fatalf("exact mode without exact match: err = %v", err)
+	}
+	if got, err := pickMatch("chat", "tea", MatchPrefix, []MatchCandidate{teamster}); err != nil || got.ID != "!teamster" {
+		t.Fatalf("single prefix match = %v, %v", got, err)
+	}
+	_, err := pickMatch("chat", "tea", MatchPrefix, []MatchCandidate{teal, teamster})
+	if !IsAmbiguous(err) {
+		t.Fatalf("two prefix matches: err = %v", err)
+	}
+	matchErr := err.(*MatchError)
+	if len(matchErr.Candidates) != 2 || matchErr.Candidates[0].ID != "!teamster" || matchErr.Mode != MatchPrefix {
+		t.Fatalf("candidates = %+v", matchErr.Candidates)
+	}
+	if got, err := pickMatch("chat", "tea", MatchFuzzy, []MatchCandidate{teal, teamster}); err != nil || got.ID != "!teamster" {
+		t.Fatalf("fuzzy clear leader = %v, %v", got, err)
+	}
+	runnerUp := MatchCandidate{ID: "!teams", Name: "Teams", Score: 0.85}
+	if _, err := pickMatch("chat", "tea", MatchFuzzy, []MatchCandidate{teamster, runnerUp}); !IsAmbiguous(err) {
+		t.Fatalf("fuzzy close scores: err = %v", err)
+	}
+	dup := exactTeam
+	if got, err := pickMatch("chat", "team", MatchExact, []MatchCandidate{exactTeam, dup}); err != nil || got.ID != "!team" {
+		t.Fatalf("duplicate exact match = %v, %v", got, err)
+	}
+}
+
+func TestResolveFuzzyFindsMisspelledChat(t *testing.T) {
+	t.Parallel()
+
+	recent := time.Now().UTC().Add(-time.Hour).Format(time.RFC3339)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/v1/chats/search" {
+			_, _ = w.Write([]byte(`[]`))
+			return
+		}
+		if r.URL.Query().Get("query") != "" {
+			_, _ = w.Write([]byte(`{"items":[],"hasMore":false}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"items":[
+			{"id":"!team:beeper.local","accountID":"acc1","title":"Project Team","type":"group","lastActivity":"` + recent + `","participants":{"hasMore":false,"items":[],"total":0}},
+			{"id":"!carol:beeper.local","accountID":"acc1","title":"","type":"single","participants":{"hasMore":false,"items":[{"id":"u2","fullName":"Carol Example"}],"total":1}}
+		],"hasMore":false}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-token", server.URL, 5*time.Second)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	if _, err := client.Chats().Resolve(context.Background(), ChatResolveParams{Query: "projct team"}); !IsNoMatch(err) {
+		t.Fatalf("exact Resolve error = %v, want no match", err)
+	}
+	got, err := client.Chats().Resolve(context.Background(), ChatResolveParams{Query: "projct team", Match: MatchFuzzy})
+	if err != nil {
+		t.Fatalf("fuzzy Resolve error = %v", err)
+	}
+	if got.ID != "!team:beeper.local" || got.MatchedOn != "title" || got.Score <= fuzzyThreshold {
+		t.Fatalf("fuzzy Resolve = %+v", got)
+	}
+	got, err = client.Chats().Resolve(context.Background(), ChatResolveParams{Query: "carol", Match: MatchFuzzy})
+	if err != nil {
+		t.Fatalf("fuzzy Resolve(carol) error = %v", err)
+	}
+	if got.ID != "!carol:beeper.local" || got.Name != "Carol Example" {
+		t.Fatalf("fuzzy Resolve(carol) = %+v", got)
+	}
+	if _, err := client.Chats().Resolve(context.Background(), ChatResolveParams{Query: "x", Match: "loose"}); err == nil {
+		t.Fatal("Resolve with invalid mode succeeded")
+	}
+}
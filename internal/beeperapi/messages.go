@@ -71,6 +71,9 @@ type MessageItem struct {
 	Reactions             []MessageReaction   `json:"reactions,omitempty"`
 	ReactionKeys          []string            `json:"reaction_keys,omitempty"`
 	DownloadedAttachments []string            `json:"downloaded_attachments,omitempty"`
+	// ReplyTo is the message LinkedMessageID points to, set by
+	// MessagesService.HydrateReplies.
+	ReplyTo *MessageReply `json:"reply_to,omitempty"`
 }
 
 // MessageAttachment represents a message attachment.
@@ -125,61 +128,7 @@ func (s *MessagesService) List(ctx context.Context, chatID string, params Messag
 	}
 
 	for _, msg := range page.Items {
-		item := MessageItem{
-			ID:              msg.ID,
-			AccountID:       msg.AccountID,
-			ChatID:          msg.ChatID,
-			SenderID:        msg.SenderID,
-			Text:            msg.Text,
-			MessageType:     string(msg.Type),
-			SortKey:         msg.SortKey,
-			LinkedMessageID: msg.LinkedMessageID,
-			IsSender:        msg.IsSender,
-			IsUnread:        msg.IsUnread,
-			HasMedia:        len(msg.Attachments) > 0,
-		}
-		if msg.SenderName != "" {
-			item.SenderName = msg.SenderName
-		} else {
-			item.SenderName = msg.SenderID
-		}
-		if !msg.Timestamp.IsZero() {
-			item.Timestamp = msg.Timestamp.Format(time.RFC3339)
-		}
-		if len(msg.Attachments) > 0 {
-			item.Attachments = make([]MessageAttachment, 0, len(msg.Attachments))
-			for _, att := range msg.Attachments {
-				item.Attachments = append(item.Attachments, MessageAttachment{
-					Type:        string(att.Type),
-					FileName:    att.FileName,
-					FileSize:    int64(att.FileSize),
-					MimeType:    att.MimeType,
-					SrcURL:      att.SrcURL,
-					Duration:    att.Duration,
-					IsGif:       att.IsGif,
-					IsSticker:   att.IsSticker,
-					IsVoiceNote: att.IsVoiceNote,
-					PosterImg:   att.PosterImg,
-					Width:       int(att.Size.Width),
-					Height:      int(att.Size.Height),
-				})
-			}
-		}
-		if len(msg.Reactions) > 0 {
-			item.Reactions = make([]MessageReaction, 0, len(msg.Reactions))
-			item.ReactionKeys = make([]string, 0, len(msg.Reactions))
-			for _, r := range msg.Reactions {
-				item.Reactions = append(item.Reactions, MessageReaction{
-					ID:            r.ID,
-					ParticipantID: r.ParticipantID,
-					ReactionKey:   r.ReactionKey,
-					Emoji:         r.Emoji,
-					ImgURL:        r.ImgURL,
-				})
-				item.ReactionKeys = append(item.ReactionKeys, r.ReactionKey)
-			}
-		}
-		result.Items = append(result.Items, item)
+		result.Items = append(result.Items, messageItemFromSDK(msg))
 	}
 
 	if result.HasMore && len(result.Items) > 0 {
@@ -444,3 +393,62 @@ func (s *MessagesService) Search(ctx context.Context, params MessageSearchParams
 
 	return result, nil
 }
+
+// messageItemFromSDK flattens an SDK message into CLI output form.
+func messageItemFromSDK(msg beeperdesktopapi.Message) MessageItem {
+	item := MessageItem{
+		ID:              msg.ID,
+		AccountID:       msg.AccountID,
+		ChatID:          msg.ChatID,
+		SenderID:        msg.SenderID,
+		Text:            msg.Text,
+		MessageType:     string(msg.Type),
+		SortKey:         msg.SortKey,
+		LinkedMessageID: msg.LinkedMessageID,
+		IsSender:        msg.IsSender,
+		IsUnread:        msg.IsUnread,
+		HasMedia:        len(msg.Attachments) > 0,
+	}
+	if msg.SenderName != "" {
+		item.SenderName = msg.SenderName
+	} else {
+		item.SenderName = msg.SenderID
+	}
+	if !msg.Timestamp.IsZero() {
+		item.Timestamp = msg.Timestamp.Format(time.RFC3339)
+	}
+	if len(msg.Attachments) > 0 {
+		item.Attachments = make([]MessageAttachment, 0, len(msg.Attachments))
+		for _, att := range msg.Attachments {
+			item.Attachments = append(item.Attachments, MessageAttachment{
+				Type:        string(att.Type),
+				FileName:    att.FileName,
+				FileSize:    int64(att.FileSize),
+				MimeType:    att.MimeType,
+				SrcURL:      att.SrcURL,
+				Duration:    att.Duration,
+				IsGif:       att.IsGif,
+				IsSticker:   att.IsSticker,
+				IsVoiceNote: att.IsVoiceNote,
+				PosterImg:   att.PosterImg,
+				Width:       int(att.Size.Width),
+				Height:      int(att.Size.Height),
+			})
+		}
+	}
+	if len(msg.Reactions) > 0 {
+		item.Reactions = make([]MessageReaction, 0, len(msg.Reactions))
+		item.ReactionKeys = make([]string, 0, len(msg.Reactions))
+		for _, r := range msg.Reactions {
+			item.Reactions = append(item.Reactions, MessageReaction{
+				ID:            r.ID,
+				ParticipantID: r.ParticipantID,
+				ReactionKey:   r.ReactionKey,
+				Emoji:         r.Emoji,
+				ImgURL:        r.ImgURL,
+			})
+			item.ReactionKeys = append(item.ReactionKeys, r.ReactionKey)
+		}
+	}
+	return item
+}
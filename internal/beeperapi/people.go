@@ -0,0 +1,458 @@
+package beeperapi
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/johntheyoung/roadrunner/internal/phone"
+)
+
+// maxIdentityLookups caps how many shared emails, phone numbers, and
+// usernames FindPeople follows to other accounts.
+const maxIdentityLookups = 8
+
+// PersonContact is a person's contact record on one account.
+type PersonContact struct {
+	AccountID string `json:"account_id"`
+	Network   string `json:"network,omitempty"`
+	Contact
+	// LinkedBy says why the contact belongs to the person:
+	// query|email|phone_number|username|manual.
+	LinkedBy string `json:"linked_by"`
+}
+
+// Ref returns the account/contact pair identifying c.
+func (c PersonContact) Ref() PersonRef {
+	return PersonRef{AccountID: c.AccountID, ContactID: c.ID}
+}
+
+// PersonRef identifies a contact on an account.
+type PersonRef struct {
+	AccountID string `json:"account_id"`
+	ContactID string `json:"contact_id"`
+}
+
+// Person is one human's contacts across accounts.
+type Person struct {
+	Name     string          `json:"name"`
+	Contacts []PersonContact `json:"contacts"`
+	// Saved is set for people saved with manual links.
+	Saved bool    `json:"saved,omitempty"`
+	Score float64 `json:"score"`
+}
+
+// PersonLink is a locally saved person: contacts linked to it by hand and
+// contacts unlinked from it. Unlinked contacts are never joined to anyone
+// by shared identifiers.
+type PersonLink struct {
+	Name     string          `json:"name"`
+	Contacts []PersonContact `json:"contacts,omitempty"`
+	Unlinked []PersonRef     `json:"unlinked,omitempty"`
+}
+
+// PeopleParams configures people lookups.
+type PeopleParams struct {
+	Query      string
+	AccountIDs []string // defaults to every account
+	Match      string   // exact|prefix|fuzzy (default prefix)
+	Links      []PersonLink
+	// Concurrency bounds parallel contact searches (default 1).
+	Concurrency int
+}
+
+// FindPeople searches every account's contacts for params.Query, follows
+// shared emails, phone numbers, and usernames to the same person's
+// contacts on other accounts, applies params.Links, and groups the result
+// into people, best match first.
+func (s *AccountsService) FindPeople(ctx context.Context, params PeopleParams) ([]Person, error) {
+	query := strings.TrimSpace(params.Query)
+	mode := params.Match
+	if mode == "" {
+		mode = MatchPrefix
+	}
+	if err := ValidateMatchMode(mode); err != nil {
+		return nil, err
+	}
+
+	accounts, err := s.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	networks := make(map[string]string, len(accounts))
+	accountIDs := params.AccountIDs
+	for _, acct := range accounts {
+		networks[acct.ID] = acct.Network
+		if len(params.AccountIDs) == 0 {
+			accountIDs = append(accountIDs, acct.ID)
+		}
+	}
+
+	g := newPeopleGraph(params.Links)
+	search := func(value string) ([][]Contact, error) {
+		return FanOut(ctx, params.Concurrency, accountIDs, func(ctx context.Context, accountID string) ([]Contact, error) {
+			return s.SearchContacts(ctx, accountID, value)
+		})
+	}
+
+	found, err := search(query)
+	if err != nil {
+		return nil, err
+	}
+	for i, contacts := range found {
+		for _, c := range contacts {
+			g.add(PersonContact{AccountID: accountIDs[i], Network: networks[accountIDs[i]], Contact: c, LinkedBy: "query"})
+		}
+	}
+	for _, link := range params.Links {
+		for _, c := range link.Contacts {
+			if len(params.AccountIDs) > 0 && !slices.Contains(params.AccountIDs, c.AccountID) {
+				continue
+			}
+			c.LinkedBy = "manual"
+			if c.Network == "" {
+				c.Network = networks[c.AccountID]
+			}
+			g.add(c)
+		}
+	}
+	g.score(query, mode)
+
+	// Follow the identifiers of matching contacts to other accounts.
+	searched := map[string]bool{strings.ToLower(query): true}
+	lookups := 0
+	for _, value := range g.matchedIdentities() {
+		if lookups == maxIdentityLookups || searched[strings.ToLower(value)] {
+			continue
+		}
+		searched[strings.ToLower(value)] = true
+		lookups++
+		found, err := search(value)
+		if err != nil {
+			return nil, err
+		}
+		for i, contacts := range found {
+			for _, c := range contacts {
+				pc := PersonContact{AccountID: accountIDs[i], Network: networks[accountIDs[i]], Contact: c}
+				if kind := g.sharedIdentity(pc); kind != "" {
+					pc.LinkedBy = kind
+					g.add(pc)
+				}
+			}
+		}
+	}
+
+	return g.people(), nil
+}
+
+// ResolvePerson returns the single person params.Query names. It returns a
+// *MatchError, with ranked candidates when ambiguous, if no single person
+// matches.
+func (s *AccountsService) ResolvePerson(ctx context.Context, params PeopleParams) (Person, error) {
+	people, err := s.FindPeople(ctx, params)
+	if err != nil {
+		return Person{}, err
+	}
+	mode := params.Match
+	if mode == "" {
+		mode = MatchPrefix
+	}
+	candidates := make([]MatchCandidate, 0, len(people))
+	byID := make(map[string]Person, len(people))
+	for _, p := range people {
+		first := p.Contacts[0]
+		byID[first.ID] = p
+		candidates = append(candidates, MatchCandidate{
+			ID:        first.ID,
+			Name:      p.Name,
+			AccountID: first.AccountID,
+			Score:     p.Score,
+			MatchedOn: "person",
+			exact:     p.Score >= 1,
+		})
+	}
+	match, err := pickMatch("person", strings.TrimSpace(params.Query), mode, candidates)
+	if err != nil {
+		return Person{}, err
+	}
+	return byID[match.ID], nil
+}
+
+// FindDirect returns the most recently active direct chat with contact on
+// accountID, or false when there is none.
+func (s *ChatsService) FindDirect(ctx context.Context, accountID string, contact Contact) (ChatSearchItem, bool, error) {
+	query := contact.FullName
+	for _, v := range []string{contact.Username, contact.PhoneNumber, contact.ID} {
+		if query == "" {
+			query = v
+		}
+	}
+	var best ChatSearchItem
+	found := false
+	chats := s.SearchAll(ctx, ChatSearchParams{
+		Query:      query,
+		AccountIDs: []string{accountID},
+		Type:       "direct",
+		Scope:      "participants",
+		Limit:      200,
+		Direction:  "before",
+	})
+	for chat, err := range chats {
+		if err != nil {
+			return ChatSearchItem{}, false, err
+		}
+		if chat.AccountID != accountID || !slices.Contains(chat.participantIDs, contact.ID) {
+			continue
+		}
+		if !found || chat.LastActivity > best.LastActivity {
+			best, found = chat, true
+		}
+	}
+	return best, found, nil
+}
+
+// peopleGraph groups contacts into people with a union-find over contact
+// refs, shared identifiers, and saved links.
+type peopleGraph struct {
+	contacts []PersonContact
+	index    map[PersonRef]int
+	parent   []int
+	scores   []float64
+	// linkOf maps contacts saved in a link to the link's index.
+	linkOf   map[PersonRef]int
+	unlinked map[PersonRef]bool
+	links    []PersonLink
+}
+
+func newPeopleGraph(links []PersonLink) *peopleGraph {
+	g := &peopleGraph{
+		index:    map[PersonRef]int{},
+		linkOf:   map[PersonRef]int{},
+		unlinked: map[PersonRef]bool{},
+		links:    links,
+	}
+	for i, link := range links {
+		for _, c := range link.Contacts {
+			g.linkOf[c.Ref()] = i
+		}
+		for _, ref := range link.Unlinked {
+			g.unlinked[ref] = true
+		}
+	}
+	return g
+}
+
+// add records c once per account/contact, keeping the strongest reason.
+func (g *peopleGraph) add(c PersonContact) {
+	ref := c.Ref()
+	if i, ok := g.index[ref]; ok {
+		if c.LinkedBy == "query" || c.LinkedBy == "manual" {
+			g.contacts[i].LinkedBy = c.LinkedBy
+		}
+		return
+	}
+	g.index[ref] = len(g.contacts)
+	g.contacts = append(g.contacts, c)
+	g.parent = append(g.parent, len(g.parent))
+	g.scores = append(g.scores, 0)
+}
+
+// score ranks every contact against query, counting saved link names.
+func (g *peopleGraph) score(query, mode string) {
+	for i, c := range g.contacts {
+		fields := []matchField{
+			{name: "id", value: c.ID, weight: 1, exactOnly: true},
+			{name: "full_name", value: c.FullName, weight: 1},
+			{name: "username", value: c.Username, weight: 1},
+			{name: "email", value: c.Email, weight: 1, exactOnly: true},
+			{name: "phone_number", value: c.PhoneNumber, weight: 1, exactOnly: true},
+		}
+		if link, ok := g.linkOf[c.Ref()]; ok {
+			fields = append(fields, matchField{name: "name", value: g.links[link].Name, weight: 1})
+		}
+		if score, _, _, ok := rankCandidate(query, mode, fields); ok {
+			g.scores[i] = score
+		}
+	}
+}
+
+// matchedIdentities lists the identifiers of matching contacts that other
+// accounts may share.
+func (g *peopleGraph) matchedIdentities() []string {
+	var values []string
+	for i, c := range g.contacts {
+		if g.scores[i] == 0 || g.unlinked[c.Ref()] {
+			continue
+		}
+		for _, v := range []string{c.Email, c.PhoneNumber, c.Username} {
+			if v = strings.TrimSpace(v); v != "" && !slices.Contains(values, v) {
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
+
+// sharedIdentity returns the kind of identifier c shares with a matching
+// contact, or "".
+func (g *peopleGraph) sharedIdentity(c PersonContact) string {
+	if g.unlinked[c.Ref()] {
+		return ""
+	}
+	keys := identityKeys(c.Contact)
+	for i, other := range g.contacts {
+		if g.scores[i] == 0 || g.unlinked[other.Ref()] {
+			continue
+		}
+		for _, k := range identityKeys(other.Contact) {
+			if slices.Contains(keys, k) {
+				kind, _, _ := strings.Cut(k, ":")
+				return kind
+			}
+		}
+	}
+	return ""
+}
+
+func (g *peopleGraph) find(i int) int {
+	for g.parent[i] != i {
+		g.parent[i] = g.parent[g.parent[i]]
+		i = g.parent[i]
+	}
+	return i
+}
+
+func (g *peopleGraph) union(a, b int) {
+	if ra, rb := g.find(a), g.find(b); ra != rb {
+		g.parent[max(ra, rb)] = min(ra, rb)
+	}
+}
+
+// people joins contacts sharing an identifier or a saved link and returns
+// the groups holding at least one matching contact.
+func (g *peopleGraph) people() []Person {
+	byKey := map[string]int{}
+	linkRoot := map[int]int{}
+	for i, c := range g.contacts {
+		if link, ok := g.linkOf[c.Ref()]; ok {
+			if root, seen := linkRoot[link]; seen {
+				g.union(root, i)
+			} else {
+				linkRoot[link] = i
+			}
+		}
+		if g.unlinked[c.Ref()] {
+			continue
+		}
+		for _, k := range identityKeys(c.Contact) {
+			if j, seen := byKey[k]; seen {
+				g.union(j, i)
+			} else {
+				byKey[k] = i
+			}
+		}
+	}
+
+	groups := map[int]*Person{}
+	var roots []int
+	for i, c := range g.contacts {
+		root := g.find(i)
+		p, ok := groups[root]
+		if !ok {
+			p = &Person{}
+			groups[root] = p
+			roots = append(roots, root)
+		}
+		p.Contacts = append(p.Contacts, c)
+		p.Score = max(p.Score, g.scores[i])
+		if link, ok := g.linkOf[c.Ref()]; ok {
+			p.Name, p.Saved = g.links[link].Name, true
+		}
+	}
+
+	people := make([]Person, 0, len(roots))
+	for _, root := range roots {
+		p := groups[root]
+		if p.Score == 0 {
+			continue
+		}
+		if p.Name == "" {
+			p.Name = personName(p.Contacts)
+		}
+		p.Score = roundScore(p.Score)
+		people = append(people, *p)
+	}
+	slices.SortStableFunc(people, func(a, b Person) int {
+		if a.Score != b.Score {
+			if a.Score > b.Score {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return people
+}
+
+// personName picks the most common full name, falling back to a username
+// or ID.
+func personName(contacts []PersonContact) string {
+	counts := map[string]int{}
+	best := ""
+	for _, c := range contacts {
+		name := strings.TrimSpace(c.FullName)
+		if name == "" {
+			continue
+		}
+		counts[name]++
+		if best == "" || counts[name] > counts[best] {
+			best = name
+		}
+	}
+	if best != "" {
+		return best
+	}
+	for _, c := range contacts {
+		if c.Username != "" {
+			return c.Username
+		}
+	}
+	return contacts[0].ID
+}
+
+// identityKeys returns the normalized identifiers that link a contact to
+// the same person elsewhere: "email:...", "phone_number:...", and
+// "username:...".
+func identityKeys(c Contact) []string {
+	var keys []string
+	if email := strings.ToLower(strings.TrimSpace(c.Email)); strings.Contains(email, "@") {
+		keys = append(keys, "email:"+email)
+	}
+	if number := phoneKey(c.PhoneNumber); len(number) >= 7 {
+		keys = append(keys, "phone_number:"+number)
+	}
+	if username := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(c.Username), "@")); len(username) >= 3 {
+		keys = append(keys, "username:"+username)
+	}
+	return keys
+}
+
+// phoneKey returns the national number of a phone number, so numbers
+// with and without a country code ("+1 555 010 0000", "555-010-0000")
+// share a key. Numbers that are not phone numbers have none.
+func phoneKey(number string) string {
+	if e164, err := phone.Normalize(number, ""); err == nil {
+		return phone.National(e164)
+	}
+	if !phone.LooksLike(number) {
+		return ""
+	}
+	digits := strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, number)
+	return strings.TrimPrefix(digits, "0")
+}
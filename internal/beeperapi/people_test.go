@@ -0,0 +1,73 @@
+package beeperapi
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPeopleGraphGroupsSharedIdentities(t *testing.T) {
+	t.Parallel()
+
+	alice := PersonContact{AccountID: "matrix", Contact: Contact{ID: "@alice:beeper.local", FullName: "Alice Example", Username: "alice", PhoneNumber: "+1 (415) 555-0101"}, LinkedBy: "query"}
+	aliceWA := PersonContact{AccountID: "whatsapp", Contact: Contact{ID: "14155550101@s.whatsapp.net", FullName: "Alice E.", PhoneNumber: "+14155550101"}}
+	aliceSlack := PersonContact{AccountID: "slack", Contact: Contact{ID: "U1", FullName: "A. Example"}}
+	alicia := PersonContact{AccountID: "signal", Contact: Contact{ID: "sig-1", FullName: "Alicia Keys", Email: "alicia@example.com"}, LinkedBy: "query"}
+	bob := PersonContact{AccountID: "matrix", Contact: Contact{ID: "@bob:beeper.local", FullName: "Bob Example", Username: "al"}, LinkedBy: "query"}
+
+	g := newPeopleGraph([]PersonLink{{Name: "Alice", Contacts: []PersonContact{alice, aliceSlack}}})
+	for _, c := range []PersonContact{alice, alicia, bob} {
+		g.add(c)
+	}
+	g.add(PersonContact{AccountID: "slack", Contact: aliceSlack.Contact, LinkedBy: "manual"})
+	g.score("ali", MatchPrefix)
+
+	if kind := g.sharedIdentity(aliceWA); kind != "phone_number" {
+		t.Fatalf("sharedIdentity(aliceWA) = %q, want phone_number", kind)
+	}
+	aliceWA.LinkedBy = "phone_number"
+	g.add(aliceWA)
+
+	people := g.people()
+	if len(people) != 2 {
+		t.Fatalf("people = %+v, want Alice and Alicia", people)
+	}
+	if people[0].Name != "Alice" || !people[0].Saved || len(people[0].Contacts) != 3 {
+		t.Fatalf("people[0] = %+v", people[0])
+	}
+	if people[1].Name != "Alicia Keys" || len(people[1].Contacts) != 1 {
+		t.Fatalf("people[1] = %+v", people[1])
+	}
+}
+
+func TestPeopleGraphUnlinkedContactsStayApart(t *testing.T) {
+	t.Parallel()
+
+	a := PersonContact{AccountID: "matrix", Contact: Contact{ID: "@sam:beeper.local", FullName: "Sam", Email: "sam@example.com"}, LinkedBy: "query"}
+	b := PersonContact{AccountID: "slack", Contact: Contact{ID: "U2", FullName: "Sam (work)", Email: "SAM@example.com"}, LinkedBy: "query"}
+
+	g := newPeopleGraph([]PersonLink{{Name: "Sam", Unlinked: []PersonRef{b.Ref()}}})
+	g.add(a)
+	g.add(b)
+	g.score("sam", MatchPrefix)
+
+	people := g.people()
+	if len(people) != 2 {
+		t.Fatalf("people = %+v, want two separate people", people)
+	}
+}
+
+func TestIdentityKeys(t *testing.T) {
+	t.Parallel()
+
+	got := identityKeys(Contact{Email: " Bob@Example.com ", PhoneNumber: "+1 415-555-0101", Username: "@Bob"})
+	want := []string{"email:bob@example.com", "phone_number:4155550101", "username:bob"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("identityKeys() = %v, want %v", got, want)
+	}
+	if got := identityKeys(Contact{PhoneNumber: "(415) 555-0101"}); !slices.Equal(got, want[1:2]) {
+		t.Fatalf("identityKeys(national) = %v, want %v", got, want[1:2])
+	}
+	if got := identityKeys(Contact{PhoneNumber: "123", Username: "al"}); len(got) != 0 {
+		t.Fatalf("identityKeys(short) = %v, want none", got)
+	}
+}
@@ -0,0 +1,353 @@
+package beeperapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/johntheyoung/roadrunner/internal/phone"
+)
+
+// MatchError reports that resolving a chat or contact by name found no
+// match, or more than one. Ambiguous errors list the best candidates.
+type MatchError struct {
+	Resource   string // chat|contact|person
+	Query      string
+	Mode       string // exact|prefix|fuzzy
+	Ambiguous  bool
+	Candidates []MatchCandidate
+}
+
+func (e *MatchError) Error() string {
+	if e.Ambiguous {
+		return fmt.Sprintf("multiple %ss matched %q", e.Resource, e.Query)
+	}
+	return fmt.Sprintf("no %s matched %q", e.Resource, e.Query)
+}
+
+// IsNoMatch returns true if err is a MatchError for a query with no match.
+func IsNoMatch(err error) bool {
+	var matchErr *MatchError
+	return errors.As(err, &matchErr) && !matchErr.Ambiguous
+}
+
+// IsAmbiguous returns true if err is a MatchError for a query with several
+// matches.
+func IsAmbiguous(err error) bool {
+	var matchErr *MatchError
+	return errors.As(err, &matchErr) && matchErr.Ambiguous
+}
+
+// LooksLikeChatID reports whether value is a Matrix-style chat ID such as
+// "!abc123:beeper.local".
+func LooksLikeChatID(value string) bool {
+	return strings.HasPrefix(value, "!") && strings.Contains(value, ":")
+}
+
+// ChatMatches reports whether chat's ID, title, or display name equals query,
+// ignoring case.
+func ChatMatches(chat ChatSearchItem, query string) bool {
+	q := strings.TrimSpace(query)
+	if q == "" {
+		return false
+	}
+	return strings.EqualFold(chat.ID, q) ||
+		strings.EqualFold(chat.Title, q) ||
+		strings.EqualFold(chat.DisplayName, q)
+}
+
+// ContactMatches reports whether contact's ID, full name, username, or
+// email equals query, ignoring case, or its phone number is the same
+// number in any format ("+1 (555) 010-0000" and "5550100000").
+func ContactMatches(contact Contact, query string) bool {
+	q := strings.TrimSpace(query)
+	if q == "" {
+		return false
+	}
+	return strings.EqualFold(contact.ID, q) ||
+		strings.EqualFold(contact.FullName, q) ||
+		strings.EqualFold(contact.Username, q) ||
+		strings.EqualFold(contact.Email, q) ||
+		phone.Match(contact.PhoneNumber, q, "")
+}
+
+// ChatResolveParams configures chat resolution.
+type ChatResolveParams struct {
+	Query      string
+	AccountIDs []string
+	Match      string // exact|prefix|fuzzy (default exact)
+}
+
+// ResolveID returns the ID of the single chat whose ID, title, or display
+// name exactly matches query, searching all pages. A query that already
+// looks like a chat ID is returned as is. Resolved IDs are kept in the
+// client's Cache when one is set. It returns a *MatchError when no
+// chat or several chats match.
+func (s *ChatsService) ResolveID(ctx context.Context, query string, accountIDs []string) (string, error) {
+	match, err := s.Resolve(ctx, ChatResolveParams{Query: query, AccountIDs: accountIDs})
+	return match.ID, err
+}
+
+// Resolve returns the chat params.Query resolves to in params.Match mode.
+// Exact mode matches the ID, title, or display name. Prefix mode also
+// accepts names or words starting with the query. Fuzzy mode ranks chats
+// by edit distance, word overlap, participant names, and recent activity,
+// and picks the best one only when it clearly leads. Resolutions are kept
+// in the client's Cache when one is set. It returns a *MatchError, with
+// ranked candidates when ambiguous, if no single chat matches.
+func (s *ChatsService) Resolve(ctx context.Context, params ChatResolveParams) (MatchCandidate, error) {
+	q := strings.TrimSpace(params.Query)
+	mode := params.Match
+	if mode == "" {
+		mode = MatchExact
+	}
+	if err := ValidateMatchMode(mode); err != nil {
+		return MatchCandidate{}, err
+	}
+	if LooksLikeChatID(q) {
+		return MatchCandidate{ID: q, Name: q, Score: 1, MatchedOn: "id"}, nil
+	}
+
+	key := cacheKey(append([]string{mode, q}, params.AccountIDs...)...)
+	var cached MatchCandidate
+	if s.client.cacheGet(cacheChats, key, &cached) {
+		return cached, nil
+	}
+
+	pool, err := s.matchPool(ctx, q, mode, params.AccountIDs)
+	if err != nil {
+		return MatchCandidate{}, err
+	}
+	now := time.Now()
+	candidates := make([]MatchCandidate, 0, len(pool))
+	for _, chat := range pool {
+		fields := []matchField{
+			{name: "id", value: chat.ID, weight: 1, exactOnly: true},
+			{name: "title", value: chat.Title, weight: 1},
+			{name: "display_name", value: chat.DisplayName, weight: 1},
+		}
+		if mode != MatchExact {
+			for _, participant := range chat.participants {
+				fields = append(fields, matchField{name: "participant", value: participant, weight: 0.9})
+			}
+		}
+		score, matchedOn, exact, ok := rankCandidate(q, mode, fields)
+		if !ok {
+			continue
+		}
+		if mode == MatchFuzzy {
+			score += recencyBonus(chat.LastActivity, now)
+		}
+		name := chat.Title
+		if chat.DisplayName != "" {
+			name = chat.DisplayName
+		}
+		candidates = append(candidates, MatchCandidate{
+			ID:           chat.ID,
+			Name:         name,
+			AccountID:    chat.AccountID,
+			Score:        roundScore(score),
+			MatchedOn:    matchedOn,
+			LastActivity: chat.LastActivity,
+			exact:        exact,
+		})
+	}
+
+	match, err := pickMatch("chat", q, mode, candidates)
+	if err != nil {
+		return MatchCandidate{}, err
+	}
+	s.client.cacheSet(cacheChats, key, match)
+	return match, nil
+}
+
+// matchPool gathers the chats to rank for query: every search result for
+// the full query and, in fuzzy mode, the first page of results for each
+// word plus the most recently active chats, since misspelled names may not
+// come back from search at all.
+func (s *ChatsService) matchPool(ctx context.Context, query, mode string, accountIDs []string) ([]ChatSearchItem, error) {
+	var pool []ChatSearchItem
+	chats := s.SearchAll(ctx, ChatSearchParams{
+		Query:      query,
+		AccountIDs: accountIDs,
+		Limit:      200,
+		Direction:  "before",
+	})
+	for item, err := range chats {
+		if err != nil {
+			return nil, err
+		}
+		pool = append(pool, item)
+	}
+	if mode != MatchFuzzy {
+		return pool, nil
+	}
+
+	queries := []string{""}
+	if words := strings.Fields(query); len(words) > 1 {
+		queries = append(queries, words...)
+	}
+	for _, q := range queries {
+		if q != "" && len([]rune(q)) < 3 {
+			continue
+		}
+		page, err := s.Search(ctx, ChatSearchParams{
+			Query:      q,
+			AccountIDs: accountIDs,
+			Limit:      200,
+			Direction:  "before",
+		})
+		if err != nil {
+			return nil, err
+		}
+		pool = append(pool, page.Items...)
+	}
+	return pool, nil
+}
+
+// ContactResolveParams configures contact resolution.
+type ContactResolveParams struct {
+	AccountID string
+	Query     string
+	Match     string // exact|prefix|fuzzy (default exact)
+	// Region is the ISO 3166 country code for phone numbers without a
+	// country code. Without one, such numbers match by national number.
+	Region string
+}
+
+// ResolveContact returns the single contact of accountID that exactly
+// matches query. Duplicate entries for the same person count once. It
+// returns a *MatchError when no contact or several contacts match.
+func (s *AccountsService) ResolveContact(ctx context.Context, accountID, query string) (Contact, error) {
+	return s.MatchContact(ctx, ContactResolveParams{AccountID: accountID, Query: query})
+}
+
+// MatchContact returns the contact params.Query resolves to in params.Match
+// mode. Names and usernames match by prefix or fuzzily in those modes; IDs,
+// emails, and phone numbers must always match exactly. Duplicate entries
+// for the same person count once. It returns a *MatchError, with ranked
+// candidates when ambiguous, if no single contact matches.
+func (s *AccountsService) MatchContact(ctx context.Context, params ContactResolveParams) (Contact, error) {
+	mode := params.Match
+	if mode == "" {
+		mode = MatchExact
+	}
+	if err := ValidateMatchMode(mode); err != nil {
+		return Contact{}, err
+	}
+	query := strings.TrimSpace(params.Query)
+
+	pool, err := s.contactMatchPool(ctx, params.AccountID, query, mode, params.Region)
+	if err != nil {
+		return Contact{}, err
+	}
+	byKey := make(map[string]Contact)
+	candidates := make([]MatchCandidate, 0, len(pool))
+	for i, item := range pool {
+		phoneNumber := item.PhoneNumber
+		if phone.Match(phoneNumber, query, params.Region) {
+			// Same number, different formatting: score it as exact.
+			phoneNumber = query
+		}
+		score, matchedOn, exact, ok := rankCandidate(query, mode, []matchField{
+			{name: "id", value: item.ID, weight: 1, exactOnly: true},
+			{name: "full_name", value: item.FullName, weight: 1},
+			{name: "username", value: item.Username, weight: 1},
+			{name: "email", value: item.Email, weight: 1, exactOnly: true},
+			{name: "phone_number", value: phoneNumber, weight: 1, exactOnly: true},
+		})
+		if !ok {
+			continue
+		}
+		key := contactKey(item)
+		if key == "" {
+			key = fmt.Sprintf("idx:%d", i)
+		}
+		byKey[key] = item
+		name := item.FullName
+		if name == "" {
+			name = item.Username
+		}
+		candidates = append(candidates, MatchCandidate{
+			ID:        key,
+			Name:      name,
+			AccountID: params.AccountID,
+			Score:     roundScore(score),
+			MatchedOn: matchedOn,
+			exact:     exact,
+		})
+	}
+
+	match, err := pickMatch("contact", query, mode, candidates)
+	if err != nil {
+		var matchErr *MatchError
+		if errors.As(err, &matchErr) {
+			for i, c := range matchErr.Candidates {
+				matchErr.Candidates[i].ID = byKey[c.ID].ID
+			}
+		}
+		return Contact{}, err
+	}
+	return byKey[match.ID], nil
+}
+
+// contactMatchPool gathers the contacts to rank for query: the search
+// results for the full query and, in fuzzy mode, for each word plus the
+// first page of the account's contacts, since misspelled names may not come
+// back from search at all. Phone number queries also search the E.164 and
+// national forms and add the first page, since search may not match a
+// differently formatted number.
+func (s *AccountsService) contactMatchPool(ctx context.Context, accountID, query, mode, region string) ([]Contact, error) {
+	pool, err := s.SearchContacts(ctx, accountID, query)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case phone.LooksLike(query):
+		if e164, err := phone.Normalize(query, region); err == nil {
+			for _, alt := range []string{e164, phone.National(e164)} {
+				if alt == query {
+					continue
+				}
+				resp, err := s.SearchContacts(ctx, accountID, alt)
+				if err != nil {
+					return nil, err
+				}
+				pool = append(pool, resp...)
+			}
+		}
+	case mode != MatchFuzzy:
+		return pool, nil
+	case len(strings.Fields(query)) > 1:
+		for _, word := range strings.Fields(query) {
+			if len([]rune(word)) < 3 {
+				continue
+			}
+			resp, err := s.SearchContacts(ctx, accountID, word)
+			if err != nil {
+				return nil, err
+			}
+			pool = append(pool, resp...)
+		}
+	}
+	page, err := s.ListContacts(ctx, accountID, ContactListParams{})
+	if err != nil {
+		return nil, err
+	}
+	return append(pool, page.Items...), nil
+}
+
+func contactKey(contact Contact) string {
+	if contact.ID != "" {
+		return "id:" + strings.ToLower(contact.ID)
+	}
+	parts := []string{
+		strings.ToLower(strings.TrimSpace(contact.FullName)),
+		strings.ToLower(strings.TrimSpace(contact.Username)),
+		strings.ToLower(strings.TrimSpace(contact.Email)),
+		strings.ToLower(strings.TrimSpace(contact.PhoneNumber)),
+	}
+	return strings.Join(parts, "|")
+}
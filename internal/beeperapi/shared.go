@@ -0,0 +1,308 @@
+package beeperapi
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultSharedMaxPages bounds how many pages of chat history Links and
+// Media scan when no limit is given.
+const DefaultSharedMaxPages = 50
+
+// SharedParams configures a scan of a chat's shared links or media.
+type SharedParams struct {
+	Since    *time.Time // skip messages before this time; scanning stops there
+	Until    *time.Time // skip messages after this time
+	Dedupe   bool       // keep only the newest occurrence of each link or file
+	Limit    int        // stop after this many items (0 = no limit)
+	MaxPages int        // history pages to scan (default DefaultSharedMaxPages)
+	Domains  []string   // Links: only these domains and their subdomains
+	Kinds    []string   // Media: only these MessageAttachment.Kind values
+}
+
+// SharedLink is a URL found in a message.
+type SharedLink struct {
+	URL        string `json:"url"`
+	Domain     string `json:"domain,omitempty"`
+	MessageID  string `json:"message_id"`
+	ChatID     string `json:"chat_id"`
+	SenderID   string `json:"sender_id,omitempty"`
+	SenderName string `json:"sender_name,omitempty"`
+	Timestamp  string `json:"timestamp,omitempty"`
+	// Count is how many times the URL was shared, set with Dedupe.
+	Count int `json:"count,omitempty"`
+}
+
+// SharedMedia is an attachment found in a message.
+type SharedMedia struct {
+	Kind       string  `json:"kind"`
+	FileName   string  `json:"file_name,omitempty"`
+	MimeType   string  `json:"mime_type,omitempty"`
+	FileSize   int64   `json:"file_size,omitempty"`
+	SrcURL     string  `json:"src_url,omitempty"`
+	Width      int     `json:"width,omitempty"`
+	Height     int     `json:"height,omitempty"`
+	Duration   float64 `json:"duration,omitempty"`
+	MessageID  string  `json:"message_id"`
+	ChatID     string  `json:"chat_id"`
+	SenderID   string  `json:"sender_id,omitempty"`
+	SenderName string  `json:"sender_name,omitempty"`
+	Timestamp  string  `json:"timestamp,omitempty"`
+	// Count is how many times the file was shared, set with Dedupe.
+	Count int `json:"count,omitempty"`
+}
+
+// SharedLinksResult lists a chat's shared links, newest first.
+type SharedLinksResult struct {
+	ChatID string       `json:"chat_id"`
+	Items  []SharedLink `json:"items"`
+	// Scanned is the number of messages read, over Pages pages.
+	Scanned int `json:"scanned"`
+	Pages   int `json:"pages"`
+	// Complete is false when MaxPages or Limit stopped the scan before
+	// Since or the start of the chat.
+	Complete bool `json:"complete"`
+}
+
+// SharedMediaResult lists a chat's shared attachments, newest first.
+type SharedMediaResult struct {
+	ChatID   string        `json:"chat_id"`
+	Items    []SharedMedia `json:"items"`
+	Scanned  int           `json:"scanned"`
+	Pages    int           `json:"pages"`
+	Complete bool          `json:"complete"`
+}
+
+// Kind classifies the attachment as gif, sticker, voice, or, for any other
+// attachment, its BaseKind.
+func (a MessageAttachment) Kind() string {
+	switch {
+	case a.IsGif:
+		return "gif"
+	case a.IsSticker:
+		return "sticker"
+	case a.IsVoiceNote:
+		return "voice"
+	}
+	return a.BaseKind()
+}
+
+// BaseKind classifies the attachment by its API type alone as image, video,
+// audio, or file, so a gif is also an image and a voice note audio.
+func (a MessageAttachment) BaseKind() string {
+	switch a.Type {
+	case "img":
+		return "image"
+	case "video", "audio":
+		return a.Type
+	}
+	return "file"
+}
+
+// Links lists the URLs shared in a chat's messages.
+func (s *MessagesService) Links(ctx context.Context, chatID string, params SharedParams) (SharedLinksResult, error) {
+	result := SharedLinksResult{ChatID: chatID, Items: []SharedLink{}}
+	seen := make(map[string]int)
+	var err error
+	result.Scanned, result.Pages, result.Complete, err = s.scanShared(ctx, chatID, params, func(item MessageItem) bool {
+		for _, raw := range ExtractURLs(item.Text) {
+			domain := ""
+			if u, err := url.Parse(raw); err == nil {
+				domain = strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+			}
+			if !matchesDomain(domain, params.Domains) {
+				continue
+			}
+			key := linkKey(raw)
+			if params.Dedupe {
+				if i, ok := seen[key]; ok {
+					result.Items[i].Count++
+					continue
+				}
+				seen[key] = len(result.Items)
+			}
+			link := SharedLink{
+				URL:        raw,
+				Domain:     domain,
+				MessageID:  item.ID,
+				ChatID:     item.ChatID,
+				SenderID:   item.SenderID,
+				SenderName: item.SenderName,
+				Timestamp:  item.Timestamp,
+			}
+			if params.Dedupe {
+				link.Count = 1
+			}
+			result.Items = append(result.Items, link)
+			if params.Limit > 0 && len(result.Items) >= params.Limit {
+				return false
+			}
+		}
+		return true
+	})
+	return result, err
+}
+
+// Media lists the attachments shared in a chat's messages.
+func (s *MessagesService) Media(ctx context.Context, chatID string, params SharedParams) (SharedMediaResult, error) {
+	result := SharedMediaResult{ChatID: chatID, Items: []SharedMedia{}}
+	seen := make(map[string]int)
+	var err error
+	result.Scanned, result.Pages, result.Complete, err = s.scanShared(ctx, chatID, params, func(item MessageItem) bool {
+		for _, att := range item.Attachments {
+			if len(params.Kinds) > 0 && !slices.Contains(params.Kinds, att.Kind()) {
+				continue
+			}
+			if params.Dedupe {
+				key := att.SrcURL
+				if key == "" {
+					key = att.FileName + "\x00" + att.MimeType + "\x00" + strconv.FormatInt(att.FileSize, 10)
+				}
+				if i, ok := seen[key]; ok {
+					result.Items[i].Count++
+					continue
+				}
+				seen[key] = len(result.Items)
+			}
+			media := SharedMedia{
+				Kind:       att.Kind(),
+				FileName:   att.FileName,
+				MimeType:   att.MimeType,
+				FileSize:   att.FileSize,
+				SrcURL:     att.SrcURL,
+				Width:      att.Width,
+				Height:     att.Height,
+				Duration:   att.Duration,
+				MessageID:  item.ID,
+				ChatID:     item.ChatID,
+				SenderID:   item.SenderID,
+				SenderName: item.SenderName,
+				Timestamp:  item.Timestamp,
+			}
+			if params.Dedupe {
+				media.Count = 1
+			}
+			result.Items = append(result.Items, media)
+			if params.Limit > 0 && len(result.Items) >= params.Limit {
+				return false
+			}
+		}
+		return true
+	})
+	return result, err
+}
+
+// scanShared pages a chat's history newest first and calls visit for each
+// message within params.Since and params.Until until visit returns false.
+// complete reports whether the scan reached Since or the start of the chat.
+func (s *MessagesService) scanShared(ctx context.Context, chatID string, params SharedParams, visit func(MessageItem) bool) (scanned, pages int, complete bool, err error) {
+	maxPages := params.MaxPages
+	if maxPages <= 0 {
+		maxPages = DefaultSharedMaxPages
+	}
+
+	cursor := ""
+	for pages < maxPages {
+		page, err := s.List(ctx, chatID, MessageListParams{Cursor: cursor, Direction: "before"})
+		if err != nil {
+			return scanned, pages, false, err
+		}
+		pages++
+		for _, item := range page.Items {
+			scanned++
+			ts, tsErr := time.Parse(time.RFC3339, item.Timestamp)
+			if tsErr == nil && params.Until != nil && ts.After(*params.Until) {
+				continue
+			}
+			if tsErr == nil && params.Since != nil && ts.Before(*params.Since) {
+				return scanned, pages, true, nil
+			}
+			if !visit(item) {
+				return scanned, pages, false, nil
+			}
+		}
+		if !page.HasMore || page.NextCursor == "" || page.NextCursor == cursor {
+			return scanned, pages, true, nil
+		}
+		cursor = page.NextCursor
+	}
+	return scanned, pages, false, nil
+}
+
+// matchesDomain reports whether domain is one of domains or a subdomain of
+// one. An empty domains list matches everything.
+func matchesDomain(domain string, domains []string) bool {
+	if len(domains) == 0 {
+		return true
+	}
+	for _, d := range domains {
+		d = strings.TrimPrefix(strings.ToLower(d), "www.")
+		if domain == d || strings.HasSuffix(domain, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+// urlPattern finds http(s) URLs and bare www. links in message text.
+var urlPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>"'` + "`" + `]+`)
+
+// ExtractURLs returns the URLs in text in order of appearance. Trailing
+// punctuation and unbalanced closing brackets are trimmed, and bare
+// www. links get an https:// scheme.
+func ExtractURLs(text string) []string {
+	matches := urlPattern.FindAllString(text, -1)
+	urls := make([]string, 0, len(matches))
+	for _, m := range matches {
+		m = trimURL(m)
+		if strings.HasPrefix(strings.ToLower(m), "www.") {
+			m = "https://" + m
+		}
+		if u, err := url.Parse(m); err != nil || u.Host == "" {
+			continue
+		}
+		urls = append(urls, m)
+	}
+	return urls
+}
+
+// trimURL drops sentence punctuation and closing brackets that are not
+// part of the URL ("(see https://x.y/a_(b))." keeps "_(b)").
+func trimURL(s string) string {
+	for s != "" {
+		last := s[len(s)-1]
+		switch last {
+		case '.', ',', ';', ':', '!', '?':
+			s = s[:len(s)-1]
+			continue
+		case ')', ']', '}':
+			open := map[byte]byte{')': '(', ']': '[', '}': '{'}[last]
+			if strings.Count(s, string(open)) < strings.Count(s, string(last)) {
+				s = s[:len(s)-1]
+				continue
+			}
+		}
+		return s
+	}
+	return s
+}
+
+// linkKey is the de-duplication key of a URL: scheme and host are
+// case-insensitive, and a fragment or trailing slash does not make a
+// different link.
+func linkKey(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	u.Fragment = ""
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	return u.String()
+}
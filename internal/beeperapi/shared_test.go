@@ -0,0 +1,60 @@
+package beeperapi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractURLs(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"see https://example.com/doc.", []string{"https://example.com/doc"}},
+		{"(notes: https://en.wikipedia.org/wiki/Go_(language))", []string{"https://en.wikipedia.org/wiki/Go_(language)"}},
+		{"two: http://a.example/x?y=1, and www.b.example!", []string{"http://a.example/x?y=1", "https://www.b.example"}},
+		{"<https://c.example/path>", []string{"https://c.example/path"}},
+		{"no links here, just example.com", []string{}},
+		{"broken https:// link", []string{}},
+	}
+	for _, tt := range tests {
+		if got := ExtractURLs(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ExtractURLs(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestLinkKeyAndDomains(t *testing.T) {
+	if linkKey("HTTPS://Example.com/Doc/#intro") != linkKey("https://example.com/Doc") {
+		t.Fatal("linkKey should ignore scheme/host case, fragments, and trailing slashes")
+	}
+	if linkKey("https://example.com/doc") == linkKey("https://example.com/Doc") {
+		t.Fatal("linkKey should keep path case")
+	}
+	if !matchesDomain("docs.google.com", []string{"google.com"}) || matchesDomain("notgoogle.com", []string{"google.com"}) {
+		t.Fatal("matchesDomain should match subdomains only")
+	}
+}
+
+func TestMessageAttachmentKind(t *testing.T) {
+	tests := map[string]MessageAttachment{
+		"image":   {Type: "img"},
+		"gif":     {Type: "img", IsGif: true},
+		"sticker": {Type: "img", IsSticker: true},
+		"voice":   {Type: "audio", IsVoiceNote: true},
+		"audio":   {Type: "audio"},
+		"video":   {Type: "video"},
+		"file":    {Type: "unknown"},
+	}
+	for want, att := range tests {
+		if got := att.Kind(); got != want {
+			t.Errorf("Kind(%+v) = %q, want %q", att, got, want)
+		}
+	}
+	if got := (MessageAttachment{Type: "img", IsGif: true}).BaseKind(); got != "image" {
+		t.Errorf("BaseKind(gif) = %q, want image", got)
+	}
+	if got := (MessageAttachment{Type: "audio", IsVoiceNote: true}).BaseKind(); got != "audio" {
+		t.Errorf("BaseKind(voice) = %q, want audio", got)
+	}
+}
@@ -0,0 +1,248 @@
+package beeperapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// DefaultThreadMaxPages bounds how many pages of chat history Thread and
+// HydrateReplies scan when no limit is given.
+const DefaultThreadMaxPages = 25
+
+// ErrMessageNotFound is returned by Thread and Context when the message is
+// not in the scanned chat history. IsNotFound reports true for it.
+var ErrMessageNotFound = errors.New("message not found")
+
+// ThreadParams configures thread reconstruction.
+type ThreadParams struct {
+	MaxPages int // history pages to scan (default DefaultThreadMaxPages)
+}
+
+// ThreadMessage is a message in a thread with its direct replies, oldest
+// first.
+type ThreadMessage struct {
+	MessageItem
+	Replies []ThreadMessage `json:"replies,omitempty"`
+}
+
+// Thread is the reply tree containing a message.
+type Thread struct {
+	ChatID    string `json:"chat_id"`
+	MessageID string `json:"message_id"`
+	RootID    string `json:"root_id"`
+	Count     int    `json:"count"`
+	// MissingParentID is the message the root replies to when it was not
+	// found in the scanned history.
+	MissingParentID string        `json:"missing_parent_id,omitempty"`
+	Pages           int           `json:"pages"`
+	Root            ThreadMessage `json:"root"`
+}
+
+// MessageReply is a compact copy of the message a reply points to.
+type MessageReply struct {
+	ID         string `json:"id"`
+	SenderID   string `json:"sender_id,omitempty"`
+	SenderName string `json:"sender_name,omitempty"`
+	Text       string `json:"text,omitempty"`
+	Timestamp  string `json:"timestamp,omitempty"`
+	HasMedia   bool   `json:"has_media,omitempty"`
+}
+
+// Thread reconstructs the reply tree around messageID: it follows
+// LinkedMessageID up to the root and collects every reply below it. Chat
+// history is scanned newest first, so once the root is found all of its
+// replies have been seen. It returns ErrMessageNotFound when messageID is
+// not within params.MaxPages pages.
+func (s *MessagesService) Thread(ctx context.Context, chatID, messageID string, params ThreadParams) (Thread, error) {
+	maxPages := params.MaxPages
+	if maxPages <= 0 {
+		maxPages = DefaultThreadMaxPages
+	}
+
+	byID := make(map[string]MessageItem)
+	var newestFirst []string
+	pages := 0
+	cursor := ""
+	for pages < maxPages {
+		page, err := s.List(ctx, chatID, MessageListParams{Cursor: cursor, Direction: "before"})
+		if err != nil {
+			return Thread{}, err
+		}
+		pages++
+		for _, item := range page.Items {
+			if _, seen := byID[item.ID]; !seen {
+				byID[item.ID] = item
+				newestFirst = append(newestFirst, item.ID)
+			}
+		}
+		if _, missing := threadRoot(byID, messageID); !missing {
+			break
+		}
+		if !page.HasMore || page.NextCursor == "" || page.NextCursor == cursor {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	if _, ok := byID[messageID]; !ok {
+		return Thread{}, fmt.Errorf("%w: %s in %s (scanned %d pages)", ErrMessageNotFound, messageID, chatID, pages)
+	}
+
+	rootID, _ := threadRoot(byID, messageID)
+	children := make(map[string][]string)
+	for i := len(newestFirst) - 1; i >= 0; i-- {
+		item := byID[newestFirst[i]]
+		if item.LinkedMessageID != "" && item.LinkedMessageID != item.ID {
+			children[item.LinkedMessageID] = append(children[item.LinkedMessageID], item.ID)
+		}
+	}
+	visited := make(map[string]bool)
+	var build func(id string) ThreadMessage
+	build = func(id string) ThreadMessage {
+		visited[id] = true
+		node := ThreadMessage{MessageItem: byID[id]}
+		for _, child := range children[id] {
+			if !visited[child] {
+				node.Replies = append(node.Replies, build(child))
+			}
+		}
+		return node
+	}
+
+	thread := Thread{
+		ChatID:    chatID,
+		MessageID: messageID,
+		RootID:    rootID,
+		Pages:     pages,
+		Root:      build(rootID),
+	}
+	thread.Count = len(visited)
+	if parent := byID[rootID].LinkedMessageID; parent != "" {
+		thread.MissingParentID = parent
+	}
+	return thread, nil
+}
+
+// threadRoot follows LinkedMessageID from id to the oldest ancestor in
+// byID. missing is true when id itself or the root's parent is not in
+// byID yet.
+func threadRoot(byID map[string]MessageItem, id string) (rootID string, missing bool) {
+	item, ok := byID[id]
+	if !ok {
+		return "", true
+	}
+	seen := map[string]bool{id: true}
+	for item.LinkedMessageID != "" && !seen[item.LinkedMessageID] {
+		parent, ok := byID[item.LinkedMessageID]
+		if !ok {
+			return item.ID, true
+		}
+		seen[parent.ID] = true
+		item = parent
+	}
+	return item.ID, false
+}
+
+// HydrateReplies sets ReplyTo on each item that replies to another message.
+// Parents are taken from items when present; otherwise each chat's history
+// is scanned back from its newest unresolved reply, up to maxPages pages
+// per chat (default DefaultThreadMaxPages). Parents that are not found
+// leave ReplyTo nil.
+func (s *MessagesService) HydrateReplies(ctx context.Context, items []MessageItem, maxPages int) error {
+	if maxPages <= 0 {
+		maxPages = DefaultThreadMaxPages
+	}
+
+	known := make(map[string]MessageItem, len(items))
+	for _, item := range items {
+		known[item.ChatID+"/"+item.ID] = item
+	}
+	pending := make(map[string][]int)
+	var chats []string
+	for i, item := range items {
+		if item.LinkedMessageID == "" {
+			continue
+		}
+		if parent, ok := known[item.ChatID+"/"+item.LinkedMessageID]; ok {
+			items[i].ReplyTo = messageReply(parent)
+			continue
+		}
+		if _, ok := pending[item.ChatID]; !ok {
+			chats = append(chats, item.ChatID)
+		}
+		pending[item.ChatID] = append(pending[item.ChatID], i)
+	}
+
+	for _, chatID := range chats {
+		want := make(map[string]bool)
+		cursor := ""
+		for n, i := range pending[chatID] {
+			want[items[i].LinkedMessageID] = true
+			// Without a sort key, scan from the newest message.
+			if items[i].SortKey == "" {
+				cursor = ""
+				break
+			}
+			if n == 0 || (cursor != "" && compareSortKeys(items[i].SortKey, cursor) > 0) {
+				cursor = items[i].SortKey
+			}
+		}
+		found := make(map[string]MessageItem)
+		for pages := 0; pages < maxPages && len(found) < len(want); pages++ {
+			page, err := s.List(ctx, chatID, MessageListParams{Cursor: cursor, Direction: "before"})
+			if err != nil {
+				return err
+			}
+			for _, item := range page.Items {
+				if want[item.ID] {
+					found[item.ID] = item
+				}
+			}
+			if !page.HasMore || page.NextCursor == "" || page.NextCursor == cursor {
+				break
+			}
+			cursor = page.NextCursor
+		}
+		for _, i := range pending[chatID] {
+			if parent, ok := found[items[i].LinkedMessageID]; ok {
+				items[i].ReplyTo = messageReply(parent)
+			}
+		}
+	}
+	return nil
+}
+
+func messageReply(item MessageItem) *MessageReply {
+	return &MessageReply{
+		ID:         item.ID,
+		SenderID:   item.SenderID,
+		SenderName: item.SenderName,
+		Text:       item.Text,
+		Timestamp:  item.Timestamp,
+		HasMedia:   item.HasMedia,
+	}
+}
+
+// compareSortKeys orders numeric sort keys numerically and falls back to
+// string comparison otherwise.
+func compareSortKeys(a, b string) int {
+	ai, aErr := strconv.ParseInt(a, 10, 64)
+	bi, bErr := strconv.ParseInt(b, 10, 64)
+	if aErr == nil && bErr == nil {
+		switch {
+		case ai < bi:
+			return -1
+		case ai > bi:
+			return 1
+		}
+		return 0
+	}
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,360 @@
+// Package cache is rr's local read-through cache for API lookups that rarely
+// change between runs: the account list, chat name resolution, and contact
+// searches. Entries live as JSON files under the config directory, scoped
+// per Desktop API URL and token, and expire after a per-resource TTL.
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Cached resources.
+const (
+	ResourceAccounts = "accounts"
+	ResourceChats    = "chats"
+	ResourceContacts = "contacts"
+)
+
+// Resources lists the cached resources in display order.
+var Resources = []string{ResourceAccounts, ResourceChats, ResourceContacts}
+
+// DefaultTTLs are the entry lifetimes used unless overridden.
+var DefaultTTLs = map[string]time.Duration{
+	ResourceAccounts: 10 * time.Minute,
+	ResourceChats:    2 * time.Minute,
+	ResourceContacts: 10 * time.Minute,
+}
+
+const statsFile = "stats.json"
+
+// Options configures a Cache.
+type Options struct {
+	// TTLs overrides DefaultTTLs per resource. A zero TTL disables caching
+	// for that resource.
+	TTLs map[string]time.Duration
+	// Refresh skips reads so every lookup goes to the API, while still
+	// storing fresh results.
+	Refresh bool
+}
+
+// Cache is the cache root. Use Scope to get the store for one API endpoint.
+type Cache struct {
+	dir     string
+	ttls    map[string]time.Duration
+	refresh bool
+
+	mu     sync.Mutex
+	hits   map[string]int64
+	misses map[string]int64
+}
+
+// ValidateResource returns an error unless name is a cached resource.
+func ValidateResource(name string) error {
+	if !slices.Contains(Resources, name) {
+		return fmt.Errorf("unknown cache resource %q (expected one of %v)", name, Resources)
+	}
+	return nil
+}
+
+// New returns a cache rooted at dir.
+func New(dir string, opts Options) (*Cache, error) {
+	ttls := make(map[string]time.Duration, len(DefaultTTLs))
+	for resource, ttl := range DefaultTTLs {
+		ttls[resource] = ttl
+	}
+	for resource, ttl := range opts.TTLs {
+		if err := ValidateResource(resource); err != nil {
+			return nil, err
+		}
+		if ttl < 0 {
+			return nil, fmt.Errorf("invalid cache TTL %s for %s (must be >= 0)", ttl, resource)
+		}
+		ttls[resource] = ttl
+	}
+	return &Cache{
+		dir:     dir,
+		ttls:    ttls,
+		refresh: opts.Refresh,
+		hits:    map[string]int64{},
+		misses:  map[string]int64{},
+	}, nil
+}
+
+// Dir returns the cache root directory.
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// Scope returns the store for the API at baseURL used with token. Entries
+// of different endpoints or tokens never mix.
+func (c *Cache) Scope(baseURL, token string) *Store {
+	sum := sha256.Sum256([]byte(baseURL + "\x00" + token))
+	return &Store{cache: c, dir: filepath.Join(c.dir, hex.EncodeToString(sum[:8]))}
+}
+
+func (c *Cache) count(resource string, hit bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if hit {
+		c.hits[resource]++
+	} else {
+		c.misses[resource]++
+	}
+}
+
+// Store is the cache of one API endpoint and token.
+type Store struct {
+	cache *Cache
+	dir   string
+}
+
+type entry struct {
+	Key      string          `json:"key"`
+	StoredAt time.Time       `json:"stored_at"`
+	Value    json.RawMessage `json:"value"`
+}
+
+func (s *Store) path(resource, key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, resource, hex.EncodeToString(sum[:12])+".json")
+}
+
+// Get decodes the fresh entry for key into v and reports whether one was
+// found. Expired, unreadable, and refreshed entries count as misses.
+func (s *Store) Get(resource, key string, v any) bool {
+	ttl := s.cache.ttls[resource]
+	if ttl <= 0 {
+		return false
+	}
+	if s.cache.refresh {
+		s.cache.count(resource, false)
+		return false
+	}
+
+	data, err := os.ReadFile(s.path(resource, key))
+	if err != nil {
+		s.cache.count(resource, false)
+		return false
+	}
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil || e.Key != key || time.Since(e.StoredAt) > ttl {
+		s.cache.count(resource, false)
+		return false
+	}
+	if err := json.Unmarshal(e.Value, v); err != nil {
+		s.cache.count(resource, false)
+		return false
+	}
+	s.cache.count(resource, true)
+	return true
+}
+
+// Set stores v for key. Caching is best-effort, so write failures are
+// ignored.
+func (s *Store) Set(resource, key string, v any) {
+	if s.cache.ttls[resource] <= 0 {
+		return
+	}
+	value, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	data, err := json.Marshal(entry{Key: key, StoredAt: time.Now().UTC(), Value: value})
+	if err != nil {
+		return
+	}
+	path := s.path(resource, key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".entry-*")
+	if err != nil {
+		return
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if writeErr != nil || closeErr != nil || os.Rename(tmp.Name(), path) != nil {
+		_ = os.Remove(tmp.Name())
+	}
+}
+
+// Invalidate drops every entry of resource for this endpoint.
+func (s *Store) Invalidate(resource string) {
+	_ = os.RemoveAll(filepath.Join(s.dir, resource))
+}
+
+// Flush adds this process's hit and miss counts to the persisted totals
+// reported by Stats.
+func (c *Cache) Flush() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.hits) == 0 && len(c.misses) == 0 {
+		return nil
+	}
+
+	path := filepath.Join(c.dir, statsFile)
+	totals := loadCounters(path)
+	for resource, n := range c.hits {
+		totals.Hits[resource] += n
+	}
+	for resource, n := range c.misses {
+		totals.Misses[resource] += n
+	}
+	c.hits = map[string]int64{}
+	c.misses = map[string]int64{}
+
+	data, err := json.MarshalIndent(totals, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+type counters struct {
+	Hits   map[string]int64 `json:"hits"`
+	Misses map[string]int64 `json:"misses"`
+}
+
+func loadCounters(path string) counters {
+	totals := counters{}
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &totals)
+	}
+	if totals.Hits == nil {
+		totals.Hits = map[string]int64{}
+	}
+	if totals.Misses == nil {
+		totals.Misses = map[string]int64{}
+	}
+	return totals
+}
+
+// ResourceStats summarizes the entries of one resource across all scopes.
+type ResourceStats struct {
+	Resource string `json:"resource"`
+	TTL      string `json:"ttl"`
+	Entries  int    `json:"entries"`
+	Fresh    int    `json:"fresh"`
+	Expired  int    `json:"expired"`
+	Bytes    int64  `json:"bytes"`
+	Hits     int64  `json:"hits"`
+	Misses   int64  `json:"misses"`
+}
+
+// Stats reports entry counts, sizes, and hit/miss totals per resource.
+func (c *Cache) Stats() ([]ResourceStats, error) {
+	byResource := make(map[string]*ResourceStats, len(Resources))
+	stats := make([]ResourceStats, len(Resources))
+	totals := loadCounters(filepath.Join(c.dir, statsFile))
+	for i, resource := range Resources {
+		stats[i] = ResourceStats{
+			Resource: resource,
+			TTL:      c.ttls[resource].String(),
+			Hits:     totals.Hits[resource],
+			Misses:   totals.Misses[resource],
+		}
+		byResource[resource] = &stats[i]
+	}
+
+	err := c.walk("", func(resource string, path string, info fs.FileInfo) {
+		st := byResource[resource]
+		st.Entries++
+		st.Bytes += info.Size()
+		if time.Since(info.ModTime()) > c.ttls[resource] {
+			st.Expired++
+		} else {
+			st.Fresh++
+		}
+	})
+	return stats, err
+}
+
+// Clear removes the entries of resource across all scopes, or every entry
+// and the hit/miss totals when resource is empty. It returns the number of
+// entries removed.
+func (c *Cache) Clear(resource string) (int, error) {
+	if resource != "" {
+		if err := ValidateResource(resource); err != nil {
+			return 0, err
+		}
+	}
+	removed := 0
+	err := c.walk(resource, func(_ string, path string, _ fs.FileInfo) {
+		if os.Remove(path) == nil {
+			removed++
+		}
+	})
+	if err != nil {
+		return removed, err
+	}
+	if resource == "" {
+		if err := os.RemoveAll(c.dir); err != nil {
+			return removed, err
+		}
+	}
+	return removed, nil
+}
+
+// walk calls fn for every entry file, limited to resource when set.
+func (c *Cache) walk(resource string, fn func(resource, path string, info fs.FileInfo)) error {
+	scopes, err := os.ReadDir(c.dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, scope := range scopes {
+		if !scope.IsDir() {
+			continue
+		}
+		for _, res := range Resources {
+			if resource != "" && res != resource {
+				continue
+			}
+			dir := filepath.Join(c.dir, scope.Name(), res)
+			files, err := os.ReadDir(dir)
+			if err != nil {
+				continue
+			}
+			for _, f := range files {
+				if f.IsDir() || filepath.Ext(f.Name()) != ".json" {
+					continue
+				}
+				info, err := f.Info()
+				if err != nil {
+					continue
+				}
+				fn(res, filepath.Join(dir, f.Name()), info)
+			}
+		}
+	}
+	return nil
+}
+
+type contextKey struct{}
+
+// WithCache attaches c to ctx.
+func WithCache(ctx context.Context, c *Cache) context.Context {
+	return context.WithValue(ctx, contextKey{}, c)
+}
+
+// FromContext returns the cache attached to ctx, or nil.
+func FromContext(ctx context.Context) *Cache {
+	c, _ := ctx.Value(contextKey{}).(*Cache)
+	return c
+}
@@ -0,0 +1,183 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStoreGetSet(t *testing.T) {
+	c, err := New(t.TempDir(), Options{})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	store := c.Scope("http://localhost:23373", "token")
+
+	var got []string
+	if store.Get(ResourceAccounts, "list", &got) {
+		t.Fatal("Get() on empty cache = true")
+	}
+	store.Set(ResourceAccounts, "list", []string{"a", "b"})
+	if !store.Get(ResourceAccounts, "list", &got) {
+		t.Fatal("Get() after Set() = false")
+	}
+	if len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Fatalf("Get() = %v", got)
+	}
+
+	other := c.Scope("http://localhost:23373", "other-token")
+	if other.Get(ResourceAccounts, "list", &got) {
+		t.Fatal("Get() in another scope = true")
+	}
+}
+
+func TestStoreExpiresEntries(t *testing.T) {
+	c, err := New(t.TempDir(), Options{TTLs: map[string]time.Duration{ResourceChats: time.Minute}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	store := c.Scope("u", "t")
+	store.Set(ResourceChats, "team", "!team:beeper.local")
+
+	path := store.path(ResourceChats, "team")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read entry: %v", err)
+	}
+	stale := []byte(`{"key":"team","stored_at":"2000-01-01T00:00:00Z","value":"!team:beeper.local"}`)
+	if err := os.WriteFile(path, stale, 0o600); err != nil {
+		t.Fatalf("write entry: %v", err)
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+
+	var got string
+	if store.Get(ResourceChats, "team", &got) {
+		t.Fatal("Get() of expired entry = true")
+	}
+	stats, err := c.Stats()
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+	if st := stats[1]; st.Resource != ResourceChats || st.Entries != 1 || st.Expired != 1 || st.Bytes != int64(len(stale)) {
+		t.Fatalf("chats stats = %+v (fresh entry was %d bytes)", st, len(data))
+	}
+}
+
+func TestZeroTTLDisablesResource(t *testing.T) {
+	dir := t.TempDir()
+	c, err := New(dir, Options{TTLs: map[string]time.Duration{ResourceContacts: 0}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	store := c.Scope("u", "t")
+	store.Set(ResourceContacts, "q", []string{"x"})
+
+	var got []string
+	if store.Get(ResourceContacts, "q", &got) {
+		t.Fatal("Get() with zero TTL = true")
+	}
+	if _, err := os.Stat(filepath.Join(store.dir, ResourceContacts)); !os.IsNotExist(err) {
+		t.Fatalf("zero TTL wrote entries: %v", err)
+	}
+}
+
+func TestRefreshSkipsReadsAndStillWrites(t *testing.T) {
+	dir := t.TempDir()
+	refreshing, err := New(dir, Options{Refresh: true})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	store := refreshing.Scope("u", "t")
+	store.Set(ResourceAccounts, "list", 1)
+
+	var got int
+	if store.Get(ResourceAccounts, "list", &got) {
+		t.Fatal("Get() with Refresh = true")
+	}
+
+	c, err := New(dir, Options{})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if !c.Scope("u", "t").Get(ResourceAccounts, "list", &got) || got != 1 {
+		t.Fatalf("Get() after refresh = %d", got)
+	}
+}
+
+func TestInvalidateDropsResource(t *testing.T) {
+	c, err := New(t.TempDir(), Options{})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	store := c.Scope("u", "t")
+	store.Set(ResourceChats, "a", "!a:x")
+	store.Set(ResourceAccounts, "list", 1)
+	store.Invalidate(ResourceChats)
+
+	var s string
+	if store.Get(ResourceChats, "a", &s) {
+		t.Fatal("Get() after Invalidate() = true")
+	}
+	var n int
+	if !store.Get(ResourceAccounts, "list", &n) {
+		t.Fatal("Invalidate(chats) dropped accounts")
+	}
+}
+
+func TestStatsCountsAndClear(t *testing.T) {
+	dir := t.TempDir()
+	c, err := New(dir, Options{})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	store := c.Scope("u", "t")
+	var v int
+	store.Get(ResourceAccounts, "list", &v)
+	store.Set(ResourceAccounts, "list", 1)
+	store.Get(ResourceAccounts, "list", &v)
+	store.Set(ResourceChats, "a", 2)
+	if err := c.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	reopened, err := New(dir, Options{})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	stats, err := reopened.Stats()
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+	if st := stats[0]; st.Entries != 1 || st.Fresh != 1 || st.Hits != 1 || st.Misses != 1 {
+		t.Fatalf("accounts stats = %+v", st)
+	}
+
+	removed, err := reopened.Clear(ResourceChats)
+	if err != nil || removed != 1 {
+		t.Fatalf("Clear(chats) = %d, %v", removed, err)
+	}
+	if !store.Get(ResourceAccounts, "list", &v) {
+		t.Fatal("Clear(chats) dropped accounts")
+	}
+
+	removed, err = reopened.Clear("")
+	if err != nil || removed != 1 {
+		t.Fatalf("Clear() = %d, %v", removed, err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Fatalf("Clear() kept cache dir: %v", err)
+	}
+}
+
+func TestNewRejectsUnknownResource(t *testing.T) {
+	if _, err := New(t.TempDir(), Options{TTLs: map[string]time.Duration{"messages": time.Minute}}); err == nil {
+		t.Fatal("New() with unknown resource succeeded")
+	}
+	if _, err := New(t.TempDir(), Options{TTLs: map[string]time.Duration{ResourceChats: -time.Second}}); err == nil {
+		t.Fatal("New() with negative TTL succeeded")
+	}
+}
@@ -0,0 +1,164 @@
+// Package cassette records Desktop API traffic to a directory and replays it
+// deterministically. HTTP exchanges are stored one per JSON file and
+// websocket sessions one per JSONL file (eventlog format), numbered in the
+// order they happened so a cassette reads like a transcript.
+package cassette
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Redacted replaces credentials in recorded requests.
+const Redacted = "REDACTED"
+
+// Interaction is one recorded HTTP request/response pair.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is the recorded request. URL holds only the path and query so
+// a cassette replays against any base URL.
+type Request struct {
+	Method  string              `json:"method"`
+	URL     string              `json:"url"`
+	Headers map[string][]string `json:"headers,omitempty"`
+	Body    *Body               `json:"body,omitempty"`
+}
+
+// Response is the recorded response.
+type Response struct {
+	Status  int                 `json:"status"`
+	Headers map[string][]string `json:"headers,omitempty"`
+	Body    *Body               `json:"body,omitempty"`
+}
+
+// Body holds a payload as JSON when it parses, as text when it is valid
+// UTF-8, and as base64 otherwise.
+type Body struct {
+	JSON   json.RawMessage `json:"json,omitempty"`
+	Text   string          `json:"text,omitempty"`
+	Base64 string          `json:"base64,omitempty"`
+}
+
+func newBody(data []byte) *Body {
+	switch {
+	case len(data) == 0:
+		return nil
+	case json.Valid(data):
+		return &Body{JSON: json.RawMessage(data)}
+	case utf8.Valid(data):
+		return &Body{Text: string(data)}
+	default:
+		return &Body{Base64: base64.StdEncoding.EncodeToString(data)}
+	}
+}
+
+// Bytes returns the decoded payload.
+func (b *Body) Bytes() ([]byte, error) {
+	switch {
+	case b == nil:
+		return nil, nil
+	case len(b.JSON) > 0:
+		return b.JSON, nil
+	case b.Base64 != "":
+		return base64.StdEncoding.DecodeString(b.Base64)
+	default:
+		return []byte(b.Text), nil
+	}
+}
+
+type ctxKey struct{}
+
+// WithCassette attaches a cassette to a context.
+func WithCassette(ctx context.Context, c *Cassette) context.Context {
+	return context.WithValue(ctx, ctxKey{}, c)
+}
+
+// FromContext retrieves the cassette from a context, or nil.
+func FromContext(ctx context.Context) *Cassette {
+	c, _ := ctx.Value(ctxKey{}).(*Cassette)
+	return c
+}
+
+// entry is a cassette file name split into its sequence number and kind.
+type entry struct {
+	index int
+	name  string
+	ws    bool
+}
+
+var entryName = regexp.MustCompile(`^(\d+)-.*\.(json|jsonl)$`)
+
+// listEntries returns cassette files in recorded order.
+func listEntries(dir string) ([]entry, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var entries []entry
+	for _, f := range files {
+		m := entryName.FindStringSubmatch(f.Name())
+		if f.IsDir() || m == nil {
+			continue
+		}
+		n, err := strconv.Atoi(m[1])
+		if err != nil {
+			continue
+		}
+		entries = append(entries, entry{index: n, name: f.Name(), ws: m[2] == "jsonl"})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].index < entries[j].index })
+	return entries, nil
+}
+
+var unsafeNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// fileName builds "0007-GET-v1-chats.json" style names.
+func fileName(index int, method, rawURL, ext string) string {
+	path := rawURL
+	if u, err := url.Parse(rawURL); err == nil {
+		path = u.Path
+	}
+	slug := unsafeNameChars.ReplaceAllString(strings.Trim(path, "/"), "-")
+	if len(slug) > 60 {
+		slug = slug[:60]
+	}
+	return fmt.Sprintf("%04d-%s-%s.%s", index, method, slug, ext)
+}
+
+func requestKey(method, rawURL string) string {
+	return method + " " + rawURL
+}
+
+func writeInteraction(path string, in Interaction) error {
+	data, err := json.MarshalIndent(in, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode interaction: %w", err)
+	}
+	data = append(data, '\n')
+	return os.WriteFile(path, data, 0o600)
+}
+
+func readInteraction(path string) (Interaction, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Interaction{}, err
+	}
+	var in Interaction
+	if err := json.Unmarshal(data, &in); err != nil {
+		return Interaction{}, fmt.Errorf("parse %s: %w", filepath.Base(path), err)
+	}
+	return in, nil
+}
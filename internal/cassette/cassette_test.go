@@ -0,0 +1,213 @@
+package cassette
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/johntheyoung/roadrunner/internal/beeperapi"
+	"github.com/johntheyoung/roadrunner/internal/fakeapi"
+)
+
+func clientFor(t *testing.T, c *Cassette, baseURL string) *beeperapi.Client {
+	t.Helper()
+	client, err := beeperapi.NewClient("secret-token", baseURL, 5*time.Second,
+		beeperapi.WithHTTPClient(c.HTTPClient()),
+		beeperapi.WithEventsDialer(c),
+	)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	return client
+}
+
+func TestRecordThenReplayHTTP(t *testing.T) {
+	t.Parallel()
+
+	fake := fakeapi.New(fakeapi.DefaultDataset())
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	defer fake.Close()
+
+	dir := t.TempDir()
+	rec, err := Record(dir)
+	if err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	ctx := context.Background()
+	client := clientFor(t, rec, server.URL)
+
+	recorded, err := client.Chats().List(ctx, beeperapi.ChatListParams{})
+	if err != nil {
+		t.Fatalf("Chats().List() error = %v", err)
+	}
+	if _, err := client.Connect().Introspect(ctx, "secret-token"); err != nil {
+		t.Fatalf("Introspect() error = %v", err)
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	if len(files) != 2 || files[0].Name() != "0001-GET-v1-chats.json" {
+		t.Fatalf("files = %v", files)
+	}
+	for _, f := range files {
+		data, err := os.ReadFile(filepath.Join(dir, f.Name()))
+		if err != nil {
+			t.Fatalf("read %s: %v", f.Name(), err)
+		}
+		if strings.Contains(string(data), "secret-token") {
+			t.Fatalf("%s leaks the token:\n%s", f.Name(), data)
+		}
+	}
+
+	// Replay against a dead address: nothing may touch the network.
+	rep, err := Replay(dir)
+	if err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+	defer func() { _ = rep.Close() }()
+	replayClient := clientFor(t, rep, "http://127.0.0.1:1")
+
+	replayed, err := replayClient.Chats().List(ctx, beeperapi.ChatListParams{})
+	if err != nil {
+		t.Fatalf("replayed Chats().List() error = %v", err)
+	}
+	if len(replayed.Items) != len(recorded.Items) || replayed.Items[0].ID != recorded.Items[0].ID {
+		t.Fatalf("replayed = %#v, recorded = %#v", replayed.Items, recorded.Items)
+	}
+
+	_, err = replayClient.Chats().List(ctx, beeperapi.ChatListParams{})
+	if err == nil || !strings.Contains(err.Error(), "no recorded response for GET /v1/chats") {
+		t.Fatalf("second replay err = %v", err)
+	}
+}
+
+func TestRecordThenReplayWebsocket(t *testing.T) {
+	t.Parallel()
+
+	fake := fakeapi.New(fakeapi.DefaultDataset())
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	defer fake.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	dir := t.TempDir()
+	rec, err := Record(dir)
+	if err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	client := clientFor(t, rec, server.URL)
+	conn, err := client.Events().Connect(ctx)
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	if err := conn.SetSubscriptions(ctx, "sub-1", []string{"*"}); err != nil {
+		t.Fatalf("SetSubscriptions() error = %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := conn.ReadEvent(ctx); err != nil {
+			t.Fatalf("ReadEvent() error = %v", err)
+		}
+	}
+	_ = conn.Close()
+	if err := rec.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	rep, err := Replay(dir)
+	if err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+	defer func() { _ = rep.Close() }()
+	replayConn, err := clientFor(t, rep, "http://127.0.0.1:1").Events().Connect(ctx)
+	if err != nil {
+		t.Fatalf("replay Connect() error = %v", err)
+	}
+	defer func() { _ = replayConn.Close() }()
+	if err := replayConn.SetSubscriptions(ctx, "sub-1", []string{"*"}); err != nil {
+		t.Fatalf("replay SetSubscriptions() error = %v", err)
+	}
+
+	for _, want := range []string{"ready", "subscriptions.updated"} {
+		evt, err := replayConn.ReadEvent(ctx)
+		if err != nil {
+			t.Fatalf("replay ReadEvent() error = %v", err)
+		}
+		if evt.Type != want {
+			t.Fatalf("replayed type = %q, want %q", evt.Type, want)
+		}
+	}
+}
+
+func TestRecordContinuesNumbering(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "0004-GET-v1-info.json"), []byte("{}"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	rec, err := Record(dir)
+	if err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if rec.next != 4 {
+		t.Fatalf("next = %d, want 4", rec.next)
+	}
+}
+
+func TestRecordRedactsSetCookie(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "session-secret"})
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	rec, err := Record(dir)
+	if err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	resp, err := rec.HTTPClient().Get(server.URL + "/v1/info")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	_ = resp.Body.Close()
+	if got := resp.Header.Get("Set-Cookie"); !strings.Contains(got, "session-secret") {
+		t.Fatalf("live Set-Cookie = %q, want it passed through", got)
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "0001-GET-v1-info.json"))
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if strings.Contains(string(data), "session-secret") || !strings.Contains(string(data), Redacted) {
+		t.Fatalf("recorded response leaks Set-Cookie:\n%s", data)
+	}
+}
+
+func TestRedactForm(t *testing.T) {
+	t.Parallel()
+
+	got := string(redactForm("application/x-www-form-urlencoded", []byte("token=abc&token_type_hint=access_token")))
+	if got != "token="+Redacted+"&token_type_hint=access_token" {
+		t.Fatalf("redactForm() = %q", got)
+	}
+}
@@ -0,0 +1,223 @@
+package cassette
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/johntheyoung/roadrunner/internal/eventlog"
+)
+
+// Cassette records or replays API traffic. It is an http.RoundTripper for
+// SDK requests and a beeperapi.EventsDialer for /v1/ws sessions.
+type Cassette struct {
+	dir    string
+	replay bool
+
+	mu sync.Mutex
+
+	// record mode
+	transport http.RoundTripper
+	next      int
+	sessions  map[*websocket.Conn]*wsSession
+
+	// replay mode
+	interactions []*replayInteraction
+	wsFrames     [][]eventlog.Frame
+	servers      []*replayServer
+}
+
+type wsSession struct {
+	file   *os.File
+	writer *eventlog.Writer
+}
+
+// Record returns a cassette that forwards traffic to the network and saves
+// it under dir. Existing entries are kept and numbering continues after
+// them, so several commands can be recorded into one cassette.
+func Record(dir string) (*Cassette, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create cassette dir: %w", err)
+	}
+	entries, err := listEntries(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read cassette dir: %w", err)
+	}
+	next := 0
+	if len(entries) > 0 {
+		next = entries[len(entries)-1].index
+	}
+	return &Cassette{
+		dir:       dir,
+		transport: http.DefaultTransport,
+		next:      next,
+		sessions:  make(map[*websocket.Conn]*wsSession),
+	}, nil
+}
+
+// HTTPClient returns an HTTP client whose requests go through the cassette.
+func (c *Cassette) HTTPClient() *http.Client {
+	return &http.Client{Transport: c}
+}
+
+// Dir returns the cassette directory.
+func (c *Cassette) Dir() string {
+	return c.dir
+}
+
+// Close flushes websocket recordings and stops replay servers.
+func (c *Cassette) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var firstErr error
+	for conn, session := range c.sessions {
+		if err := session.file.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(c.sessions, conn)
+	}
+	for _, srv := range c.servers {
+		srv.close()
+	}
+	c.servers = nil
+	return firstErr
+}
+
+// RoundTrip implements http.RoundTripper.
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	if c.replay {
+		return c.replayHTTP(req)
+	}
+	return c.recordHTTP(req)
+}
+
+func (c *Cassette) recordHTTP(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		data, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		reqBody = data
+		req.Body = io.NopCloser(bytes.NewReader(data))
+	}
+
+	resp, err := c.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	in := Interaction{
+		Request: Request{
+			Method:  req.Method,
+			URL:     req.URL.RequestURI(),
+			Headers: redactHeaders(req.Header),
+			Body:    newBody(redactForm(req.Header.Get("Content-Type"), reqBody)),
+		},
+		Response: Response{
+			Status:  resp.StatusCode,
+			Headers: redactHeaders(resp.Header),
+			Body:    newBody(respBody),
+		},
+	}
+
+	c.mu.Lock()
+	c.next++
+	name := fileName(c.next, req.Method, in.Request.URL, "json")
+	c.mu.Unlock()
+	if err := writeInteraction(filepath.Join(c.dir, name), in); err != nil {
+		return nil, fmt.Errorf("record %s: %w", name, err)
+	}
+	return resp, nil
+}
+
+// DialEvents implements beeperapi.EventsDialer.
+func (c *Cassette) DialEvents(ctx context.Context, dialer *websocket.Dialer, rawURL string, header http.Header) (*websocket.Conn, *http.Response, error) {
+	if c.replay {
+		return c.replayEvents(ctx, dialer, rawURL, header)
+	}
+
+	conn, resp, err := dialer.DialContext(ctx, rawURL, header)
+	if err != nil {
+		return conn, resp, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.next++
+	path := rawURL
+	if u, err := url.Parse(rawURL); err == nil {
+		path = u.RequestURI()
+	}
+	name := fileName(c.next, "WS", path, "jsonl")
+	f, err := os.OpenFile(filepath.Join(c.dir, name), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		_ = conn.Close()
+		return nil, resp, fmt.Errorf("record %s: %w", name, err)
+	}
+	c.sessions[conn] = &wsSession{file: f, writer: eventlog.NewWriter(f)}
+	return conn, resp, nil
+}
+
+// ObserveEventsFrame implements beeperapi.EventsDialer.
+func (c *Cassette) ObserveEventsFrame(conn *websocket.Conn, frame []byte) {
+	if c.replay {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if session, ok := c.sessions[conn]; ok {
+		_ = session.writer.Write(time.Now(), frame)
+	}
+}
+
+func redactHeaders(h http.Header) map[string][]string {
+	out := h.Clone()
+	for key := range out {
+		switch strings.ToLower(key) {
+		case "authorization", "cookie", "proxy-authorization", "set-cookie":
+			out[key] = []string{Redacted}
+		}
+	}
+	return out
+}
+
+// redactForm hides token fields in form bodies (e.g. /oauth/introspect).
+func redactForm(contentType string, body []byte) []byte {
+	if !strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		return body
+	}
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return body
+	}
+	changed := false
+	for key := range values {
+		if strings.Contains(strings.ToLower(key), "token") && key != "token_type_hint" {
+			values[key] = []string{Redacted}
+			changed = true
+		}
+	}
+	if !changed {
+		return body
+	}
+	return []byte(values.Encode())
+}
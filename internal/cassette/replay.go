@@ -0,0 +1,173 @@
+package cassette
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/johntheyoung/roadrunner/internal/eventlog"
+)
+
+type replayInteraction struct {
+	name string
+	Interaction
+	used bool
+}
+
+type replayServer struct {
+	listener net.Listener
+	events   *eventlog.Server
+	server   *http.Server
+}
+
+func (s *replayServer) close() {
+	s.events.Close()
+	_ = s.server.Close()
+}
+
+// Replay returns a cassette that answers requests from the recordings in
+// dir without touching the network. Each request is served by the first
+// unused interaction with the same method, path, and query; websocket
+// dials get recorded sessions in order.
+func Replay(dir string) (*Cassette, error) {
+	entries, err := listEntries(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read cassette dir: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("cassette %s is empty", dir)
+	}
+
+	c := &Cassette{dir: dir, replay: true}
+	for _, e := range entries {
+		path := filepath.Join(dir, e.name)
+		if e.ws {
+			f, err := os.Open(path)
+			if err != nil {
+				return nil, err
+			}
+			frames, err := eventlog.Read(f)
+			_ = f.Close()
+			if err != nil {
+				return nil, fmt.Errorf("read %s: %w", e.name, err)
+			}
+			c.wsFrames = append(c.wsFrames, frames)
+			continue
+		}
+		in, err := readInteraction(path)
+		if err != nil {
+			return nil, err
+		}
+		c.interactions = append(c.interactions, &replayInteraction{name: e.name, Interaction: in})
+	}
+	return c, nil
+}
+
+func (c *Cassette) replayHTTP(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_, _ = io.Copy(io.Discard, req.Body)
+		_ = req.Body.Close()
+	}
+
+	key := requestKey(req.Method, req.URL.RequestURI())
+	c.mu.Lock()
+	var match *replayInteraction
+	for _, in := range c.interactions {
+		if !in.used && requestKey(in.Request.Method, in.Request.URL) == key {
+			in.used = true
+			match = in
+			break
+		}
+	}
+	c.mu.Unlock()
+
+	if match == nil {
+		return missResponse(req, fmt.Sprintf("cassette %s has no recorded response for %s", c.dir, key)), nil
+	}
+
+	body, err := match.Response.Body.Bytes()
+	if err != nil {
+		return nil, fmt.Errorf("cassette %s: %w", match.name, err)
+	}
+	header := http.Header(match.Response.Headers).Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Del("Content-Encoding")
+	header.Del("Content-Length")
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", match.Response.Status, http.StatusText(match.Response.Status)),
+		StatusCode:    match.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// missResponse reports an unmatched request as a non-retryable error
+// response so the SDK surfaces it immediately.
+func missResponse(req *http.Request, message string) *http.Response {
+	body := fmt.Sprintf(`{"code":"CASSETTE_MISS","message":%q}`, message)
+	return &http.Response{
+		Status:     "501 Not Implemented",
+		StatusCode: http.StatusNotImplemented,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header: http.Header{
+			"Content-Type":   []string{"application/json"},
+			"X-Should-Retry": []string{"false"},
+		},
+		Body:          io.NopCloser(bytes.NewBufferString(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// replayEvents serves the next recorded websocket session from a loopback
+// eventlog server and dials it with the caller's dialer.
+func (c *Cassette) replayEvents(ctx context.Context, dialer *websocket.Dialer, rawURL string, header http.Header) (*websocket.Conn, *http.Response, error) {
+	c.mu.Lock()
+	if len(c.wsFrames) == 0 {
+		c.mu.Unlock()
+		return nil, nil, fmt.Errorf("cassette %s has no more recorded websocket sessions", c.dir)
+	}
+	frames := c.wsFrames[0]
+	c.wsFrames = c.wsFrames[1:]
+	c.mu.Unlock()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, nil, fmt.Errorf("start websocket replay: %w", err)
+	}
+	events := eventlog.NewServer(frames, 0)
+	srv := &replayServer{
+		listener: listener,
+		events:   events,
+		server:   &http.Server{Handler: events, ReadHeaderTimeout: 10 * time.Second},
+	}
+	go func() { _ = srv.server.Serve(listener) }()
+
+	c.mu.Lock()
+	c.servers = append(c.servers, srv)
+	c.mu.Unlock()
+
+	target := url.URL{Scheme: "ws", Host: listener.Addr().String(), Path: "/v1/ws"}
+	if u, err := url.Parse(rawURL); err == nil && u.Path != "" {
+		target.Path = u.Path
+	}
+	return dialer.DialContext(ctx, target.String(), header)
+}
@@ -0,0 +1,187 @@
+// Package chatmeta stores local chat metadata, tags and notes keyed by chat
+// ID, that rr attaches to chats without touching the server. Tags act as
+// custom folders on top of Beeper's primary/low-priority/archive inboxes.
+package chatmeta
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// FileName is the metadata file name inside the config directory.
+const FileName = "chat_metadata.json"
+
+// Entry is the metadata of one chat.
+type Entry struct {
+	Tags      []string `json:"tags,omitempty"`
+	Note      string   `json:"note,omitempty"`
+	UpdatedAt string   `json:"updated_at,omitempty"`
+}
+
+// Store holds chat metadata loaded from a file.
+type Store struct {
+	path  string
+	chats map[string]Entry
+}
+
+type storeFile struct {
+	Chats map[string]Entry `json:"chats"`
+}
+
+// Open loads the store at path. A missing file is an empty store.
+func Open(path string) (*Store, error) {
+	s := &Store{path: path, chats: map[string]Entry{}}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("read chat metadata: %w", err)
+	}
+	var file storeFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse chat metadata %s: %w", path, err)
+	}
+	if file.Chats != nil {
+		s.chats = file.Chats
+	}
+	return s, nil
+}
+
+// NormalizeTag lowercases and trims tag. Tags must be non-empty and must not
+// contain whitespace or commas.
+func NormalizeTag(tag string) (string, error) {
+	t := strings.ToLower(strings.TrimSpace(tag))
+	switch {
+	case t == "":
+		return "", fmt.Errorf("tag cannot be empty")
+	case strings.ContainsRune(t, ',') || strings.ContainsFunc(t, func(r rune) bool { return unicode.IsSpace(r) || unicode.IsControl(r) }):
+		return "", fmt.Errorf("invalid tag %q (must not contain whitespace or commas)", tag)
+	}
+	return t, nil
+}
+
+// NormalizeTags normalizes tags, dropping duplicates.
+func NormalizeTags(tags []string) ([]string, error) {
+	out := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		t, err := NormalizeTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(out, t) {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
+// Get returns the metadata of chatID.
+func (s *Store) Get(chatID string) Entry {
+	return s.chats[chatID]
+}
+
+// HasTags reports whether chatID carries every tag in tags.
+func (s *Store) HasTags(chatID string, tags []string) bool {
+	have := s.chats[chatID].Tags
+	for _, tag := range tags {
+		if !slices.Contains(have, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// AddTags adds normalized tags to chatID and returns its updated entry.
+func (s *Store) AddTags(chatID string, tags ...string) Entry {
+	entry := s.chats[chatID]
+	for _, tag := range tags {
+		if !slices.Contains(entry.Tags, tag) {
+			entry.Tags = append(entry.Tags, tag)
+		}
+	}
+	slices.Sort(entry.Tags)
+	return s.put(chatID, entry)
+}
+
+// RemoveTags removes normalized tags from chatID and returns its updated
+// entry.
+func (s *Store) RemoveTags(chatID string, tags ...string) Entry {
+	entry := s.chats[chatID]
+	entry.Tags = slices.DeleteFunc(slices.Clone(entry.Tags), func(t string) bool {
+		return slices.Contains(tags, t)
+	})
+	return s.put(chatID, entry)
+}
+
+// SetNote sets the note of chatID; an empty note removes it.
+func (s *Store) SetNote(chatID, note string) Entry {
+	entry := s.chats[chatID]
+	entry.Note = note
+	return s.put(chatID, entry)
+}
+
+// Tags returns the chat IDs carrying each tag, sorted.
+func (s *Store) Tags() map[string][]string {
+	out := map[string][]string{}
+	for chatID, entry := range s.chats {
+		for _, tag := range entry.Tags {
+			out[tag] = append(out[tag], chatID)
+		}
+	}
+	for _, ids := range out {
+		slices.Sort(ids)
+	}
+	return out
+}
+
+// put stores entry, dropping chats left without tags or a note.
+func (s *Store) put(chatID string, entry Entry) Entry {
+	if len(entry.Tags) == 0 {
+		entry.Tags = nil
+	}
+	if len(entry.Tags) == 0 && entry.Note == "" {
+		delete(s.chats, chatID)
+		return Entry{}
+	}
+	entry.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	s.chats[chatID] = entry
+	return entry
+}
+
+// Save writes the store atomically with owner-only permissions.
+func (s *Store) Save() error {
+	data, err := json.MarshalIndent(storeFile{Chats: s.chats}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode chat metadata: %w", err)
+	}
+
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("create chat metadata dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(s.path)+".*")
+	if err != nil {
+		return fmt.Errorf("write chat metadata: %w", err)
+	}
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("write chat metadata: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("write chat metadata: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("write chat metadata: %w", err)
+	}
+	return nil
+}
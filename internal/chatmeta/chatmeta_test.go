@@ -0,0 +1,78 @@
+package chatmeta
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestStoreTagsAndNotes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	s.AddTags("!a:x", "vendor", "customer")
+	s.AddTags("!a:x", "customer")
+	s.AddTags("!b:x", "customer")
+	s.SetNote("!b:x", "renewal in May")
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if got := reopened.Get("!a:x").Tags; !slices.Equal(got, []string{"customer", "vendor"}) {
+		t.Fatalf("tags = %v", got)
+	}
+	if got := reopened.Get("!b:x").Note; got != "renewal in May" {
+		t.Fatalf("note = %q", got)
+	}
+	if !reopened.HasTags("!a:x", []string{"customer", "vendor"}) || reopened.HasTags("!b:x", []string{"customer", "vendor"}) {
+		t.Fatal("HasTags() requires every tag")
+	}
+	if got := reopened.Tags()["customer"]; !slices.Equal(got, []string{"!a:x", "!b:x"}) {
+		t.Fatalf("Tags()[customer] = %v", got)
+	}
+
+	reopened.RemoveTags("!a:x", "customer", "vendor")
+	reopened.SetNote("!b:x", "")
+	if _, ok := reopened.chats["!a:x"]; ok {
+		t.Fatal("chat without tags or note kept")
+	}
+	if entry := reopened.Get("!b:x"); !slices.Equal(entry.Tags, []string{"customer"}) || entry.Note != "" {
+		t.Fatalf("!b:x = %+v", entry)
+	}
+}
+
+func TestOpenMissingAndCorruptFiles(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Open(filepath.Join(dir, "missing.json"))
+	if err != nil || len(s.Tags()) != 0 {
+		t.Fatalf("Open(missing) = %v, %v", s, err)
+	}
+
+	bad := filepath.Join(dir, FileName)
+	if err := os.WriteFile(bad, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(bad); err == nil {
+		t.Fatal("Open(corrupt) succeeded")
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	got, err := NormalizeTags([]string{" Customer ", "customer", "VIP"})
+	if err != nil || !slices.Equal(got, []string{"customer", "vip"}) {
+		t.Fatalf("NormalizeTags() = %v, %v", got, err)
+	}
+	for _, bad := range []string{"", "two words", "a,b"} {
+		if _, err := NormalizeTag(bad); err == nil {
+			t.Errorf("NormalizeTag(%q) succeeded", bad)
+		}
+	}
+}
@@ -20,7 +20,7 @@ type AccountsCmd struct {
 
 // AccountsListCmd lists all connected accounts.
 type AccountsListCmd struct {
-	Fields      []string `help:"Comma-separated list of fields for --plain output" name:"fields" sep:","`
+	Fields      []string `help:"Comma-separated list of fields for --plain, --csv, --yaml, or --markdown output" name:"fields" sep:","`
 	FailIfEmpty bool     `help:"Exit with code 1 if no results" name:"fail-if-empty"`
 }
 
@@ -41,7 +41,7 @@ func (c *AccountsListCmd) Run(ctx context.Context, flags *RootFlags) error {
 
 	// Create client
 	timeout := time.Duration(flags.Timeout) * time.Second
-	client, err := beeperapi.NewClient(token, flags.BaseURL, timeout)
+	client, err := newAPIClient(ctx, token, flags.BaseURL, timeout)
 	if err != nil {
 		return err
 	}
@@ -64,13 +64,13 @@ func (c *AccountsListCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	// Plain output (TSV)
-	if outfmt.IsPlain(ctx) {
+	if outfmt.IsTabular(ctx) {
 		fields, err := resolveFields(c.Fields, []string{"id", "network", "display_name"})
 		if err != nil {
 			return err
 		}
 		for _, a := range accounts {
-			writePlainFields(u, fields, map[string]string{
+			writePlainFields(ctx, fields, map[string]any{
 				"id":           a.ID,
 				"network":      a.Network,
 				"display_name": a.DisplayName,
@@ -154,9 +154,9 @@ func (c *AccountsAliasListCmd) Run(ctx context.Context) error {
 		}, "accounts alias list")
 	}
 
-	if outfmt.IsPlain(ctx) {
+	if outfmt.IsTabular(ctx) {
 		for alias, accountID := range aliases {
-			u.Out().Printf("%s\t%s", alias, accountID)
+			writePlainRow(ctx, []string{"alias", "account_id"}, alias, accountID)
 		}
 		return nil
 	}
@@ -25,6 +25,7 @@ type AssetsCmd struct {
 	Serve        AssetsServeCmd        `cmd:"" help:"Stream an asset by URL (raw bytes)"`
 	Upload       AssetsUploadCmd       `cmd:"" help:"Upload an asset and return upload ID"`
 	UploadBase64 AssetsUploadBase64Cmd `cmd:"" name:"upload-base64" help:"Upload base64 data and return upload ID"`
+	Sync         AssetsSyncCmd         `cmd:"" help:"Download chat attachments into a deduplicated local tree"`
 }
 
 // AssetsDownloadCmd downloads an asset from a Matrix URL.
@@ -42,9 +43,10 @@ type AssetsServeCmd struct {
 
 // AssetsUploadCmd uploads a local file.
 type AssetsUploadCmd struct {
-	FilePath string `arg:"" name:"file" help:"File path to upload"`
-	FileName string `help:"Filename to send in metadata (optional)" name:"file-name"`
-	MimeType string `help:"MIME type override (optional)" name:"mime-type"`
+	FilePath      string `arg:"" name:"file" help:"File path to upload"`
+	FileName      string `help:"Filename to send in metadata (optional)" name:"file-name"`
+	MimeType      string `help:"MIME type override (optional)" name:"mime-type"`
+	StripMetadata bool   `help:"Remove EXIF (including GPS location), XMP, and comments from JPEGs before upload" name:"strip-metadata"`
 }
 
 // AssetsUploadBase64Cmd uploads base64 content.
@@ -70,7 +72,7 @@ func (c *AssetsDownloadCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	timeout := time.Duration(flags.Timeout) * time.Second
-	client, err := beeperapi.NewClient(token, flags.BaseURL, timeout)
+	client, err := newAPIClient(ctx, token, flags.BaseURL, timeout)
 	if err != nil {
 		return err
 	}
@@ -101,11 +103,11 @@ func (c *AssetsDownloadCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	// Plain output
-	if outfmt.IsPlain(ctx) {
+	if outfmt.IsTabular(ctx) {
 		if destPath != "" {
-			u.Out().Printf("%s", destPath)
+			writePlainRow(ctx, []string{"path"}, destPath)
 		} else {
-			u.Out().Printf("%s", srcURL)
+			writePlainRow(ctx, []string{"src_url"}, srcURL)
 		}
 		return nil
 	}
@@ -130,7 +132,7 @@ func (c *AssetsServeCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if c.Dest != "" && c.Stdout {
 		return errfmt.UsageError("cannot use --dest with --stdout")
 	}
-	if outfmt.IsJSON(ctx) || outfmt.IsPlain(ctx) {
+	if outfmt.IsJSON(ctx) || outfmt.IsTabular(ctx) {
 		if c.Dest == "" {
 			return errfmt.UsageError("--dest is required with --json or --plain for assets serve")
 		}
@@ -150,7 +152,7 @@ func (c *AssetsServeCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	timeout := time.Duration(flags.Timeout) * time.Second
-	client, err := beeperapi.NewClient(token, flags.BaseURL, timeout)
+	client, err := newAPIClient(ctx, token, flags.BaseURL, timeout)
 	if err != nil {
 		return err
 	}
@@ -193,8 +195,8 @@ func (c *AssetsServeCmd) Run(ctx context.Context, flags *RootFlags) error {
 		}, "assets serve")
 	}
 
-	if outfmt.IsPlain(ctx) {
-		u.Out().Printf("%s\t%d\t%s", c.Dest, serve.BytesWritten, serve.ContentType)
+	if outfmt.IsTabular(ctx) {
+		writePlainRow(ctx, []string{"dest", "bytes_written", "content_type"}, c.Dest, serve.BytesWritten, serve.ContentType)
 		return nil
 	}
 
@@ -215,9 +217,10 @@ func (c *AssetsUploadCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return errfmt.UsageError("file path is required")
 	}
 	if handled, err := handleDryRunWrite(ctx, flags, "assets upload", map[string]any{
-		"file_path": c.FilePath,
-		"file_name": c.FileName,
-		"mime_type": c.MimeType,
+		"file_path":      c.FilePath,
+		"file_name":      c.FileName,
+		"mime_type":      c.MimeType,
+		"strip_metadata": c.StripMetadata,
 	}); handled {
 		return err
 	}
@@ -228,26 +231,29 @@ func (c *AssetsUploadCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	timeout := time.Duration(flags.Timeout) * time.Second
-	client, err := beeperapi.NewClient(token, flags.BaseURL, timeout)
+	client, err := newAPIClient(ctx, token, flags.BaseURL, timeout)
 	if err != nil {
 		return err
 	}
 	if err := checkAndRememberNonIdempotentDuplicate(ctx, flags, "assets upload", struct {
-		FilePath string `json:"file_path"`
-		FileName string `json:"file_name"`
-		MimeType string `json:"mime_type"`
+		FilePath      string `json:"file_path"`
+		FileName      string `json:"file_name"`
+		MimeType      string `json:"mime_type"`
+		StripMetadata bool   `json:"strip_metadata,omitempty"`
 	}{
-		FilePath: c.FilePath,
-		FileName: c.FileName,
-		MimeType: c.MimeType,
+		FilePath:      c.FilePath,
+		FileName:      c.FileName,
+		MimeType:      c.MimeType,
+		StripMetadata: c.StripMetadata,
 	}); err != nil {
 		return err
 	}
 
 	resp, err := client.Assets().Upload(ctx, beeperapi.AssetUploadParams{
-		FilePath: c.FilePath,
-		FileName: c.FileName,
-		MimeType: c.MimeType,
+		FilePath:      c.FilePath,
+		FileName:      c.FileName,
+		MimeType:      c.MimeType,
+		StripMetadata: c.StripMetadata,
 	})
 	if err != nil {
 		if beeperapi.IsUnsupportedRoute(err, "POST", "/assets/upload") {
@@ -260,8 +266,8 @@ func (c *AssetsUploadCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return writeJSON(ctx, resp, "assets upload")
 	}
 
-	if outfmt.IsPlain(ctx) {
-		u.Out().Printf("%s\t%s\t%s\t%s\t%d", resp.UploadID, resp.FileName, resp.MimeType, resp.SrcURL, resp.FileSize)
+	if outfmt.IsTabular(ctx) {
+		writePlainRow(ctx, []string{"upload_id", "file_name", "mime_type", "src_url", "file_size"}, resp.UploadID, resp.FileName, resp.MimeType, resp.SrcURL, resp.FileSize)
 		return nil
 	}
 
@@ -304,7 +310,7 @@ func (c *AssetsUploadBase64Cmd) Run(ctx context.Context, flags *RootFlags) error
 	}
 
 	timeout := time.Duration(flags.Timeout) * time.Second
-	client, err := beeperapi.NewClient(token, flags.BaseURL, timeout)
+	client, err := newAPIClient(ctx, token, flags.BaseURL, timeout)
 	if err != nil {
 		return err
 	}
@@ -336,8 +342,8 @@ func (c *AssetsUploadBase64Cmd) Run(ctx context.Context, flags *RootFlags) error
 		return writeJSON(ctx, resp, "assets upload-base64")
 	}
 
-	if outfmt.IsPlain(ctx) {
-		u.Out().Printf("%s\t%s\t%s\t%s\t%d", resp.UploadID, resp.FileName, resp.MimeType, resp.SrcURL, resp.FileSize)
+	if outfmt.IsTabular(ctx) {
+		writePlainRow(ctx, []string{"upload_id", "file_name", "mime_type", "src_url", "file_size"}, resp.UploadID, resp.FileName, resp.MimeType, resp.SrcURL, resp.FileSize)
 		return nil
 	}
 
@@ -0,0 +1,722 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/johntheyoung/roadrunner/internal/beeperapi"
+	"github.com/johntheyoung/roadrunner/internal/cassette"
+	"github.com/johntheyoung/roadrunner/internal/errfmt"
+	"github.com/johntheyoung/roadrunner/internal/outfmt"
+	"github.com/johntheyoung/roadrunner/internal/ui"
+)
+
+// assetManifestName is the manifest file kept at the root of the sync tree.
+const assetManifestName = ".rr-manifest.json"
+
+const assetManifestVersion = 1
+
+// assetManifestSaveEvery is how many stored files trigger an intermediate
+// manifest save, so an interrupted sync resumes close to where it stopped.
+const assetManifestSaveEvery = 25
+
+// AssetsSyncCmd downloads chat attachments into a deduplicated local tree.
+type AssetsSyncCmd struct {
+	ChatID      string   `arg:"" optional:"" name:"chatID" help:"Chat ID to sync (or use --all-chats)"`
+	AllChats    bool     `help:"Sync every chat" name:"all-chats"`
+	AccountIDs  []string `help:"With --all-chats, only chats on these accounts" name:"account-ids" sep:","`
+	Dest        string   `help:"Root directory of the media tree" name:"dest" default:"."`
+	Workers     int      `help:"Parallel downloads (1-16)" name:"workers" default:"4"`
+	Mime        []string `help:"Only MIME types matching these patterns, e.g. image/* (repeatable)" name:"mime" sep:","`
+	MinSize     string   `help:"Only files at least this large (e.g. 10K, 1.5M)" name:"min-size"`
+	MaxSize     string   `help:"Only files at most this large (e.g. 50M, 2G)" name:"max-size"`
+	Since       string   `help:"Only attachments sent at or after time (RFC3339 or duration)" name:"since"`
+	Until       string   `help:"Only attachments sent at or before time (RFC3339 or duration)" name:"until"`
+	Fields      []string `help:"Comma-separated list of fields for --plain, --csv, --yaml, or --markdown output" name:"fields" sep:","`
+	FailIfEmpty bool     `help:"Exit with code 1 if no attachments were found" name:"fail-if-empty"`
+}
+
+// assetSyncFilter holds the validated attachment filters of assets sync.
+type assetSyncFilter struct {
+	mime         []string
+	minSize      int64
+	maxSize      int64
+	since, until *time.Time
+}
+
+// assetSyncJob is one attachment to store.
+type assetSyncJob struct {
+	key       string // manifest attachment key
+	chatID    string
+	messageID string
+	srcURL    string
+	fileName  string
+	mimeType  string
+	fileSize  int64
+	dir       string // directory relative to --dest
+}
+
+// assetSyncFile is the outcome of one attachment.
+type assetSyncFile struct {
+	Status    string `json:"status"` // downloaded|duplicate|skipped|filtered|planned|failed
+	Path      string `json:"path,omitempty"`
+	SHA256    string `json:"sha256,omitempty"`
+	Size      int64  `json:"size,omitempty"`
+	MimeType  string `json:"mime_type,omitempty"`
+	ChatID    string `json:"chat_id"`
+	MessageID string `json:"message_id"`
+	SrcURL    string `json:"src_url"`
+	Error     string `json:"error,omitempty"`
+}
+
+// assetSyncResult is the JSON output of assets sync.
+type assetSyncResult struct {
+	Dest       string          `json:"dest"`
+	Manifest   string          `json:"manifest"`
+	Chats      int             `json:"chats"`
+	Scanned    int             `json:"scanned"`
+	Found      int             `json:"found"`
+	Downloaded int             `json:"downloaded"`
+	Duplicates int             `json:"duplicates"`
+	Skipped    int             `json:"skipped"`
+	Filtered   int             `json:"filtered"`
+	Failed     int             `json:"failed"`
+	Bytes      int64           `json:"bytes"`
+	Files      []assetSyncFile `json:"files"`
+}
+
+// assetManifest records what a sync tree holds: stored files by SHA-256,
+// and which attachment resolved to which file.
+type assetManifest struct {
+	Version     int                          `json:"version"`
+	Files       map[string]assetManifestFile `json:"files"`
+	Attachments map[string]string            `json:"attachments"`
+	UpdatedAt   string                       `json:"updated_at,omitempty"`
+
+	path    string
+	root    string
+	mu      sync.Mutex
+	pending int
+}
+
+// assetManifestFile is a stored file; Path is relative to the tree root.
+type assetManifestFile struct {
+	Path     string `json:"path"`
+	Size     int64  `json:"size"`
+	MimeType string `json:"mime_type,omitempty"`
+}
+
+// Run executes the assets sync command.
+func (c *AssetsSyncCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+
+	if (c.ChatID == "") == !c.AllChats {
+		return errfmt.UsageError("specify a chat ID or --all-chats")
+	}
+	if c.Workers < 1 || c.Workers > beeperapi.MaxConcurrency {
+		return errfmt.UsageError("invalid --workers %d (expected 1-%d)", c.Workers, beeperapi.MaxConcurrency)
+	}
+	if strings.TrimSpace(c.Dest) == "" {
+		return errfmt.UsageError("--dest must not be empty")
+	}
+	filter, err := c.filter()
+	if err != nil {
+		return err
+	}
+
+	manifest, err := openAssetManifest(c.Dest)
+	if err != nil {
+		return err
+	}
+
+	client, err := newClientFromFlags(ctx, flags)
+	if err != nil {
+		return err
+	}
+
+	chats, err := c.chats(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	result := assetSyncResult{Dest: c.Dest, Manifest: manifest.path, Chats: len(chats), Files: []assetSyncFile{}}
+	var jobs []assetSyncJob
+	for _, chat := range chats {
+		scanned, chatJobs, filtered, err := scanAssetSyncChat(ctx, client, chat, filter)
+		if err != nil {
+			return err
+		}
+		result.Scanned += scanned
+		result.Filtered += filtered
+		jobs = append(jobs, chatJobs...)
+	}
+	result.Found = len(jobs)
+	if err := failIfEmpty(c.FailIfEmpty, len(jobs), "attachments"); err != nil {
+		return err
+	}
+
+	// Attachments already in the manifest whose file is still on disk are
+	// not downloaded again.
+	var todo []assetSyncJob
+	for _, job := range jobs {
+		if file, ok := manifest.lookup(job.key); ok {
+			result.Files = append(result.Files, assetSyncFile{
+				Status: "skipped", Path: file.Path, SHA256: manifest.Attachments[job.key], Size: file.Size, MimeType: file.MimeType,
+				ChatID: job.chatID, MessageID: job.messageID, SrcURL: job.srcURL,
+			})
+			continue
+		}
+		todo = append(todo, job)
+	}
+
+	if flags.DryRun {
+		for _, job := range todo {
+			result.Files = append(result.Files, assetSyncFile{
+				Status: "planned", Path: filepath.ToSlash(filepath.Join(job.dir, assetFileName(job))), Size: job.fileSize, MimeType: job.mimeType,
+				ChatID: job.chatID, MessageID: job.messageID, SrcURL: job.srcURL,
+			})
+		}
+	} else {
+		workers := c.Workers
+		if cassette.FromContext(ctx) != nil {
+			workers = 1
+		}
+		// Each job reports its own error so one failed download does not
+		// cancel the others.
+		files, err := beeperapi.FanOut(ctx, workers, todo, func(ctx context.Context, job assetSyncJob) (assetSyncFile, error) {
+			return syncAsset(ctx, client, manifest, filter, job), nil
+		})
+		if saveErr := manifest.save(); saveErr != nil && err == nil {
+			err = saveErr
+		}
+		if err != nil {
+			return err
+		}
+		result.Files = append(result.Files, files...)
+	}
+
+	for _, file := range result.Files {
+		switch file.Status {
+		case "downloaded":
+			result.Downloaded++
+			result.Bytes += file.Size
+		case "duplicate":
+			result.Duplicates++
+		case "skipped":
+			result.Skipped++
+		case "filtered":
+			result.Filtered++
+		case "failed":
+			result.Failed++
+		}
+	}
+
+	var failErr error
+	if result.Failed > 0 {
+		failErr = errfmt.WithCode(fmt.Errorf("%d of %d attachments failed", result.Failed, len(todo)), errfmt.ExitFailure)
+	}
+
+	if outfmt.IsJSON(ctx) {
+		if err := writeJSON(ctx, result, "assets sync"); err != nil {
+			return err
+		}
+		return failErr
+	}
+
+	if outfmt.IsTabular(ctx) {
+		fields, err := resolveFields(c.Fields, []string{"status", "path", "sha256", "chat_id", "message_id"})
+		if err != nil {
+			return err
+		}
+		for _, file := range result.Files {
+			writePlainFields(ctx, fields, map[string]any{
+				"status":     file.Status,
+				"path":       file.Path,
+				"sha256":     file.SHA256,
+				"size":       file.Size,
+				"mime_type":  file.MimeType,
+				"chat_id":    file.ChatID,
+				"message_id": file.MessageID,
+				"src_url":    file.SrcURL,
+				"error":      file.Error,
+			})
+		}
+		return failErr
+	}
+
+	for _, file := range result.Files {
+		switch file.Status {
+		case "planned":
+			u.Out().Printf("  would download %s", file.Path)
+		case "failed":
+			u.Out().Warnf("  %s in %s: %s", file.MessageID, file.ChatID, file.Error)
+		}
+	}
+	if flags.DryRun {
+		u.Out().Printf("Would download %d attachments into %s (%d already synced)", len(todo), c.Dest, result.Skipped)
+		return nil
+	}
+	u.Out().Successf("Synced %d chats into %s", result.Chats, c.Dest)
+	u.Out().Printf("  downloaded: %d (%s)", result.Downloaded, formatFileSize(result.Bytes))
+	u.Out().Printf("  duplicates: %d", result.Duplicates)
+	u.Out().Printf("  skipped:    %d", result.Skipped)
+	u.Out().Printf("  filtered:   %d", result.Filtered)
+	if result.Failed > 0 {
+		u.Out().Printf("  failed:     %d", result.Failed)
+	}
+	return failErr
+}
+
+// filter validates the attachment filter flags.
+func (c *AssetsSyncCmd) filter() (assetSyncFilter, error) {
+	var filter assetSyncFilter
+	for _, pattern := range c.Mime {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if pattern == "" {
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return filter, errfmt.UsageError("invalid --mime %q: %v", pattern, err)
+		}
+		filter.mime = append(filter.mime, pattern)
+	}
+	var err error
+	if filter.minSize, err = parseByteSize(c.MinSize); err != nil {
+		return filter, errfmt.UsageError("invalid --min-size %q: %v", c.MinSize, err)
+	}
+	if filter.maxSize, err = parseByteSize(c.MaxSize); err != nil {
+		return filter, errfmt.UsageError("invalid --max-size %q: %v", c.MaxSize, err)
+	}
+	if filter.maxSize > 0 && filter.maxSize < filter.minSize {
+		return filter, errfmt.UsageError("--max-size must not be below --min-size")
+	}
+	if c.Since != "" {
+		t, err := parseTime(c.Since)
+		if err != nil {
+			return filter, errfmt.UsageError("invalid --since %q (expected RFC3339 or duration)", c.Since)
+		}
+		filter.since = &t
+	}
+	if c.Until != "" {
+		t, err := parseTime(c.Until)
+		if err != nil {
+			return filter, errfmt.UsageError("invalid --until %q (expected RFC3339 or duration)", c.Until)
+		}
+		filter.until = &t
+	}
+	if filter.since != nil && filter.until != nil && filter.until.Before(*filter.since) {
+		return filter, errfmt.UsageError("--until must not be before --since")
+	}
+	return filter, nil
+}
+
+// chats returns the chats to sync.
+func (c *AssetsSyncCmd) chats(ctx context.Context, client *beeperapi.Client) ([]beeperapi.ChatListItem, error) {
+	if !c.AllChats {
+		chatID := normalizeChatID(c.ChatID)
+		chat, err := client.Chats().Get(ctx, chatID, beeperapi.ChatGetParams{})
+		if err != nil {
+			return nil, err
+		}
+		return []beeperapi.ChatListItem{{ID: chat.ID, Title: chat.Title, AccountID: chat.AccountID}}, nil
+	}
+	var chats []beeperapi.ChatListItem
+	for chat, err := range client.Chats().All(ctx, beeperapi.ChatListParams{AccountIDs: c.AccountIDs}) {
+		if err != nil {
+			return nil, err
+		}
+		chats = append(chats, chat)
+	}
+	return chats, nil
+}
+
+// matchesMeta reports whether an attachment passes the MIME and size
+// filters. A size of 0 means unknown and is checked after download.
+func (f assetSyncFilter) matchesMeta(mimeType string, size int64) bool {
+	if len(f.mime) > 0 {
+		mimeType = strings.ToLower(mimeType)
+		if i := strings.IndexByte(mimeType, ';'); i >= 0 {
+			mimeType = strings.TrimSpace(mimeType[:i])
+		}
+		found := false
+		for _, pattern := range f.mime {
+			if ok, _ := path.Match(pattern, mimeType); ok {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return f.matchesSize(size)
+}
+
+func (f assetSyncFilter) matchesSize(size int64) bool {
+	if size <= 0 {
+		return true
+	}
+	return size >= f.minSize && (f.maxSize == 0 || size <= f.maxSize)
+}
+
+// scanAssetSyncChat pages a chat's history newest first and returns the
+// attachments that pass the filters, with the number of messages read and
+// attachments filtered out.
+func scanAssetSyncChat(ctx context.Context, client *beeperapi.Client, chat beeperapi.ChatListItem, filter assetSyncFilter) (scanned int, jobs []assetSyncJob, filtered int, err error) {
+	chatDir := filepath.Join(sanitizePathSegment(chat.AccountID, "account"), sanitizePathSegment(chat.Title, "chat")+"_"+shortHash(chat.ID))
+	for item, err := range client.Messages().All(ctx, chat.ID, beeperapi.MessageListParams{Direction: "before"}) {
+		if err != nil {
+			return scanned, nil, filtered, err
+		}
+		scanned++
+		month := "undated"
+		if ts, tsErr := time.Parse(time.RFC3339, item.Timestamp); tsErr == nil {
+			if filter.until != nil && ts.After(*filter.until) {
+				continue
+			}
+			if filter.since != nil && ts.Before(*filter.since) {
+				break
+			}
+			month = ts.UTC().Format("2006-01")
+		}
+		for _, att := range item.Attachments {
+			if att.SrcURL == "" {
+				continue
+			}
+			if !filter.matchesMeta(att.MimeType, att.FileSize) {
+				filtered++
+				continue
+			}
+			jobs = append(jobs, assetSyncJob{
+				key:       chat.ID + "/" + item.ID + "/" + att.SrcURL,
+				chatID:    chat.ID,
+				messageID: item.ID,
+				srcURL:    att.SrcURL,
+				fileName:  att.FileName,
+				mimeType:  att.MimeType,
+				fileSize:  att.FileSize,
+				dir:       filepath.Join(chatDir, month),
+			})
+		}
+	}
+	return scanned, jobs, filtered, nil
+}
+
+// syncAsset downloads one attachment, hashing it on the way into a temp
+// file under the tree root, and then stores it unless a file with the same
+// content is already stored.
+func syncAsset(ctx context.Context, client *beeperapi.Client, manifest *assetManifest, filter assetSyncFilter, job assetSyncJob) assetSyncFile {
+	file := assetSyncFile{ChatID: job.chatID, MessageID: job.messageID, SrcURL: job.srcURL, MimeType: job.mimeType}
+	fail := func(err error) assetSyncFile {
+		file.Status = "failed"
+		file.Error = err.Error()
+		return file
+	}
+
+	src := job.srcURL
+	if strings.HasPrefix(src, "mxc://") || strings.HasPrefix(src, "localmxc://") {
+		local, err := client.Assets().Download(ctx, src)
+		if err != nil {
+			return fail(err)
+		}
+		src = local
+	}
+	if strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://") {
+		return fail(fmt.Errorf("unsupported attachment URL: %s", src))
+	}
+	localPath, err := assetLocalPath(src)
+	if err != nil {
+		return fail(err)
+	}
+
+	tmpPath, sum, size, err := hashCopyToTemp(localPath, manifest.root)
+	if err != nil {
+		return fail(err)
+	}
+	defer func() {
+		_ = os.Remove(tmpPath)
+	}()
+	file.SHA256 = sum
+	file.Size = size
+	if !filter.matchesSize(size) {
+		file.Status = "filtered"
+		return file
+	}
+
+	name := assetFileName(job)
+	if name == "" {
+		name = filepath.Base(localPath)
+	}
+	rel, status, err := manifest.store(tmpPath, job, name, sum, size)
+	if err != nil {
+		return fail(err)
+	}
+	file.Path = rel
+	file.Status = status
+	return file
+}
+
+// hashCopyToTemp copies src into a temp file in dir and returns the temp
+// path with the content's SHA-256 and size.
+func hashCopyToTemp(src, dir string) (tmpPath, sum string, size int64, err error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return "", "", 0, fmt.Errorf("open %s: %w", src, err)
+	}
+	defer func() {
+		_ = in.Close()
+	}()
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", "", 0, fmt.Errorf("create dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, ".rr-sync-*")
+	if err != nil {
+		return "", "", 0, fmt.Errorf("create temp file: %w", err)
+	}
+	hash := sha256.New()
+	size, err = io.Copy(io.MultiWriter(tmp, hash), in)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return "", "", 0, fmt.Errorf("copy asset: %w", err)
+	}
+	return tmp.Name(), hex.EncodeToString(hash.Sum(nil)), size, nil
+}
+
+// openAssetManifest loads the manifest of the tree at root, or starts an
+// empty one.
+func openAssetManifest(root string) (*assetManifest, error) {
+	m := &assetManifest{
+		Version:     assetManifestVersion,
+		Files:       map[string]assetManifestFile{},
+		Attachments: map[string]string{},
+		path:        filepath.Join(root, assetManifestName),
+		root:        root,
+	}
+	data, err := os.ReadFile(m.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read manifest: %w", err)
+	}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, errfmt.UsageError("invalid manifest %s: %v", m.path, err)
+	}
+	if m.Version != assetManifestVersion {
+		return nil, errfmt.UsageError("invalid manifest %s: unsupported version %d", m.path, m.Version)
+	}
+	if m.Files == nil {
+		m.Files = map[string]assetManifestFile{}
+	}
+	if m.Attachments == nil {
+		m.Attachments = map[string]string{}
+	}
+	return m, nil
+}
+
+// lookup returns the stored file of an attachment when it is still on disk.
+func (m *assetManifest) lookup(key string) (assetManifestFile, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sum, ok := m.Attachments[key]
+	if !ok {
+		return assetManifestFile{}, false
+	}
+	return m.existing(sum)
+}
+
+// existing returns the stored file with content sum when it is still on
+// disk. The caller holds m.mu.
+func (m *assetManifest) existing(sum string) (assetManifestFile, bool) {
+	file, ok := m.Files[sum]
+	if !ok {
+		return assetManifestFile{}, false
+	}
+	if _, err := os.Stat(filepath.Join(m.root, filepath.FromSlash(file.Path))); err != nil {
+		return assetManifestFile{}, false
+	}
+	return file, true
+}
+
+// store records the attachment and moves tmpPath into dir/name unless
+// content sum is already stored. A different file already at dir/name
+// keeps its name; the new one gets a hash suffix. An identical file there
+// (left by a sync whose manifest was lost) is adopted. It returns the path
+// relative to the root and "downloaded", "duplicate", or "skipped".
+func (m *assetManifest) store(tmpPath string, job assetSyncJob, name, sum string, size int64) (string, string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	status := "duplicate"
+	file, ok := m.existing(sum)
+	if !ok {
+		ext := filepath.Ext(name)
+		candidates := []string{
+			filepath.Join(job.dir, name),
+			filepath.Join(job.dir, strings.TrimSuffix(name, ext)+"-"+sum[:8]+ext),
+		}
+		rel := ""
+		for _, candidate := range candidates {
+			target := filepath.Join(m.root, candidate)
+			if _, err := os.Stat(target); err != nil {
+				if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+					return "", "", fmt.Errorf("create dir: %w", err)
+				}
+				if err := os.Rename(tmpPath, target); err != nil {
+					return "", "", fmt.Errorf("store %s: %w", target, err)
+				}
+				rel, status = candidate, "downloaded"
+				break
+			}
+			existing, err := fileSHA256(target)
+			if err != nil {
+				return "", "", err
+			}
+			if existing == sum {
+				rel, status = candidate, "skipped"
+				break
+			}
+		}
+		if rel == "" {
+			return "", "", fmt.Errorf("%s already exists with different content", filepath.Join(m.root, candidates[1]))
+		}
+		file = assetManifestFile{Path: filepath.ToSlash(rel), Size: size, MimeType: job.mimeType}
+		m.Files[sum] = file
+	}
+	m.Attachments[job.key] = sum
+
+	m.pending++
+	if m.pending >= assetManifestSaveEvery {
+		if err := m.saveLocked(); err != nil {
+			return "", "", err
+		}
+	}
+	return file.Path, status, nil
+}
+
+// save writes the manifest atomically.
+func (m *assetManifest) save() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.saveLocked()
+}
+
+func (m *assetManifest) saveLocked() error {
+	m.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode manifest: %w", err)
+	}
+	if err := os.MkdirAll(m.root, 0o755); err != nil {
+		return fmt.Errorf("create dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(m.root, "."+assetManifestName+".*")
+	if err != nil {
+		return fmt.Errorf("write manifest: %w", err)
+	}
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("write manifest: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("write manifest: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), m.path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("write manifest: %w", err)
+	}
+	m.pending = 0
+	return nil
+}
+
+// assetFileName is the sanitized file name of an attachment, or "" when
+// the attachment has none.
+func assetFileName(job assetSyncJob) string {
+	if job.fileName == "" {
+		return ""
+	}
+	return sanitizePathSegment(filepath.Base(filepath.FromSlash(job.fileName)), "")
+}
+
+// sanitizePathSegment makes s safe as a single path segment, falling back
+// to fallback when nothing printable is left.
+func sanitizePathSegment(s, fallback string) string {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r < 0x20, r == 0x7f:
+			return -1
+		case strings.ContainsRune(`/\:*?"<>|`, r):
+			return '_'
+		}
+		return r
+	}, s)
+	s = strings.Trim(strings.TrimSpace(s), ".")
+	if len(s) > 100 {
+		s = strings.ToValidUTF8(s[:100], "")
+	}
+	if s == "" {
+		return fallback
+	}
+	return s
+}
+
+// shortHash keeps directory names unique when chat titles collide.
+func shortHash(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:4])
+}
+
+func fileSHA256(name string) (string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", fmt.Errorf("open %s: %w", name, err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", fmt.Errorf("read %s: %w", name, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// parseByteSize parses a size such as 512, 10K, 1.5MB, or 2GiB. Units are
+// binary (1K = 1024 bytes). An empty string is 0.
+func parseByteSize(s string) (int64, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if s == "" {
+		return 0, nil
+	}
+	s = strings.TrimSuffix(strings.TrimSuffix(s, "B"), "I")
+	mult := int64(1)
+	if n := len(s); n > 0 {
+		if i := strings.IndexByte("KMGT", s[n-1]); i >= 0 {
+			mult = int64(1) << (10 * (i + 1))
+			s = s[:n-1]
+		}
+	}
+	v, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("expected a size like 512, 10K, 1.5M, or 2G")
+	}
+	return int64(v * float64(mult)), nil
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/johntheyoung/roadrunner/internal/fakeapi"
+)
+
+func TestAssetsSync(t *testing.T) {
+	t.Setenv("BEEPER_TOKEN", "test-token")
+	t.Setenv("BEEPER_ACCESS_TOKEN", "")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	// The spec is shared in Team and forwarded (new URL, same bytes) to Carol.
+	ds := fakeapi.DefaultDataset()
+	jan := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	ds.Messages = append(ds.Messages,
+		fakeapi.Message{
+			ID: "$spec", ChatID: "!team:beeper.local", SenderID: "@bob:beeper.local", Timestamp: jan,
+			Attachments: []fakeapi.Attachment{{Type: "unknown", SrcURL: "mxc://beeper.local/spec", FileName: "spec.pdf", MimeType: "application/pdf", FileSize: 9}},
+		},
+		fakeapi.Message{
+			ID: "$photo", ChatID: "!team:beeper.local", SenderID: "@bob:beeper.local", Timestamp: jan.AddDate(0, 0, 15),
+			Attachments: []fakeapi.Attachment{{Type: "img", SrcURL: "mxc://beeper.local/photo", FileName: "photo.jpg", MimeType: "image/jpeg", FileSize: 4}},
+		},
+		fakeapi.Message{
+			ID: "$fwd", ChatID: "!carol:whatsapp.local", SenderID: "+14155550100", IsSender: true, Timestamp: jan.AddDate(0, 1, 0),
+			Attachments: []fakeapi.Attachment{{Type: "unknown", SrcURL: "mxc://beeper.local/spec-fwd", FileName: "spec.pdf", MimeType: "application/pdf"}},
+		},
+	)
+	ds.Assets = append(ds.Assets,
+		fakeapi.Asset{URL: "mxc://beeper.local/spec", FileName: "spec.pdf", MimeType: "application/pdf", Content: []byte("%PDF-spec")},
+		fakeapi.Asset{URL: "mxc://beeper.local/spec-fwd", FileName: "spec.pdf", MimeType: "application/pdf", Content: []byte("%PDF-spec")},
+		fakeapi.Asset{URL: "mxc://beeper.local/photo", FileName: "photo.jpg", MimeType: "image/jpeg", Content: []byte("JPEG")},
+	)
+
+	fake := fakeapi.New(ds)
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	defer fake.Close()
+
+	run := func(args ...string) (string, string, int) {
+		t.Helper()
+		var out, errText string
+		var code int
+		withArgs(t, append([]string{"rr", "--base-url", server.URL}, args...), func() {
+			out, errText = captureOutput(t, func() {
+				code = Execute()
+			})
+		})
+		return out, errText, code
+	}
+	type syncResult struct {
+		Downloaded int `json:"downloaded"`
+		Duplicates int `json:"duplicates"`
+		Skipped    int `json:"skipped"`
+		Filtered   int `json:"filtered"`
+		Failed     int `json:"failed"`
+		Files      []struct {
+			Status    string `json:"status"`
+			Path      string `json:"path"`
+			MessageID string `json:"message_id"`
+		} `json:"files"`
+	}
+	sync := func(args ...string) syncResult {
+		t.Helper()
+		out, errText, code := run(append([]string{"--json", "assets", "sync"}, args...)...)
+		if code != 0 {
+			t.Fatalf("%v: exit code = %d, stderr = %q", args, code, errText)
+		}
+		var result syncResult
+		if err := json.Unmarshal([]byte(out), &result); err != nil {
+			t.Fatalf("decode %v: %v\n%s", args, err, out)
+		}
+		return result
+	}
+
+	dest := t.TempDir()
+	first := sync("--all-chats", "--dest", dest, "--workers", "2")
+	if first.Downloaded != 2 || first.Duplicates != 1 || first.Failed != 0 {
+		t.Fatalf("first sync = %+v", first)
+	}
+	paths := map[string]string{}
+	for _, f := range first.Files {
+		paths[f.MessageID] = f.Path
+	}
+	teamDir := "matrix/Team_" + shortHash("!team:beeper.local")
+	if paths["$spec"] != teamDir+"/2026-01/spec.pdf" || paths["$photo"] != teamDir+"/2026-01/photo.jpg" {
+		t.Fatalf("paths = %v", paths)
+	}
+	if paths["$fwd"] != paths["$spec"] {
+		t.Fatalf("forwarded spec stored at %q, want %q", paths["$fwd"], paths["$spec"])
+	}
+	if _, err := os.Stat(filepath.Join(dest, "whatsapp")); !os.IsNotExist(err) {
+		t.Fatalf("duplicate created a whatsapp dir: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dest, filepath.FromSlash(paths["$spec"])))
+	if err != nil || string(data) != "%PDF-spec" {
+		t.Fatalf("spec = %q, %v", data, err)
+	}
+
+	// A second run resumes from the manifest and downloads nothing.
+	second := sync("--all-chats", "--dest", dest)
+	if second.Downloaded != 0 || second.Duplicates != 0 || second.Skipped != 3 {
+		t.Fatalf("second sync = %+v", second)
+	}
+
+	// A deleted file is fetched again.
+	if err := os.Remove(filepath.Join(dest, filepath.FromSlash(paths["$photo"]))); err != nil {
+		t.Fatal(err)
+	}
+	third := sync("!team:beeper.local", "--dest", dest)
+	if third.Downloaded != 1 || third.Skipped != 1 {
+		t.Fatalf("third sync = %+v", third)
+	}
+
+	mime := sync("!team:beeper.local", "--dest", t.TempDir(), "--mime", "image/*")
+	if mime.Downloaded != 1 || mime.Filtered != 1 || mime.Files[0].MessageID != "$photo" {
+		t.Fatalf("--mime sync = %+v", mime)
+	}
+	dated := sync("!team:beeper.local", "--dest", t.TempDir(), "--since", "2026-01-01T00:00:00Z", "--until", "2026-01-10T00:00:00Z")
+	if dated.Downloaded != 1 || dated.Files[0].MessageID != "$spec" {
+		t.Fatalf("--since/--until sync = %+v", dated)
+	}
+	sized := sync("--all-chats", "--dest", t.TempDir(), "--min-size", "5", "--max-size", "1K")
+	if sized.Downloaded != 1 || sized.Duplicates != 1 || sized.Filtered != 1 {
+		t.Fatalf("--min-size sync = %+v", sized)
+	}
+
+	if _, _, code := run("assets", "sync", "--dest", dest); code != 2 {
+		t.Fatalf("missing chat exit code = %d, want 2", code)
+	}
+	if _, _, code := run("assets", "sync", "--all-chats", "--max-size", "lots"); code != 2 {
+		t.Fatalf("bad --max-size exit code = %d, want 2", code)
+	}
+}
+
+func TestParseByteSize(t *testing.T) {
+	for in, want := range map[string]int64{"": 0, "512": 512, "10K": 10 << 10, "1.5M": 3 << 19, "2GiB": 2 << 30, "3mb": 3 << 20} {
+		if got, err := parseByteSize(in); err != nil || got != want {
+			t.Errorf("parseByteSize(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+	if _, err := parseByteSize("-1K"); err == nil {
+		t.Error("parseByteSize(-1K) succeeded")
+	}
+}
@@ -84,7 +84,7 @@ func (c *AuthSetCmd) Run(ctx context.Context) error {
 // AuthStatusCmd shows authentication status.
 type AuthStatusCmd struct {
 	Check  bool     `help:"Validate token by making API call" short:"c"`
-	Fields []string `help:"Comma-separated list of fields for --plain output" name:"fields" sep:","`
+	Fields []string `help:"Comma-separated list of fields for --plain, --csv, --yaml, or --markdown output" name:"fields" sep:","`
 }
 
 // Run executes the auth status command.
@@ -100,16 +100,16 @@ func (c *AuthStatusCmd) Run(ctx context.Context, flags *RootFlags) error {
 				"error":         err.Error(),
 			}, "auth status")
 		}
-		if outfmt.IsPlain(ctx) {
+		if outfmt.IsTabular(ctx) {
 			fields, err := resolveFields(c.Fields, []string{"authenticated", "source", "config_path", "valid", "validation_method", "connect_name", "connect_version", "connect_runtime"})
 			if err != nil {
 				return err
 			}
-			writePlainFields(u, fields, map[string]string{
-				"authenticated":     "false",
+			writePlainFields(ctx, fields, map[string]any{
+				"authenticated":     false,
 				"source":            "none",
 				"config_path":       "",
-				"valid":             "",
+				"valid":             nil,
 				"validation_method": "",
 				"connect_name":      "",
 				"connect_version":   "",
@@ -183,25 +183,25 @@ func (c *AuthStatusCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	// Plain output (TSV)
-	if outfmt.IsPlain(ctx) {
+	if outfmt.IsTabular(ctx) {
 		fields, err := resolveFields(c.Fields, []string{"authenticated", "source", "config_path", "valid", "validation_method", "connect_name", "connect_version", "connect_runtime"})
 		if err != nil {
 			return err
 		}
-		valid := ""
+		var valid any
 		validationMethod := ""
 		connectName := ""
 		connectVersion := ""
 		connectRuntime := ""
 		if validation != nil {
-			valid = formatBool(validation.Valid)
+			valid = validation.Valid
 			validationMethod = validation.ValidationMethod
 			connectName = validation.ConnectName
 			connectVersion = validation.ConnectVersion
 			connectRuntime = validation.ConnectRuntime
 		}
-		writePlainFields(u, fields, map[string]string{
-			"authenticated":     "true",
+		writePlainFields(ctx, fields, map[string]any{
+			"authenticated":     true,
 			"source":            source.String(),
 			"config_path":       configPath,
 			"valid":             valid,
@@ -1,8 +1,11 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 
+	"github.com/johntheyoung/roadrunner/internal/beeperapi"
 	"github.com/johntheyoung/roadrunner/internal/errfmt"
 )
 
@@ -33,16 +36,7 @@ func resolveAutoPageLimitNamed(all bool, maxItems int, allFlagName, maxItemsFlag
 }
 
 func nextSearchCursor(direction, oldestCursor, newestCursor string) string {
-	if direction == "after" {
-		if newestCursor != "" {
-			return newestCursor
-		}
-		return oldestCursor
-	}
-	if oldestCursor != "" {
-		return oldestCursor
-	}
-	return newestCursor
+	return beeperapi.NextCursor(direction, oldestCursor, newestCursor)
 }
 
 func limitReached(count, limit int) bool {
@@ -92,3 +86,205 @@ func autoPageStoppedMessage(limit int) string {
 func autoPageStoppedMessageNamed(limit int, maxItemsFlagName string) string {
 	return fmt.Sprintf("Stopped after %s=%d. Narrow filters or increase %s to fetch more.", maxItemsFlagName, limit, maxItemsFlagName)
 }
+
+// autoPage is one page of an auto-paged listing.
+type autoPage[T any] struct {
+	Items   []T
+	HasMore bool
+	// Next is the cursor for the following page (from nextSearchCursor or
+	// the API's nextCursor).
+	Next string
+}
+
+// autoPager drives --all pagination the same way for every paged command:
+// it follows cursors until the last page, applies --max-items/--max-pages,
+// writes pages as JSON lines as they arrive when streaming, and keeps the
+// --checkpoint file current after each page.
+type autoPager[T any] struct {
+	Limits autoPageLimits
+	// Cursor is the cursor the first page was fetched with.
+	Cursor string
+	// Fetch loads the page at cursor.
+	Fetch func(cursor string) (autoPage[T], error)
+	// Key identifies an item for --checkpoint duplicate skipping.
+	Key func(item T) string
+	// Checkpoint is nil unless --checkpoint is set.
+	Checkpoint *pageCheckpoint
+	// Prepare optionally runs on each streamed page before it is written.
+	Prepare func(items []T) error
+	// Prefetch requests the next page while the current one is handled
+	// (--concurrency above 1).
+	Prefetch bool
+}
+
+// autoPageResult summarizes an --all run.
+type autoPageResult[T any] struct {
+	// Items holds the collected items; it stays empty when streaming.
+	Items  []T
+	Count  int
+	Pages  int
+	Capped bool
+	// LastPage holds the items of the last page kept.
+	LastPage []T
+}
+
+func (p autoPager[T]) run(ctx context.Context, first autoPage[T]) (autoPageResult[T], error) {
+	res := autoPageResult[T]{Pages: 1}
+	if !p.Limits.Stream {
+		res.Items = make([]T, 0, len(first.Items))
+	}
+	cp := p.Checkpoint
+	if cp != nil && !p.Limits.Stream {
+		pending, err := p.pendingItems()
+		if err != nil {
+			return res, err
+		}
+		res.Items = append(res.Items, pending...)
+		res.Count = len(pending)
+	}
+
+	page := first
+	cursor := p.Cursor
+	for {
+		items := p.unseen(page.Items)
+		truncated := false
+		if limitReached(res.Count+len(items), p.Limits.MaxItems) {
+			truncated = res.Count+len(items) > p.Limits.MaxItems
+			items = items[:max(p.Limits.MaxItems-res.Count, 0)]
+			res.Capped = true
+		}
+
+		more := !res.Capped && page.HasMore
+		if more && pageLimitReached(res.Pages, p.Limits) {
+			res.Capped = true
+			more = false
+		}
+		more = more && page.Next != "" && page.Next != cursor
+
+		// Request the next page while this one is written or collected.
+		var next *beeperapi.Prefetch[autoPage[T]]
+		if more && p.Prefetch {
+			nextCursor := page.Next
+			next = beeperapi.StartPrefetch(func() (autoPage[T], error) { return p.Fetch(nextCursor) })
+		}
+		if err := p.handlePage(ctx, &res, items, page, cursor, truncated); err != nil {
+			if next != nil {
+				_, _ = next.Wait()
+			}
+			return res, err
+		}
+		if !more {
+			break
+		}
+		cursor = page.Next
+
+		var err error
+		if next != nil {
+			page, err = next.Wait()
+		} else {
+			page, err = p.Fetch(cursor)
+		}
+		if err != nil {
+			return res, err
+		}
+		res.Pages++
+	}
+
+	if cp != nil && !p.Limits.Stream {
+		// Buffered output is written right after run returns.
+		if err := p.commitPending(res.Items); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// handlePage streams or collects the kept items of page and updates the
+// --checkpoint file.
+func (p autoPager[T]) handlePage(ctx context.Context, res *autoPageResult[T], items []T, page autoPage[T], cursor string, truncated bool) error {
+	if p.Limits.Stream {
+		if p.Prepare != nil {
+			if err := p.Prepare(items); err != nil {
+				return err
+			}
+		}
+		if err := writeJSONLines(ctx, items); err != nil {
+			return err
+		}
+	} else {
+		res.Items = append(res.Items, items...)
+	}
+	res.Count += len(items)
+	res.LastPage = items
+
+	if p.Checkpoint == nil {
+		return nil
+	}
+	resume := page.Next
+	if truncated || resume == "" {
+		resume = cursor
+	}
+	return p.checkpointPage(items, resume)
+}
+
+// unseen drops items already emitted or collected under --checkpoint.
+func (p autoPager[T]) unseen(items []T) []T {
+	if p.Checkpoint == nil || p.Key == nil {
+		return items
+	}
+	out := make([]T, 0, len(items))
+	for _, item := range items {
+		key := p.Key(item)
+		if p.Checkpoint.seenKey(key) {
+			continue
+		}
+		p.Checkpoint.markSeen(key)
+		out = append(out, item)
+	}
+	return out
+}
+
+// checkpointPage records a processed page: streamed items are emitted
+// already, buffered items stay pending until the output is written.
+func (p autoPager[T]) checkpointPage(items []T, resume string) error {
+	cp := p.Checkpoint
+	cp.Cursor = resume
+	cp.Pages++
+	for _, item := range items {
+		if p.Limits.Stream {
+			cp.Emitted = append(cp.Emitted, p.Key(item))
+			continue
+		}
+		raw, err := json.Marshal(item)
+		if err != nil {
+			return fmt.Errorf("encode checkpoint item: %w", err)
+		}
+		cp.Pending = append(cp.Pending, raw)
+	}
+	return cp.save()
+}
+
+// pendingItems decodes items a previous buffered run collected but never
+// wrote, and marks them seen.
+func (p autoPager[T]) pendingItems() ([]T, error) {
+	items := make([]T, 0, len(p.Checkpoint.Pending))
+	for _, raw := range p.Checkpoint.Pending {
+		var item T
+		if err := json.Unmarshal(raw, &item); err != nil {
+			return nil, fmt.Errorf("decode checkpoint item: %w", err)
+		}
+		p.Checkpoint.markSeen(p.Key(item))
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// commitPending moves collected items from pending to emitted.
+func (p autoPager[T]) commitPending(items []T) error {
+	cp := p.Checkpoint
+	for _, item := range items {
+		cp.Emitted = append(cp.Emitted, p.Key(item))
+	}
+	cp.Pending = nil
+	return cp.save()
+}
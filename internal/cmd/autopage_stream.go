@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"context"
+	"os"
+
+	"github.com/johntheyoung/roadrunner/internal/errfmt"
+	"github.com/johntheyoung/roadrunner/internal/outfmt"
+)
+
+// defaultStreamMaxPages guards streamed --all exports, which have no item cap.
+const defaultStreamMaxPages = 1000
+
+// autoPageLimits holds the resolved --max-items/--max-pages guards for --all.
+// Zero means unlimited.
+type autoPageLimits struct {
+	MaxItems int
+	MaxPages int
+	// Stream is set when --all results are written page by page (--jsonl or
+	// --stream) instead of being collected first.
+	Stream bool
+}
+
+// resolveAutoPageLimits validates --max-items/--max-pages. Buffered --all keeps
+// the --max-items cap (default 500, max 5000); streamed --all has no item cap
+// by default and is bounded by --max-pages (default 1000) instead.
+func resolveAutoPageLimits(ctx context.Context, all bool, maxItems, maxPages int) (autoPageLimits, error) {
+	if !all {
+		if maxPages != 0 {
+			return autoPageLimits{}, errfmt.UsageError("--max-pages requires --all")
+		}
+		_, err := resolveAutoPageLimit(all, maxItems)
+		return autoPageLimits{}, err
+	}
+	if maxPages < 0 {
+		return autoPageLimits{}, errfmt.UsageError("invalid --max-pages %d (expected >= 1)", maxPages)
+	}
+
+	if outfmt.IsJSONL(ctx) {
+		if maxItems < 0 {
+			return autoPageLimits{}, errfmt.UsageError("invalid --max-items %d (expected >= 1)", maxItems)
+		}
+		if maxPages == 0 {
+			maxPages = defaultStreamMaxPages
+		}
+		return autoPageLimits{MaxItems: maxItems, MaxPages: maxPages, Stream: true}, nil
+	}
+
+	limit, err := resolveAutoPageLimit(all, maxItems)
+	if err != nil {
+		return autoPageLimits{}, err
+	}
+	return autoPageLimits{MaxItems: limit, MaxPages: maxPages}, nil
+}
+
+// pageLimitReached reports whether --max-pages stops auto-paging after pages.
+func pageLimitReached(pages int, limits autoPageLimits) bool {
+	return limits.MaxPages > 0 && pages >= limits.MaxPages
+}
+
+// streamTrailer is the final line written by --stream after all items.
+type streamTrailer struct {
+	Type       string                     `json:"type"`
+	Command    string                     `json:"command"`
+	Count      int                        `json:"count"`
+	Pagination *outfmt.EnvelopePagination `json:"pagination"`
+}
+
+// writeStreamTrailer emits the pagination summary line in --stream mode.
+// Plain --jsonl output stays items-only.
+func writeStreamTrailer(ctx context.Context, command string, count int, pagination *outfmt.EnvelopePagination) error {
+	if !outfmt.IsStream(ctx) {
+		return nil
+	}
+	return outfmt.WriteJSONLine(os.Stdout, streamTrailer{
+		Type:       "pagination",
+		Command:    command,
+		Count:      count,
+		Pagination: pagination,
+	})
+}
@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/johntheyoung/roadrunner/internal/outfmt"
+	"github.com/johntheyoung/roadrunner/internal/ui"
+)
+
+func testStreamContext(t *testing.T, mode outfmt.Mode) context.Context {
+	t.Helper()
+
+	testUI, err := ui.New(ui.Options{Color: "never"})
+	if err != nil {
+		t.Fatalf("ui.New() error = %v", err)
+	}
+	ctx := ui.WithUI(context.Background(), testUI)
+	return outfmt.WithMode(ctx, mode)
+}
+
+func TestResolveAutoPageLimits(t *testing.T) {
+	jsonCtx := testStreamContext(t, outfmt.Mode{JSON: true})
+	streamCtx := testStreamContext(t, outfmt.Mode{Stream: true})
+
+	limits, err := resolveAutoPageLimits(streamCtx, true, maxAutoPageItems+1, 0)
+	if err != nil {
+		t.Fatalf("stream resolve error = %v", err)
+	}
+	if !limits.Stream || limits.MaxItems != maxAutoPageItems+1 || limits.MaxPages != defaultStreamMaxPages {
+		t.Fatalf("stream limits = %+v", limits)
+	}
+
+	limits, err = resolveAutoPageLimits(jsonCtx, true, 0, 3)
+	if err != nil {
+		t.Fatalf("buffered resolve error = %v", err)
+	}
+	if limits.Stream || limits.MaxItems != defaultAutoPageMaxItems || limits.MaxPages != 3 {
+		t.Fatalf("buffered limits = %+v", limits)
+	}
+
+	if _, err := resolveAutoPageLimits(jsonCtx, true, maxAutoPageItems+1, 0); err == nil {
+		t.Fatal("expected buffered --max-items cap error")
+	}
+	if _, err := resolveAutoPageLimits(jsonCtx, false, 0, 2); err == nil {
+		t.Fatal("expected --max-pages requires --all error")
+	}
+	if _, err := resolveAutoPageLimits(streamCtx, true, 0, -1); err == nil {
+		t.Fatal("expected invalid --max-pages error")
+	}
+}
+
+// pagedChatsServer serves total chats, two per page, chained by cursor.
+func pagedChatsServer(t *testing.T, total int, requests *int) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chats" {
+			http.NotFound(w, r)
+			return
+		}
+		*requests++
+		start := 0
+		if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+			start, _ = strconv.Atoi(strings.TrimPrefix(cursor, "c"))
+		}
+		end := min(start+2, total)
+		items := make([]string, 0, end-start)
+		for i := start; i < end; i++ {
+			items = append(items, fmt.Sprintf(`{"id":"!chat%d:beeper.local","accountID":"acc1","participants":{"hasMore":false,"items":[],"total":0},"title":"Chat %d","type":"group","unreadCount":0}`, i, i))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"items":[%s],"hasMore":%t,"oldestCursor":"c%d","newestCursor":""}`, strings.Join(items, ","), end < total, end)
+	}))
+}
+
+func TestChatsListStreamWritesItemsAndTrailer(t *testing.T) {
+	t.Setenv("BEEPER_TOKEN", "test-token")
+	t.Setenv("BEEPER_ACCESS_TOKEN", "")
+
+	requests := 0
+	server := pagedChatsServer(t, 5, &requests)
+	defer server.Close()
+
+	ctx := testStreamContext(t, outfmt.Mode{Stream: true})
+	cmd := ChatsListCmd{All: true}
+	out, _ := captureOutput(t, func() {
+		if err := cmd.Run(ctx, &RootFlags{BaseURL: server.URL, Timeout: 5}); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+	})
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 6 {
+		t.Fatalf("lines = %d, want 5 items + trailer\noutput: %s", len(lines), out)
+	}
+	var trailer streamTrailer
+	if err := json.Unmarshal([]byte(lines[5]), &trailer); err != nil {
+		t.Fatalf("unmarshal trailer: %v", err)
+	}
+	if trailer.Type != "pagination" || trailer.Count != 5 || trailer.Pagination == nil {
+		t.Fatalf("trailer = %+v", trailer)
+	}
+	if p := trailer.Pagination; p.HasMore || p.Capped || p.Pages != 3 || p.MaxPages != defaultStreamMaxPages || !p.AutoPaged {
+		t.Fatalf("trailer pagination = %+v", p)
+	}
+	if requests != 3 {
+		t.Fatalf("requests = %d, want 3", requests)
+	}
+}
+
+func TestChatsListStreamMaxPagesGuard(t *testing.T) {
+	t.Setenv("BEEPER_TOKEN", "test-token")
+	t.Setenv("BEEPER_ACCESS_TOKEN", "")
+
+	requests := 0
+	server := pagedChatsServer(t, 10, &requests)
+	defer server.Close()
+
+	ctx := testStreamContext(t, outfmt.Mode{JSONL: true})
+	cmd := ChatsListCmd{All: true, MaxPages: 2}
+	out, _ := captureOutput(t, func() {
+		if err := cmd.Run(ctx, &RootFlags{BaseURL: server.URL, Timeout: 5}); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+	})
+
+	// --jsonl streams items without a trailer.
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 4 || requests != 2 {
+		t.Fatalf("lines = %d, requests = %d; want 4 and 2\noutput: %s", len(lines), requests, out)
+	}
+	if strings.Contains(out, `"pagination"`) {
+		t.Fatalf("unexpected trailer in --jsonl output: %s", out)
+	}
+}
+
+func TestChatsListStreamMaxItemsTruncatesPage(t *testing.T) {
+	t.Setenv("BEEPER_TOKEN", "test-token")
+	t.Setenv("BEEPER_ACCESS_TOKEN", "")
+
+	requests := 0
+	server := pagedChatsServer(t, 10, &requests)
+	defer server.Close()
+
+	ctx := testStreamContext(t, outfmt.Mode{Stream: true})
+	cmd := ChatsListCmd{All: true, MaxItems: 3}
+	out, _ := captureOutput(t, func() {
+		if err := cmd.Run(ctx, &RootFlags{BaseURL: server.URL, Timeout: 5}); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+	})
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("lines = %d, want 3 items + trailer\noutput: %s", len(lines), out)
+	}
+	var trailer streamTrailer
+	if err := json.Unmarshal([]byte(lines[3]), &trailer); err != nil {
+		t.Fatalf("unmarshal trailer: %v", err)
+	}
+	if trailer.Count != 3 || !trailer.Pagination.Capped || !trailer.Pagination.HasMore {
+		t.Fatalf("trailer = %+v pagination = %+v", trailer, trailer.Pagination)
+	}
+}
+
+func TestChatsListAllPrefetchKeepsOrder(t *testing.T) {
+	t.Setenv("BEEPER_TOKEN", "test-token")
+	t.Setenv("BEEPER_ACCESS_TOKEN", "")
+
+	requests := 0
+	server := pagedChatsServer(t, 9, &requests)
+	defer server.Close()
+
+	ctx := testStreamContext(t, outfmt.Mode{JSONL: true})
+	cmd := ChatsListCmd{All: true}
+	out, _ := captureOutput(t, func() {
+		if err := cmd.Run(ctx, &RootFlags{BaseURL: server.URL, Timeout: 5, Concurrency: 4}); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+	})
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 9 {
+		t.Fatalf("lines = %d, want 9\noutput: %s", len(lines), out)
+	}
+	for i, line := range lines {
+		if !strings.Contains(line, fmt.Sprintf(`"!chat%d:beeper.local"`, i)) {
+			t.Fatalf("line %d = %s, want chat %d", i, line, i)
+		}
+	}
+	if requests != 5 {
+		t.Fatalf("requests = %d, want 5 (no extra prefetch past the last page)", requests)
+	}
+}
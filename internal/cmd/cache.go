@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/johntheyoung/roadrunner/internal/cache"
+	"github.com/johntheyoung/roadrunner/internal/config"
+	"github.com/johntheyoung/roadrunner/internal/errfmt"
+	"github.com/johntheyoung/roadrunner/internal/outfmt"
+	"github.com/johntheyoung/roadrunner/internal/ui"
+)
+
+// CacheCmd is the parent command for local cache subcommands.
+type CacheCmd struct {
+	Stats CacheStatsCmd `cmd:"" help:"Show cached entries and hit rates per resource"`
+	Clear CacheClearCmd `cmd:"" help:"Remove cached entries"`
+}
+
+// openCache opens the local cache with the TTLs and refresh mode from flags.
+func openCache(flags *RootFlags) (*cache.Cache, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return nil, fmt.Errorf("get config dir: %w", err)
+	}
+	c, err := cache.New(filepath.Join(dir, "cache"), cache.Options{
+		TTLs:    flags.CacheTTL,
+		Refresh: flags.Refresh,
+	})
+	if err != nil {
+		return nil, errfmt.UsageError("invalid --cache-ttl: %v", err)
+	}
+	return c, nil
+}
+
+// CacheStatsCmd reports what the local cache holds.
+type CacheStatsCmd struct{}
+
+// CacheStatsResponse is the JSON output of cache stats.
+type CacheStatsResponse struct {
+	Dir       string                `json:"dir"`
+	Enabled   bool                  `json:"enabled"`
+	Resources []cache.ResourceStats `json:"resources"`
+}
+
+// Run executes the cache stats command.
+func (c *CacheStatsCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+
+	store, err := openCache(flags)
+	if err != nil {
+		return err
+	}
+	stats, err := store.Stats()
+	if err != nil {
+		return err
+	}
+
+	resp := CacheStatsResponse{
+		Dir:       store.Dir(),
+		Enabled:   !flags.NoCache,
+		Resources: stats,
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return writeJSON(ctx, resp, "cache stats")
+	}
+
+	if outfmt.IsTabular(ctx) {
+		for _, st := range stats {
+			writePlainRow(ctx, []string{"resource", "ttl", "entries", "fresh", "expired", "bytes", "hits", "misses"}, st.Resource, st.TTL, st.Entries, st.Fresh, st.Expired, st.Bytes, st.Hits, st.Misses)
+		}
+		return nil
+	}
+
+	u.Out().Printf("Cache: %s", resp.Dir)
+	if !resp.Enabled {
+		u.Out().Dim("Disabled for this run (--no-cache)")
+	}
+	for _, st := range stats {
+		u.Out().Printf("  %-9s ttl %-6s %d entries (%d fresh, %d expired), %d bytes, %d hits / %d misses",
+			st.Resource, st.TTL, st.Entries, st.Fresh, st.Expired, st.Bytes, st.Hits, st.Misses)
+	}
+	return nil
+}
+
+// CacheClearCmd removes cached entries.
+type CacheClearCmd struct {
+	Resource string `help:"Only clear this resource: accounts|chats|contacts"`
+}
+
+// Run executes the cache clear command.
+func (c *CacheClearCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+
+	resource := strings.TrimSpace(c.Resource)
+	if resource != "" {
+		if err := cache.ValidateResource(resource); err != nil {
+			return errfmt.UsageError("invalid --resource: %v", err)
+		}
+	}
+	if handled, err := handleDryRunWrite(ctx, flags, "cache clear", map[string]any{
+		"resource": resource,
+	}); handled {
+		return err
+	}
+
+	store, err := openCache(flags)
+	if err != nil {
+		return err
+	}
+	removed, err := store.Clear(resource)
+	if err != nil {
+		return err
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return writeJSON(ctx, map[string]any{
+			"success":  true,
+			"resource": resource,
+			"removed":  removed,
+		}, "cache clear")
+	}
+
+	if outfmt.IsTabular(ctx) {
+		writePlainRow(ctx, []string{"removed"}, removed)
+		return nil
+	}
+
+	if resource == "" {
+		u.Out().Successf("Cleared %d cached entries", removed)
+	} else {
+		u.Out().Successf("Cleared %d cached %s entries", removed, resource)
+	}
+	return nil
+}
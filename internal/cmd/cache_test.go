@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/johntheyoung/roadrunner/internal/errfmt"
+	"github.com/johntheyoung/roadrunner/internal/fakeapi"
+)
+
+func TestAccountsListUsesCache(t *testing.T) {
+	t.Setenv("BEEPER_TOKEN", "test-token")
+	t.Setenv("BEEPER_ACCESS_TOKEN", "")
+	t.Setenv("BEEPER_NO_CACHE", "false")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"accountID":"acc1","network":"WhatsApp","user":{"id":"u1","fullName":"Me"}}]`))
+	}))
+	defer server.Close()
+
+	run := func(args ...string) string {
+		t.Helper()
+		var out string
+		withArgs(t, append([]string{"rr", "--base-url", server.URL}, args...), func() {
+			var code int
+			var errText string
+			out, errText = captureOutput(t, func() {
+				code = Execute()
+			})
+			if code != 0 {
+				t.Fatalf("%v: exit code = %d, stderr = %q", args, code, errText)
+			}
+		})
+		return out
+	}
+
+	run("--plain", "accounts", "list")
+	run("--plain", "accounts", "list")
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("API calls after cached run = %d, want 1", got)
+	}
+	run("--refresh", "--plain", "accounts", "list")
+	run("--no-cache", "--plain", "accounts", "list")
+	if got := calls.Load(); got != 3 {
+		t.Fatalf("API calls after --refresh/--no-cache = %d, want 3", got)
+	}
+
+	var stats CacheStatsResponse
+	if err := json.Unmarshal([]byte(run("--json", "cache", "stats")), &stats); err != nil {
+		t.Fatalf("decode cache stats: %v", err)
+	}
+	accounts := stats.Resources[0]
+	if accounts.Resource != "accounts" || accounts.Entries != 1 || accounts.Hits != 1 || accounts.Misses != 2 {
+		t.Fatalf("accounts stats = %+v", accounts)
+	}
+
+	var cleared map[string]any
+	if err := json.Unmarshal([]byte(run("--json", "cache", "clear", "--resource", "accounts")), &cleared); err != nil {
+		t.Fatalf("decode cache clear: %v", err)
+	}
+	if cleared["removed"] != float64(1) {
+		t.Fatalf("cache clear = %v", cleared)
+	}
+	run("--plain", "accounts", "list")
+	if got := calls.Load(); got != 4 {
+		t.Fatalf("API calls after clear = %d, want 4", got)
+	}
+}
+
+func TestChatWritesDoNotUseStaleResolutions(t *testing.T) {
+	t.Setenv("BEEPER_TOKEN", "test-token")
+	t.Setenv("BEEPER_ACCESS_TOKEN", "")
+	t.Setenv("BEEPER_NO_CACHE", "false")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	fake := fakeapi.New(fakeapi.DefaultDataset())
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	defer fake.Close()
+
+	run := func(args ...string) (int, string, string) {
+		t.Helper()
+		var code int
+		var out, errText string
+		withArgs(t, append([]string{"rr", "--base-url", server.URL}, args...), func() {
+			out, errText = captureOutput(t, func() {
+				code = Execute()
+			})
+		})
+		return code, out, errText
+	}
+	resolve := func(args ...string) (int, string) {
+		t.Helper()
+		code, out, errText := run(append([]string{"--plain", "chats", "resolve", "Team", "--fields", "id"}, args...)...)
+		return code, strings.TrimSpace(out) + errText
+	}
+
+	if code, got := resolve(); code != 0 || got != "!team:beeper.local" {
+		t.Fatalf("first resolve: code = %d, output = %q", code, got)
+	}
+
+	// A second "Team" created without the cache leaves the cached
+	// resolution stale: reads still see it, writes must not.
+	if code, _, errText := run("--no-cache", "chats", "create", "matrix", "--type", "group", "--title", "Team", "--participant", "@alice:beeper.local", "--participant", "@bob:beeper.local"); code != 0 {
+		t.Fatalf("chats create: code = %d, stderr = %q", code, errText)
+	}
+	if code, got := resolve(); code != 0 || got != "!team:beeper.local" {
+		t.Fatalf("cached resolve: code = %d, output = %q", code, got)
+	}
+	if code, _, errText := run("messages", "send", "--chat", "Team", "hello"); code != errfmt.ExitFailure || !strings.Contains(errText, `multiple chats matched "Team"`) {
+		t.Fatalf("messages send --chat Team: code = %d, stderr = %q", code, errText)
+	}
+
+	// Any chat write through the cache drops the stale resolutions.
+	if code, _, errText := run("chats", "create", "matrix", "--type", "group", "--title", "Standup", "--participant", "@alice:beeper.local", "--participant", "@bob:beeper.local"); code != 0 {
+		t.Fatalf("chats create: code = %d, stderr = %q", code, errText)
+	}
+	if code, got := resolve(); code != errfmt.ExitFailure || !strings.Contains(got, `multiple chats matched "Team"`) {
+		t.Fatalf("resolve after create: code = %d, output = %q", code, got)
+	}
+}
@@ -50,23 +50,37 @@ func readCommands() []string {
 		"accounts alias list",
 		"assets download",
 		"assets serve",
+		"assets sync",
 		"auth status",
+		"cache stats",
 		"connect info",
+		"chats alias list",
 		"chats get",
+		"chats links",
 		"chats list",
+		"chats media",
 		"chats resolve",
 		"chats search",
+		"chats tag list",
+		"contacts export",
 		"contacts list",
 		"contacts resolve",
 		"contacts search",
 		"doctor",
 		"describe",
+		"dev fake-server",
+		"events record",
+		"events replay",
 		"events tail",
 		"messages context",
 		"messages list",
 		"messages search",
 		"messages tail",
+		"messages thread",
 		"messages wait",
+		"people list",
+		"people search",
+		"people show",
 		"search",
 		"status",
 		"unread",
@@ -81,27 +95,42 @@ func retryClasses() map[string]string {
 		"accounts alias list":  "safe",
 		"assets download":      "safe",
 		"assets serve":         "safe",
+		"assets sync":          "safe",
 		"auth status":          "safe",
 		"auth set":             "safe",
 		"auth clear":           "safe",
+		"cache stats":          "safe",
+		"cache clear":          "safe",
 		"connect info":         "safe",
 		"capabilities":         "safe",
+		"chats alias list":     "safe",
 		"chats get":            "safe",
+		"chats links":          "safe",
 		"chats list":           "safe",
+		"chats media":          "safe",
 		"chats resolve":        "safe",
 		"chats search":         "safe",
+		"chats tag list":       "safe",
+		"contacts export":      "safe",
 		"contacts resolve":     "safe",
 		"contacts search":      "safe",
 		"contacts list":        "safe",
 		"doctor":               "safe",
 		"describe":             "safe",
+		"dev fake-server":      "safe",
+		"events record":        "safe",
+		"events replay":        "safe",
 		"events tail":          "safe",
 		"focus":                "safe",
 		"messages context":     "safe",
 		"messages list":        "safe",
 		"messages search":      "safe",
 		"messages tail":        "safe",
+		"messages thread":      "safe",
 		"messages wait":        "safe",
+		"people list":          "safe",
+		"people search":        "safe",
+		"people show":          "safe",
 		"search":               "safe",
 		"status":               "safe",
 		"unread":               "safe",
@@ -114,10 +143,18 @@ func retryClasses() map[string]string {
 		"reminders clear":      "state-convergent",
 		"accounts alias set":   "state-convergent",
 		"accounts alias unset": "state-convergent",
+		"chats alias set":      "state-convergent",
+		"chats alias unset":    "state-convergent",
+		"chats tag add":        "state-convergent",
+		"chats tag remove":     "state-convergent",
+		"chats note set":       "state-convergent",
+		"people link":          "state-convergent",
+		"people unlink":        "state-convergent",
 		"messages send":        "non-idempotent",
 		"messages send-file":   "non-idempotent",
 		"chats create":         "non-idempotent",
 		"chats start":          "non-idempotent",
+		"contacts import":      "non-idempotent",
 		"assets upload":        "non-idempotent",
 		"assets upload-base64": "non-idempotent",
 	}
@@ -138,12 +175,12 @@ func (c *CapabilitiesCmd) Run(ctx context.Context, flags *RootFlags) error {
 
 	resp := CapabilitiesResponse{
 		Version:  Version,
-		Features: []string{"enable-commands", "readonly", "dry-run", "envelope", "agent-mode", "error-hints", "request-id", "dedupe-guard", "retry-classes", "describe", "jsonl"},
+		Features: []string{"enable-commands", "readonly", "dry-run", "envelope", "agent-mode", "error-hints", "request-id", "dedupe-guard", "retry-classes", "describe", "jsonl", "stream", "checkpoint", "cassettes", "cache", "match-modes", "chat-tags", "people", "threads", "query", "template"},
 		Defaults: CapDefaults{
 			Timeout: flags.Timeout,
 			BaseURL: flags.BaseURL,
 		},
-		OutputModes: []string{"human", "json", "jsonl", "plain"},
+		OutputModes: []string{"human", "json", "jsonl", "stream", "plain", "csv", "yaml", "markdown"},
 		Safety: CapSafety{
 			EnableCommandsDesc: "Comma-separated allowlist of top-level commands",
 			ReadonlyDesc:       "Block data write operations",
@@ -158,7 +195,13 @@ func (c *CapabilitiesCmd) Run(ctx context.Context, flags *RootFlags) error {
 		Flags: map[string]string{
 			"--json":            "Output JSON to stdout",
 			"--jsonl":           "Output JSON Lines to stdout (one object per line)",
+			"--stream":          "Output JSON Lines as pages arrive, ending with a pagination trailer",
 			"--plain":           "Output stable TSV to stdout",
+			"--csv":             "Output RFC 4180 CSV with a header row (respects --fields)",
+			"--yaml":            "Output YAML (respects --fields)",
+			"--markdown":        "Output a GitHub-flavored Markdown table (respects --fields)",
+			"--query":           "Filter JSON output with a jq-style expression",
+			"--template":        "Render JSON output with a Go text/template",
 			"--envelope":        "Wrap JSON in {success,data,error,metadata}",
 			"--no-input":        "Never prompt; fail instead",
 			"--readonly":        "Block data write operations",
@@ -166,10 +209,17 @@ func (c *CapabilitiesCmd) Run(ctx context.Context, flags *RootFlags) error {
 			"--enable-commands": "Allowlist of commands",
 			"--agent":           "Agent profile mode",
 			"--account":         "Default account ID for commands",
+			"--region":          "Country (ISO 3166) for phone numbers without a country code",
 			"--request-id":      "Optional request ID for envelope metadata",
 			"--dedupe-window":   "Window for duplicate non-idempotent write blocking",
 			"--timeout":         "API timeout in seconds",
+			"--concurrency":     "Maximum parallel API requests for fan-out and page prefetching",
 			"--force":           "Skip confirmations",
+			"--record":          "Record API traffic to a cassette directory",
+			"--replay":          "Replay API traffic from a cassette directory",
+			"--no-cache":        "Bypass the local cache of accounts, chat resolutions, and contact searches",
+			"--refresh":         "Ignore cached entries and refetch, updating the cache",
+			"--cache-ttl":       "Per-resource cache TTLs (resource=duration)",
 		},
 	}
 
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+
+	"github.com/johntheyoung/roadrunner/internal/cassette"
+	"github.com/johntheyoung/roadrunner/internal/config"
+	"github.com/johntheyoung/roadrunner/internal/errfmt"
+)
+
+// replayToken stands in for a real token when replaying a cassette on a
+// machine that has never authenticated; recorded requests are redacted anyway.
+const replayToken = "cassette-replay"
+
+// openCassette returns the cassette selected by --record/--replay, or nil.
+func openCassette(flags *RootFlags) (*cassette.Cassette, error) {
+	record := strings.TrimSpace(flags.Record)
+	replay := strings.TrimSpace(flags.Replay)
+
+	switch {
+	case record != "" && replay != "":
+		return nil, errfmt.UsageError("--record and --replay are mutually exclusive")
+	case record != "":
+		return cassette.Record(record)
+	case replay != "":
+		c, err := cassette.Replay(replay)
+		if err != nil {
+			return nil, errfmt.UsageError("invalid --replay: %v", err)
+		}
+		if _, _, err := config.GetToken(); err != nil {
+			_ = os.Setenv("BEEPER_TOKEN", replayToken)
+		}
+		return c, nil
+	default:
+		return nil, nil
+	}
+}
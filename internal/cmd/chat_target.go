@@ -2,11 +2,12 @@ package cmd
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"strings"
 
 	"github.com/johntheyoung/roadrunner/internal/beeperapi"
 	"github.com/johntheyoung/roadrunner/internal/errfmt"
+	"github.com/johntheyoung/roadrunner/internal/ui"
 )
 
 func resolveChatTargetInput(chatIDArg, chatQuery string) (string, string, error) {
@@ -26,52 +27,58 @@ func resolveChatTargetInput(chatIDArg, chatQuery string) (string, string, error)
 	return chatID, query, nil
 }
 
-func resolveChatIDByQuery(ctx context.Context, client *beeperapi.Client, query string, accountIDs []string) (string, error) {
+// resolveChatIDByQuery resolves a --chat query to a chat ID: chat aliases
+// first, then chat IDs, then titles in match mode (exact|prefix|fuzzy).
+func resolveChatIDByQuery(ctx context.Context, client *beeperapi.Client, query string, accountIDs []string, match string) (string, error) {
 	q := strings.TrimSpace(query)
 	if q == "" {
 		return "", errfmt.UsageError("query is required")
 	}
 
-	if looksLikeChatID(q) {
-		normalized := normalizeChatID(q)
-		if err := validateResourceID(normalized, "chatID"); err != nil {
+	if chatID, ok := lookupChatAlias(q); ok {
+		if err := validateResourceID(chatID, "chatID"); err != nil {
 			return "", err
 		}
-		return normalized, nil
+		return chatID, nil
 	}
 
-	cursor := ""
-	var matchID string
-	for {
-		resp, err := client.Chats().Search(ctx, beeperapi.ChatSearchParams{
-			Query:      q,
-			AccountIDs: accountIDs,
-			Limit:      200,
-			Cursor:     cursor,
-			Direction:  "before",
-		})
-		if err != nil {
+	if beeperapi.LooksLikeChatID(q) {
+		normalized := normalizeChatID(q)
+		if err := validateResourceID(normalized, "chatID"); err != nil {
 			return "", err
 		}
+		return normalized, nil
+	}
 
-		for _, item := range resp.Items {
-			if chatExactMatch(item, q) {
-				if matchID != "" && matchID != item.ID {
-					return "", errfmt.WithCode(fmt.Errorf("multiple chats matched %q", q), errfmt.ExitFailure)
-				}
-				matchID = item.ID
-			}
-		}
-
-		if !resp.HasMore || resp.OldestCursor == "" {
-			break
-		}
-		cursor = resp.OldestCursor
+	resolved, err := client.Chats().Resolve(ctx, beeperapi.ChatResolveParams{
+		Query:      q,
+		AccountIDs: accountIDs,
+		Match:      match,
+	})
+	if err != nil {
+		return "", matchErrorCode(err)
 	}
+	return resolved.ID, nil
+}
 
-	if matchID == "" {
-		return "", errfmt.WithCode(fmt.Errorf("no chat matched %q", q), errfmt.ExitFailure)
+// matchErrorCode maps chat/contact resolution misses to exit code 1.
+func matchErrorCode(err error) error {
+	var matchErr *beeperapi.MatchError
+	if errors.As(err, &matchErr) {
+		return errfmt.WithCode(err, errfmt.ExitFailure)
 	}
+	return err
+}
 
-	return matchID, nil
+// writeMatchCandidates lists the ranked candidates of an ambiguous
+// chat/contact match below the error message.
+func writeMatchCandidates(u *ui.UI, err error) {
+	var matchErr *beeperapi.MatchError
+	if !errors.As(err, &matchErr) || len(matchErr.Candidates) == 0 {
+		return
+	}
+	u.Err().Printf("Candidates:")
+	for _, c := range matchErr.Candidates {
+		u.Err().Printf("  %s\t%s\t%.3f (%s)", c.ID, c.Name, c.Score, c.MatchedOn)
+	}
 }
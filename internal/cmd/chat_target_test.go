@@ -1,10 +1,14 @@
 package cmd
 
 import (
+	"encoding/json"
 	"errors"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/johntheyoung/roadrunner/internal/errfmt"
+	"github.com/johntheyoung/roadrunner/internal/fakeapi"
 )
 
 func TestResolveChatTargetInput(t *testing.T) {
@@ -65,3 +69,149 @@ func TestResolveChatTargetInput(t *testing.T) {
 		})
 	}
 }
+
+func TestChatsResolveMatchModes(t *testing.T) {
+	t.Setenv("BEEPER_TOKEN", "test-token")
+	t.Setenv("BEEPER_ACCESS_TOKEN", "")
+
+	fake := fakeapi.New(fakeapi.DefaultDataset())
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	defer fake.Close()
+
+	run := func(args ...string) (int, string, string) {
+		t.Helper()
+		var code int
+		var out, errText string
+		withArgs(t, append([]string{"rr", "--base-url", server.URL}, args...), func() {
+			out, errText = captureOutput(t, func() {
+				code = Execute()
+			})
+		})
+		return code, out, errText
+	}
+
+	if code, _, errText := run("chats", "resolve", "Taem"); code != errfmt.ExitFailure || !strings.Contains(errText, `no chat matched "Taem"`) {
+		t.Fatalf("exact resolve: code = %d, stderr = %q", code, errText)
+	}
+	if code, out, errText := run("--plain", "chats", "resolve", "Taem", "--match", "fuzzy", "--fields", "id"); code != 0 || strings.TrimSpace(out) != "!team:beeper.local" {
+		t.Fatalf("fuzzy resolve: code = %d, out = %q, stderr = %q", code, out, errText)
+	}
+
+	code, out, _ := run("--json", "--envelope", "chats", "resolve", "a", "--match", "prefix")
+	if code != errfmt.ExitFailure {
+		t.Fatalf("ambiguous prefix resolve: code = %d, want %d", code, errfmt.ExitFailure)
+	}
+	var env struct {
+		Error struct {
+			Code    string `json:"code"`
+			Details struct {
+				Match      string `json:"match"`
+				Candidates []struct {
+					ID        string  `json:"id"`
+					Score     float64 `json:"score"`
+					MatchedOn string  `json:"matched_on"`
+				} `json:"candidates"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(out), &env); err != nil {
+		t.Fatalf("unmarshal envelope: %v\n%s", err, out)
+	}
+	if env.Error.Code != errfmt.ErrCodeAmbiguous || env.Error.Details.Match != "prefix" {
+		t.Fatalf("envelope error = %+v", env.Error)
+	}
+	candidates := env.Error.Details.Candidates
+	if len(candidates) < 2 || candidates[0].ID != "!alice:beeper.local" || candidates[0].Score < candidates[1].Score {
+		t.Fatalf("candidates = %+v", candidates)
+	}
+
+	if code, _, errText := run("chats", "resolve", "a", "--match", "prefix"); code != errfmt.ExitFailure || !strings.Contains(errText, "Candidates:") || !strings.Contains(errText, "!alice:beeper.local") {
+		t.Fatalf("human ambiguous resolve: code = %d, stderr = %q", code, errText)
+	}
+}
+
+func TestChatAliasesResolveEverywhere(t *testing.T) {
+	t.Setenv("BEEPER_TOKEN", "test-token")
+	t.Setenv("BEEPER_ACCESS_TOKEN", "")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	fake := fakeapi.New(fakeapi.DefaultDataset())
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	defer fake.Close()
+
+	run := func(args ...string) (int, string, string) {
+		t.Helper()
+		var code int
+		var out, errText string
+		withArgs(t, append([]string{"rr", "--base-url", server.URL}, args...), func() {
+			out, errText = captureOutput(t, func() {
+				code = Execute()
+			})
+		})
+		return code, out, errText
+	}
+
+	if code, _, errText := run("chats", "alias", "set", "@oncall", "!team:beeper.local"); code != 0 {
+		t.Fatalf("alias set: code = %d, stderr = %q", code, errText)
+	}
+	if code, out, _ := run("--plain", "chats", "alias", "list"); code != 0 || strings.TrimSpace(out) != "oncall\t!team:beeper.local" {
+		t.Fatalf("alias list: code = %d, out = %q", code, out)
+	}
+
+	if code, out, errText := run("--plain", "chats", "resolve", "@oncall", "--fields", "id"); code != 0 || strings.TrimSpace(out) != "!team:beeper.local" {
+		t.Fatalf("resolve @oncall: code = %d, out = %q, stderr = %q", code, out, errText)
+	}
+	code, out, errText := run("--json", "messages", "send", "--chat", "@oncall", "hello")
+	if code != 0 {
+		t.Fatalf("send --chat @oncall: code = %d, stderr = %q", code, errText)
+	}
+	var sent struct {
+		ChatID string `json:"chat_id"`
+	}
+	if err := json.Unmarshal([]byte(out), &sent); err != nil || sent.ChatID != "!team:beeper.local" {
+		t.Fatalf("send --chat @oncall = %q (%v)", out, err)
+	}
+	if code, _, errText := run("--json", "messages", "list", "oncall"); code != 0 {
+		t.Fatalf("messages list oncall: code = %d, stderr = %q", code, errText)
+	}
+
+	if code, _, errText := run("chats", "alias", "set", "!team", "!team:beeper.local"); code != errfmt.ExitUsageError || !strings.Contains(errText, "invalid alias") {
+		t.Fatalf("alias set with chat-ID name: code = %d, stderr = %q", code, errText)
+	}
+	if code, _, errText := run("chats", "alias", "unset", "oncall"); code != 0 {
+		t.Fatalf("alias unset: code = %d, stderr = %q", code, errText)
+	}
+	if code, out, _ := run("--plain", "chats", "alias", "list"); code != 0 || strings.TrimSpace(out) != "" {
+		t.Fatalf("alias list after unset: code = %d, out = %q", code, out)
+	}
+}
+
+func TestContactsResolvePhoneFormats(t *testing.T) {
+	t.Setenv("BEEPER_TOKEN", "test-token")
+	t.Setenv("BEEPER_ACCESS_TOKEN", "")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	fake := fakeapi.New(fakeapi.DefaultDataset())
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	defer fake.Close()
+
+	for _, args := range [][]string{
+		{"contacts", "resolve", "+1 (415) 555-0101", "--account-id", "matrix"},
+		{"contacts", "resolve", "4155550101", "--account-id", "matrix"},
+		{"--region", "US", "contacts", "resolve", "1-415-555-0101", "--account-id", "matrix"},
+	} {
+		var code int
+		var out, errText string
+		withArgs(t, append([]string{"rr", "--base-url", server.URL, "--plain"}, args...), func() {
+			out, errText = captureOutput(t, func() {
+				code = Execute()
+			})
+		})
+		if code != 0 || !strings.HasPrefix(out, "@alice:beeper.local\t") {
+			t.Fatalf("%v: code = %d, out = %q, stderr = %q", args, code, out, errText)
+		}
+	}
+}
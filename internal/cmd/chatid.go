@@ -1,7 +1,14 @@
 package cmd
 
-// normalizeChatID strips any leading backslashes before a Matrix-style chat ID.
-// This protects against shell history expansion artifacts like "\\!room".
+import (
+	"strings"
+
+	"github.com/johntheyoung/roadrunner/internal/config"
+)
+
+// normalizeChatID resolves chat aliases and strips any leading backslashes
+// before a Matrix-style chat ID. The latter protects against shell history
+// expansion artifacts like "\\!room".
 func normalizeChatID(id string) string {
 	if id == "" {
 		return id
@@ -16,6 +23,10 @@ func normalizeChatID(id string) string {
 		return id[i:]
 	}
 
+	if chatID, ok := lookupChatAlias(id); ok {
+		return chatID
+	}
+
 	return id
 }
 
@@ -30,3 +41,13 @@ func normalizeChatIDs(ids []string) []string {
 	}
 	return out
 }
+
+// lookupChatAlias returns the chat ID a configured alias ("oncall" or
+// "@oncall") points to. Chat IDs themselves are never looked up.
+func lookupChatAlias(value string) (string, bool) {
+	if value == "" || strings.HasPrefix(value, "!") {
+		return "", false
+	}
+	resolved := config.ResolveChatAlias(value)
+	return resolved, resolved != value
+}
@@ -3,10 +3,13 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
 	"strings"
 	"text/tabwriter"
 	"time"
+	"unicode"
 
 	"github.com/johntheyoung/roadrunner/internal/beeperapi"
 	"github.com/johntheyoung/roadrunner/internal/config"
@@ -19,21 +22,29 @@ import (
 type ChatsCmd struct {
 	List    ChatsListCmd    `cmd:"" help:"List chats"`
 	Search  ChatsSearchCmd  `cmd:"" help:"Search chats"`
-	Resolve ChatsResolveCmd `cmd:"" help:"Resolve a chat by exact match"`
+	Resolve ChatsResolveCmd `cmd:"" help:"Resolve a chat by exact, prefix, or fuzzy match"`
 	Get     ChatsGetCmd     `cmd:"" help:"Get chat details"`
+	Links   ChatsLinksCmd   `cmd:"" help:"List URLs shared in a chat"`
+	Media   ChatsMediaCmd   `cmd:"" help:"List attachments shared in a chat"`
 	Create  ChatsCreateCmd  `cmd:"" help:"Create a new chat"`
 	Start   ChatsStartCmd   `cmd:"" help:"Resolve/create a direct chat from merged contact data"`
 	Archive ChatsArchiveCmd `cmd:"" help:"Archive or unarchive a chat"`
+	Alias   ChatsAliasCmd   `cmd:"" help:"Manage chat aliases"`
+	Tag     ChatsTagCmd     `cmd:"" help:"Manage local chat tags"`
+	Note    ChatsNoteCmd    `cmd:"" help:"Manage local chat notes"`
 }
 
 // ChatsListCmd lists chats.
 type ChatsListCmd struct {
 	AccountIDs  []string `help:"Filter by account IDs" name:"account-ids"`
+	Tags        []string `help:"Only chats carrying every given local tag (repeatable)" name:"tag"`
 	Cursor      string   `help:"Pagination cursor"`
 	Direction   string   `help:"Pagination direction: before|after" enum:"before,after," default:""`
 	All         bool     `help:"Fetch all pages automatically" name:"all"`
-	MaxItems    int      `help:"Maximum items to collect with --all (default 500, max 5000)" name:"max-items" default:"0"`
-	Fields      []string `help:"Comma-separated list of fields for --plain output" name:"fields" sep:","`
+	MaxItems    int      `help:"Maximum items to collect with --all (default 500, max 5000; unlimited when streaming)" name:"max-items" default:"0"`
+	MaxPages    int      `help:"Maximum pages to fetch with --all (default 1000 when streaming, otherwise unlimited)" name:"max-pages" default:"0"`
+	Checkpoint  string   `help:"Resume --all from a checkpoint file, saved after each page" name:"checkpoint" type:"path"`
+	Fields      []string `help:"Comma-separated list of fields for --plain, --csv, --yaml, or --markdown output" name:"fields" sep:","`
 	FailIfEmpty bool     `help:"Exit with code 1 if no results" name:"fail-if-empty"`
 }
 
@@ -41,7 +52,12 @@ type ChatsListCmd struct {
 func (c *ChatsListCmd) Run(ctx context.Context, flags *RootFlags) error {
 	u := ui.FromContext(ctx)
 
-	autoPageLimit, err := resolveAutoPageLimit(c.All, c.MaxItems)
+	limits, err := resolveAutoPageLimits(ctx, c.All, c.MaxItems, c.MaxPages)
+	if err != nil {
+		return err
+	}
+	autoPageLimit := limits.MaxItems
+	meta, tags, err := openChatTagFilter(c.Tags)
 	if err != nil {
 		return err
 	}
@@ -52,97 +68,115 @@ func (c *ChatsListCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	timeout := time.Duration(flags.Timeout) * time.Second
-	client, err := beeperapi.NewClient(token, flags.BaseURL, timeout)
+	client, err := newAPIClient(ctx, token, flags.BaseURL, timeout)
 	if err != nil {
 		return err
 	}
 
 	accountIDs := applyAccountDefault(c.AccountIDs, flags.Account)
-	resp, err := client.Chats().List(ctx, beeperapi.ChatListParams{
-		AccountIDs: accountIDs,
-		Cursor:     c.Cursor,
-		Direction:  c.Direction,
-	})
+	checkpoint, err := openPageCheckpoint(c.Checkpoint, c.All, "chats list", strings.Join(accountIDs, ","), c.Direction, tagFilterScope(tags))
+	if err != nil {
+		return err
+	}
+	listPage := func(cursor string) (beeperapi.ChatListResult, error) {
+		page, err := client.Chats().List(ctx, beeperapi.ChatListParams{
+			AccountIDs: accountIDs,
+			Cursor:     cursor,
+			Direction:  c.Direction,
+		})
+		page.Items = tagChatListItems(meta, tags, page.Items)
+		return page, err
+	}
+	startCursor := checkpoint.resumeCursor(c.Cursor)
+	resp, err := listPage(startCursor)
 	if err != nil {
 		return err
 	}
 	capped := false
+	pages := 1
+	streamed := 0
 	if c.All {
-		items := make([]beeperapi.ChatListItem, 0, len(resp.Items))
-		items = append(items, resp.Items...)
-		lastCursor := c.Cursor
-
-		for resp.HasMore {
-			if limitReached(len(items), autoPageLimit) {
-				capped = true
-				break
-			}
-
-			nextCursor := nextSearchCursor(c.Direction, resp.OldestCursor, resp.NewestCursor)
-			if nextCursor == "" || nextCursor == lastCursor {
-				break
-			}
-			lastCursor = nextCursor
-
-			page, err := client.Chats().List(ctx, beeperapi.ChatListParams{
-				AccountIDs: accountIDs,
-				Cursor:     nextCursor,
-				Direction:  c.Direction,
-			})
-			if err != nil {
-				return err
-			}
-
-			items = append(items, page.Items...)
-			resp.HasMore = page.HasMore
-			resp.OldestCursor = page.OldestCursor
-			resp.NewestCursor = page.NewestCursor
+		pager := autoPager[beeperapi.ChatListItem]{
+			Limits:     limits,
+			Cursor:     startCursor,
+			Key:        func(item beeperapi.ChatListItem) string { return item.ID },
+			Checkpoint: checkpoint,
+			Prefetch:   fetchConcurrency(ctx, flags) > 1,
+			Fetch: func(cursor string) (autoPage[beeperapi.ChatListItem], error) {
+				page, err := listPage(cursor)
+				if err != nil {
+					return autoPage[beeperapi.ChatListItem]{}, err
+				}
+				resp.HasMore = page.HasMore
+				resp.OldestCursor = page.OldestCursor
+				resp.NewestCursor = page.NewestCursor
+				return autoPage[beeperapi.ChatListItem]{
+					Items:   page.Items,
+					HasMore: page.HasMore,
+					Next:    nextSearchCursor(c.Direction, page.OldestCursor, page.NewestCursor),
+				}, nil
+			},
 		}
-		if limitReached(len(items), autoPageLimit) {
-			if len(items) > autoPageLimit {
-				items = items[:autoPageLimit]
-			}
-			capped = true
+		res, err := pager.run(ctx, autoPage[beeperapi.ChatListItem]{
+			Items:   resp.Items,
+			HasMore: resp.HasMore,
+			Next:    nextSearchCursor(c.Direction, resp.OldestCursor, resp.NewestCursor),
+		})
+		if err != nil {
+			return err
 		}
-		resp.Items = items
+		capped, pages, streamed = res.Capped, res.Pages, res.Count
+		resp.Items = res.Items
 		if capped {
 			resp.HasMore = true
 		}
 	}
 
+	pagination := &outfmt.EnvelopePagination{
+		HasMore:      resp.HasMore,
+		Direction:    c.Direction,
+		OldestCursor: resp.OldestCursor,
+		NewestCursor: resp.NewestCursor,
+		AutoPaged:    c.All,
+		Capped:       capped,
+	}
+	if c.All {
+		pagination.MaxItems = autoPageLimit
+		pagination.Pages = pages
+		pagination.MaxPages = limits.MaxPages
+	}
+
+	if limits.Stream {
+		if err := writeStreamTrailer(ctx, "chats list", streamed, pagination); err != nil {
+			return err
+		}
+		return failIfEmpty(c.FailIfEmpty, streamed, "chats")
+	}
+
 	if err := failIfEmpty(c.FailIfEmpty, len(resp.Items), "chats"); err != nil {
 		return err
 	}
 
 	if outfmt.IsJSONL(ctx) {
-		return writeJSONLines(resp.Items)
+		if err := writeJSONLines(ctx, resp.Items); err != nil {
+			return err
+		}
+		return writeStreamTrailer(ctx, "chats list", len(resp.Items), pagination)
 	}
 
 	// JSON output
 	if outfmt.IsJSON(ctx) {
-		maxItems := 0
-		if c.All {
-			maxItems = autoPageLimit
-		}
-		return writeJSONWithPagination(ctx, resp, "chats list", &outfmt.EnvelopePagination{
-			HasMore:      resp.HasMore,
-			Direction:    c.Direction,
-			OldestCursor: resp.OldestCursor,
-			NewestCursor: resp.NewestCursor,
-			AutoPaged:    c.All,
-			Capped:       capped,
-			MaxItems:     maxItems,
-		})
+		return writeJSONWithPagination(ctx, resp, "chats list", pagination)
 	}
 
 	// Plain output (TSV)
-	if outfmt.IsPlain(ctx) {
+	if outfmt.IsTabular(ctx) {
 		fields, err := resolveFields(c.Fields, []string{"id", "title", "account_id", "display_name", "last_activity", "preview"})
 		if err != nil {
 			return err
 		}
 		for _, item := range resp.Items {
-			writePlainFields(u, fields, map[string]string{
+			writePlainFields(ctx, fields, map[string]any{
 				"id":            item.ID,
 				"title":         item.Title,
 				"display_name":  item.DisplayName,
@@ -191,6 +225,7 @@ type ChatsSearchCmd struct {
 	Query              string   `arg:"" optional:"" help:"Search query"`
 	AccountIDs         []string `help:"Filter by account IDs" name:"account-ids"`
 	Inbox              string   `help:"Filter by inbox: primary|low-priority|archive" enum:"primary,low-priority,archive," default:""`
+	Tags               []string `help:"Only chats carrying every given local tag (repeatable)" name:"tag"`
 	UnreadOnly         bool     `help:"Only show unread chats" name:"unread-only"`
 	IncludeMuted       *bool    `help:"Include muted chats (default true)" name:"include-muted"`
 	LastActivityAfter  string   `help:"Only include chats after time (RFC3339 or duration)" name:"last-activity-after"`
@@ -201,8 +236,10 @@ type ChatsSearchCmd struct {
 	Cursor             string   `help:"Pagination cursor"`
 	Direction          string   `help:"Pagination direction: before|after" enum:"before,after," default:""`
 	All                bool     `help:"Fetch all pages automatically" name:"all"`
-	MaxItems           int      `help:"Maximum items to collect with --all (default 500, max 5000)" name:"max-items" default:"0"`
-	Fields             []string `help:"Comma-separated list of fields for --plain output" name:"fields" sep:","`
+	MaxItems           int      `help:"Maximum items to collect with --all (default 500, max 5000; unlimited when streaming)" name:"max-items" default:"0"`
+	MaxPages           int      `help:"Maximum pages to fetch with --all (default 1000 when streaming, otherwise unlimited)" name:"max-pages" default:"0"`
+	Checkpoint         string   `help:"Resume --all from a checkpoint file, saved after each page" name:"checkpoint" type:"path"`
+	Fields             []string `help:"Comma-separated list of fields for --plain, --csv, --yaml, or --markdown output" name:"fields" sep:","`
 	FailIfEmpty        bool     `help:"Exit with code 1 if no results" name:"fail-if-empty"`
 }
 
@@ -213,10 +250,11 @@ func (c *ChatsSearchCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if c.Limit < 1 || c.Limit > 200 {
 		return errfmt.UsageError("invalid --limit %d (expected 1-200)", c.Limit)
 	}
-	autoPageLimit, err := resolveAutoPageLimit(c.All, c.MaxItems)
+	limits, err := resolveAutoPageLimits(ctx, c.All, c.MaxItems, c.MaxPages)
 	if err != nil {
 		return err
 	}
+	autoPageLimit := limits.MaxItems
 
 	var lastAfter *time.Time
 	if c.LastActivityAfter != "" {
@@ -236,129 +274,158 @@ func (c *ChatsSearchCmd) Run(ctx context.Context, flags *RootFlags) error {
 		lastBefore = &t
 	}
 
+	meta, tags, err := openChatTagFilter(c.Tags)
+	if err != nil {
+		return err
+	}
+
 	token, _, err := config.GetToken()
 	if err != nil {
 		return err
 	}
 
 	timeout := time.Duration(flags.Timeout) * time.Second
-	client, err := beeperapi.NewClient(token, flags.BaseURL, timeout)
+	client, err := newAPIClient(ctx, token, flags.BaseURL, timeout)
 	if err != nil {
 		return err
 	}
 
 	accountIDs := applyAccountDefault(c.AccountIDs, flags.Account)
-	resp, err := client.Chats().Search(ctx, beeperapi.ChatSearchParams{
-		Query:              c.Query,
-		AccountIDs:         accountIDs,
-		Inbox:              c.Inbox,
-		UnreadOnly:         c.UnreadOnly,
-		IncludeMuted:       c.IncludeMuted,
-		LastActivityAfter:  lastAfter,
-		LastActivityBefore: lastBefore,
-		Type:               c.Type,
-		Scope:              c.Scope,
-		Limit:              c.Limit,
-		Cursor:             c.Cursor,
-		Direction:          c.Direction,
-	})
+	searchPage := func(cursor string) (beeperapi.ChatSearchResult, error) {
+		page, err := client.Chats().Search(ctx, beeperapi.ChatSearchParams{
+			Query:              c.Query,
+			AccountIDs:         accountIDs,
+			Inbox:              c.Inbox,
+			UnreadOnly:         c.UnreadOnly,
+			IncludeMuted:       c.IncludeMuted,
+			LastActivityAfter:  lastAfter,
+			LastActivityBefore: lastBefore,
+			Type:               c.Type,
+			Scope:              c.Scope,
+			Limit:              c.Limit,
+			Cursor:             cursor,
+			Direction:          c.Direction,
+		})
+		page.Items = tagChatSearchItems(meta, tags, page.Items)
+		return page, err
+	}
+
+	checkpoint, err := openPageCheckpoint(c.Checkpoint, c.All, "chats search",
+		c.Query,
+		strings.Join(accountIDs, ","),
+		c.Inbox,
+		formatBool(c.UnreadOnly),
+		formatBool(c.IncludeMuted == nil || *c.IncludeMuted),
+		c.LastActivityAfter,
+		c.LastActivityBefore,
+		c.Type,
+		c.Scope,
+		fmt.Sprint(c.Limit),
+		c.Direction,
+		tagFilterScope(tags),
+	)
+	if err != nil {
+		return err
+	}
+	startCursor := checkpoint.resumeCursor(c.Cursor)
+	resp, err := searchPage(startCursor)
 	if err != nil {
 		return err
 	}
 	capped := false
+	pages := 1
+	streamed := 0
 	if c.All {
-		items := make([]beeperapi.ChatSearchItem, 0, len(resp.Items))
-		items = append(items, resp.Items...)
-		lastCursor := c.Cursor
-		for resp.HasMore {
-			if limitReached(len(items), autoPageLimit) {
-				capped = true
-				break
-			}
-
-			nextCursor := nextSearchCursor(c.Direction, resp.OldestCursor, resp.NewestCursor)
-			if nextCursor == "" || nextCursor == lastCursor {
-				break
-			}
-			lastCursor = nextCursor
-
-			page, err := client.Chats().Search(ctx, beeperapi.ChatSearchParams{
-				Query:              c.Query,
-				AccountIDs:         accountIDs,
-				Inbox:              c.Inbox,
-				UnreadOnly:         c.UnreadOnly,
-				IncludeMuted:       c.IncludeMuted,
-				LastActivityAfter:  lastAfter,
-				LastActivityBefore: lastBefore,
-				Type:               c.Type,
-				Scope:              c.Scope,
-				Limit:              c.Limit,
-				Cursor:             nextCursor,
-				Direction:          c.Direction,
-			})
-			if err != nil {
-				return err
-			}
-
-			items = append(items, page.Items...)
-			resp.HasMore = page.HasMore
-			resp.OldestCursor = page.OldestCursor
-			resp.NewestCursor = page.NewestCursor
+		pager := autoPager[beeperapi.ChatSearchItem]{
+			Limits:     limits,
+			Cursor:     startCursor,
+			Key:        func(item beeperapi.ChatSearchItem) string { return item.ID },
+			Checkpoint: checkpoint,
+			Prefetch:   fetchConcurrency(ctx, flags) > 1,
+			Fetch: func(cursor string) (autoPage[beeperapi.ChatSearchItem], error) {
+				page, err := searchPage(cursor)
+				if err != nil {
+					return autoPage[beeperapi.ChatSearchItem]{}, err
+				}
+				resp.HasMore = page.HasMore
+				resp.OldestCursor = page.OldestCursor
+				resp.NewestCursor = page.NewestCursor
+				return autoPage[beeperapi.ChatSearchItem]{
+					Items:   page.Items,
+					HasMore: page.HasMore,
+					Next:    nextSearchCursor(c.Direction, page.OldestCursor, page.NewestCursor),
+				}, nil
+			},
 		}
-		if limitReached(len(items), autoPageLimit) {
-			if len(items) > autoPageLimit {
-				items = items[:autoPageLimit]
-			}
-			capped = true
+		res, err := pager.run(ctx, autoPage[beeperapi.ChatSearchItem]{
+			Items:   resp.Items,
+			HasMore: resp.HasMore,
+			Next:    nextSearchCursor(c.Direction, resp.OldestCursor, resp.NewestCursor),
+		})
+		if err != nil {
+			return err
 		}
-		resp.Items = items
+		capped, pages, streamed = res.Capped, res.Pages, res.Count
+		resp.Items = res.Items
 		if capped {
 			resp.HasMore = true
 		}
 	}
 
+	pagination := &outfmt.EnvelopePagination{
+		HasMore:      resp.HasMore,
+		Direction:    c.Direction,
+		OldestCursor: resp.OldestCursor,
+		NewestCursor: resp.NewestCursor,
+		AutoPaged:    c.All,
+		Capped:       capped,
+	}
+	if c.All {
+		pagination.MaxItems = autoPageLimit
+		pagination.Pages = pages
+		pagination.MaxPages = limits.MaxPages
+	}
+
+	if limits.Stream {
+		if err := writeStreamTrailer(ctx, "chats search", streamed, pagination); err != nil {
+			return err
+		}
+		return failIfEmpty(c.FailIfEmpty, streamed, "chats")
+	}
+
 	if err := failIfEmpty(c.FailIfEmpty, len(resp.Items), "chats"); err != nil {
 		return err
 	}
 
 	if outfmt.IsJSONL(ctx) {
-		return writeJSONLines(resp.Items)
+		if err := writeJSONLines(ctx, resp.Items); err != nil {
+			return err
+		}
+		return writeStreamTrailer(ctx, "chats search", len(resp.Items), pagination)
 	}
 
 	// JSON output
 	if outfmt.IsJSON(ctx) {
-		maxItems := 0
-		if c.All {
-			maxItems = autoPageLimit
-		}
-		return writeJSONWithPagination(ctx, resp, "chats search", &outfmt.EnvelopePagination{
-			HasMore:      resp.HasMore,
-			Direction:    c.Direction,
-			OldestCursor: resp.OldestCursor,
-			NewestCursor: resp.NewestCursor,
-			AutoPaged:    c.All,
-			Capped:       capped,
-			MaxItems:     maxItems,
-		})
+		return writeJSONWithPagination(ctx, resp, "chats search", pagination)
 	}
 
 	// Plain output (TSV)
-	if outfmt.IsPlain(ctx) {
+	if outfmt.IsTabular(ctx) {
 		fields, err := resolveFields(c.Fields, []string{"id", "title", "type", "unread_count", "display_name", "network", "account_id", "is_archived", "is_muted"})
 		if err != nil {
 			return err
 		}
 		for _, item := range resp.Items {
-			writePlainFields(u, fields, map[string]string{
+			writePlainFields(ctx, fields, map[string]any{
 				"id":           item.ID,
 				"title":        item.Title,
 				"display_name": item.DisplayName,
 				"type":         item.Type,
 				"network":      item.Network,
 				"account_id":   item.AccountID,
-				"unread_count": fmt.Sprintf("%d", item.UnreadCount),
-				"is_archived":  formatBool(item.IsArchived),
-				"is_muted":     formatBool(item.IsMuted),
+				"unread_count": item.UnreadCount,
+				"is_archived":  item.IsArchived,
+				"is_muted":     item.IsMuted,
 			})
 		}
 		return nil
@@ -406,11 +473,12 @@ type ChatsGetCmd struct {
 	MaxParticipantCount int    `help:"Maximum participants to return: -1 for all, otherwise 0-500" name:"max-participant-count" default:"-1"`
 }
 
-// ChatsResolveCmd resolves a chat by exact match.
+// ChatsResolveCmd resolves a chat by exact, prefix, or fuzzy match.
 type ChatsResolveCmd struct {
-	Query      string   `arg:"" help:"Exact chat title, display name, or ID"`
+	Query      string   `arg:"" help:"Chat title, display name, or ID"`
 	AccountIDs []string `help:"Filter by account IDs" name:"account-ids"`
-	Fields     []string `help:"Comma-separated list of fields for --plain output" name:"fields" sep:","`
+	Match      string   `help:"Match mode: exact|prefix|fuzzy (prefix/fuzzy rank candidates; ambiguous results list them)" name:"match" enum:"exact,prefix,fuzzy" default:"exact"`
+	Fields     []string `help:"Comma-separated list of fields for --plain, --csv, --yaml, or --markdown output" name:"fields" sep:","`
 }
 
 // ChatsCreateCmd creates a new chat.
@@ -427,7 +495,7 @@ type ChatsStartCmd struct {
 	AccountID   string `arg:"" name:"accountID" optional:"" help:"Account ID to start the chat on (uses --account default if omitted)"`
 	UserID      string `help:"Known user ID candidate" name:"user-id"`
 	Email       string `help:"Email candidate" name:"email"`
-	PhoneNumber string `help:"Phone number candidate (E.164 preferred)" name:"phone-number"`
+	PhoneNumber string `help:"Phone number candidate, normalized to E.164 (national numbers use --region)" name:"phone-number"`
 	Username    string `help:"Username/handle candidate" name:"username"`
 	FullName    string `help:"Display name hint for ranking" name:"full-name"`
 	AllowInvite *bool  `help:"Allow invite-based DM creation when required by the platform" name:"allow-invite"`
@@ -448,7 +516,7 @@ func (c *ChatsGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	timeout := time.Duration(flags.Timeout) * time.Second
-	client, err := beeperapi.NewClient(token, flags.BaseURL, timeout)
+	client, err := newAPIClient(ctx, token, flags.BaseURL, timeout)
 	if err != nil {
 		return err
 	}
@@ -460,6 +528,12 @@ func (c *ChatsGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if err != nil {
 		return err
 	}
+	meta, err := openChatMeta()
+	if err != nil {
+		return err
+	}
+	entry := meta.Get(chat.ID)
+	chat.Tags, chat.Note = entry.Tags, entry.Note
 
 	// JSON output
 	if outfmt.IsJSON(ctx) {
@@ -467,8 +541,8 @@ func (c *ChatsGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	// Plain output
-	if outfmt.IsPlain(ctx) {
-		u.Out().Printf("%s\t%s\t%s", chat.ID, chat.Title, chat.AccountID)
+	if outfmt.IsTabular(ctx) {
+		writePlainRow(ctx, []string{"id", "title", "account_id"}, chat.ID, chat.Title, chat.AccountID)
 		return nil
 	}
 
@@ -491,6 +565,12 @@ func (c *ChatsGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if chat.LastActivity != "" {
 		u.Out().Printf("Last:    %s", chat.LastActivity)
 	}
+	if len(chat.Tags) > 0 {
+		u.Out().Printf("Tags:    %s", strings.Join(chat.Tags, ", "))
+	}
+	if chat.Note != "" {
+		u.Out().Printf("Note:    %s", chat.Note)
+	}
 
 	return nil
 }
@@ -509,14 +589,14 @@ func (c *ChatsResolveCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	timeout := time.Duration(flags.Timeout) * time.Second
-	client, err := beeperapi.NewClient(token, flags.BaseURL, timeout)
+	client, err := newAPIClient(ctx, token, flags.BaseURL, timeout)
 	if err != nil {
 		return err
 	}
 
 	query := strings.TrimSpace(c.Query)
 	accountIDs := applyAccountDefault(c.AccountIDs, flags.Account)
-	matchID, err := resolveChatIDByQuery(ctx, client, query, accountIDs)
+	matchID, err := resolveChatIDByQuery(ctx, client, query, accountIDs, c.Match)
 	if err != nil {
 		return err
 	}
@@ -566,7 +646,7 @@ func (c *ChatsCreateCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	timeout := time.Duration(flags.Timeout) * time.Second
-	client, err := beeperapi.NewClient(token, flags.BaseURL, timeout)
+	client, err := newAPIClient(ctx, token, flags.BaseURL, timeout)
 	if err != nil {
 		return err
 	}
@@ -603,8 +683,8 @@ func (c *ChatsCreateCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	// Plain output
-	if outfmt.IsPlain(ctx) {
-		u.Out().Printf("%s", resp.ChatID)
+	if outfmt.IsTabular(ctx) {
+		writePlainRow(ctx, []string{"chat_id"}, resp.ChatID)
 		return nil
 	}
 
@@ -632,6 +712,15 @@ func (c *ChatsStartCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if user.ID == "" && user.Email == "" && user.PhoneNumber == "" && user.Username == "" && user.FullName == "" {
 		return errfmt.UsageError("at least one user identifier is required (--user-id, --email, --phone-number, --username, or --full-name)")
 	}
+	if user.PhoneNumber != "" {
+		region, err := phoneRegion(flags)
+		if err != nil {
+			return err
+		}
+		if user.PhoneNumber, err = normalizePhone(user.PhoneNumber, region); err != nil {
+			return err
+		}
+	}
 	if handled, err := handleDryRunWrite(ctx, flags, "chats start", map[string]any{
 		"account_id":   accountID,
 		"user":         user,
@@ -647,7 +736,7 @@ func (c *ChatsStartCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	timeout := time.Duration(flags.Timeout) * time.Second
-	client, err := beeperapi.NewClient(token, flags.BaseURL, timeout)
+	client, err := newAPIClient(ctx, token, flags.BaseURL, timeout)
 	if err != nil {
 		return err
 	}
@@ -681,8 +770,8 @@ func (c *ChatsStartCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	// Plain output
-	if outfmt.IsPlain(ctx) {
-		u.Out().Printf("%s\t%s", resp.ChatID, resp.Status)
+	if outfmt.IsTabular(ctx) {
+		writePlainRow(ctx, []string{"chat_id", "status"}, resp.ChatID, resp.Status)
 		return nil
 	}
 
@@ -701,27 +790,6 @@ func (c *ChatsStartCmd) Run(ctx context.Context, flags *RootFlags) error {
 	return nil
 }
 
-func chatExactMatch(chat beeperapi.ChatSearchItem, query string) bool {
-	q := strings.TrimSpace(query)
-	if q == "" {
-		return false
-	}
-	if strings.EqualFold(chat.ID, q) {
-		return true
-	}
-	if strings.EqualFold(chat.Title, q) {
-		return true
-	}
-	if strings.EqualFold(chat.DisplayName, q) {
-		return true
-	}
-	return false
-}
-
-func looksLikeChatID(value string) bool {
-	return strings.HasPrefix(value, "!") && strings.Contains(value, ":")
-}
-
 func writeResolvedChat(ctx context.Context, u *ui.UI, client *beeperapi.Client, chatID string, fields []string) error {
 	chat, err := client.Chats().Get(ctx, chatID, beeperapi.ChatGetParams{})
 	if err != nil {
@@ -732,21 +800,21 @@ func writeResolvedChat(ctx context.Context, u *ui.UI, client *beeperapi.Client,
 		return writeJSON(ctx, chat, "chats resolve")
 	}
 
-	if outfmt.IsPlain(ctx) {
+	if outfmt.IsTabular(ctx) {
 		cols, err := resolveFields(fields, []string{"id", "title", "display_name", "account_id", "type", "network", "unread_count", "is_archived", "is_muted"})
 		if err != nil {
 			return err
 		}
-		writePlainFields(u, cols, map[string]string{
+		writePlainFields(ctx, cols, map[string]any{
 			"id":           chat.ID,
 			"title":        chat.Title,
 			"display_name": chat.DisplayName,
 			"account_id":   chat.AccountID,
 			"type":         chat.Type,
 			"network":      chat.Network,
-			"unread_count": fmt.Sprintf("%d", chat.UnreadCount),
-			"is_archived":  formatBool(chat.IsArchived),
-			"is_muted":     formatBool(chat.IsMuted),
+			"unread_count": chat.UnreadCount,
+			"is_archived":  chat.IsArchived,
+			"is_muted":     chat.IsMuted,
 		})
 		return nil
 	}
@@ -798,7 +866,7 @@ func (c *ChatsArchiveCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	timeout := time.Duration(flags.Timeout) * time.Second
-	client, err := beeperapi.NewClient(token, flags.BaseURL, timeout)
+	client, err := newAPIClient(ctx, token, flags.BaseURL, timeout)
 	if err != nil {
 		return err
 	}
@@ -820,12 +888,12 @@ func (c *ChatsArchiveCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	// Plain output
-	if outfmt.IsPlain(ctx) {
+	if outfmt.IsTabular(ctx) {
 		action := "archived"
 		if c.Unarchive {
 			action = "unarchived"
 		}
-		u.Out().Printf("%s\t%s", chatID, action)
+		writePlainRow(ctx, []string{"chat_id", "action"}, chatID, action)
 		return nil
 	}
 
@@ -838,3 +906,145 @@ func (c *ChatsArchiveCmd) Run(ctx context.Context, flags *RootFlags) error {
 
 	return nil
 }
+
+// ChatsAliasCmd is the parent command for chat alias subcommands.
+type ChatsAliasCmd struct {
+	Set   ChatsAliasSetCmd   `cmd:"" help:"Create or update a chat alias"`
+	List  ChatsAliasListCmd  `cmd:"" help:"List chat aliases"`
+	Unset ChatsAliasUnsetCmd `cmd:"" help:"Remove a chat alias"`
+}
+
+// ChatsAliasSetCmd creates or updates a chat alias.
+type ChatsAliasSetCmd struct {
+	Alias  string `arg:"" help:"Alias name (e.g., 'oncall'; use as @oncall or oncall wherever a chat is accepted)"`
+	ChatID string `arg:"" name:"chatID" help:"Chat ID to map the alias to"`
+}
+
+// Run executes the chats alias set command.
+func (c *ChatsAliasSetCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+
+	alias, err := validateChatAliasName(c.Alias)
+	if err != nil {
+		return err
+	}
+	chatID := normalizeChatID(strings.TrimSpace(c.ChatID))
+	if chatID == "" {
+		return errfmt.UsageError("chatID is required")
+	}
+	if err := validateResourceID(chatID, "chatID"); err != nil {
+		return err
+	}
+
+	if handled, err := handleDryRunWrite(ctx, flags, "chats alias set", map[string]any{
+		"alias":   alias,
+		"chat_id": chatID,
+	}); handled {
+		return err
+	}
+
+	if err := config.SetChatAlias(alias, chatID); err != nil {
+		return err
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return writeJSON(ctx, map[string]any{
+			"success": true,
+			"alias":   alias,
+			"chat_id": chatID,
+		}, "chats alias set")
+	}
+
+	u.Out().Success("Alias saved: @" + alias + " -> " + chatID)
+	return nil
+}
+
+// ChatsAliasListCmd lists all chat aliases.
+type ChatsAliasListCmd struct{}
+
+// Run executes the chats alias list command.
+func (c *ChatsAliasListCmd) Run(ctx context.Context) error {
+	u := ui.FromContext(ctx)
+
+	aliases, err := config.GetChatAliases()
+	if err != nil {
+		return err
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return writeJSON(ctx, map[string]any{
+			"aliases": aliases,
+		}, "chats alias list")
+	}
+
+	names := slices.Sorted(maps.Keys(aliases))
+
+	if outfmt.IsTabular(ctx) {
+		for _, alias := range names {
+			writePlainRow(ctx, []string{"alias", "chat_id"}, alias, aliases[alias])
+		}
+		return nil
+	}
+
+	if len(aliases) == 0 {
+		u.Out().Warn("No chat aliases configured")
+		u.Out().Dim("Use: rr chats alias set <alias> <chat-id>")
+		return nil
+	}
+
+	u.Out().Printf("Chat aliases:")
+	for _, alias := range names {
+		u.Out().Printf("  @%s -> %s", alias, aliases[alias])
+	}
+
+	return nil
+}
+
+// ChatsAliasUnsetCmd removes a chat alias.
+type ChatsAliasUnsetCmd struct {
+	Alias string `arg:"" help:"Alias name to remove"`
+}
+
+// Run executes the chats alias unset command.
+func (c *ChatsAliasUnsetCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+
+	alias := strings.TrimPrefix(strings.TrimSpace(c.Alias), "@")
+	if handled, err := handleDryRunWrite(ctx, flags, "chats alias unset", map[string]any{
+		"alias": alias,
+	}); handled {
+		return err
+	}
+
+	if err := config.UnsetChatAlias(alias); err != nil {
+		return err
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return writeJSON(ctx, map[string]any{
+			"success": true,
+			"alias":   alias,
+		}, "chats alias unset")
+	}
+
+	u.Out().Success("Alias removed: @" + alias)
+	return nil
+}
+
+// validateChatAliasName trims an optional leading "@" from name and rejects
+// names that could be mistaken for chat IDs or event wildcards.
+func validateChatAliasName(name string) (string, error) {
+	alias := strings.TrimPrefix(strings.TrimSpace(name), "@")
+	switch {
+	case alias == "":
+		return "", errfmt.UsageError("alias name is required")
+	case strings.HasPrefix(alias, "!") || strings.HasPrefix(alias, "@") || strings.HasPrefix(alias, "\\"):
+		return "", errfmt.UsageError("invalid alias %q (must not start with !, @, or \\)", name)
+	case alias == "*" || strings.ContainsAny(alias, ":,") || strings.ContainsFunc(alias, unicode.IsSpace):
+		return "", errfmt.UsageError("invalid alias %q (must not be * or contain whitespace, ':' or ',')", name)
+	}
+	if err := validateResourceID(alias, "alias"); err != nil {
+		return "", err
+	}
+	return alias, nil
+}
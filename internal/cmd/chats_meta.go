@@ -0,0 +1,281 @@
+package cmd
+
+import (
+	"context"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/johntheyoung/roadrunner/internal/beeperapi"
+	"github.com/johntheyoung/roadrunner/internal/chatmeta"
+	"github.com/johntheyoung/roadrunner/internal/config"
+	"github.com/johntheyoung/roadrunner/internal/errfmt"
+	"github.com/johntheyoung/roadrunner/internal/outfmt"
+	"github.com/johntheyoung/roadrunner/internal/ui"
+)
+
+// ChatsTagCmd is the parent command for local chat tag subcommands.
+type ChatsTagCmd struct {
+	Add    ChatsTagAddCmd    `cmd:"" help:"Add local tags to a chat"`
+	Remove ChatsTagRemoveCmd `cmd:"" help:"Remove local tags from a chat"`
+	List   ChatsTagListCmd   `cmd:"" help:"List local tags and the chats carrying them"`
+}
+
+// ChatsTagAddCmd adds local tags to a chat.
+type ChatsTagAddCmd struct {
+	ChatID string   `arg:"" name:"chatID" help:"Chat ID or alias to tag"`
+	Tags   []string `arg:"" name:"tag" help:"Tags to add (e.g., customer escalated)"`
+}
+
+// Run executes the chats tag add command.
+func (c *ChatsTagAddCmd) Run(ctx context.Context, flags *RootFlags) error {
+	return runChatTagWrite(ctx, flags, "chats tag add", c.ChatID, c.Tags, func(meta *chatmeta.Store, chatID string, tags []string) chatmeta.Entry {
+		return meta.AddTags(chatID, tags...)
+	})
+}
+
+// ChatsTagRemoveCmd removes local tags from a chat.
+type ChatsTagRemoveCmd struct {
+	ChatID string   `arg:"" name:"chatID" help:"Chat ID or alias to untag"`
+	Tags   []string `arg:"" name:"tag" help:"Tags to remove"`
+}
+
+// Run executes the chats tag remove command.
+func (c *ChatsTagRemoveCmd) Run(ctx context.Context, flags *RootFlags) error {
+	return runChatTagWrite(ctx, flags, "chats tag remove", c.ChatID, c.Tags, func(meta *chatmeta.Store, chatID string, tags []string) chatmeta.Entry {
+		return meta.RemoveTags(chatID, tags...)
+	})
+}
+
+func runChatTagWrite(ctx context.Context, flags *RootFlags, command, chatIDArg string, tagArgs []string, apply func(*chatmeta.Store, string, []string) chatmeta.Entry) error {
+	u := ui.FromContext(ctx)
+
+	chatID, err := chatMetaTarget(chatIDArg)
+	if err != nil {
+		return err
+	}
+	tags, err := chatmeta.NormalizeTags(tagArgs)
+	if err != nil {
+		return errfmt.UsageError("%v", err)
+	}
+
+	if handled, err := handleDryRunWrite(ctx, flags, command, map[string]any{
+		"chat_id": chatID,
+		"tags":    tags,
+	}); handled {
+		return err
+	}
+
+	meta, err := openChatMeta()
+	if err != nil {
+		return err
+	}
+	entry := apply(meta, chatID, tags)
+	if err := meta.Save(); err != nil {
+		return err
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return writeJSON(ctx, map[string]any{
+			"success": true,
+			"chat_id": chatID,
+			"tags":    nonNilTags(entry.Tags),
+		}, command)
+	}
+
+	if outfmt.IsTabular(ctx) {
+		writePlainRow(ctx, []string{"chat_id", "tags"}, chatID, strings.Join(entry.Tags, ","))
+		return nil
+	}
+
+	if len(entry.Tags) == 0 {
+		u.Out().Success("Tags cleared: " + chatID)
+		return nil
+	}
+	u.Out().Success("Tags for " + chatID + ": " + strings.Join(entry.Tags, ", "))
+	return nil
+}
+
+// ChatsTagListCmd lists local tags.
+type ChatsTagListCmd struct{}
+
+type chatTagSummary struct {
+	Tag   string   `json:"tag"`
+	Count int      `json:"count"`
+	Chats []string `json:"chats"`
+}
+
+// Run executes the chats tag list command.
+func (c *ChatsTagListCmd) Run(ctx context.Context) error {
+	u := ui.FromContext(ctx)
+
+	meta, err := openChatMeta()
+	if err != nil {
+		return err
+	}
+	byTag := meta.Tags()
+	summaries := make([]chatTagSummary, 0, len(byTag))
+	for _, tag := range slices.Sorted(maps.Keys(byTag)) {
+		summaries = append(summaries, chatTagSummary{Tag: tag, Count: len(byTag[tag]), Chats: byTag[tag]})
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return writeJSON(ctx, map[string]any{
+			"tags": summaries,
+		}, "chats tag list")
+	}
+
+	if outfmt.IsTabular(ctx) {
+		for _, s := range summaries {
+			writePlainRow(ctx, []string{"tag", "count", "chats"}, s.Tag, s.Count, strings.Join(s.Chats, ","))
+		}
+		return nil
+	}
+
+	if len(summaries) == 0 {
+		u.Out().Warn("No chat tags")
+		u.Out().Dim("Use: rr chats tag add <chat-id> <tag>...")
+		return nil
+	}
+
+	u.Out().Printf("Chat tags:")
+	for _, s := range summaries {
+		u.Out().Printf("  %s (%d)", s.Tag, s.Count)
+		for _, chatID := range s.Chats {
+			u.Out().Dim("    " + chatID)
+		}
+	}
+	return nil
+}
+
+// ChatsNoteCmd is the parent command for local chat note subcommands.
+type ChatsNoteCmd struct {
+	Set ChatsNoteSetCmd `cmd:"" help:"Set or clear a chat's local note"`
+}
+
+// ChatsNoteSetCmd sets a chat's local note.
+type ChatsNoteSetCmd struct {
+	ChatID string `arg:"" name:"chatID" help:"Chat ID or alias"`
+	Note   string `arg:"" optional:"" help:"Note text (omit or pass \"\" to clear)"`
+}
+
+// Run executes the chats note set command.
+func (c *ChatsNoteSetCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+
+	chatID, err := chatMetaTarget(c.ChatID)
+	if err != nil {
+		return err
+	}
+	note := strings.TrimSpace(c.Note)
+
+	if handled, err := handleDryRunWrite(ctx, flags, "chats note set", map[string]any{
+		"chat_id": chatID,
+		"note":    note,
+	}); handled {
+		return err
+	}
+
+	meta, err := openChatMeta()
+	if err != nil {
+		return err
+	}
+	meta.SetNote(chatID, note)
+	if err := meta.Save(); err != nil {
+		return err
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return writeJSON(ctx, map[string]any{
+			"success": true,
+			"chat_id": chatID,
+			"note":    note,
+		}, "chats note set")
+	}
+
+	if note == "" {
+		u.Out().Success("Note cleared: " + chatID)
+		return nil
+	}
+	u.Out().Success("Note saved: " + chatID)
+	return nil
+}
+
+// openChatMeta opens the local chat metadata store in the config directory.
+func openChatMeta() (*chatmeta.Store, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return nil, err
+	}
+	return chatmeta.Open(filepath.Join(dir, chatmeta.FileName))
+}
+
+// chatMetaTarget resolves and validates the chat a tag/note command targets.
+func chatMetaTarget(chatIDArg string) (string, error) {
+	chatID := normalizeChatID(strings.TrimSpace(chatIDArg))
+	if chatID == "" {
+		return "", errfmt.UsageError("chatID is required")
+	}
+	if err := validateResourceID(chatID, "chatID"); err != nil {
+		return "", err
+	}
+	return chatID, nil
+}
+
+// openChatTagFilter validates --tag filters and opens the metadata store used
+// to filter and annotate chat output.
+func openChatTagFilter(tags []string) (*chatmeta.Store, []string, error) {
+	normalized, err := chatmeta.NormalizeTags(tags)
+	if err != nil {
+		return nil, nil, errfmt.UsageError("invalid --tag: %v", err)
+	}
+	meta, err := openChatMeta()
+	if err != nil {
+		return nil, nil, err
+	}
+	return meta, normalized, nil
+}
+
+// tagFilterScope is the checkpoint scope of a --tag filter: the order
+// tags were given in does not change the results.
+func tagFilterScope(tags []string) string {
+	return strings.Join(slices.Sorted(slices.Values(tags)), ",")
+}
+
+// tagChatListItems keeps chats carrying every tag and fills in their local
+// tags and notes.
+func tagChatListItems(meta *chatmeta.Store, tags []string, items []beeperapi.ChatListItem) []beeperapi.ChatListItem {
+	out := make([]beeperapi.ChatListItem, 0, len(items))
+	for _, item := range items {
+		if !meta.HasTags(item.ID, tags) {
+			continue
+		}
+		entry := meta.Get(item.ID)
+		item.Tags, item.Note = entry.Tags, entry.Note
+		out = append(out, item)
+	}
+	return out
+}
+
+// tagChatSearchItems keeps chats carrying every tag and fills in their local
+// tags and notes.
+func tagChatSearchItems(meta *chatmeta.Store, tags []string, items []beeperapi.ChatSearchItem) []beeperapi.ChatSearchItem {
+	out := make([]beeperapi.ChatSearchItem, 0, len(items))
+	for _, item := range items {
+		if !meta.HasTags(item.ID, tags) {
+			continue
+		}
+		entry := meta.Get(item.ID)
+		item.Tags, item.Note = entry.Tags, entry.Note
+		out = append(out, item)
+	}
+	return out
+}
+
+func nonNilTags(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
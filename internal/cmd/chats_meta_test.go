@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/johntheyoung/roadrunner/internal/errfmt"
+	"github.com/johntheyoung/roadrunner/internal/fakeapi"
+)
+
+func TestChatTagsFilterAndAnnotateChats(t *testing.T) {
+	t.Setenv("BEEPER_TOKEN", "test-token")
+	t.Setenv("BEEPER_ACCESS_TOKEN", "")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	fake := fakeapi.New(fakeapi.DefaultDataset())
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	defer fake.Close()
+
+	run := func(args ...string) string {
+		t.Helper()
+		var out string
+		withArgs(t, append([]string{"rr", "--base-url", server.URL}, args...), func() {
+			var code int
+			var errText string
+			out, errText = captureOutput(t, func() {
+				code = Execute()
+			})
+			if code != 0 {
+				t.Fatalf("%v: exit code = %d, stderr = %q", args, code, errText)
+			}
+		})
+		return out
+	}
+
+	run("chats", "tag", "add", "!alice:beeper.local", "Customer", "escalated")
+	run("chats", "tag", "add", "!team:beeper.local", "customer")
+	run("chats", "note", "set", "!alice:beeper.local", "Renewal due in May")
+
+	var list struct {
+		Items []struct {
+			ID   string   `json:"id"`
+			Tags []string `json:"tags"`
+			Note string   `json:"note"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal([]byte(run("--json", "chats", "list", "--tag", "customer")), &list); err != nil {
+		t.Fatalf("decode chats list: %v", err)
+	}
+	if len(list.Items) != 2 {
+		t.Fatalf("chats list --tag customer = %+v", list.Items)
+	}
+	for _, item := range list.Items {
+		if !slices.Contains(item.Tags, "customer") || (item.ID == "!alice:beeper.local") != (item.Note != "") {
+			t.Fatalf("chats list item = %+v", item)
+		}
+	}
+
+	var search struct {
+		Items []struct {
+			ID   string   `json:"id"`
+			Tags []string `json:"tags"`
+			Note string   `json:"note"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal([]byte(run("--json", "chats", "search", "--tag", "customer,escalated")), &search); err != nil {
+		t.Fatalf("decode chats search: %v", err)
+	}
+	if len(search.Items) != 1 || search.Items[0].ID != "!alice:beeper.local" ||
+		!slices.Equal(search.Items[0].Tags, []string{"customer", "escalated"}) || search.Items[0].Note != "Renewal due in May" {
+		t.Fatalf("chats search --tag customer,escalated = %+v", search.Items)
+	}
+
+	var summary statusSummary
+	if err := json.Unmarshal([]byte(run("--json", "status", "--tag", "escalated")), &summary); err != nil {
+		t.Fatalf("decode status: %v", err)
+	}
+	if summary.Chats != 1 {
+		t.Fatalf("status --tag escalated chats = %d, want 1", summary.Chats)
+	}
+
+	run("chats", "tag", "remove", "!alice:beeper.local", "escalated")
+	if out := run("--plain", "chats", "tag", "list"); strings.TrimSpace(out) != "customer\t2\t!alice:beeper.local,!team:beeper.local" {
+		t.Fatalf("chats tag list = %q", out)
+	}
+
+	withArgs(t, []string{"rr", "--base-url", server.URL, "chats", "list", "--tag", "two words"}, func() {
+		var code int
+		_, errText := captureOutput(t, func() {
+			code = Execute()
+		})
+		if code != errfmt.ExitUsageError || !strings.Contains(errText, "invalid --tag") {
+			t.Fatalf("invalid --tag: code = %d, stderr = %q", code, errText)
+		}
+	})
+}
@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/johntheyoung/roadrunner/internal/beeperapi"
+	"github.com/johntheyoung/roadrunner/internal/errfmt"
+	"github.com/johntheyoung/roadrunner/internal/outfmt"
+	"github.com/johntheyoung/roadrunner/internal/ui"
+)
+
+// ChatsLinksCmd lists the URLs shared in a chat.
+type ChatsLinksCmd struct {
+	ChatID      string   `arg:"" name:"chatID" help:"Chat ID to scan"`
+	Since       string   `help:"Only links shared at or after time (RFC3339 or duration)" name:"since"`
+	Until       string   `help:"Only links shared at or before time (RFC3339 or duration)" name:"until"`
+	Domain      []string `help:"Only links on these domains, including subdomains (repeatable)" name:"domain" sep:","`
+	Dedupe      bool     `help:"List each URL once (newest share) with a share count" name:"dedupe"`
+	Limit       int      `help:"Stop after this many links (0 = no limit)" name:"limit" default:"0"`
+	MaxPages    int      `help:"Maximum pages of chat history to scan (1-1000)" name:"max-pages" default:"50"`
+	Fields      []string `help:"Comma-separated list of fields for --plain, --csv, --yaml, or --markdown output" name:"fields" sep:","`
+	FailIfEmpty bool     `help:"Exit with code 1 if no results" name:"fail-if-empty"`
+}
+
+// ChatsMediaCmd lists the attachments shared in a chat.
+type ChatsMediaCmd struct {
+	ChatID      string   `arg:"" name:"chatID" help:"Chat ID to scan"`
+	Since       string   `help:"Only attachments shared at or after time (RFC3339 or duration)" name:"since"`
+	Until       string   `help:"Only attachments shared at or before time (RFC3339 or duration)" name:"until"`
+	Kind        []string `help:"Only attachments of kind: image|video|audio|file|gif|sticker|voice (repeatable)" name:"kind" sep:","`
+	Dedupe      bool     `help:"List each file once (newest share) with a share count" name:"dedupe"`
+	Limit       int      `help:"Stop after this many attachments (0 = no limit)" name:"limit" default:"0"`
+	MaxPages    int      `help:"Maximum pages of chat history to scan (1-1000)" name:"max-pages" default:"50"`
+	Fields      []string `help:"Comma-separated list of fields for --plain, --csv, --yaml, or --markdown output" name:"fields" sep:","`
+	FailIfEmpty bool     `help:"Exit with code 1 if no results" name:"fail-if-empty"`
+}
+
+// Run executes the chats links command.
+func (c *ChatsLinksCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	chatID := normalizeChatID(c.ChatID)
+
+	params, err := sharedParams(c.Since, c.Until, c.Dedupe, c.Limit, c.MaxPages)
+	if err != nil {
+		return err
+	}
+	for _, d := range c.Domain {
+		if d = strings.TrimSpace(d); d != "" {
+			params.Domains = append(params.Domains, d)
+		}
+	}
+
+	client, err := newClientFromFlags(ctx, flags)
+	if err != nil {
+		return err
+	}
+	result, err := client.Messages().Links(ctx, chatID, params)
+	if err != nil {
+		return err
+	}
+	if err := failIfEmpty(c.FailIfEmpty, len(result.Items), "links"); err != nil {
+		return err
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return writeJSON(ctx, result, "chats links")
+	}
+
+	if outfmt.IsTabular(ctx) {
+		fields, err := resolveFields(c.Fields, []string{"url", "domain", "timestamp", "sender_name", "message_id", "count"})
+		if err != nil {
+			return err
+		}
+		for _, link := range result.Items {
+			writePlainFields(ctx, fields, map[string]any{
+				"url":         link.URL,
+				"domain":      link.Domain,
+				"timestamp":   link.Timestamp,
+				"sender_name": link.SenderName,
+				"sender_id":   link.SenderID,
+				"message_id":  link.MessageID,
+				"count":       link.Count,
+			})
+		}
+		return nil
+	}
+
+	if len(result.Items) == 0 {
+		u.Out().Warn("No links found")
+	} else {
+		u.Out().Printf("Links (%d):", len(result.Items))
+		for _, link := range result.Items {
+			line := fmt.Sprintf("  [%s] %s: %s", shortTimestamp(link.Timestamp), link.SenderName, link.URL)
+			if link.Count > 1 {
+				line += fmt.Sprintf(" (×%d)", link.Count)
+			}
+			u.Out().Println(line)
+		}
+	}
+	if !result.Complete {
+		u.Out().Dim(sharedIncompleteMessage(result.Scanned, result.Pages))
+	}
+	return nil
+}
+
+// Run executes the chats media command.
+func (c *ChatsMediaCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	chatID := normalizeChatID(c.ChatID)
+
+	params, err := sharedParams(c.Since, c.Until, c.Dedupe, c.Limit, c.MaxPages)
+	if err != nil {
+		return err
+	}
+	for _, kind := range c.Kind {
+		kind = strings.ToLower(strings.TrimSpace(kind))
+		if !slices.Contains(attachmentKinds, kind) {
+			return errfmt.UsageError("invalid --kind %q (expected %s)", kind, strings.Join(attachmentKinds, "|"))
+		}
+		params.Kinds = append(params.Kinds, kind)
+	}
+
+	client, err := newClientFromFlags(ctx, flags)
+	if err != nil {
+		return err
+	}
+	result, err := client.Messages().Media(ctx, chatID, params)
+	if err != nil {
+		return err
+	}
+	if err := failIfEmpty(c.FailIfEmpty, len(result.Items), "attachments"); err != nil {
+		return err
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return writeJSON(ctx, result, "chats media")
+	}
+
+	if outfmt.IsTabular(ctx) {
+		fields, err := resolveFields(c.Fields, []string{"kind", "file_name", "mime_type", "file_size", "timestamp", "sender_name", "message_id", "src_url", "count"})
+		if err != nil {
+			return err
+		}
+		for _, media := range result.Items {
+			writePlainFields(ctx, fields, map[string]any{
+				"kind":        media.Kind,
+				"file_name":   media.FileName,
+				"mime_type":   media.MimeType,
+				"file_size":   media.FileSize,
+				"timestamp":   media.Timestamp,
+				"sender_name": media.SenderName,
+				"sender_id":   media.SenderID,
+				"message_id":  media.MessageID,
+				"src_url":     media.SrcURL,
+				"count":       media.Count,
+			})
+		}
+		return nil
+	}
+
+	if len(result.Items) == 0 {
+		u.Out().Warn("No attachments found")
+	} else {
+		u.Out().Printf("Attachments (%d):", len(result.Items))
+		for _, media := range result.Items {
+			name := media.FileName
+			if name == "" {
+				name = media.SrcURL
+			}
+			line := fmt.Sprintf("  [%s] %s: %s %s", shortTimestamp(media.Timestamp), media.SenderName, media.Kind, name)
+			if media.FileSize > 0 {
+				line += " (" + formatFileSize(media.FileSize) + ")"
+			}
+			if media.Count > 1 {
+				line += fmt.Sprintf(" ×%d", media.Count)
+			}
+			u.Out().Println(line)
+		}
+	}
+	if !result.Complete {
+		u.Out().Dim(sharedIncompleteMessage(result.Scanned, result.Pages))
+	}
+	return nil
+}
+
+// sharedParams validates the flags shared by chats links and chats media.
+func sharedParams(since, until string, dedupe bool, limit, maxPages int) (beeperapi.SharedParams, error) {
+	params := beeperapi.SharedParams{Dedupe: dedupe, Limit: limit, MaxPages: maxPages}
+	if limit < 0 {
+		return params, errfmt.UsageError("invalid --limit %d (expected >= 0)", limit)
+	}
+	if maxPages < 1 || maxPages > 1000 {
+		return params, errfmt.UsageError("invalid --max-pages %d (expected 1-1000)", maxPages)
+	}
+	if since != "" {
+		t, err := parseTime(since)
+		if err != nil {
+			return params, errfmt.UsageError("invalid --since %q (expected RFC3339 or duration)", since)
+		}
+		params.Since = &t
+	}
+	if until != "" {
+		t, err := parseTime(until)
+		if err != nil {
+			return params, errfmt.UsageError("invalid --until %q (expected RFC3339 or duration)", until)
+		}
+		params.Until = &t
+	}
+	if params.Since != nil && params.Until != nil && params.Until.Before(*params.Since) {
+		return params, errfmt.UsageError("--until must not be before --since")
+	}
+	return params, nil
+}
+
+func sharedIncompleteMessage(scanned, pages int) string {
+	return fmt.Sprintf("Stopped after the newest %d messages (%d pages); older history was not scanned. Raise --limit or --max-pages, or set --since.", scanned, pages)
+}
+
+// shortTimestamp formats an RFC3339 timestamp like the other human listings.
+func shortTimestamp(ts string) string {
+	t, err := time.Parse(time.RFC3339, ts)
+	if err != nil {
+		return ""
+	}
+	return t.Format("Jan 2 15:04")
+}
+
+// formatFileSize renders a byte count with a binary unit.
+func formatFileSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/johntheyoung/roadrunner/internal/fakeapi"
+)
+
+func TestChatsLinksAndMedia(t *testing.T) {
+	t.Setenv("BEEPER_TOKEN", "test-token")
+	t.Setenv("BEEPER_ACCESS_TOKEN", "")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	// A month of project chat, one message a day, spanning two pages.
+	ds := fakeapi.DefaultDataset()
+	base := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	add := func(day int, text string, atts ...fakeapi.Attachment) {
+		ds.Messages = append(ds.Messages, fakeapi.Message{
+			ID: fmt.Sprintf("$d%d", day), ChatID: "!team:beeper.local", SenderID: "@bob:beeper.local", SenderName: "Bob Example",
+			Timestamp: base.AddDate(0, 0, day), Text: text, Attachments: atts,
+		})
+	}
+	spec := fakeapi.Attachment{Type: "unknown", SrcURL: "mxc://beeper.local/spec", FileName: "spec.pdf", MimeType: "application/pdf", FileSize: 2048}
+	for day := 1; day <= 30; day++ {
+		switch day {
+		case 3:
+			add(day, "Spec draft: https://docs.google.com/document/d/abc/edit.", spec)
+		case 10:
+			add(day, "Mockups at www.figma.com/file/xyz and https://docs.google.com/document/d/abc/edit#heading")
+		case 12:
+			add(day, "", fakeapi.Attachment{Type: "img", SrcURL: "mxc://beeper.local/shot", FileName: "shot.png", MimeType: "image/png", FileSize: 100})
+		case 20:
+			add(day, "Re-sending (https://github.com/org/repo/pull/7)", spec)
+		case 25:
+			add(day, "", fakeapi.Attachment{Type: "audio", SrcURL: "mxc://beeper.local/memo", MimeType: "audio/ogg", IsVoiceNote: true})
+		default:
+			add(day, fmt.Sprintf("day %d standup", day))
+		}
+	}
+
+	fake := fakeapi.New(ds)
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	defer fake.Close()
+
+	run := func(args ...string) (string, string, int) {
+		t.Helper()
+		var out, errText string
+		var code int
+		withArgs(t, append([]string{"rr", "--base-url", server.URL}, args...), func() {
+			out, errText = captureOutput(t, func() {
+				code = Execute()
+			})
+		})
+		return out, errText, code
+	}
+	mustRun := func(args ...string) string {
+		t.Helper()
+		out, errText, code := run(args...)
+		if code != 0 {
+			t.Fatalf("%v: exit code = %d, stderr = %q", args, code, errText)
+		}
+		return out
+	}
+
+	type links struct {
+		Items []struct {
+			URL       string `json:"url"`
+			Domain    string `json:"domain"`
+			MessageID string `json:"message_id"`
+			Count     int    `json:"count"`
+		} `json:"items"`
+		Pages    int  `json:"pages"`
+		Complete bool `json:"complete"`
+	}
+	var all links
+	if err := json.Unmarshal([]byte(mustRun("--json", "chats", "links", "!team:beeper.local")), &all); err != nil {
+		t.Fatalf("decode links: %v", err)
+	}
+	var got []string
+	for _, item := range all.Items {
+		got = append(got, item.MessageID+" "+item.URL)
+	}
+	want := []string{
+		"$d20 https://github.com/org/repo/pull/7",
+		"$d10 https://www.figma.com/file/xyz",
+		"$d10 https://docs.google.com/document/d/abc/edit#heading",
+		"$d3 https://docs.google.com/document/d/abc/edit",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") || !all.Complete || all.Pages != 2 {
+		t.Fatalf("links = %q (pages %d, complete %v), want %q", got, all.Pages, all.Complete, want)
+	}
+
+	var deduped links
+	if err := json.Unmarshal([]byte(mustRun("--json", "chats", "links", "!team:beeper.local", "--dedupe", "--domain", "google.com")), &deduped); err != nil {
+		t.Fatalf("decode deduped links: %v", err)
+	}
+	if len(deduped.Items) != 1 || deduped.Items[0].MessageID != "$d10" || deduped.Items[0].Count != 2 || deduped.Items[0].Domain != "docs.google.com" {
+		t.Fatalf("deduped links = %+v", deduped.Items)
+	}
+
+	var ranged links
+	if err := json.Unmarshal([]byte(mustRun("--json", "chats", "links", "!team:beeper.local", "--since", "2026-01-05T00:00:00Z", "--until", "2026-01-15T00:00:00Z")), &ranged); err != nil {
+		t.Fatalf("decode ranged links: %v", err)
+	}
+	if len(ranged.Items) != 2 || ranged.Items[0].MessageID != "$d10" || !ranged.Complete {
+		t.Fatalf("ranged links = %+v", ranged)
+	}
+
+	csv := mustRun("--csv", "chats", "media", "!team:beeper.local", "--dedupe", "--fields", "kind,file_name,file_size,count")
+	wantCSV := "kind,file_name,file_size,count\nvoice,,0,1\nfile,spec.pdf,2048,2\nimage,shot.png,100,1\n"
+	if csv != wantCSV {
+		t.Fatalf("media csv = %q, want %q", csv, wantCSV)
+	}
+
+	var media struct {
+		Items []struct {
+			Kind      string `json:"kind"`
+			MessageID string `json:"message_id"`
+		} `json:"items"`
+		Complete bool `json:"complete"`
+	}
+	if err := json.Unmarshal([]byte(mustRun("--json", "chats", "media", "!team:beeper.local", "--kind", "file,image", "--limit", "2")), &media); err != nil {
+		t.Fatalf("decode media: %v", err)
+	}
+	if len(media.Items) != 2 || media.Items[0].MessageID != "$d20" || media.Items[1].Kind != "image" || media.Complete {
+		t.Fatalf("media --kind --limit = %+v", media)
+	}
+
+	for _, args := range [][]string{
+		{"chats", "media", "!team:beeper.local", "--kind", "pdf"},
+		{"chats", "links", "!team:beeper.local", "--since", "2026-02-01", "--until", "2026-01-01"},
+		{"chats", "links", "!team:beeper.local", "--max-pages", "0"},
+	} {
+		if _, _, code := run(args...); code != 2 {
+			t.Fatalf("%v exit code = %d, want 2", args, code)
+		}
+	}
+}
@@ -100,3 +100,53 @@ func TestChatsStartSendsStartModePayload(t *testing.T) {
 		t.Fatalf("user.email payload = %#v, want %q", user["email"], "alice@example.com")
 	}
 }
+
+func TestChatsStartNormalizesPhoneNumber(t *testing.T) {
+	t.Setenv("BEEPER_TOKEN", "test-token")
+	t.Setenv("BEEPER_ACCESS_TOKEN", "")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	var captured map[string]any
+	startCalled := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		startCalled++
+		if err := json.NewDecoder(r.Body).Decode(&captured); err != nil {
+			http.Error(w, "bad payload", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"chatID":"chat-1","status":"created"}`))
+	}))
+	defer server.Close()
+
+	ctx := outfmt.WithMode(context.Background(), outfmt.Mode{JSON: true})
+	flags := &RootFlags{BaseURL: server.URL, Timeout: 5, Region: "us"}
+	cmd := ChatsStartCmd{AccountID: "acc-1", PhoneNumber: "(415) 555-0101"}
+	if err := cmd.Run(ctx, flags); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	user, _ := captured["user"].(map[string]any)
+	if user["phoneNumber"] != "+14155550101" {
+		t.Fatalf("user.phoneNumber payload = %#v, want %q", user["phoneNumber"], "+14155550101")
+	}
+
+	for _, tc := range []struct {
+		number, region string
+	}{
+		{"call me maybe", "US"},
+		{"555-0100", "US"},
+		{"4155550101", ""},
+		{"+28 1234 5678", ""},
+		{"4155550101", "Narnia"},
+	} {
+		cmd := ChatsStartCmd{AccountID: "acc-1", PhoneNumber: tc.number}
+		err := cmd.Run(ctx, &RootFlags{BaseURL: server.URL, Timeout: 5, Region: tc.region})
+		var exitErr *errfmt.ExitError
+		if !errors.As(err, &exitErr) || exitErr.Code != errfmt.ExitUsageError {
+			t.Fatalf("Run(%q, region %q) error = %v, want usage error", tc.number, tc.region, err)
+		}
+	}
+	if startCalled != 1 {
+		t.Fatalf("start endpoint calls = %d, want 1 (invalid numbers must not reach /start)", startCalled)
+	}
+}
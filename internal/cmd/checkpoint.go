@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/johntheyoung/roadrunner/internal/errfmt"
+)
+
+const pageCheckpointVersion = 1
+
+// pageCheckpoint persists --all progress for --checkpoint: the cursor to
+// resume from and the keys of items already emitted. Items collected for
+// buffered output but not yet written are kept as Pending so a failed run
+// loses nothing.
+type pageCheckpoint struct {
+	Version   int               `json:"version"`
+	Command   string            `json:"command"`
+	Scope     []string          `json:"scope"`
+	Cursor    string            `json:"cursor,omitempty"`
+	Pages     int               `json:"pages"`
+	Emitted   []string          `json:"emitted"`
+	Pending   []json.RawMessage `json:"pending,omitempty"`
+	UpdatedAt string            `json:"updated_at"`
+
+	path string
+	seen map[string]struct{}
+}
+
+// openPageCheckpoint loads the checkpoint at path, or starts a new one when
+// the file does not exist yet. scope captures the command's filters; a
+// checkpoint written for a different command or scope is rejected rather
+// than silently mixing result sets. It returns nil when path is empty.
+func openPageCheckpoint(path string, all bool, command string, scope ...string) (*pageCheckpoint, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, nil
+	}
+	if !all {
+		return nil, errfmt.UsageError("--checkpoint requires --all")
+	}
+
+	cp := &pageCheckpoint{
+		Version: pageCheckpointVersion,
+		Command: command,
+		Scope:   append([]string{}, scope...),
+		Emitted: []string{},
+		path:    path,
+		seen:    map[string]struct{}{},
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cp, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read checkpoint: %w", err)
+	}
+
+	var saved pageCheckpoint
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, errfmt.UsageError("invalid --checkpoint %s: %v", path, err)
+	}
+	if saved.Version != pageCheckpointVersion {
+		return nil, errfmt.UsageError("invalid --checkpoint %s: unsupported version %d", path, saved.Version)
+	}
+	if saved.Command != command || !slices.Equal(saved.Scope, scope) {
+		return nil, errfmt.UsageError("--checkpoint %s belongs to %q with different filters; delete it or use another file", path, saved.Command)
+	}
+	saved.path = path
+	saved.seen = make(map[string]struct{}, len(saved.Emitted))
+	for _, key := range saved.Emitted {
+		saved.seen[key] = struct{}{}
+	}
+	if saved.Emitted == nil {
+		saved.Emitted = []string{}
+	}
+	return &saved, nil
+}
+
+// resumeCursor returns the cursor to start from: the saved cursor when
+// resuming, otherwise fallback (--cursor).
+func (cp *pageCheckpoint) resumeCursor(fallback string) string {
+	if cp == nil || cp.Cursor == "" {
+		return fallback
+	}
+	return cp.Cursor
+}
+
+// seenKey reports whether key was already emitted or is pending.
+func (cp *pageCheckpoint) seenKey(key string) bool {
+	_, ok := cp.seen[key]
+	return ok
+}
+
+// markSeen records key so later pages skip duplicates.
+func (cp *pageCheckpoint) markSeen(key string) {
+	cp.seen[key] = struct{}{}
+}
+
+// save writes the checkpoint atomically.
+func (cp *pageCheckpoint) save() error {
+	cp.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode checkpoint: %w", err)
+	}
+
+	dir := filepath.Dir(cp.path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("create checkpoint dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(cp.path)+".*")
+	if err != nil {
+		return fmt.Errorf("write checkpoint: %w", err)
+	}
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("write checkpoint: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), cp.path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("write checkpoint: %w", err)
+	}
+	return nil
+}
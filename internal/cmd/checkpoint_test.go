@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/johntheyoung/roadrunner/internal/beeperapi"
+	"github.com/johntheyoung/roadrunner/internal/outfmt"
+)
+
+// flakyChatsServer serves total chats two per page and fails the page at
+// failCursor while *failing is set.
+func flakyChatsServer(t *testing.T, total int, failCursor string, failing *bool, cursors *[]string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chats" {
+			http.NotFound(w, r)
+			return
+		}
+		cursor := r.URL.Query().Get("cursor")
+		*cursors = append(*cursors, cursor)
+		if *failing && cursor == failCursor {
+			w.Header().Set("X-Should-Retry", "false")
+			http.Error(w, `{"message":"timeout"}`, http.StatusBadRequest)
+			return
+		}
+		start := 0
+		if cursor != "" {
+			start, _ = strconv.Atoi(strings.TrimPrefix(cursor, "c"))
+		}
+		end := min(start+2, total)
+		items := make([]string, 0, 2)
+		for i := start; i < end; i++ {
+			items = append(items, fmt.Sprintf(`{"id":"!chat%d:beeper.local","accountID":"acc1","participants":{"hasMore":false,"items":[],"total":0},"title":"Chat %d","type":"group","unreadCount":0}`, i, i))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"items":[%s],"hasMore":%t,"oldestCursor":"c%d","newestCursor":""}`, strings.Join(items, ","), end < total, end)
+	}))
+}
+
+func TestChatsListCheckpointResumesStream(t *testing.T) {
+	t.Setenv("BEEPER_TOKEN", "test-token")
+	t.Setenv("BEEPER_ACCESS_TOKEN", "")
+
+	failing := true
+	var cursors []string
+	server := flakyChatsServer(t, 5, "c4", &failing, &cursors)
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "chats.checkpoint.json")
+	ctx := testStreamContext(t, outfmt.Mode{JSONL: true})
+	flags := &RootFlags{BaseURL: server.URL, Timeout: 5}
+	cmd := ChatsListCmd{All: true, Checkpoint: path}
+
+	out, _ := captureOutput(t, func() {
+		if err := cmd.Run(ctx, flags); err == nil {
+			t.Fatal("expected first run to fail")
+		}
+	})
+	if got := strings.Count(out, `"id"`); got != 4 {
+		t.Fatalf("first run items = %d, want 4\noutput: %s", got, out)
+	}
+
+	failing = false
+	cursors = nil
+	out, _ = captureOutput(t, func() {
+		if err := cmd.Run(ctx, flags); err != nil {
+			t.Fatalf("resume Run() error = %v", err)
+		}
+	})
+	if strings.TrimSpace(out) == "" || strings.Count(out, `"id"`) != 1 || !strings.Contains(out, "!chat4:beeper.local") {
+		t.Fatalf("resume output = %s", out)
+	}
+	if len(cursors) != 1 || cursors[0] != "c4" {
+		t.Fatalf("resume cursors = %#v, want [c4]", cursors)
+	}
+
+	var cp pageCheckpoint
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read checkpoint: %v", err)
+	}
+	if err := json.Unmarshal(data, &cp); err != nil {
+		t.Fatalf("decode checkpoint: %v", err)
+	}
+	if len(cp.Emitted) != 5 || len(cp.Pending) != 0 || cp.Command != "chats list" {
+		t.Fatalf("checkpoint = %+v", cp)
+	}
+}
+
+func TestChatsListCheckpointKeepsBufferedItems(t *testing.T) {
+	t.Setenv("BEEPER_TOKEN", "test-token")
+	t.Setenv("BEEPER_ACCESS_TOKEN", "")
+
+	failing := true
+	var cursors []string
+	server := flakyChatsServer(t, 5, "c4", &failing, &cursors)
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "chats.checkpoint.json")
+	ctx := testJSONContext(t)
+	flags := &RootFlags{BaseURL: server.URL, Timeout: 5}
+	cmd := ChatsListCmd{All: true, Checkpoint: path}
+
+	captureOutput(t, func() {
+		if err := cmd.Run(ctx, flags); err == nil {
+			t.Fatal("expected first run to fail")
+		}
+	})
+
+	failing = false
+	out, _ := captureOutput(t, func() {
+		if err := cmd.Run(ctx, flags); err != nil {
+			t.Fatalf("resume Run() error = %v", err)
+		}
+	})
+	var resp beeperapi.ChatListResult
+	if err := json.Unmarshal([]byte(out), &resp); err != nil {
+		t.Fatalf("unmarshal output: %v\noutput: %s", err, out)
+	}
+	if len(resp.Items) != 5 || resp.Items[0].ID != "!chat0:beeper.local" || resp.Items[4].ID != "!chat4:beeper.local" {
+		t.Fatalf("items = %#v", resp.Items)
+	}
+
+	// A rerun only returns items that were not emitted before.
+	out, _ = captureOutput(t, func() {
+		if err := cmd.Run(ctx, flags); err != nil {
+			t.Fatalf("rerun Run() error = %v", err)
+		}
+	})
+	if err := json.Unmarshal([]byte(out), &resp); err != nil {
+		t.Fatalf("unmarshal rerun output: %v\noutput: %s", err, out)
+	}
+	if len(resp.Items) != 0 {
+		t.Fatalf("rerun items = %#v, want none", resp.Items)
+	}
+}
+
+func TestOpenPageCheckpointRejectsOtherScope(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cp.json")
+	cp, err := openPageCheckpoint(path, true, "messages list", "!a:beeper.local", "before")
+	if err != nil {
+		t.Fatalf("openPageCheckpoint() error = %v", err)
+	}
+	if err := cp.save(); err != nil {
+		t.Fatalf("save() error = %v", err)
+	}
+
+	if _, err := openPageCheckpoint(path, true, "messages list", "!b:beeper.local", "before"); err == nil {
+		t.Fatal("expected scope mismatch error")
+	}
+	if _, err := openPageCheckpoint(path, false, "messages list", "!a:beeper.local", "before"); err == nil {
+		t.Fatal("expected --checkpoint requires --all error")
+	}
+	if cp, err := openPageCheckpoint("", false, "messages list"); err != nil || cp != nil {
+		t.Fatalf("empty path = %v, %v; want nil, nil", cp, err)
+	}
+}
+
+func TestChatsListCheckpointScopesTags(t *testing.T) {
+	t.Setenv("BEEPER_TOKEN", "test-token")
+	t.Setenv("BEEPER_ACCESS_TOKEN", "")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	failing := false
+	var cursors []string
+	server := flakyChatsServer(t, 3, "", &failing, &cursors)
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "chats.checkpoint.json")
+	ctx := testJSONContext(t)
+	flags := &RootFlags{BaseURL: server.URL, Timeout: 5}
+	run := func(tags ...string) error {
+		t.Helper()
+		cmd := ChatsListCmd{All: true, Checkpoint: path, Tags: tags}
+		var err error
+		captureOutput(t, func() {
+			err = cmd.Run(ctx, flags)
+		})
+		return err
+	}
+
+	if err := run("work", "family"); err != nil {
+		t.Fatalf("first Run() error = %v", err)
+	}
+	if err := run("family", "work"); err != nil {
+		t.Fatalf("reordered tags Run() error = %v", err)
+	}
+	if err := run(); err == nil || !strings.Contains(err.Error(), "different filters") {
+		t.Fatalf("untagged Run() error = %v, want scope mismatch", err)
+	}
+}
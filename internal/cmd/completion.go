@@ -33,17 +33,22 @@ _rr_completions() {
     cur="${COMP_WORDS[COMP_CWORD]}"
     prev="${COMP_WORDS[COMP_CWORD-1]}"
 
-    commands="auth connect events accounts contacts assets chats messages reminders search status unread focus doctor version describe capabilities completion"
+    commands="auth connect events accounts contacts people assets chats messages reminders search status unread focus doctor dev cache version describe capabilities completion"
     auth_cmds="set status clear"
     connect_cmds="info"
-    events_cmds="tail"
+    events_cmds="tail record replay"
     accounts_cmds="list alias"
     accounts_alias_cmds="set list unset"
-    contacts_cmds="list search resolve"
-    assets_cmds="download serve upload upload-base64"
-    chats_cmds="list search resolve get create start archive"
-    messages_cmds="list search send send-file edit react unreact tail wait context"
+    contacts_cmds="list search resolve export import"
+    people_cmds="search show list link unlink"
+    assets_cmds="download serve sync upload upload-base64"
+    chats_cmds="list search resolve get links media create start archive alias tag note"
+    chats_tag_cmds="add remove list"
+    chats_note_cmds="set"
+    messages_cmds="list search send send-file edit react unreact tail wait context thread"
     reminders_cmds="set clear"
+    dev_cmds="fake-server"
+    cache_cmds="stats clear"
 
     case "${prev}" in
         rr)
@@ -70,6 +75,10 @@ _rr_completions() {
             COMPREPLY=( $(compgen -W "${contacts_cmds}" -- "${cur}") )
             return 0
             ;;
+        people)
+            COMPREPLY=( $(compgen -W "${people_cmds}" -- "${cur}") )
+            return 0
+            ;;
         assets)
             COMPREPLY=( $(compgen -W "${assets_cmds}" -- "${cur}") )
             return 0
@@ -82,6 +91,14 @@ _rr_completions() {
             COMPREPLY=( $(compgen -W "${chats_cmds}" -- "${cur}") )
             return 0
             ;;
+        tag)
+            COMPREPLY=( $(compgen -W "${chats_tag_cmds}" -- "${cur}") )
+            return 0
+            ;;
+        note)
+            COMPREPLY=( $(compgen -W "${chats_note_cmds}" -- "${cur}") )
+            return 0
+            ;;
         messages)
             COMPREPLY=( $(compgen -W "${messages_cmds}" -- "${cur}") )
             return 0
@@ -90,6 +107,14 @@ _rr_completions() {
             COMPREPLY=( $(compgen -W "${reminders_cmds}" -- "${cur}") )
             return 0
             ;;
+        dev)
+            COMPREPLY=( $(compgen -W "${dev_cmds}" -- "${cur}") )
+            return 0
+            ;;
+        cache)
+            COMPREPLY=( $(compgen -W "${cache_cmds}" -- "${cur}") )
+            return 0
+            ;;
         completion)
             COMPREPLY=( $(compgen -W "bash zsh fish" -- "${cur}") )
             return 0
@@ -111,6 +136,7 @@ _rr() {
         'events:Manage websocket live events (experimental)'
         'accounts:Manage messaging accounts'
         'contacts:Search contacts'
+        'people:Find people across networks'
         'assets:Manage assets'
         'chats:Manage chats'
         'messages:Manage messages'
@@ -120,6 +146,8 @@ _rr() {
         'unread:List unread chats'
         'focus:Focus Beeper Desktop app'
         'doctor:Diagnose configuration and connectivity'
+        'dev:Developer tools (fake API server)'
+        'cache:Inspect and clear the local API cache'
         'version:Show version information'
         'describe:Describe command schema for runtime introspection'
         'capabilities:Show CLI capabilities for agent discovery'
@@ -150,7 +178,18 @@ _rr() {
     contacts_cmds=(
         'list:List contacts on an account'
         'search:Search contacts on an account'
-        'resolve:Resolve a contact by exact match'
+        'resolve:Resolve a contact by exact, prefix, or fuzzy match'
+        'export:Export contacts as vCard, CSV, or JSONL'
+        'import:Start a direct chat for each card in a vCard file'
+    )
+
+    local -a people_cmds
+    people_cmds=(
+        'search:Find people across accounts, linking contacts by email, phone, or username'
+        'show:Show contacts, direct chats, and recent messages across networks'
+        'list:List people saved with manual links'
+        'link:Link a contact to a person (stored locally)'
+        'unlink:Unlink a contact from a person (stored locally)'
     )
 
     local -a connect_cmds
@@ -161,12 +200,15 @@ _rr() {
     local -a events_cmds
     events_cmds=(
         'tail:Follow live websocket events'
+        'record:Record raw websocket frames to a JSONL file'
+        'replay:Serve a recording through a local /v1/ws endpoint'
     )
 
     local -a assets_cmds
     assets_cmds=(
         'download:Download an asset by mxc:// URL'
         'serve:Stream an asset by URL (raw bytes)'
+        'sync:Download chat attachments into a deduplicated local tree'
         'upload:Upload an asset and return upload ID'
         'upload-base64:Upload base64 data and return upload ID'
     )
@@ -175,11 +217,16 @@ _rr() {
     chats_cmds=(
         'list:List chats'
         'search:Search chats'
-        'resolve:Resolve a chat by exact match'
+        'resolve:Resolve a chat by exact, prefix, or fuzzy match'
         'get:Get chat details'
+        'links:List URLs shared in a chat'
+        'media:List attachments shared in a chat'
         'create:Create a new chat'
         'start:Resolve/create a direct chat from merged contact data'
         'archive:Archive or unarchive a chat'
+        'alias:Manage chat aliases'
+        'tag:Manage local chat tags'
+        'note:Manage local chat notes'
     )
 
     local -a messages_cmds
@@ -194,6 +241,7 @@ _rr() {
         'tail:Follow messages in a chat'
         'wait:Wait for a matching message'
         'context:Fetch context around a message'
+        'thread:Show the reply thread containing a message'
     )
 
     local -a reminders_cmds
@@ -202,6 +250,17 @@ _rr() {
         'clear:Clear a reminder from a chat'
     )
 
+    local -a dev_cmds
+    dev_cmds=(
+        'fake-server:Serve an in-memory fake Desktop API for testing'
+    )
+
+    local -a cache_cmds
+    cache_cmds=(
+        'stats:Show cached entries and hit rates per resource'
+        'clear:Remove cached entries'
+    )
+
     local -a completion_cmds
     completion_cmds=(
         'bash:Generate bash completions'
@@ -235,6 +294,9 @@ _rr() {
                 contacts)
                     _describe -t commands 'contacts commands' contacts_cmds
                     ;;
+                people)
+                    _describe -t commands 'people commands' people_cmds
+                    ;;
                 assets)
                     _describe -t commands 'assets commands' assets_cmds
                     ;;
@@ -247,6 +309,12 @@ _rr() {
                 reminders)
                     _describe -t commands 'reminders commands' reminders_cmds
                     ;;
+                dev)
+                    _describe -t commands 'dev commands' dev_cmds
+                    ;;
+                cache)
+                    _describe -t commands 'cache commands' cache_cmds
+                    ;;
                 completion)
                     _describe -t commands 'completion commands' completion_cmds
                     ;;
@@ -276,6 +344,7 @@ complete -c rr -n '__fish_use_subcommand' -a 'connect' -d 'Discover Connect serv
 complete -c rr -n '__fish_use_subcommand' -a 'events' -d 'Manage websocket live events (experimental)'
 complete -c rr -n '__fish_use_subcommand' -a 'accounts' -d 'Manage messaging accounts'
 complete -c rr -n '__fish_use_subcommand' -a 'contacts' -d 'Search contacts'
+complete -c rr -n '__fish_use_subcommand' -a 'people' -d 'Find people across networks'
 complete -c rr -n '__fish_use_subcommand' -a 'assets' -d 'Manage assets'
 complete -c rr -n '__fish_use_subcommand' -a 'chats' -d 'Manage chats'
 complete -c rr -n '__fish_use_subcommand' -a 'messages' -d 'Manage messages'
@@ -285,6 +354,8 @@ complete -c rr -n '__fish_use_subcommand' -a 'status' -d 'Show chat and unread s
 complete -c rr -n '__fish_use_subcommand' -a 'unread' -d 'List unread chats'
 complete -c rr -n '__fish_use_subcommand' -a 'focus' -d 'Focus Beeper Desktop app'
 complete -c rr -n '__fish_use_subcommand' -a 'doctor' -d 'Diagnose configuration and connectivity'
+complete -c rr -n '__fish_use_subcommand' -a 'dev' -d 'Developer tools (fake API server)'
+complete -c rr -n '__fish_use_subcommand' -a 'cache' -d 'Inspect and clear the local API cache'
 complete -c rr -n '__fish_use_subcommand' -a 'version' -d 'Show version information'
 complete -c rr -n '__fish_use_subcommand' -a 'describe' -d 'Describe command schema for runtime introspection'
 complete -c rr -n '__fish_use_subcommand' -a 'capabilities' -d 'Show CLI capabilities for agent discovery'
@@ -304,15 +375,34 @@ complete -c rr -n '__fish_seen_subcommand_from connect' -a 'info' -d 'Show Conne
 
 # events subcommands
 complete -c rr -n '__fish_seen_subcommand_from events' -a 'tail' -d 'Follow live websocket events'
+complete -c rr -n '__fish_seen_subcommand_from events' -a 'record' -d 'Record raw websocket frames to a JSONL file'
+complete -c rr -n '__fish_seen_subcommand_from events' -a 'replay' -d 'Serve a recording through a local /v1/ws endpoint'
 
 # contacts subcommands
 complete -c rr -n '__fish_seen_subcommand_from contacts' -a 'list' -d 'List contacts on an account'
 complete -c rr -n '__fish_seen_subcommand_from contacts' -a 'search' -d 'Search contacts on an account'
-complete -c rr -n '__fish_seen_subcommand_from contacts' -a 'resolve' -d 'Resolve a contact by exact match'
+complete -c rr -n '__fish_seen_subcommand_from contacts' -a 'resolve' -d 'Resolve a contact by exact, prefix, or fuzzy match'
+complete -c rr -n '__fish_seen_subcommand_from contacts' -a 'export' -d 'Export contacts as vCard, CSV, or JSONL'
+complete -c rr -n '__fish_seen_subcommand_from contacts' -a 'import' -d 'Start a direct chat for each card in a vCard file'
+complete -c rr -n '__fish_seen_subcommand_from contacts; and __fish_seen_subcommand_from resolve' -l match -r -a 'exact prefix fuzzy' -d 'Match mode'
+complete -c rr -n '__fish_seen_subcommand_from contacts; and __fish_seen_subcommand_from export' -l format -r -a 'vcf csv jsonl' -d 'Export format'
+complete -c rr -n '__fish_seen_subcommand_from contacts; and __fish_seen_subcommand_from export' -l out -r -F -d 'Write to a file instead of stdout'
+complete -c rr -n '__fish_seen_subcommand_from contacts; and __fish_seen_subcommand_from export' -l avatars -d 'Embed contact avatars'
+complete -c rr -n '__fish_seen_subcommand_from contacts; and __fish_seen_subcommand_from import' -l account-id -r -d 'Account to start chats on'
+complete -c rr -n '__fish_seen_subcommand_from contacts; and __fish_seen_subcommand_from import' -l stop-on-failure -d 'Stop at the first failed card'
+
+# people subcommands
+complete -c rr -n '__fish_seen_subcommand_from people' -a 'search' -d 'Find people across accounts, linking contacts by email, phone, or username'
+complete -c rr -n '__fish_seen_subcommand_from people' -a 'show' -d 'Show contacts, direct chats, and recent messages across networks'
+complete -c rr -n '__fish_seen_subcommand_from people' -a 'list' -d 'List people saved with manual links'
+complete -c rr -n '__fish_seen_subcommand_from people' -a 'link' -d 'Link a contact to a person (stored locally)'
+complete -c rr -n '__fish_seen_subcommand_from people' -a 'unlink' -d 'Unlink a contact from a person (stored locally)'
+complete -c rr -n '__fish_seen_subcommand_from people; and __fish_seen_subcommand_from search show link' -l match -r -a 'exact prefix fuzzy' -d 'Match mode'
 
 # assets subcommands
 complete -c rr -n '__fish_seen_subcommand_from assets' -a 'download' -d 'Download an asset by mxc:// URL'
 complete -c rr -n '__fish_seen_subcommand_from assets' -a 'serve' -d 'Stream an asset by URL (raw bytes)'
+complete -c rr -n '__fish_seen_subcommand_from assets' -a 'sync' -d 'Download chat attachments into a deduplicated local tree'
 complete -c rr -n '__fish_seen_subcommand_from assets' -a 'upload' -d 'Upload an asset and return upload ID'
 complete -c rr -n '__fish_seen_subcommand_from assets' -a 'upload-base64' -d 'Upload base64 data and return upload ID'
 
@@ -325,14 +415,42 @@ complete -c rr -n '__fish_seen_subcommand_from accounts; and __fish_seen_subcomm
 complete -c rr -n '__fish_seen_subcommand_from accounts; and __fish_seen_subcommand_from alias' -a 'list' -d 'List account aliases'
 complete -c rr -n '__fish_seen_subcommand_from accounts; and __fish_seen_subcommand_from alias' -a 'unset' -d 'Remove an account alias'
 
+# chats alias subcommands
+complete -c rr -n '__fish_seen_subcommand_from chats; and __fish_seen_subcommand_from alias' -a 'set' -d 'Create or update a chat alias'
+complete -c rr -n '__fish_seen_subcommand_from chats; and __fish_seen_subcommand_from alias' -a 'list' -d 'List chat aliases'
+complete -c rr -n '__fish_seen_subcommand_from chats; and __fish_seen_subcommand_from alias' -a 'unset' -d 'Remove a chat alias'
+
+# chats tag/note subcommands
+complete -c rr -n '__fish_seen_subcommand_from chats; and __fish_seen_subcommand_from tag' -a 'add' -d 'Add local tags to a chat'
+complete -c rr -n '__fish_seen_subcommand_from chats; and __fish_seen_subcommand_from tag' -a 'remove' -d 'Remove local tags from a chat'
+complete -c rr -n '__fish_seen_subcommand_from chats; and __fish_seen_subcommand_from tag' -a 'list' -d 'List local tags and the chats carrying them'
+complete -c rr -n '__fish_seen_subcommand_from chats; and __fish_seen_subcommand_from note' -a 'set' -d 'Set or clear a local chat note'
+
+# local tag filters
+complete -c rr -n '__fish_seen_subcommand_from chats; and __fish_seen_subcommand_from list search' -l tag -r -d 'Only chats carrying this local tag'
+complete -c rr -n '__fish_seen_subcommand_from unread status' -l tag -r -d 'Only chats carrying this local tag'
+
 # chats subcommands
 complete -c rr -n '__fish_seen_subcommand_from chats' -a 'list' -d 'List chats'
 complete -c rr -n '__fish_seen_subcommand_from chats' -a 'search' -d 'Search chats'
-complete -c rr -n '__fish_seen_subcommand_from chats' -a 'resolve' -d 'Resolve a chat by exact match'
+complete -c rr -n '__fish_seen_subcommand_from chats' -a 'resolve' -d 'Resolve a chat by exact, prefix, or fuzzy match'
+complete -c rr -n '__fish_seen_subcommand_from chats; and __fish_seen_subcommand_from resolve' -l match -r -a 'exact prefix fuzzy' -d 'Match mode'
 complete -c rr -n '__fish_seen_subcommand_from chats' -a 'get' -d 'Get chat details'
+complete -c rr -n '__fish_seen_subcommand_from chats' -a 'links' -d 'List URLs shared in a chat'
+complete -c rr -n '__fish_seen_subcommand_from chats' -a 'media' -d 'List attachments shared in a chat'
+complete -c rr -n '__fish_seen_subcommand_from chats; and __fish_seen_subcommand_from links media' -l since -r -d 'Only items shared at or after time'
+complete -c rr -n '__fish_seen_subcommand_from chats; and __fish_seen_subcommand_from links media' -l until -r -d 'Only items shared at or before time'
+complete -c rr -n '__fish_seen_subcommand_from chats; and __fish_seen_subcommand_from links media' -l dedupe -d 'List each link or file once with a share count'
+complete -c rr -n '__fish_seen_subcommand_from chats; and __fish_seen_subcommand_from links media' -l limit -r -d 'Stop after this many items'
+complete -c rr -n '__fish_seen_subcommand_from chats; and __fish_seen_subcommand_from links media' -l max-pages -r -d 'Maximum pages of chat history to scan'
+complete -c rr -n '__fish_seen_subcommand_from chats; and __fish_seen_subcommand_from links' -l domain -r -d 'Only links on this domain'
+complete -c rr -n '__fish_seen_subcommand_from chats; and __fish_seen_subcommand_from media' -l kind -r -a 'image video audio file gif sticker voice' -d 'Only attachments of this kind'
 complete -c rr -n '__fish_seen_subcommand_from chats' -a 'create' -d 'Create a new chat'
 complete -c rr -n '__fish_seen_subcommand_from chats' -a 'start' -d 'Resolve/create a direct chat from merged contact data'
 complete -c rr -n '__fish_seen_subcommand_from chats' -a 'archive' -d 'Archive or unarchive a chat'
+complete -c rr -n '__fish_seen_subcommand_from chats' -a 'alias' -d 'Manage chat aliases'
+complete -c rr -n '__fish_seen_subcommand_from chats' -a 'tag' -d 'Manage local chat tags'
+complete -c rr -n '__fish_seen_subcommand_from chats' -a 'note' -d 'Manage local chat notes'
 
 # messages subcommands
 complete -c rr -n '__fish_seen_subcommand_from messages' -a 'list' -d 'List messages in a chat'
@@ -345,9 +463,30 @@ complete -c rr -n '__fish_seen_subcommand_from messages' -a 'unreact' -d 'Remove
 complete -c rr -n '__fish_seen_subcommand_from messages' -a 'tail' -d 'Follow messages in a chat'
 complete -c rr -n '__fish_seen_subcommand_from messages' -a 'wait' -d 'Wait for a matching message'
 complete -c rr -n '__fish_seen_subcommand_from messages' -a 'context' -d 'Fetch context around a message'
+complete -c rr -n '__fish_seen_subcommand_from messages' -a 'thread' -d 'Show the reply thread containing a message'
+
+# messages list/search/thread/context flags
+complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcommand_from list' -l contains -r -d 'Only messages containing text'
+complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcommand_from list' -l regex -r -d 'Only messages matching a regular expression'
+complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcommand_from list' -l sender -r -d 'Only messages from a sender ID or name'
+complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcommand_from list' -l since -r -d 'Only messages at or after time'
+complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcommand_from list' -l until -r -d 'Only messages at or before time'
+complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcommand_from list' -l has-attachment -d 'Only messages with attachments'
+complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcommand_from list' -l attachment-type -r -a 'image video audio file gif sticker voice' -d 'Only messages with this attachment type'
+complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcommand_from list' -l from-me -d 'Only messages sent by you'
+complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcommand_from list' -l from-others -d 'Only messages sent by others'
+complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcommand_from list' -l unread -d 'Only unread messages'
+complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcommand_from list' -l limit -r -d 'Stop after this many matching messages'
+complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcommand_from list search' -l hydrate-replies -d 'Embed the message each reply points to'
+complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcommand_from context' -l message-id -r -d 'Anchor on this message ID'
+complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcommand_from context' -l at -r -d 'Anchor on the newest message at or before this time'
+complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcommand_from thread context' -l max-pages -r -d 'Maximum pages of chat history to scan'
 
 # messages send flags
 complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcommand_from send' -l chat -d 'Exact chat title/display name or ID (alternative to chatID arg)'
+complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcommand_from send' -l match -r -a 'exact prefix fuzzy' -d 'How --chat matches chat names'
+complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcommand_from send' -l person -r -d 'Person to message across networks'
+complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcommand_from send' -l prefer-network -r -d 'With --person, prefer this network or account ID'
 complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcommand_from send' -l reply-to -d 'Message ID to reply to'
 complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcommand_from send' -l text-file -d 'Read message text from file'
 complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcommand_from send' -l stdin -d 'Read message text from stdin'
@@ -361,6 +500,7 @@ complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcomm
 
 # messages send-file flags
 complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcommand_from send-file' -l chat -d 'Exact chat title/display name or ID (alternative to chatID arg)'
+complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcommand_from send-file' -l match -r -a 'exact prefix fuzzy' -d 'How --chat matches chat names'
 complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcommand_from send-file' -l reply-to -d 'Message ID to reply to'
 complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcommand_from send-file' -l text-file -d 'Read message text from file'
 complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcommand_from send-file' -l stdin -d 'Read message text from stdin'
@@ -372,15 +512,30 @@ complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcomm
 complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcommand_from send-file' -l attachment-duration -d 'Attachment duration override in seconds'
 complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcommand_from send-file' -l attachment-width -d 'Attachment width override in pixels'
 complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcommand_from send-file' -l attachment-height -d 'Attachment height override in pixels'
+complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcommand_from send-file' -l strip-metadata -d 'Remove EXIF/GPS, XMP, and comments from JPEGs before upload'
+complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcommand_from send-file' -l no-detect -d 'Do not detect MIME type, dimensions, duration, or voice notes'
 
 # messages edit flags
 complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcommand_from edit' -l chat -d 'Exact chat title/display name or ID (alternative to chatID arg)'
+complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcommand_from edit' -l match -r -a 'exact prefix fuzzy' -d 'How --chat matches chat names'
 complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcommand_from edit' -l text-file -d 'Read replacement text from file'
 complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcommand_from edit' -l stdin -d 'Read replacement text from stdin'
 
+# assets sync flags
+complete -c rr -n '__fish_seen_subcommand_from assets; and __fish_seen_subcommand_from sync' -l all-chats -d 'Sync every chat'
+complete -c rr -n '__fish_seen_subcommand_from assets; and __fish_seen_subcommand_from sync' -l account-ids -d 'With --all-chats, only chats on these accounts'
+complete -c rr -n '__fish_seen_subcommand_from assets; and __fish_seen_subcommand_from sync' -l dest -d 'Root directory of the media tree'
+complete -c rr -n '__fish_seen_subcommand_from assets; and __fish_seen_subcommand_from sync' -l workers -d 'Parallel downloads (1-16)'
+complete -c rr -n '__fish_seen_subcommand_from assets; and __fish_seen_subcommand_from sync' -l mime -d 'Only MIME types matching these patterns'
+complete -c rr -n '__fish_seen_subcommand_from assets; and __fish_seen_subcommand_from sync' -l min-size -d 'Only files at least this large'
+complete -c rr -n '__fish_seen_subcommand_from assets; and __fish_seen_subcommand_from sync' -l max-size -d 'Only files at most this large'
+complete -c rr -n '__fish_seen_subcommand_from assets; and __fish_seen_subcommand_from sync' -l since -d 'Only attachments sent at or after time'
+complete -c rr -n '__fish_seen_subcommand_from assets; and __fish_seen_subcommand_from sync' -l until -d 'Only attachments sent at or before time'
+
 # assets upload flags
 complete -c rr -n '__fish_seen_subcommand_from assets; and __fish_seen_subcommand_from upload' -l file-name -d 'Filename override for upload metadata'
 complete -c rr -n '__fish_seen_subcommand_from assets; and __fish_seen_subcommand_from upload' -l mime-type -d 'MIME type override for upload metadata'
+complete -c rr -n '__fish_seen_subcommand_from assets; and __fish_seen_subcommand_from upload' -l strip-metadata -d 'Remove EXIF/GPS, XMP, and comments from JPEGs before upload'
 
 # assets upload-base64 flags
 complete -c rr -n '__fish_seen_subcommand_from assets; and __fish_seen_subcommand_from upload-base64' -l content-file -d 'Read base64 content from file'
@@ -391,10 +546,16 @@ complete -c rr -n '__fish_seen_subcommand_from assets; and __fish_seen_subcomman
 # chats pagination flags
 complete -c rr -n '__fish_seen_subcommand_from chats; and __fish_seen_subcommand_from list' -l all -d 'Fetch all pages automatically'
 complete -c rr -n '__fish_seen_subcommand_from chats; and __fish_seen_subcommand_from list' -l max-items -d 'Maximum items to collect with --all'
+complete -c rr -n '__fish_seen_subcommand_from chats; and __fish_seen_subcommand_from list' -l max-pages -d 'Maximum pages to fetch with --all'
+complete -c rr -n '__fish_seen_subcommand_from chats; and __fish_seen_subcommand_from list' -l checkpoint -r -F -d 'Resume --all from a checkpoint file'
 complete -c rr -n '__fish_seen_subcommand_from chats; and __fish_seen_subcommand_from search' -l all -d 'Fetch all pages automatically'
 complete -c rr -n '__fish_seen_subcommand_from chats; and __fish_seen_subcommand_from search' -l max-items -d 'Maximum items to collect with --all'
+complete -c rr -n '__fish_seen_subcommand_from chats; and __fish_seen_subcommand_from search' -l max-pages -d 'Maximum pages to fetch with --all'
+complete -c rr -n '__fish_seen_subcommand_from chats; and __fish_seen_subcommand_from search' -l checkpoint -r -F -d 'Resume --all from a checkpoint file'
 complete -c rr -n '__fish_seen_subcommand_from contacts; and __fish_seen_subcommand_from list' -l all -d 'Fetch all pages automatically'
 complete -c rr -n '__fish_seen_subcommand_from contacts; and __fish_seen_subcommand_from list' -l max-items -d 'Maximum items to collect with --all'
+complete -c rr -n '__fish_seen_subcommand_from contacts; and __fish_seen_subcommand_from list' -l max-pages -d 'Maximum pages to fetch with --all'
+complete -c rr -n '__fish_seen_subcommand_from contacts; and __fish_seen_subcommand_from list' -l checkpoint -r -F -d 'Resume --all from a checkpoint file'
 
 # chats get flags
 complete -c rr -n '__fish_seen_subcommand_from chats; and __fish_seen_subcommand_from get' -l max-participant-count -d 'Maximum participants to return'
@@ -402,8 +563,12 @@ complete -c rr -n '__fish_seen_subcommand_from chats; and __fish_seen_subcommand
 # messages pagination flags
 complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcommand_from list' -l all -d 'Fetch all pages automatically'
 complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcommand_from list' -l max-items -d 'Maximum items to collect with --all'
+complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcommand_from list' -l max-pages -d 'Maximum pages to fetch with --all'
+complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcommand_from list' -l checkpoint -r -F -d 'Resume --all from a checkpoint file'
 complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcommand_from search' -l all -d 'Fetch all pages automatically'
 complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcommand_from search' -l max-items -d 'Maximum items to collect with --all'
+complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcommand_from search' -l max-pages -d 'Maximum pages to fetch with --all'
+complete -c rr -n '__fish_seen_subcommand_from messages; and __fish_seen_subcommand_from search' -l checkpoint -r -F -d 'Resume --all from a checkpoint file'
 
 # global search message pagination flags
 complete -c rr -n '__fish_seen_subcommand_from search' -l messages-cursor -d 'Cursor for message results pagination'
@@ -411,6 +576,7 @@ complete -c rr -n '__fish_seen_subcommand_from search' -l messages-direction -d
 complete -c rr -n '__fish_seen_subcommand_from search' -l messages-limit -d 'Max messages per page when paging (1-20)'
 complete -c rr -n '__fish_seen_subcommand_from search' -l messages-all -d 'Fetch all message pages automatically'
 complete -c rr -n '__fish_seen_subcommand_from search' -l messages-max-items -d 'Maximum message items to collect with --messages-all'
+complete -c rr -n '__fish_seen_subcommand_from search' -l checkpoint -r -F -d 'Resume --messages-all from a checkpoint file'
 
 # reminders subcommands
 complete -c rr -n '__fish_seen_subcommand_from reminders' -a 'set' -d 'Set a reminder for a chat'
@@ -418,7 +584,20 @@ complete -c rr -n '__fish_seen_subcommand_from reminders' -a 'clear' -d 'Clear a
 
 # reminders flags
 complete -c rr -n '__fish_seen_subcommand_from reminders; and __fish_seen_subcommand_from set' -l chat -d 'Exact chat title/display name or ID (alternative to chatID arg)'
+complete -c rr -n '__fish_seen_subcommand_from reminders; and __fish_seen_subcommand_from set' -l match -r -a 'exact prefix fuzzy' -d 'How --chat matches chat names'
 complete -c rr -n '__fish_seen_subcommand_from reminders; and __fish_seen_subcommand_from clear' -l chat -d 'Exact chat title/display name or ID (alternative to chatID arg)'
+complete -c rr -n '__fish_seen_subcommand_from reminders; and __fish_seen_subcommand_from clear' -l match -r -a 'exact prefix fuzzy' -d 'How --chat matches chat names'
+
+# dev subcommands
+complete -c rr -n '__fish_seen_subcommand_from dev' -a 'fake-server' -d 'Serve an in-memory fake Desktop API for testing'
+complete -c rr -n '__fish_seen_subcommand_from dev; and __fish_seen_subcommand_from fake-server' -l seed -r -F -d 'JSON dataset to seed the server with'
+complete -c rr -n '__fish_seen_subcommand_from dev; and __fish_seen_subcommand_from fake-server' -l listen -d 'Listen address'
+complete -c rr -n '__fish_seen_subcommand_from dev; and __fish_seen_subcommand_from fake-server' -l token -d 'Require this bearer token'
+
+# cache subcommands
+complete -c rr -n '__fish_seen_subcommand_from cache' -a 'stats' -d 'Show cached entries and hit rates per resource'
+complete -c rr -n '__fish_seen_subcommand_from cache' -a 'clear' -d 'Remove cached entries'
+complete -c rr -n '__fish_seen_subcommand_from cache; and __fish_seen_subcommand_from clear' -l resource -r -a 'accounts chats contacts' -d 'Only clear this resource'
 
 # completion subcommands
 complete -c rr -n '__fish_seen_subcommand_from completion' -a 'bash' -d 'Generate bash completions'
@@ -429,14 +608,27 @@ complete -c rr -n '__fish_seen_subcommand_from completion' -a 'fish' -d 'Generat
 complete -c rr -l help -s h -d 'Show help'
 complete -c rr -l json -d 'Output JSON to stdout'
 complete -c rr -l jsonl -d 'Output JSON Lines (one JSON object per line)'
+complete -c rr -l stream -d 'Output JSON Lines as pages arrive, with a pagination trailer'
+complete -c rr -l query -r -d 'Filter JSON output with a jq-style expression'
+complete -c rr -l template -r -d 'Render JSON output with a Go text/template'
 complete -c rr -l plain -d 'Output stable TSV to stdout'
+complete -c rr -l csv -d 'Output RFC 4180 CSV with a header row'
+complete -c rr -l yaml -d 'Output YAML'
+complete -c rr -l markdown -d 'Output a Markdown table'
 complete -c rr -l verbose -s v -d 'Enable debug logging'
 complete -c rr -l force -s f -d 'Skip confirmations'
 complete -c rr -l timeout -d 'Timeout for API calls in seconds'
+complete -c rr -l concurrency -r -d 'Maximum parallel API requests (1-16)'
 complete -c rr -l base-url -d 'API base URL'
 complete -c rr -l agent -d 'Agent profile mode'
 complete -c rr -l account -d 'Default account ID'
+complete -c rr -l region -r -d 'Country for phone numbers without a country code'
 complete -c rr -l request-id -d 'Optional request ID for envelope metadata'
 complete -c rr -l dedupe-window -d 'Duplicate non-idempotent write window (e.g. 10m)'
+complete -c rr -l record -r -a '(__fish_complete_directories)' -d 'Record API traffic to a cassette directory'
+complete -c rr -l replay -r -a '(__fish_complete_directories)' -d 'Replay API traffic from a cassette directory'
+complete -c rr -l no-cache -d 'Bypass the local API cache'
+complete -c rr -l refresh -d 'Ignore cached entries and refetch'
+complete -c rr -l cache-ttl -r -d 'Per-resource cache TTLs (e.g. chats=30s;accounts=1h)'
 complete -c rr -l version -d 'Show version and exit'
 `
@@ -20,7 +20,7 @@ type ConnectCmd struct {
 
 // ConnectInfoCmd retrieves server metadata from GET /v1/info.
 type ConnectInfoCmd struct {
-	Fields []string `help:"Comma-separated list of fields for --plain output" name:"fields" sep:","`
+	Fields []string `help:"Comma-separated list of fields for --plain, --csv, --yaml, or --markdown output" name:"fields" sep:","`
 }
 
 // Run executes the connect info command.
@@ -33,7 +33,7 @@ func (c *ConnectInfoCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	timeout := time.Duration(flags.Timeout) * time.Second
-	client, err := beeperapi.NewClient(token, flags.BaseURL, timeout)
+	client, err := newAPIClient(ctx, token, flags.BaseURL, timeout)
 	if err != nil {
 		return err
 	}
@@ -53,12 +53,12 @@ func (c *ConnectInfoCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return writeJSON(ctx, info, "connect info")
 	}
 
-	if outfmt.IsPlain(ctx) {
+	if outfmt.IsTabular(ctx) {
 		fields, err := resolveFields(c.Fields, []string{"name", "version", "runtime", "oauth_introspect_url", "ws_url"})
 		if err != nil {
 			return err
 		}
-		writePlainFields(u, fields, map[string]string{
+		writePlainFields(ctx, fields, map[string]any{
 			"name":                 info.Name,
 			"version":              info.Version,
 			"runtime":              info.Runtime,
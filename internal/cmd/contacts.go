@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"strings"
 	"text/tabwriter"
 	"time"
 
@@ -19,7 +18,9 @@ import (
 type ContactsCmd struct {
 	List    ContactsListCmd    `cmd:"" help:"List contacts on an account"`
 	Search  ContactsSearchCmd  `cmd:"" help:"Search contacts on an account"`
-	Resolve ContactsResolveCmd `cmd:"" help:"Resolve a contact by exact match"`
+	Resolve ContactsResolveCmd `cmd:"" help:"Resolve a contact by exact, prefix, or fuzzy match"`
+	Export  ContactsExportCmd  `cmd:"" help:"Export contacts from one or all accounts as vCard, CSV, or JSONL"`
+	Import  ContactsImportCmd  `cmd:"" help:"Start a direct chat for each card in a vCard file"`
 }
 
 // ContactsListCmd lists contacts within an account.
@@ -29,8 +30,10 @@ type ContactsListCmd struct {
 	Cursor        string   `help:"Pagination cursor"`
 	Direction     string   `help:"Pagination direction: before|after" enum:"before,after," default:""`
 	All           bool     `help:"Fetch all pages automatically" name:"all"`
-	MaxItems      int      `help:"Maximum items to collect with --all (default 500, max 5000)" name:"max-items" default:"0"`
-	Fields        []string `help:"Comma-separated list of fields for --plain output" name:"fields" sep:","`
+	MaxItems      int      `help:"Maximum items to collect with --all (default 500, max 5000; unlimited when streaming)" name:"max-items" default:"0"`
+	MaxPages      int      `help:"Maximum pages to fetch with --all (default 1000 when streaming, otherwise unlimited)" name:"max-pages" default:"0"`
+	Checkpoint    string   `help:"Resume --all from a checkpoint file, saved after each page" name:"checkpoint" type:"path"`
+	Fields        []string `help:"Comma-separated list of fields for --plain, --csv, --yaml, or --markdown output" name:"fields" sep:","`
 	FailIfEmpty   bool     `help:"Exit with code 1 if no results" name:"fail-if-empty"`
 }
 
@@ -39,26 +42,28 @@ type ContactsSearchCmd struct {
 	AccountID     string   `arg:"" optional:"" name:"accountID" help:"Account ID or alias"`
 	Query         string   `arg:"" optional:"" help:"Search query"`
 	AccountIDFlag string   `help:"Account ID to search (uses --account default if omitted)" name:"account-id"`
-	Fields        []string `help:"Comma-separated list of fields for --plain output" name:"fields" sep:","`
+	Fields        []string `help:"Comma-separated list of fields for --plain, --csv, --yaml, or --markdown output" name:"fields" sep:","`
 	FailIfEmpty   bool     `help:"Exit with code 1 if no results" name:"fail-if-empty"`
 }
 
-// ContactsResolveCmd resolves a contact by exact match.
+// ContactsResolveCmd resolves a contact by exact, prefix, or fuzzy match.
 type ContactsResolveCmd struct {
 	AccountID     string   `arg:"" optional:"" name:"accountID" help:"Account ID or alias"`
-	Query         string   `arg:"" optional:"" help:"Exact contact name, username, email, phone, or ID"`
+	Query         string   `arg:"" optional:"" help:"Contact name, username, email, phone, or ID"`
 	AccountIDFlag string   `help:"Account ID to search (uses --account default if omitted)" name:"account-id"`
-	Fields        []string `help:"Comma-separated list of fields for --plain output" name:"fields" sep:","`
+	Match         string   `help:"Match mode: exact|prefix|fuzzy (names and usernames only; IDs, emails, and phones stay exact)" name:"match" enum:"exact,prefix,fuzzy" default:"exact"`
+	Fields        []string `help:"Comma-separated list of fields for --plain, --csv, --yaml, or --markdown output" name:"fields" sep:","`
 }
 
 // Run executes the contacts list command.
 func (c *ContactsListCmd) Run(ctx context.Context, flags *RootFlags) error {
 	u := ui.FromContext(ctx)
 
-	autoPageLimit, err := resolveAutoPageLimit(c.All, c.MaxItems)
+	limits, err := resolveAutoPageLimits(ctx, c.All, c.MaxItems, c.MaxPages)
 	if err != nil {
 		return err
 	}
+	autoPageLimit := limits.MaxItems
 
 	accountID := resolveAccount(c.AccountIDFlag, flags.Account)
 	if accountID == "" {
@@ -74,15 +79,23 @@ func (c *ContactsListCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	timeout := time.Duration(flags.Timeout) * time.Second
-	client, err := beeperapi.NewClient(token, flags.BaseURL, timeout)
+	client, err := newAPIClient(ctx, token, flags.BaseURL, timeout)
 	if err != nil {
 		return err
 	}
 
-	resp, err := client.Accounts().ListContacts(ctx, accountID, beeperapi.ContactListParams{
-		Cursor:    c.Cursor,
-		Direction: c.Direction,
-	})
+	listPage := func(cursor string) (beeperapi.ContactListResult, error) {
+		return client.Accounts().ListContacts(ctx, accountID, beeperapi.ContactListParams{
+			Cursor:    cursor,
+			Direction: c.Direction,
+		})
+	}
+	checkpoint, err := openPageCheckpoint(c.Checkpoint, c.All, "contacts list", accountID, c.Direction)
+	if err != nil {
+		return err
+	}
+	startCursor := checkpoint.resumeCursor(c.Cursor)
+	resp, err := listPage(startCursor)
 	if err != nil {
 		if beeperapi.IsUnsupportedRoute(err, "GET", "/contacts/list") {
 			return fmt.Errorf("contacts list is not supported by this Beeper Desktop API version (requires a newer Beeper Desktop build)")
@@ -90,84 +103,94 @@ func (c *ContactsListCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 	capped := false
+	pages := 1
+	streamed := 0
 	if c.All {
-		items := make([]beeperapi.Contact, 0, len(resp.Items))
-		items = append(items, resp.Items...)
-		lastCursor := c.Cursor
-
-		for resp.HasMore {
-			if limitReached(len(items), autoPageLimit) {
-				capped = true
-				break
-			}
-
-			nextCursor := nextSearchCursor(c.Direction, resp.OldestCursor, resp.NewestCursor)
-			if nextCursor == "" || nextCursor == lastCursor {
-				break
-			}
-			lastCursor = nextCursor
-
-			page, err := client.Accounts().ListContacts(ctx, accountID, beeperapi.ContactListParams{
-				Cursor:    nextCursor,
-				Direction: c.Direction,
-			})
-			if err != nil {
-				return err
-			}
-			items = append(items, page.Items...)
-			resp.HasMore = page.HasMore
-			resp.OldestCursor = page.OldestCursor
-			resp.NewestCursor = page.NewestCursor
+		pager := autoPager[beeperapi.Contact]{
+			Limits:     limits,
+			Cursor:     startCursor,
+			Key:        func(item beeperapi.Contact) string { return item.ID },
+			Checkpoint: checkpoint,
+			Prefetch:   fetchConcurrency(ctx, flags) > 1,
+			Fetch: func(cursor string) (autoPage[beeperapi.Contact], error) {
+				page, err := listPage(cursor)
+				if err != nil {
+					return autoPage[beeperapi.Contact]{}, err
+				}
+				resp.HasMore = page.HasMore
+				resp.OldestCursor = page.OldestCursor
+				resp.NewestCursor = page.NewestCursor
+				return autoPage[beeperapi.Contact]{
+					Items:   page.Items,
+					HasMore: page.HasMore,
+					Next:    nextSearchCursor(c.Direction, page.OldestCursor, page.NewestCursor),
+				}, nil
+			},
 		}
-		if limitReached(len(items), autoPageLimit) {
-			if len(items) > autoPageLimit {
-				items = items[:autoPageLimit]
-			}
-			capped = true
+		res, err := pager.run(ctx, autoPage[beeperapi.Contact]{
+			Items:   resp.Items,
+			HasMore: resp.HasMore,
+			Next:    nextSearchCursor(c.Direction, resp.OldestCursor, resp.NewestCursor),
+		})
+		if err != nil {
+			return err
 		}
-		resp.Items = items
+		capped, pages, streamed = res.Capped, res.Pages, res.Count
+		resp.Items = res.Items
 		if capped {
 			resp.HasMore = true
 		}
 	}
 
+	pagination := &outfmt.EnvelopePagination{
+		HasMore:      resp.HasMore,
+		Direction:    c.Direction,
+		OldestCursor: resp.OldestCursor,
+		NewestCursor: resp.NewestCursor,
+		AutoPaged:    c.All,
+		Capped:       capped,
+	}
+	if c.All {
+		pagination.MaxItems = autoPageLimit
+		pagination.Pages = pages
+		pagination.MaxPages = limits.MaxPages
+	}
+
+	if limits.Stream {
+		if err := writeStreamTrailer(ctx, "contacts list", streamed, pagination); err != nil {
+			return err
+		}
+		return failIfEmpty(c.FailIfEmpty, streamed, "contacts")
+	}
+
 	if err := failIfEmpty(c.FailIfEmpty, len(resp.Items), "contacts"); err != nil {
 		return err
 	}
 
 	if outfmt.IsJSONL(ctx) {
-		return writeJSONLines(resp.Items)
+		if err := writeJSONLines(ctx, resp.Items); err != nil {
+			return err
+		}
+		return writeStreamTrailer(ctx, "contacts list", len(resp.Items), pagination)
 	}
 
 	if outfmt.IsJSON(ctx) {
-		maxItems := 0
-		if c.All {
-			maxItems = autoPageLimit
-		}
-		return writeJSONWithPagination(ctx, resp, "contacts list", &outfmt.EnvelopePagination{
-			HasMore:      resp.HasMore,
-			Direction:    c.Direction,
-			OldestCursor: resp.OldestCursor,
-			NewestCursor: resp.NewestCursor,
-			AutoPaged:    c.All,
-			Capped:       capped,
-			MaxItems:     maxItems,
-		})
+		return writeJSONWithPagination(ctx, resp, "contacts list", pagination)
 	}
 
-	if outfmt.IsPlain(ctx) {
+	if outfmt.IsTabular(ctx) {
 		fields, err := resolveFields(c.Fields, []string{"id", "full_name", "username", "phone_number", "email", "cannot_message"})
 		if err != nil {
 			return err
 		}
 		for _, item := range resp.Items {
-			writePlainFields(u, fields, map[string]string{
+			writePlainFields(ctx, fields, map[string]any{
 				"id":             item.ID,
 				"full_name":      item.FullName,
 				"username":       item.Username,
 				"phone_number":   item.PhoneNumber,
 				"email":          item.Email,
-				"cannot_message": formatBool(item.CannotMessage),
+				"cannot_message": item.CannotMessage,
 			})
 		}
 		return nil
@@ -235,7 +258,7 @@ func (c *ContactsSearchCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	timeout := time.Duration(flags.Timeout) * time.Second
-	client, err := beeperapi.NewClient(token, flags.BaseURL, timeout)
+	client, err := newAPIClient(ctx, token, flags.BaseURL, timeout)
 	if err != nil {
 		return err
 	}
@@ -250,7 +273,7 @@ func (c *ContactsSearchCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	if outfmt.IsJSONL(ctx) {
-		return writeJSONLines(resp)
+		return writeJSONLines(ctx, resp)
 	}
 
 	// JSON output
@@ -259,18 +282,18 @@ func (c *ContactsSearchCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	// Plain output (TSV)
-	if outfmt.IsPlain(ctx) {
+	if outfmt.IsTabular(ctx) {
 		fields, err := resolveFields(c.Fields, []string{"id", "full_name", "username", "phone_number", "cannot_message"})
 		if err != nil {
 			return err
 		}
 		for _, item := range resp {
-			writePlainFields(u, fields, map[string]string{
+			writePlainFields(ctx, fields, map[string]any{
 				"id":             item.ID,
 				"full_name":      item.FullName,
 				"username":       item.Username,
 				"phone_number":   item.PhoneNumber,
-				"cannot_message": formatBool(item.CannotMessage),
+				"cannot_message": item.CannotMessage,
 			})
 		}
 		return nil
@@ -326,62 +349,48 @@ func (c *ContactsResolveCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return errfmt.UsageError("account ID and query are required")
 	}
 
-	token, _, err := config.GetToken()
+	region, err := phoneRegion(flags)
 	if err != nil {
 		return err
 	}
 
-	timeout := time.Duration(flags.Timeout) * time.Second
-	client, err := beeperapi.NewClient(token, flags.BaseURL, timeout)
+	token, _, err := config.GetToken()
 	if err != nil {
 		return err
 	}
 
-	resp, err := client.Accounts().SearchContacts(ctx, accountID, query)
+	timeout := time.Duration(flags.Timeout) * time.Second
+	client, err := newAPIClient(ctx, token, flags.BaseURL, timeout)
 	if err != nil {
 		return err
 	}
 
-	matches := make([]beeperapi.Contact, 0, 1)
-	seen := make(map[string]struct{})
-	for i, item := range resp {
-		if contactExactMatch(item, query) {
-			key := contactKey(item)
-			if key == "" {
-				key = fmt.Sprintf("idx:%d", i)
-			}
-			if _, ok := seen[key]; ok {
-				continue
-			}
-			seen[key] = struct{}{}
-			matches = append(matches, item)
-		}
-	}
-
-	if len(matches) == 0 {
-		return errfmt.WithCode(fmt.Errorf("no contact matched %q", query), errfmt.ExitFailure)
-	}
-	if len(matches) > 1 {
-		return errfmt.WithCode(fmt.Errorf("multiple contacts matched %q", query), errfmt.ExitFailure)
+	contact, err := client.Accounts().MatchContact(ctx, beeperapi.ContactResolveParams{
+		AccountID: accountID,
+		Query:     query,
+		Match:     c.Match,
+		Region:    region,
+	})
+	if err != nil {
+		return matchErrorCode(err)
 	}
-	contact := matches[0]
 
 	if outfmt.IsJSON(ctx) {
 		return writeJSON(ctx, contact, "contacts resolve")
 	}
 
-	if outfmt.IsPlain(ctx) {
+	if outfmt.IsTabular(ctx) {
 		fields, err := resolveFields(c.Fields, []string{"id", "full_name", "username", "phone_number", "email", "cannot_message"})
 		if err != nil {
 			return err
 		}
-		writePlainFields(u, fields, map[string]string{
+		writePlainFields(ctx, fields, map[string]any{
 			"id":             contact.ID,
 			"full_name":      contact.FullName,
 			"username":       contact.Username,
 			"phone_number":   contact.PhoneNumber,
 			"email":          contact.Email,
-			"cannot_message": formatBool(contact.CannotMessage),
+			"cannot_message": contact.CannotMessage,
 		})
 		return nil
 	}
@@ -410,39 +419,3 @@ func (c *ContactsResolveCmd) Run(ctx context.Context, flags *RootFlags) error {
 
 	return nil
 }
-
-func contactExactMatch(contact beeperapi.Contact, query string) bool {
-	q := strings.TrimSpace(query)
-	if q == "" {
-		return false
-	}
-	if strings.EqualFold(contact.ID, q) {
-		return true
-	}
-	if strings.EqualFold(contact.FullName, q) {
-		return true
-	}
-	if strings.EqualFold(contact.Username, q) {
-		return true
-	}
-	if strings.EqualFold(contact.Email, q) {
-		return true
-	}
-	if strings.EqualFold(contact.PhoneNumber, q) {
-		return true
-	}
-	return false
-}
-
-func contactKey(contact beeperapi.Contact) string {
-	if contact.ID != "" {
-		return "id:" + strings.ToLower(contact.ID)
-	}
-	parts := []string{
-		strings.ToLower(strings.TrimSpace(contact.FullName)),
-		strings.ToLower(strings.TrimSpace(contact.Username)),
-		strings.ToLower(strings.TrimSpace(contact.Email)),
-		strings.ToLower(strings.TrimSpace(contact.PhoneNumber)),
-	}
-	return strings.Join(parts, "|")
-}
@@ -0,0 +1,383 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/johntheyoung/roadrunner/internal/beeperapi"
+	"github.com/johntheyoung/roadrunner/internal/errfmt"
+	"github.com/johntheyoung/roadrunner/internal/outfmt"
+	"github.com/johntheyoung/roadrunner/internal/phone"
+	"github.com/johntheyoung/roadrunner/internal/ui"
+	"github.com/johntheyoung/roadrunner/internal/vcard"
+)
+
+// maxAvatarBytes caps avatars embedded by contacts export --avatars.
+const maxAvatarBytes = 1 << 20
+
+// ContactsExportCmd exports contacts from one or all accounts.
+type ContactsExportCmd struct {
+	AccountIDs []string `help:"Account IDs to export (default: all accounts)" name:"account-ids"`
+	Format     string   `help:"Export format: vcf|csv|jsonl" enum:"vcf,csv,jsonl" default:"vcf"`
+	Out        string   `help:"Write to this file instead of stdout (required with --json or --plain)" name:"out" type:"path"`
+	Avatars    bool     `help:"Embed contact avatars as data: URIs (vcf and jsonl; up to 1 MiB each)" name:"avatars"`
+}
+
+// exportedContact is a contact with the account it was exported from.
+type exportedContact struct {
+	AccountID string `json:"account_id"`
+	Network   string `json:"network,omitempty"`
+	beeperapi.Contact
+	Photo string `json:"photo,omitempty"`
+}
+
+// Run executes the contacts export command.
+func (c *ContactsExportCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+
+	if (outfmt.IsJSON(ctx) || outfmt.IsTabular(ctx)) && c.Out == "" {
+		return errfmt.UsageError("--out is required with --json or --plain for contacts export")
+	}
+
+	client, err := newClientFromFlags(ctx, flags)
+	if err != nil {
+		return err
+	}
+	accounts, err := client.Accounts().List(ctx)
+	if err != nil {
+		return err
+	}
+	networks := make(map[string]string, len(accounts))
+	for _, acct := range accounts {
+		networks[acct.ID] = acct.Network
+	}
+	accountIDs := applyAccountDefault(c.AccountIDs, flags.Account)
+	if len(accountIDs) == 0 {
+		for _, acct := range accounts {
+			accountIDs = append(accountIDs, acct.ID)
+		}
+	}
+
+	pages, err := beeperapi.FanOut(ctx, fetchConcurrency(ctx, flags), accountIDs, func(ctx context.Context, accountID string) ([]beeperapi.Contact, error) {
+		var contacts []beeperapi.Contact
+		for contact, err := range client.Accounts().AllContacts(ctx, accountID, beeperapi.ContactListParams{}) {
+			if err != nil {
+				return nil, err
+			}
+			contacts = append(contacts, contact)
+		}
+		return contacts, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var contacts []exportedContact
+	avatars, avatarsSkipped := 0, 0
+	for i, page := range pages {
+		for _, contact := range page {
+			ec := exportedContact{AccountID: accountIDs[i], Network: networks[accountIDs[i]], Contact: contact}
+			if c.Avatars && c.Format != "csv" && contact.ImgURL != "" {
+				if photo, err := avatarDataURI(ctx, client, contact.ImgURL); err == nil {
+					ec.Photo = photo
+					avatars++
+				} else {
+					avatarsSkipped++
+				}
+			}
+			contacts = append(contacts, ec)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := encodeContacts(&buf, c.Format, contacts); err != nil {
+		return err
+	}
+	if c.Out == "" {
+		_, err := os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.Out), 0o755); err != nil {
+		return fmt.Errorf("create dir: %w", err)
+	}
+	if err := os.WriteFile(c.Out, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("write %s: %w", c.Out, err)
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return writeJSON(ctx, map[string]any{
+			"out":             c.Out,
+			"format":          c.Format,
+			"accounts":        accountIDs,
+			"contacts":        len(contacts),
+			"avatars":         avatars,
+			"avatars_skipped": avatarsSkipped,
+		}, "contacts export")
+	}
+
+	if outfmt.IsTabular(ctx) {
+		writePlainRow(ctx, []string{"out", "format", "count"}, c.Out, c.Format, len(contacts))
+		return nil
+	}
+
+	u.Out().Successf("Exported %d contacts from %d accounts to %s", len(contacts), len(accountIDs), c.Out)
+	if c.Avatars {
+		u.Out().Printf("Avatars: %d embedded, %d skipped", avatars, avatarsSkipped)
+	}
+	return nil
+}
+
+// encodeContacts writes contacts in format (vcf|csv|jsonl).
+func encodeContacts(w io.Writer, format string, contacts []exportedContact) error {
+	switch format {
+	case "csv":
+		cw := csv.NewWriter(w)
+		_ = cw.Write([]string{"account_id", "network", "id", "full_name", "username", "email", "phone_number", "cannot_message", "img_url"})
+		for _, c := range contacts {
+			_ = cw.Write([]string{c.AccountID, c.Network, c.ID, c.FullName, c.Username, c.Email, c.PhoneNumber, formatBool(c.CannotMessage), c.ImgURL})
+		}
+		cw.Flush()
+		return cw.Error()
+	case "jsonl":
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		for _, c := range contacts {
+			if err := enc.Encode(c); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	cards := make([]vcard.Card, 0, len(contacts))
+	for _, c := range contacts {
+		card := vcard.Card{
+			FullName:  c.FullName,
+			Username:  c.Username,
+			AccountID: c.AccountID,
+			ContactID: c.ID,
+			Photo:     c.Photo,
+		}
+		if card.FullName == "" {
+			for _, v := range []string{c.Username, c.PhoneNumber, c.Email, c.ID} {
+				if card.FullName == "" {
+					card.FullName = v
+				}
+			}
+		}
+		if c.PhoneNumber != "" {
+			card.Phones = []string{c.PhoneNumber}
+		}
+		if c.Email != "" {
+			card.Emails = []string{c.Email}
+		}
+		cards = append(cards, card)
+	}
+	return vcard.Write(w, cards...)
+}
+
+// avatarDataURI fetches an avatar and encodes it as a data: URI.
+func avatarDataURI(ctx context.Context, client *beeperapi.Client, url string) (string, error) {
+	var buf bytes.Buffer
+	served, err := client.Assets().Serve(ctx, url, &buf)
+	if err != nil {
+		return "", err
+	}
+	if buf.Len() == 0 || buf.Len() > maxAvatarBytes {
+		return "", fmt.Errorf("avatar size %d outside 1..%d bytes", buf.Len(), maxAvatarBytes)
+	}
+	mediaType, _, err := mime.ParseMediaType(served.ContentType)
+	if err != nil || !strings.HasPrefix(mediaType, "image/") {
+		mediaType = "image/jpeg"
+	}
+	return "data:" + mediaType + ";base64," + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// ContactsImportCmd starts a direct chat for each card in a vCard file.
+type ContactsImportCmd struct {
+	File          string `arg:"" help:"vCard file to import ('-' for stdin)"`
+	AccountID     string `help:"Account ID or alias to import into (uses --account default if omitted)" name:"account-id"`
+	AllowInvite   *bool  `help:"Allow invite-based DM creation when required by the platform" name:"allow-invite"`
+	StopOnFailure bool   `help:"Stop at the first card that fails instead of reporting it and continuing" name:"stop-on-failure"`
+}
+
+// contactImportResult is the outcome of importing one card.
+type contactImportResult struct {
+	Card        int    `json:"card"`
+	Name        string `json:"name,omitempty"`
+	PhoneNumber string `json:"phone_number,omitempty"`
+	Email       string `json:"email,omitempty"`
+	Username    string `json:"username,omitempty"`
+	Status      string `json:"status"` // planned|created|existing|skipped|failed
+	ChatID      string `json:"chat_id,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// Run executes the contacts import command.
+func (c *ContactsImportCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+
+	accountID := resolveAccount(c.AccountID, flags.Account)
+	if accountID == "" {
+		return errfmt.UsageError("--account-id is required")
+	}
+	cards, err := readVCards(c.File)
+	if err != nil {
+		return err
+	}
+	if len(cards) == 0 {
+		return errfmt.UsageError("no vCards found in %s", c.File)
+	}
+	region, err := phoneRegion(flags)
+	if err != nil {
+		return err
+	}
+
+	results := make([]contactImportResult, len(cards))
+	users := make([]beeperapi.ChatStartUser, len(cards))
+	for i, card := range cards {
+		users[i] = beeperapi.ChatStartUser{FullName: strings.TrimSpace(card.FullName), Username: strings.TrimSpace(card.Username)}
+		var phoneErr error
+		for _, number := range card.Phones {
+			e164, err := phone.Normalize(number, region)
+			if err == nil {
+				users[i].PhoneNumber, phoneErr = e164, nil
+				break
+			}
+			if phoneErr == nil {
+				phoneErr = err
+			}
+		}
+		if len(card.Emails) > 0 {
+			users[i].Email = card.Emails[0]
+		}
+		// Contact IDs only carry over into the account they came from.
+		if card.AccountID == accountID {
+			users[i].ID = card.ContactID
+		}
+		results[i] = contactImportResult{
+			Card:        i + 1,
+			Name:        users[i].FullName,
+			PhoneNumber: users[i].PhoneNumber,
+			Email:       users[i].Email,
+			Username:    users[i].Username,
+			Status:      "planned",
+		}
+		if users[i].ID == "" && users[i].PhoneNumber == "" && users[i].Email == "" && users[i].Username == "" {
+			results[i].Status = "skipped"
+			results[i].Error = "no phone number, email, or username"
+			if phoneErr != nil {
+				results[i].Error = phoneErr.Error()
+			}
+		}
+	}
+
+	if handled, err := handleDryRunWrite(ctx, flags, "contacts import", map[string]any{
+		"account_id":   accountID,
+		"file":         c.File,
+		"allow_invite": c.AllowInvite,
+		"cards":        results,
+	}); handled {
+		return err
+	}
+
+	client, err := newClientFromFlags(ctx, flags)
+	if err != nil {
+		return err
+	}
+	for i := range results {
+		if results[i].Status == "skipped" {
+			continue
+		}
+		started, err := client.Chats().Start(ctx, beeperapi.ChatStartParams{
+			AccountID:   accountID,
+			User:        users[i],
+			AllowInvite: c.AllowInvite,
+		})
+		if err != nil {
+			if c.StopOnFailure {
+				return fmt.Errorf("card %d (%s): %w", i+1, results[i].Name, err)
+			}
+			results[i].Status, results[i].Error = "failed", errfmt.Format(err)
+			continue
+		}
+		results[i].ChatID = started.ChatID
+		results[i].Status = started.Status
+		if results[i].Status == "" {
+			results[i].Status = "created"
+		}
+	}
+
+	counts := map[string]int{}
+	for _, r := range results {
+		counts[r.Status]++
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return writeJSON(ctx, map[string]any{
+			"account_id": accountID,
+			"cards":      len(results),
+			"created":    counts["created"],
+			"existing":   counts["existing"],
+			"skipped":    counts["skipped"],
+			"failed":     counts["failed"],
+			"results":    results,
+		}, "contacts import")
+	}
+
+	if outfmt.IsTabular(ctx) {
+		for _, r := range results {
+			writePlainRow(ctx, []string{"card", "status", "name", "chat_id", "error"}, r.Card, r.Status, r.Name, r.ChatID, r.Error)
+		}
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	u.Out().Printf("Imported %d cards into %s:", len(results), accountID)
+	for _, r := range results {
+		detail := r.ChatID
+		if r.Error != "" {
+			detail = r.Error
+		}
+		if _, err := fmt.Fprintf(w, "  %d\t%s\t%s\t%s\n", r.Card, r.Name, r.Status, detail); err != nil {
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	u.Out().Printf("Created: %d  Existing: %d  Skipped: %d  Failed: %d", counts["created"], counts["existing"], counts["skipped"], counts["failed"])
+	if counts["failed"] > 0 {
+		return errfmt.WithCode(fmt.Errorf("%d of %d cards failed", counts["failed"], len(results)), errfmt.ExitFailure)
+	}
+	return nil
+}
+
+// readVCards parses the cards in path, or stdin for "-".
+func readVCards(path string) ([]vcard.Card, error) {
+	if path == "-" {
+		return vcard.Parse(os.Stdin)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	cards, err := vcard.Parse(f)
+	if err != nil {
+		return nil, errfmt.UsageError("parse %s: %v", path, err)
+	}
+	return cards, nil
+}
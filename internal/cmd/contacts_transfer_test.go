@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/johntheyoung/roadrunner/internal/errfmt"
+	"github.com/johntheyoung/roadrunner/internal/fakeapi"
+	"github.com/johntheyoung/roadrunner/internal/vcard"
+)
+
+func TestContactsExportImport(t *testing.T) {
+	t.Setenv("BEEPER_TOKEN", "test-token")
+	t.Setenv("BEEPER_ACCESS_TOKEN", "")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	ds := fakeapi.DefaultDataset()
+	ds.Contacts["matrix"][0].ImgURL = "mxc://beeper.local/trip"
+	fake := fakeapi.New(ds)
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	defer fake.Close()
+
+	run := func(args ...string) (string, string, int) {
+		t.Helper()
+		var out, errText string
+		var code int
+		withArgs(t, append([]string{"rr", "--base-url", server.URL}, args...), func() {
+			out, errText = captureOutput(t, func() {
+				code = Execute()
+			})
+		})
+		return out, errText, code
+	}
+
+	dir := t.TempDir()
+	exported := filepath.Join(dir, "contacts.vcf")
+	out, errText, code := run("--json", "contacts", "export", "--out", exported, "--avatars")
+	if code != 0 {
+		t.Fatalf("contacts export: code = %d, stderr = %q", code, errText)
+	}
+	var summary struct {
+		Contacts int `json:"contacts"`
+		Avatars  int `json:"avatars"`
+	}
+	if err := json.Unmarshal([]byte(out), &summary); err != nil || summary.Contacts != 3 || summary.Avatars != 1 {
+		t.Fatalf("contacts export summary = %q (%v)", out, err)
+	}
+	f, err := os.Open(exported)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cards, err := vcard.Parse(f)
+	_ = f.Close()
+	if err != nil || len(cards) != 3 {
+		t.Fatalf("exported cards = %+v (%v)", cards, err)
+	}
+	if cards[0].FullName != "Alice Example" || cards[0].AccountID != "matrix" || !strings.HasPrefix(cards[0].Photo, "data:image/png;base64,") {
+		t.Fatalf("exported alice = %+v", cards[0])
+	}
+
+	if out, _, code := run("contacts", "export", "--account-ids", "whatsapp", "--format", "csv"); code != 0 ||
+		!strings.HasPrefix(out, "account_id,network,id,") || !strings.Contains(out, "whatsapp,WhatsApp,+14155550199,Carol Example") {
+		t.Fatalf("contacts export --format csv = %q (code %d)", out, code)
+	}
+
+	importFile := filepath.Join(dir, "import.vcf")
+	extra := "BEGIN:VCARD\r\nVERSION:4.0\r\nFN:Nobody\r\nEND:VCARD\r\n" +
+		"BEGIN:VCARD\r\nVERSION:4.0\r\nFN:Stranger\r\nEMAIL:stranger@example.com\r\nEND:VCARD\r\n"
+	data, err := os.ReadFile(exported)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(importFile, append(data[:len(data):len(data)], extra...), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var plan struct {
+		Plan struct {
+			Cards []contactImportResult `json:"cards"`
+		} `json:"plan"`
+	}
+	out, errText, code = run("--json", "--dry-run", "contacts", "import", importFile, "--account-id", "matrix")
+	if code != 0 {
+		t.Fatalf("contacts import --dry-run: code = %d, stderr = %q", code, errText)
+	}
+	if err := json.Unmarshal([]byte(out), &plan); err != nil || len(plan.Plan.Cards) != 5 || plan.Plan.Cards[3].Status != "skipped" || plan.Plan.Cards[0].Status != "planned" {
+		t.Fatalf("contacts import plan = %q (%v)", out, err)
+	}
+
+	var report struct {
+		Created  int                   `json:"created"`
+		Existing int                   `json:"existing"`
+		Skipped  int                   `json:"skipped"`
+		Failed   int                   `json:"failed"`
+		Results  []contactImportResult `json:"results"`
+	}
+	out, errText, code = run("--json", "contacts", "import", importFile, "--account-id", "matrix")
+	if code != 0 {
+		t.Fatalf("contacts import: code = %d, stderr = %q", code, errText)
+	}
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("decode contacts import: %v", err)
+	}
+	// Alice has a DM, Bob and Carol get one, the stranger is unknown, and
+	// Nobody has no identifiers.
+	if report.Existing != 1 || report.Created != 2 || report.Failed != 1 || report.Skipped != 1 ||
+		report.Results[0].ChatID != "!alice:beeper.local" {
+		t.Fatalf("contacts import report = %+v", report)
+	}
+
+	if _, errText, code := run("contacts", "import", importFile, "--account-id", "matrix"); code != errfmt.ExitFailure || !strings.Contains(errText, "1 of 5 cards failed") {
+		t.Fatalf("contacts import human: code = %d, stderr = %q", code, errText)
+	}
+}
@@ -91,9 +91,9 @@ func (c *DescribeCmd) Run(ctx context.Context) error {
 		return writeJSON(ctx, resp, command)
 	}
 
-	if outfmt.IsPlain(ctx) {
+	if outfmt.IsTabular(ctx) {
 		for _, sub := range resp.Subcommands {
-			u.Out().Printf("%s\t%s", sub.Name, sub.Help)
+			writePlainRow(ctx, []string{"name", "help"}, sub.Name, sub.Help)
 		}
 		return nil
 	}
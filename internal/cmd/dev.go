@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/johntheyoung/roadrunner/internal/errfmt"
+	"github.com/johntheyoung/roadrunner/internal/fakeapi"
+	"github.com/johntheyoung/roadrunner/internal/outfmt"
+	"github.com/johntheyoung/roadrunner/internal/ui"
+)
+
+// DevCmd is the parent command for developer tooling.
+type DevCmd struct {
+	FakeServer DevFakeServerCmd `cmd:"" name:"fake-server" help:"Serve an in-memory fake Desktop API for testing"`
+}
+
+// DevFakeServerCmd serves internal/fakeapi on a local address.
+type DevFakeServerCmd struct {
+	Seed      string        `help:"JSON dataset to seed the server with (default: built-in sample data)" name:"seed" type:"existingfile"`
+	Listen    string        `help:"Listen address" name:"listen" default:"127.0.0.1:0"`
+	Token     string        `help:"Require this bearer token (default: accept any)" name:"token"`
+	StopAfter time.Duration `help:"Stop serving after duration (0=forever)" name:"stop-after" default:"0s"`
+	PrintSeed bool          `help:"Print the built-in sample dataset as JSON and exit" name:"print-seed"`
+}
+
+// Run executes the dev fake-server command.
+func (c *DevFakeServerCmd) Run(ctx context.Context) error {
+	u := ui.FromContext(ctx)
+
+	if c.StopAfter < 0 {
+		return errfmt.UsageError("invalid --stop-after %s (must be >= 0)", c.StopAfter)
+	}
+
+	if c.PrintSeed {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(fakeapi.DefaultDataset())
+	}
+
+	data := fakeapi.DefaultDataset()
+	if strings.TrimSpace(c.Seed) != "" {
+		var err error
+		data, err = fakeapi.LoadDataset(c.Seed)
+		if err != nil {
+			return err
+		}
+	}
+
+	listener, err := net.Listen("tcp", c.Listen)
+	if err != nil {
+		return fmt.Errorf("listen %s: %w", c.Listen, err)
+	}
+
+	fake := fakeapi.New(data)
+	if c.Token != "" {
+		fake.RequireToken(c.Token)
+	}
+	server := &http.Server{Handler: fake, ReadHeaderTimeout: 10 * time.Second}
+	go func() { _ = server.Serve(listener) }()
+	defer func() {
+		fake.Close()
+		_ = server.Close()
+	}()
+
+	baseURL := "http://" + listener.Addr().String()
+	switch {
+	case outfmt.IsJSON(ctx):
+		if err := writeJSON(ctx, map[string]any{
+			"base_url": baseURL,
+			"seed":     c.Seed,
+			"accounts": len(data.Accounts),
+			"chats":    len(data.Chats),
+			"messages": len(data.Messages),
+		}, "dev fake-server"); err != nil {
+			return err
+		}
+	case outfmt.IsTabular(ctx):
+		writePlainRow(ctx, []string{"base_url", "chats", "messages"}, baseURL, len(data.Chats), len(data.Messages))
+	default:
+		u.Out().Successf("Fake Desktop API on %s (%d chats, %d messages)", baseURL, len(data.Chats), len(data.Messages))
+		token := c.Token
+		if token == "" {
+			token = "fake"
+		}
+		u.Out().Dim(fmt.Sprintf("Point rr at it with: BEEPER_URL=%s BEEPER_TOKEN=%s rr chats list", baseURL, token))
+	}
+
+	var stop <-chan time.Time
+	if c.StopAfter > 0 {
+		timer := time.NewTimer(c.StopAfter)
+		defer timer.Stop()
+		stop = timer.C
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-stop:
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/johntheyoung/roadrunner/internal/beeperapi"
+	"github.com/johntheyoung/roadrunner/internal/fakeapi"
+)
+
+func TestDevFakeServerLoadsSeedAndPrintsBaseURL(t *testing.T) {
+	seed := fakeapi.Dataset{
+		Accounts: []fakeapi.Account{{AccountID: "acc1", Network: "Test", User: fakeapi.User{ID: "@me:test", IsSelf: true}}},
+		Chats:    []fakeapi.Chat{{ID: "!c1:test", AccountID: "acc1", Title: "Only chat"}},
+	}
+	raw, err := json.Marshal(seed)
+	if err != nil {
+		t.Fatalf("marshal seed: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "seed.json")
+	if err := os.WriteFile(path, raw, 0o600); err != nil {
+		t.Fatalf("write seed: %v", err)
+	}
+
+	ctx := testJSONContext(t)
+	cmd := DevFakeServerCmd{Seed: path, Listen: "127.0.0.1:0", StopAfter: 50 * time.Millisecond}
+	out, _ := captureOutput(t, func() {
+		if err := cmd.Run(ctx); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+	})
+
+	var resp map[string]any
+	if err := json.Unmarshal([]byte(out), &resp); err != nil {
+		t.Fatalf("unmarshal output: %v\noutput: %s", err, out)
+	}
+	if baseURL, _ := resp["base_url"].(string); !strings.HasPrefix(baseURL, "http://127.0.0.1:") {
+		t.Fatalf("base_url = %v", resp["base_url"])
+	}
+	if resp["chats"] != float64(1) || resp["messages"] != float64(0) {
+		t.Fatalf("resp = %#v", resp)
+	}
+}
+
+func TestMessagesSendAgainstFakeAPI(t *testing.T) {
+	t.Setenv("BEEPER_TOKEN", "test-token")
+	t.Setenv("BEEPER_ACCESS_TOKEN", "")
+
+	fake := fakeapi.New(fakeapi.DefaultDataset())
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	defer fake.Close()
+
+	ctx := testJSONContext(t)
+	flags := &RootFlags{BaseURL: server.URL, Timeout: 5}
+	send := MessagesSendCmd{Chat: "Team", Text: "shipping it"}
+	captureOutput(t, func() {
+		if err := send.Run(ctx, flags); err != nil {
+			t.Fatalf("send Run() error = %v", err)
+		}
+	})
+
+	list := MessagesListCmd{ChatID: "!team:beeper.local", Direction: "before"}
+	out, _ := captureOutput(t, func() {
+		if err := list.Run(ctx, flags); err != nil {
+			t.Fatalf("list Run() error = %v", err)
+		}
+	})
+
+	var resp beeperapi.MessageListResult
+	if err := json.Unmarshal([]byte(out), &resp); err != nil {
+		t.Fatalf("unmarshal output: %v\noutput: %s", err, out)
+	}
+	if len(resp.Items) != 3 || resp.Items[0].Text != "shipping it" || !resp.Items[0].IsSender {
+		t.Fatalf("items = %#v", resp.Items)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/johntheyoung/roadrunner/internal/cassette"
 	"github.com/johntheyoung/roadrunner/internal/config"
 	"github.com/johntheyoung/roadrunner/internal/errfmt"
 	"github.com/johntheyoung/roadrunner/internal/outfmt"
@@ -15,7 +16,7 @@ import (
 
 // DoctorCmd validates configuration and connectivity.
 type DoctorCmd struct {
-	Fields []string `help:"Comma-separated list of fields for --plain output" name:"fields" sep:","`
+	Fields []string `help:"Comma-separated list of fields for --plain, --csv, --yaml, or --markdown output" name:"fields" sep:","`
 }
 
 // DoctorResult holds the results of all checks.
@@ -68,7 +69,7 @@ func (c *DoctorCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	// Check 3: API reachable (read-only health check)
-	apiErr := checkAPIReachable(flags.BaseURL, flags.Timeout)
+	apiErr := checkAPIReachable(ctx, flags.BaseURL, flags.Timeout)
 	if apiErr == nil {
 		result.APIReachable = true
 	} else {
@@ -99,24 +100,24 @@ func (c *DoctorCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	// Plain output (TSV)
-	if outfmt.IsPlain(ctx) {
+	if outfmt.IsTabular(ctx) {
 		fields, err := resolveFields(c.Fields, []string{"config_path", "config_exists", "token_source", "has_token", "api_reachable", "api_url", "token_valid", "validation_method", "connect_name", "connect_version", "connect_runtime", "all_passed"})
 		if err != nil {
 			return err
 		}
-		writePlainFields(u, fields, map[string]string{
+		writePlainFields(ctx, fields, map[string]any{
 			"config_path":       result.ConfigPath,
-			"config_exists":     formatBool(result.ConfigExists),
+			"config_exists":     result.ConfigExists,
 			"token_source":      result.TokenSource,
-			"has_token":         formatBool(result.HasToken),
-			"api_reachable":     formatBool(result.APIReachable),
+			"has_token":         result.HasToken,
+			"api_reachable":     result.APIReachable,
 			"api_url":           result.APIURL,
-			"token_valid":       formatBool(result.TokenValid),
+			"token_valid":       result.TokenValid,
 			"validation_method": result.ValidationMethod,
 			"connect_name":      result.ConnectName,
 			"connect_version":   result.ConnectVersion,
 			"connect_runtime":   result.ConnectRuntime,
-			"all_passed":        formatBool(result.AllPassed),
+			"all_passed":        result.AllPassed,
 		})
 		return nil
 	}
@@ -182,10 +183,14 @@ func printCheck(u *ui.UI, name, value string, ok bool, okSuffix, failSuffix stri
 	}
 }
 
-func checkAPIReachable(baseURL string, timeoutSec int) error {
+func checkAPIReachable(ctx context.Context, baseURL string, timeoutSec int) error {
 	timeout := time.Duration(timeoutSec) * time.Second
 
 	client := &http.Client{Timeout: timeout}
+	if tape := cassette.FromContext(ctx); tape != nil {
+		client = tape.HTTPClient()
+		client.Timeout = timeout
+	}
 
 	// Try a simple GET to the base URL or a health endpoint
 	// Using /v1/accounts as a read-only endpoint
@@ -22,9 +22,9 @@ func handleDryRunWrite(ctx context.Context, flags *RootFlags, command string, pl
 	}
 
 	u := ui.FromContext(ctx)
-	if outfmt.IsPlain(ctx) {
+	if outfmt.IsTabular(ctx) {
 		b, _ := json.Marshal(plan)
-		u.Out().Printf("%s\t%s", command, string(b))
+		writePlainRow(ctx, []string{"command", "plan"}, command, string(b))
 		return true, nil
 	}
 
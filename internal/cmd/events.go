@@ -7,7 +7,9 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -16,23 +18,28 @@ import (
 	"github.com/johntheyoung/roadrunner/internal/beeperapi"
 	"github.com/johntheyoung/roadrunner/internal/config"
 	"github.com/johntheyoung/roadrunner/internal/errfmt"
+	"github.com/johntheyoung/roadrunner/internal/eventlog"
 	"github.com/johntheyoung/roadrunner/internal/outfmt"
 	"github.com/johntheyoung/roadrunner/internal/ui"
 )
 
 // EventsCmd is the parent command for websocket event streaming.
 type EventsCmd struct {
-	Tail EventsTailCmd `cmd:"" help:"Follow live websocket events"`
+	Tail   EventsTailCmd   `cmd:"" help:"Follow live websocket events"`
+	Record EventsRecordCmd `cmd:"" help:"Record raw websocket frames to a JSONL file"`
+	Replay EventsReplayCmd `cmd:"" help:"Serve a recording through a local /v1/ws endpoint"`
 }
 
 // EventsTailCmd streams live events from GET /v1/ws.
 type EventsTailCmd struct {
 	ChatIDs        []string      `help:"Subscribe to specific chat IDs (repeatable)" name:"chat-id"`
+	Types          []string      `help:"Only emit these event types (repeatable; supports prefix wildcards like message.*)" name:"type"`
 	All            bool          `help:"Subscribe to all chats" name:"all"`
 	IncludeControl bool          `help:"Include control messages (ready, subscriptions.updated, error)" name:"include-control"`
 	Reconnect      bool          `help:"Reconnect on disconnect/errors" default:"true"`
 	ReconnectDelay time.Duration `help:"Delay before reconnect attempts" name:"reconnect-delay" default:"2s"`
 	StopAfter      time.Duration `help:"Stop after duration (0=forever)" name:"stop-after" default:"0s"`
+	Backfill       bool          `help:"After reconnecting, emit messages missed while disconnected as message.backfill events" name:"backfill"`
 }
 
 // Run executes the events tail command.
@@ -49,6 +56,10 @@ func (c *EventsTailCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if err != nil {
 		return err
 	}
+	types, err := resolveEventTypes(c.Types)
+	if err != nil {
+		return err
+	}
 
 	token, _, err := config.GetToken()
 	if err != nil {
@@ -56,7 +67,7 @@ func (c *EventsTailCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	timeout := time.Duration(flags.Timeout) * time.Second
-	client, err := beeperapi.NewClient(token, flags.BaseURL, timeout)
+	client, err := newAPIClient(ctx, token, flags.BaseURL, timeout)
 	if err != nil {
 		return err
 	}
@@ -69,6 +80,12 @@ func (c *EventsTailCmd) Run(ctx context.Context, flags *RootFlags) error {
 	encoder := json.NewEncoder(os.Stdout)
 	encoder.SetEscapeHTML(false)
 
+	var backfill *eventBackfill
+	if c.Backfill {
+		backfill = newEventBackfill(client, chatIDs)
+	}
+	reconnecting := false
+
 	for {
 		if stopReached(stopDeadline) {
 			return nil
@@ -93,7 +110,20 @@ func (c *EventsTailCmd) Run(ctx context.Context, flags *RootFlags) error {
 			return err
 		}
 
-		if err := c.readLoop(ctx, stopDeadline, conn, encoder, u); err != nil {
+		if backfill != nil {
+			if reconnecting {
+				// Live events queue on the socket while the gap is filled,
+				// so backfilled messages are always written first.
+				if err := writeBackfillEvents(ctx, encoder, u, backfill, backfill.gap(), types); err != nil {
+					_ = conn.Close()
+					return err
+				}
+			}
+			backfill.connected()
+		}
+		reconnecting = true
+
+		if err := c.readLoop(ctx, stopDeadline, conn, encoder, u, backfill, types); err != nil {
 			_ = conn.Close()
 			if isEventsStreamClosed(err) {
 				if !c.Reconnect {
@@ -118,7 +148,201 @@ func (c *EventsTailCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 }
 
-func (c *EventsTailCmd) readLoop(ctx context.Context, stopDeadline time.Time, conn *beeperapi.EventsConnection, encoder *json.Encoder, u *ui.UI) error {
+// EventsRecordCmd captures raw websocket frames with receive timestamps.
+type EventsRecordCmd struct {
+	Out       string        `help:"Recording file path (JSONL)" name:"out" required:""`
+	ChatIDs   []string      `help:"Subscribe to specific chat IDs (repeatable)" name:"chat-id"`
+	All       bool          `help:"Subscribe to all chats" name:"all"`
+	StopAfter time.Duration `help:"Stop after duration (0=until the stream closes)" name:"stop-after" default:"0s"`
+	MaxFrames int           `help:"Stop after N frames (0=unlimited)" name:"max-frames" default:"0"`
+}
+
+// Run executes the events record command.
+func (c *EventsRecordCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+
+	if strings.TrimSpace(c.Out) == "" {
+		return errfmt.UsageError("--out is required")
+	}
+	if c.StopAfter < 0 {
+		return errfmt.UsageError("invalid --stop-after %s (must be >= 0)", c.StopAfter)
+	}
+	if c.MaxFrames < 0 {
+		return errfmt.UsageError("invalid --max-frames %d (must be >= 0)", c.MaxFrames)
+	}
+	chatIDs, err := resolveEventSubscriptions(c.All, c.ChatIDs)
+	if err != nil {
+		return err
+	}
+
+	token, _, err := config.GetToken()
+	if err != nil {
+		return err
+	}
+
+	timeout := time.Duration(flags.Timeout) * time.Second
+	client, err := newAPIClient(ctx, token, flags.BaseURL, timeout)
+	if err != nil {
+		return err
+	}
+
+	conn, err := client.Events().Connect(ctx)
+	if err != nil {
+		if beeperapi.IsEventsUnsupported(err) {
+			return errUnsupportedWebsocketEvents()
+		}
+		return err
+	}
+	defer func() { _ = conn.Close() }()
+
+	if err := conn.SetSubscriptions(ctx, "", chatIDs); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.Out), 0755); err != nil {
+		return fmt.Errorf("create dir: %w", err)
+	}
+	f, err := os.Create(c.Out)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", c.Out, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	stopDeadline := time.Time{}
+	if c.StopAfter > 0 {
+		stopDeadline = time.Now().Add(c.StopAfter)
+	}
+
+	recorder := eventlog.NewWriter(f)
+	for {
+		if stopReached(stopDeadline) || (c.MaxFrames > 0 && recorder.Count() >= c.MaxFrames) {
+			break
+		}
+
+		readCtx, cancel := nextEventsReadContext(ctx, stopDeadline)
+		data, err := conn.ReadRaw(readCtx)
+		deadlineReached := errors.Is(readCtx.Err(), context.DeadlineExceeded)
+		cancel()
+		if err != nil {
+			if isReadTimeout(err) && (stopReached(stopDeadline) || (deadlineReached && !stopDeadline.IsZero())) {
+				break
+			}
+			if isEventsStreamClosed(err) {
+				break
+			}
+			return err
+		}
+		if err := recorder.Write(time.Now(), data); err != nil {
+			return fmt.Errorf("write %s: %w", c.Out, err)
+		}
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("close %s: %w", c.Out, err)
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return writeJSON(ctx, map[string]any{
+			"out":    c.Out,
+			"frames": recorder.Count(),
+		}, "events record")
+	}
+
+	if outfmt.IsTabular(ctx) {
+		writePlainRow(ctx, []string{"out", "frames"}, c.Out, recorder.Count())
+		return nil
+	}
+
+	u.Out().Successf("Recorded %d frames to %s", recorder.Count(), c.Out)
+	return nil
+}
+
+// EventsReplayCmd serves a recording through a local fake /v1/ws endpoint.
+type EventsReplayCmd struct {
+	File      string        `arg:"" help:"Recording file from events record"`
+	Listen    string        `help:"Listen address" name:"listen" default:"127.0.0.1:0"`
+	Speed     float64       `help:"Replay speed multiplier (2=twice as fast, 0=no delays)" name:"speed" default:"1"`
+	Once      bool          `help:"Exit after the first client session ends" name:"once"`
+	StopAfter time.Duration `help:"Stop serving after duration (0=forever)" name:"stop-after" default:"0s"`
+}
+
+// Run executes the events replay command.
+func (c *EventsReplayCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+
+	if strings.TrimSpace(c.File) == "" {
+		return errfmt.UsageError("recording file is required")
+	}
+	if c.Speed < 0 {
+		return errfmt.UsageError("invalid --speed %v (must be >= 0)", c.Speed)
+	}
+	if c.StopAfter < 0 {
+		return errfmt.UsageError("invalid --stop-after %s (must be >= 0)", c.StopAfter)
+	}
+
+	f, err := os.Open(c.File)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", c.File, err)
+	}
+	frames, err := eventlog.Read(f)
+	_ = f.Close()
+	if err != nil {
+		return fmt.Errorf("read %s: %w", c.File, err)
+	}
+
+	listener, err := net.Listen("tcp", c.Listen)
+	if err != nil {
+		return fmt.Errorf("listen %s: %w", c.Listen, err)
+	}
+
+	replay := eventlog.NewServer(frames, c.Speed)
+	server := &http.Server{Handler: replay, ReadHeaderTimeout: 10 * time.Second}
+	go func() { _ = server.Serve(listener) }()
+	defer func() {
+		replay.Close()
+		_ = server.Close()
+	}()
+
+	baseURL := "http://" + listener.Addr().String()
+	switch {
+	case outfmt.IsJSON(ctx):
+		if err := writeJSON(ctx, map[string]any{
+			"base_url": baseURL,
+			"file":     c.File,
+			"frames":   len(frames),
+			"speed":    c.Speed,
+		}, "events replay"); err != nil {
+			return err
+		}
+	case outfmt.IsTabular(ctx):
+		writePlainRow(ctx, []string{"base_url", "frames"}, baseURL, len(frames))
+	default:
+		u.Out().Successf("Replaying %d frames on %s", len(frames), baseURL)
+		u.Out().Dim(fmt.Sprintf("Point clients at it with: rr events tail --all --base-url %s", baseURL))
+	}
+
+	var stop <-chan time.Time
+	if c.StopAfter > 0 {
+		timer := time.NewTimer(c.StopAfter)
+		defer timer.Stop()
+		stop = timer.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-stop:
+			return nil
+		case <-replay.Sessions():
+			if c.Once {
+				return nil
+			}
+		}
+	}
+}
+
+func (c *EventsTailCmd) readLoop(ctx context.Context, stopDeadline time.Time, conn *beeperapi.EventsConnection, encoder *json.Encoder, u *ui.UI, backfill *eventBackfill, types []string) error {
 	for {
 		if stopReached(stopDeadline) {
 			return nil
@@ -139,15 +363,44 @@ func (c *EventsTailCmd) readLoop(ctx context.Context, stopDeadline time.Time, co
 			return err
 		}
 
+		if backfill != nil {
+			duplicate := backfill.duplicate(evt)
+			backfill.observe(evt)
+			if duplicate {
+				continue
+			}
+		}
 		if !c.IncludeControl && evt.IsControlMessage() {
 			continue
 		}
+		if !evt.IsControlMessage() && !eventTypeMatches(evt.Type, types) {
+			continue
+		}
 		if err := writeEventOutput(ctx, encoder, u, evt); err != nil {
 			return err
 		}
 	}
 }
 
+// writeBackfillEvents emits messages missed during gap. Lookup failures are
+// reported as warnings so the live stream keeps running.
+func writeBackfillEvents(ctx context.Context, encoder *json.Encoder, u *ui.UI, backfill *eventBackfill, gap eventGap, types []string) error {
+	events, err := backfill.collect(ctx, gap)
+	for _, evt := range events {
+		if !eventTypeMatches(evt.Type, types) {
+			continue
+		}
+		if writeErr := writeEventOutput(ctx, encoder, u, evt); writeErr != nil {
+			return writeErr
+		}
+	}
+	if err != nil {
+		u.Err().Warn(fmt.Sprintf("backfill incomplete for gap %s..%s: %v",
+			gap.Start.Format(time.RFC3339), gap.End.Format(time.RFC3339), err))
+	}
+	return nil
+}
+
 func resolveEventSubscriptions(all bool, chatIDs []string) ([]string, error) {
 	if all && len(chatIDs) > 0 {
 		return nil, errfmt.UsageError("cannot combine --all with --chat-id")
@@ -167,7 +420,7 @@ func resolveEventSubscriptions(all bool, chatIDs []string) ([]string, error) {
 		if value == "" {
 			return nil, errfmt.UsageError("--chat-id cannot be empty")
 		}
-		trimmed = append(trimmed, value)
+		trimmed = append(trimmed, normalizeChatID(value))
 	}
 
 	hasWildcard := false
@@ -184,24 +437,90 @@ func resolveEventSubscriptions(all bool, chatIDs []string) ([]string, error) {
 	return trimmed, nil
 }
 
+func resolveEventTypes(types []string) ([]string, error) {
+	trimmed := make([]string, 0, len(types))
+	for _, eventType := range types {
+		value := strings.TrimSpace(eventType)
+		if value == "" {
+			return nil, errfmt.UsageError("--type cannot be empty")
+		}
+		trimmed = append(trimmed, value)
+	}
+	return trimmed, nil
+}
+
+// eventTypeMatches reports whether eventType passes the --type filter.
+// An empty filter matches everything; a trailing "*" matches by prefix.
+func eventTypeMatches(eventType string, types []string) bool {
+	if len(types) == 0 {
+		return true
+	}
+	for _, want := range types {
+		if prefix, ok := strings.CutSuffix(want, "*"); ok {
+			if strings.HasPrefix(eventType, prefix) {
+				return true
+			}
+			continue
+		}
+		if eventType == want {
+			return true
+		}
+	}
+	return false
+}
+
 func writeEventOutput(ctx context.Context, encoder *json.Encoder, u *ui.UI, evt beeperapi.Event) error {
 	switch {
 	case outfmt.IsJSON(ctx):
-		if err := encoder.Encode(evt); err != nil {
-			return fmt.Errorf("encode json: %w", err)
+		if err := writeJSONStream(ctx, encoder, evt); err != nil {
+			return err
 		}
-	case outfmt.IsPlain(ctx):
-		u.Out().Printf("%s\t%d\t%d\t%s\t%s", evt.Type, evt.Seq, evt.TS, evt.ChatID, strings.Join(evt.IDs, ","))
+	case outfmt.IsTabular(ctx):
+		writePlainRow(ctx, []string{"type", "seq", "ts", "chat_id", "ids"}, evt.Type, evt.Seq, evt.TS, evt.ChatID, strings.Join(evt.IDs, ","))
 	default:
 		if evt.IsControlMessage() {
 			u.Out().Dim(fmt.Sprintf("[control] %s %s", evt.Type, evt.Message))
 			return nil
 		}
-		u.Out().Printf("[%s] chat=%s seq=%d ids=%s", evt.Type, evt.ChatID, evt.Seq, strings.Join(evt.IDs, ","))
+		writeEventHuman(u, evt.Decode())
 	}
 	return nil
 }
 
+// writeEventHuman renders decoded events, showing messages the same way
+// messages tail does. Events without typed entries fall back to IDs.
+func writeEventHuman(u *ui.UI, evt beeperapi.DecodedEvent) {
+	switch {
+	case len(evt.Messages) > 0:
+		for _, item := range evt.Messages {
+			ts := ""
+			if item.Timestamp != "" {
+				if t, err := time.Parse(time.RFC3339, item.Timestamp); err == nil {
+					ts = t.Format("Jan 2 15:04")
+				}
+			}
+			line := fmt.Sprintf("[%s] %s: %s", ts, item.SenderName, ui.Truncate(item.Text, 60))
+			if evt.Type == beeperapi.EventTypeMessageBackfill {
+				u.Out().Dim(line + " (backfill)")
+				continue
+			}
+			u.Out().Printf("%s", line)
+		}
+	case len(evt.Chats) > 0:
+		for _, chat := range evt.Chats {
+			name := chat.DisplayName
+			if name == "" {
+				name = chat.Title
+			}
+			u.Out().Printf("[chat] %s (%s)", name, chat.ID)
+		}
+	case len(evt.DeletedIDs) > 0:
+		u.Out().Dim(fmt.Sprintf("[%s] chat=%s ids=%s", evt.Type, evt.ChatID, strings.Join(evt.DeletedIDs, ",")))
+	default:
+		u.Out().Printf("[%s] chat=%s seq=%d ids=%s", evt.Type, evt.ChatID, evt.Seq, strings.Join(evt.IDs, ","))
+	}
+}
+
 func waitForReconnect(ctx context.Context, delay time.Duration, stopDeadline time.Time) error {
 	if stopReached(stopDeadline) {
 		return context.DeadlineExceeded
@@ -0,0 +1,268 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/johntheyoung/roadrunner/internal/beeperapi"
+)
+
+// maxBackfillSeenIDs bounds the de-duplication set kept across reconnects.
+const maxBackfillSeenIDs = 5000
+
+// eventGap is the window during which the websocket was not delivering events.
+type eventGap struct {
+	Start time.Time
+	End   time.Time
+}
+
+// eventBackfill tracks per-chat stream positions so messages missed while
+// disconnected can be recovered once events tail reconnects.
+type eventBackfill struct {
+	client     *beeperapi.Client
+	chatIDs    []string
+	sortKeys   map[string]string
+	seen       map[string]string   // message ID -> fingerprint of its last content
+	backfilled map[string]struct{} // IDs recovered by backfill, not yet delivered live
+	seenOrder  []string
+	lastFrame  time.Time
+}
+
+func newEventBackfill(client *beeperapi.Client, chatIDs []string) *eventBackfill {
+	return &eventBackfill{
+		client:     client,
+		chatIDs:    chatIDs,
+		sortKeys:   make(map[string]string),
+		seen:       make(map[string]string),
+		backfilled: make(map[string]struct{}),
+	}
+}
+
+// observe records the stream position carried by a live event.
+func (b *eventBackfill) observe(evt beeperapi.Event) {
+	b.lastFrame = time.Now()
+	if evt.IsControlMessage() || evt.ChatID == "" {
+		return
+	}
+	if !strings.HasPrefix(evt.Type, "message.") {
+		return
+	}
+	for _, id := range evt.IDs {
+		b.markSeen(id)
+		delete(b.backfilled, id)
+	}
+	for _, msg := range evt.Decode().Messages {
+		b.record(msg.ID, messageFingerprint(msg))
+		delete(b.backfilled, msg.ID)
+		if msg.SortKey != "" {
+			b.sortKeys[evt.ChatID] = msg.SortKey
+		}
+	}
+}
+
+// duplicate reports whether a live event only repeats messages already
+// emitted, such as a message recovered by backfill that the websocket
+// delivers as well. Upserts that change a message (edits, reactions) are
+// not duplicates. Events without entries carry no content to compare, so
+// they are duplicates only when every ID was recovered by backfill and has
+// not been delivered live since.
+func (b *eventBackfill) duplicate(evt beeperapi.Event) bool {
+	if evt.Type != beeperapi.EventTypeMessageUpserted {
+		return false
+	}
+	decoded := evt.Decode()
+	if len(decoded.Unknown) > 0 {
+		return false
+	}
+	if len(decoded.Messages) == 0 {
+		if len(evt.IDs) == 0 {
+			return false
+		}
+		for _, id := range evt.IDs {
+			if _, ok := b.backfilled[id]; !ok {
+				return false
+			}
+		}
+		return true
+	}
+	for _, msg := range decoded.Messages {
+		if fingerprint, ok := b.seen[msg.ID]; !ok || fingerprint != messageFingerprint(msg) {
+			return false
+		}
+	}
+	return true
+}
+
+// connected marks the start of a fresh connection so the gap window
+// begins no earlier than the first frame we could have received.
+func (b *eventBackfill) connected() {
+	if b.lastFrame.IsZero() {
+		b.lastFrame = time.Now()
+	}
+}
+
+// gap returns the disconnect window ending now.
+func (b *eventBackfill) gap() eventGap {
+	return eventGap{Start: b.lastFrame, End: time.Now()}
+}
+
+// collect fetches messages missed during gap and returns them as synthetic
+// backfill events, oldest first per chat, skipping IDs already seen. Chats
+// that fail or hit the message cap are reported in the returned error; the
+// messages recovered so far are still returned.
+func (b *eventBackfill) collect(ctx context.Context, gap eventGap) ([]beeperapi.Event, error) {
+	chatIDs, err := b.affectedChats(ctx, gap)
+	errs := []error{err}
+
+	var events []beeperapi.Event
+	for _, chatID := range chatIDs {
+		items, err := b.missedMessages(ctx, chatID, gap)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("backfill %s: %w", chatID, err))
+		}
+		for _, item := range items {
+			if b.isSeen(item.ID) {
+				continue
+			}
+			b.record(item.ID, messageFingerprint(item))
+			b.backfilled[item.ID] = struct{}{}
+			if item.SortKey != "" {
+				b.sortKeys[chatID] = item.SortKey
+			}
+			events = append(events, beeperapi.NewBackfillEvent(chatID, item))
+		}
+	}
+	return events, errors.Join(errs...)
+}
+
+// affectedChats returns the chats to backfill: the subscribed chats, or for
+// --all every chat with a known position plus every chat active since the
+// gap started.
+func (b *eventBackfill) affectedChats(ctx context.Context, gap eventGap) ([]string, error) {
+	if len(b.chatIDs) != 1 || b.chatIDs[0] != "*" {
+		return b.chatIDs, nil
+	}
+
+	chatIDs := make([]string, 0, len(b.sortKeys))
+	seen := make(map[string]struct{}, len(b.sortKeys))
+	for chatID := range b.sortKeys {
+		chatIDs = append(chatIDs, chatID)
+		seen[chatID] = struct{}{}
+	}
+
+	after := gap.Start
+	chats := b.client.Chats().SearchAll(ctx, beeperapi.ChatSearchParams{
+		LastActivityAfter: &after,
+		Limit:             200,
+	})
+	for chat, err := range chats {
+		if err != nil {
+			return chatIDs, fmt.Errorf("backfill chat discovery: %w", err)
+		}
+		if _, ok := seen[chat.ID]; ok {
+			continue
+		}
+		seen[chat.ID] = struct{}{}
+		chatIDs = append(chatIDs, chat.ID)
+	}
+	return chatIDs, nil
+}
+
+// missedMessages pages through the messages of chatID sent during gap,
+// oldest first. It stops after defaultAutoPageMaxItems messages and reports
+// the truncation as an error alongside the messages it kept.
+func (b *eventBackfill) missedMessages(ctx context.Context, chatID string, gap eventGap) ([]beeperapi.MessageItem, error) {
+	var items []beeperapi.MessageItem
+	cursor, ok := b.sortKeys[chatID]
+	if !ok {
+		// No position known for this chat: walk back from the latest
+		// message to the start of the gap.
+		start := gap.Start.Truncate(time.Second)
+		messages := b.client.Messages().All(ctx, chatID, beeperapi.MessageListParams{
+			Direction: "before",
+		})
+		for item, err := range messages {
+			if err != nil {
+				slices.Reverse(items)
+				return items, err
+			}
+			ts, err := time.Parse(time.RFC3339, item.Timestamp)
+			if err != nil {
+				continue
+			}
+			if ts.Before(start) {
+				break
+			}
+			if len(items) == defaultAutoPageMaxItems {
+				slices.Reverse(items)
+				return items, backfillTruncated(len(items))
+			}
+			items = append(items, item)
+		}
+		slices.Reverse(items)
+		return items, nil
+	}
+
+	messages := b.client.Messages().All(ctx, chatID, beeperapi.MessageListParams{
+		Cursor:    cursor,
+		Direction: "after",
+	})
+	for item, err := range messages {
+		if err != nil {
+			return items, err
+		}
+		if len(items) == defaultAutoPageMaxItems {
+			return items, backfillTruncated(len(items))
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func backfillTruncated(n int) error {
+	return fmt.Errorf("stopped after %d messages; the rest of the gap was not recovered", n)
+}
+
+func (b *eventBackfill) isSeen(id string) bool {
+	_, ok := b.seen[id]
+	return ok
+}
+
+// markSeen records id without changing the content already recorded for it.
+func (b *eventBackfill) markSeen(id string) {
+	b.record(id, b.seen[id])
+}
+
+// record marks id seen with the fingerprint of its content, evicting the
+// oldest ID once maxBackfillSeenIDs are kept.
+func (b *eventBackfill) record(id, fingerprint string) {
+	if id == "" {
+		return
+	}
+	if b.isSeen(id) {
+		b.seen[id] = fingerprint
+		return
+	}
+	b.seen[id] = fingerprint
+	b.seenOrder = append(b.seenOrder, id)
+	if len(b.seenOrder) > maxBackfillSeenIDs {
+		delete(b.seen, b.seenOrder[0])
+		delete(b.backfilled, b.seenOrder[0])
+		b.seenOrder = b.seenOrder[1:]
+	}
+}
+
+// messageFingerprint identifies the content of a message, so repeated
+// deliveries can be told apart from edits.
+func messageFingerprint(item beeperapi.MessageItem) string {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
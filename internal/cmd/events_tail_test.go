@@ -2,15 +2,21 @@ package cmd
 
 import (
 	"context"
+	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/johntheyoung/roadrunner/internal/beeperapi"
+	"github.com/johntheyoung/roadrunner/internal/fakeapi"
 	"github.com/johntheyoung/roadrunner/internal/outfmt"
+	"github.com/johntheyoung/roadrunner/internal/ui"
 )
 
 func TestEventsTailJSONOutputsDomainEvents(t *testing.T) {
@@ -421,3 +427,365 @@ func TestEventsTailIdleAfterControlEventsStopsCleanly(t *testing.T) {
 		}
 	})
 }
+
+func TestEventsTailBackfillEmitsMissedMessagesAfterReconnect(t *testing.T) {
+	t.Setenv("BEEPER_TOKEN", "test-token")
+	t.Setenv("BEEPER_ACCESS_TOKEN", "")
+
+	upgrader := websocket.Upgrader{
+		CheckOrigin: func(r *http.Request) bool { return true },
+	}
+
+	var connections atomic.Int32
+	var backfillCursor atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/chats/chat_a/messages":
+			backfillCursor.Store(r.URL.Query().Get("cursor") + "|" + r.URL.Query().Get("direction"))
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{
+				"items": [
+					{"id":"m1","accountID":"acc1","chatID":"chat_a","senderID":"u1","sortKey":"s1","timestamp":"2026-02-11T00:00:00Z","text":"one"},
+					{"id":"m2","accountID":"acc1","chatID":"chat_a","senderID":"u1","sortKey":"s2","timestamp":"2026-02-11T00:01:00Z","text":"two"}
+				],
+				"hasMore": false
+			}`))
+			return
+		case "/v1/ws":
+		default:
+			http.NotFound(w, r)
+			return
+		}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+
+		var sub map[string]any
+		if err := conn.ReadJSON(&sub); err != nil {
+			return
+		}
+
+		if connections.Add(1) == 1 {
+			_ = conn.WriteJSON(map[string]any{
+				"type":    "message.upserted",
+				"seq":     1,
+				"chatID":  "chat_a",
+				"ids":     []string{"m1"},
+				"entries": []map[string]any{{"id": "m1", "sortKey": "s1"}},
+			})
+			return
+		}
+		// m2 arrives live after it was backfilled: the repeat is dropped,
+		// the later edit is not.
+		m2 := map[string]any{"id": "m2", "accountID": "acc1", "chatID": "chat_a", "senderID": "u1", "sortKey": "s2", "timestamp": "2026-02-11T00:01:00Z", "text": "two"}
+		_ = conn.WriteJSON(map[string]any{"type": "message.upserted", "seq": 2, "chatID": "chat_a", "ids": []string{"m2"}, "entries": []map[string]any{m2}})
+		m2["text"] = "two (edited)"
+		_ = conn.WriteJSON(map[string]any{"type": "message.upserted", "seq": 3, "chatID": "chat_a", "ids": []string{"m2"}, "entries": []map[string]any{m2}})
+		_ = conn.WriteJSON(map[string]any{
+			"type":   "message.upserted",
+			"seq":    4,
+			"chatID": "chat_a",
+			"ids":    []string{"m3"},
+		})
+		time.Sleep(300 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	ctx := outfmt.WithMode(context.Background(), outfmt.Mode{JSON: true})
+	cmd := EventsTailCmd{
+		ChatIDs:        []string{"chat_a"},
+		Reconnect:      true,
+		ReconnectDelay: 10 * time.Millisecond,
+		StopAfter:      250 * time.Millisecond,
+		Backfill:       true,
+	}
+
+	out, _ := captureOutput(t, func() {
+		if err := cmd.Run(ctx, &RootFlags{BaseURL: server.URL, Timeout: 5}); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+	})
+
+	if got, _ := backfillCursor.Load().(string); got != "s1|after" {
+		t.Fatalf("expected backfill from cursor s1 after, got %q", got)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 events, got %d: %s", len(lines), out)
+	}
+	if !strings.Contains(lines[0], `"ids":["m1"]`) || !strings.Contains(lines[0], `"type":"message.upserted"`) {
+		t.Fatalf("unexpected first event: %s", lines[0])
+	}
+	if !strings.Contains(lines[1], `"type":"message.backfill"`) || !strings.Contains(lines[1], `"ids":["m2"]`) {
+		t.Fatalf("expected m2 backfill event, got: %s", lines[1])
+	}
+	if !strings.Contains(lines[1], `"sortKey":"s2"`) || strings.Contains(lines[1], `"sort_key"`) {
+		t.Fatalf("backfill entry is not in the live event shape: %s", lines[1])
+	}
+	if !strings.Contains(lines[2], `"type":"message.upserted"`) || !strings.Contains(lines[2], `two (edited)`) {
+		t.Fatalf("expected live m2 edit after backfill, got: %s", lines[2])
+	}
+	if !strings.Contains(lines[3], `"ids":["m3"]`) {
+		t.Fatalf("expected live m3 after backfill, got: %s", lines[3])
+	}
+}
+
+func TestEventBackfillDeduplicatesSeenIDs(t *testing.T) {
+	backfill := newEventBackfill(nil, []string{"chat_a"})
+	backfill.observe(beeperapi.Event{Type: "message.upserted", ChatID: "chat_a", IDs: []string{"m1"}})
+
+	if !backfill.isSeen("m1") {
+		t.Fatal("expected m1 to be marked seen")
+	}
+	if backfill.isSeen("m2") {
+		t.Fatal("did not expect m2 to be marked seen")
+	}
+
+	for i := 0; i < maxBackfillSeenIDs; i++ {
+		backfill.markSeen(fmt.Sprintf("x%d", i))
+	}
+	if backfill.isSeen("m1") {
+		t.Fatal("expected oldest seen ID to be evicted")
+	}
+}
+
+func TestEventBackfillDuplicateIDOnlyUpserts(t *testing.T) {
+	backfill := newEventBackfill(nil, []string{"chat_a"})
+	backfill.observe(beeperapi.Event{Type: "message.upserted", ChatID: "chat_a", IDs: []string{"m1"}})
+	backfill.record("m2", "")
+	backfill.backfilled["m2"] = struct{}{}
+
+	idOnly := func(ids ...string) beeperapi.Event {
+		return beeperapi.Event{Type: "message.upserted", ChatID: "chat_a", IDs: ids}
+	}
+	if backfill.duplicate(idOnly("m1")) {
+		t.Fatal("ID-only upsert for a live message was dropped")
+	}
+	if !backfill.duplicate(idOnly("m2")) {
+		t.Fatal("expected the live delivery of backfilled m2 to be a duplicate")
+	}
+	if backfill.duplicate(idOnly("m1", "m2")) {
+		t.Fatal("upsert mixing live and backfilled IDs was dropped")
+	}
+	backfill.observe(idOnly("m2"))
+	if backfill.duplicate(idOnly("m2")) {
+		t.Fatal("ID-only upsert for m2 after its live delivery was dropped")
+	}
+}
+
+func TestEventTypeMatches(t *testing.T) {
+	tests := []struct {
+		eventType string
+		types     []string
+		want      bool
+	}{
+		{"message.upserted", nil, true},
+		{"message.upserted", []string{"message.upserted"}, true},
+		{"message.deleted", []string{"message.upserted"}, false},
+		{"message.backfill", []string{"message.*"}, true},
+		{"chat.upserted", []string{"message.*"}, false},
+		{"chat.deleted", []string{"message.upserted", "chat.*"}, true},
+	}
+	for _, tt := range tests {
+		if got := eventTypeMatches(tt.eventType, tt.types); got != tt.want {
+			t.Errorf("eventTypeMatches(%q, %v) = %v, want %v", tt.eventType, tt.types, got, tt.want)
+		}
+	}
+}
+
+func TestEventsTailHumanRendersTypedMessagesWithTypeFilter(t *testing.T) {
+	t.Setenv("BEEPER_TOKEN", "test-token")
+	t.Setenv("BEEPER_ACCESS_TOKEN", "")
+
+	upgrader := websocket.Upgrader{
+		CheckOrigin: func(r *http.Request) bool { return true },
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/ws" {
+			http.NotFound(w, r)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+
+		var sub map[string]any
+		if err := conn.ReadJSON(&sub); err != nil {
+			return
+		}
+		_ = conn.WriteJSON(map[string]any{
+			"type":   "chat.upserted",
+			"chatID": "chat_a",
+			"ids":    []string{"chat_a"},
+		})
+		_ = conn.WriteJSON(map[string]any{
+			"type":   "message.upserted",
+			"seq":    2,
+			"chatID": "chat_a",
+			"ids":    []string{"m1"},
+			"entries": []map[string]any{{
+				"id":         "m1",
+				"accountID":  "acc1",
+				"chatID":     "chat_a",
+				"senderID":   "u1",
+				"senderName": "Alice",
+				"sortKey":    "s1",
+				"timestamp":  "2026-02-11T09:30:00Z",
+				"text":       "hello there",
+			}},
+		})
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	cmd := EventsTailCmd{
+		All:       true,
+		Types:     []string{"message.*"},
+		Reconnect: false,
+		StopAfter: 200 * time.Millisecond,
+	}
+
+	out, _ := captureOutput(t, func() {
+		testUI, err := ui.New(ui.Options{Color: "never"})
+		if err != nil {
+			t.Fatalf("ui.New() error = %v", err)
+		}
+		ctx := ui.WithUI(context.Background(), testUI)
+		if err := cmd.Run(ctx, &RootFlags{BaseURL: server.URL, Timeout: 5}); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+	})
+
+	if !strings.Contains(out, "Alice: hello there") {
+		t.Fatalf("expected rendered message, got: %s", out)
+	}
+	if strings.Contains(out, "chat.upserted") || strings.Contains(out, "[chat]") {
+		t.Fatalf("expected chat event to be filtered, got: %s", out)
+	}
+}
+
+func TestEventsRecordThenReplayThroughTail(t *testing.T) {
+	t.Setenv("BEEPER_TOKEN", "test-token")
+	t.Setenv("BEEPER_ACCESS_TOKEN", "")
+
+	upgrader := websocket.Upgrader{
+		CheckOrigin: func(r *http.Request) bool { return true },
+	}
+	live := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/ws" {
+			http.NotFound(w, r)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+
+		var sub map[string]any
+		if err := conn.ReadJSON(&sub); err != nil {
+			return
+		}
+		_ = conn.WriteJSON(map[string]any{"type": "ready", "version": 1, "chatIDs": []string{"*"}})
+		_ = conn.WriteJSON(map[string]any{"type": "message.upserted", "seq": 1, "chatID": "chat_a", "ids": []string{"m1"}})
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer live.Close()
+
+	recording := filepath.Join(t.TempDir(), "session.jsonl")
+	record := EventsRecordCmd{Out: recording, All: true, MaxFrames: 2}
+	out, _ := captureOutput(t, func() {
+		if err := record.Run(testJSONContext(t), &RootFlags{BaseURL: live.URL, Timeout: 5}); err != nil {
+			t.Fatalf("record Run() error = %v", err)
+		}
+	})
+	if !strings.Contains(out, `"frames": 2`) {
+		t.Fatalf("expected 2 recorded frames, got: %s", out)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := listener.Addr().String()
+	_ = listener.Close()
+
+	replay := EventsReplayCmd{File: recording, Listen: addr, Speed: 0, Once: true, StopAfter: 2 * time.Second}
+	tail := EventsTailCmd{All: true, Reconnect: false, StopAfter: 300 * time.Millisecond}
+	out, _ = captureOutput(t, func() {
+		ctx := outfmt.WithMode(context.Background(), outfmt.Mode{JSON: true})
+		replayDone := make(chan error, 1)
+		go func() {
+			replayDone <- replay.Run(ctx, &RootFlags{})
+		}()
+
+		var runErr error
+		for attempt := 0; attempt < 20; attempt++ {
+			if runErr = tail.Run(ctx, &RootFlags{BaseURL: "http://" + addr, Timeout: 5}); runErr == nil {
+				break
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		if runErr != nil {
+			t.Fatalf("tail Run() error = %v", runErr)
+		}
+		if err := <-replayDone; err != nil {
+			t.Fatalf("replay Run() error = %v", err)
+		}
+	})
+	if !strings.Contains(out, `"base_url": "http://`+addr+`"`) {
+		t.Fatalf("expected replay base_url in output, got: %s", out)
+	}
+	if !strings.Contains(out, `"ids":["m1"]`) {
+		t.Fatalf("expected replayed m1 event, got: %s", out)
+	}
+}
+
+func TestEventBackfillPagesAcrossTheGap(t *testing.T) {
+	gapStart := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	ds := fakeapi.DefaultDataset()
+	add := func(chatID, prefix string, n int) {
+		for i := range n {
+			ds.Messages = append(ds.Messages, fakeapi.Message{
+				ID: fmt.Sprintf("$%s%d", prefix, i), ChatID: chatID, SenderID: "@bob:beeper.local", SenderName: "Bob Example",
+				Timestamp: gapStart.Add(time.Duration(i-5) * time.Second), Text: fmt.Sprintf("msg %d", i),
+			})
+		}
+	}
+	// Five messages before the gap, then more than one page inside it.
+	add("!team:beeper.local", "t", 50)
+	// More messages inside the gap than backfill recovers per chat.
+	add("!alice:beeper.local", "a", defaultAutoPageMaxItems+15)
+	fake := fakeapi.New(ds)
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	defer fake.Close()
+
+	client, err := beeperapi.NewClient("test-token", server.URL, 5*time.Second)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	gap := eventGap{Start: gapStart, End: gapStart.Add(time.Hour)}
+
+	events, err := newEventBackfill(client, []string{"!team:beeper.local"}).collect(context.Background(), gap)
+	if err != nil {
+		t.Fatalf("collect() error = %v", err)
+	}
+	if len(events) != 45 || events[0].IDs[0] != "$t5" || events[44].IDs[0] != "$t49" {
+		t.Fatalf("collect() = %d events, want $t5..$t49", len(events))
+	}
+
+	events, err = newEventBackfill(client, []string{"!alice:beeper.local"}).collect(context.Background(), gap)
+	if err == nil || !strings.Contains(err.Error(), fmt.Sprintf("stopped after %d messages", defaultAutoPageMaxItems)) {
+		t.Fatalf("collect() error = %v, want truncation error", err)
+	}
+	if len(events) != defaultAutoPageMaxItems || events[len(events)-1].IDs[0] != fmt.Sprintf("$a%d", defaultAutoPageMaxItems+14) {
+		t.Fatalf("collect() = %d events, want the newest %d", len(events), defaultAutoPageMaxItems)
+	}
+}
@@ -1,10 +1,12 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
 	"github.com/johntheyoung/roadrunner/internal/errfmt"
+	"github.com/johntheyoung/roadrunner/internal/outfmt"
 	"github.com/johntheyoung/roadrunner/internal/ui"
 )
 
@@ -39,12 +41,35 @@ func resolveFields(fields []string, allowed []string) ([]string, error) {
 	return out, nil
 }
 
-func writePlainFields(u *ui.UI, fields []string, values map[string]string) {
-	row := make([]string, len(fields))
+// writePlainFields writes one --fields row in the active tabular mode
+// (TSV, CSV, YAML, or Markdown). Values are strings, bools, or numbers so
+// YAML can keep their types.
+func writePlainFields(ctx context.Context, fields []string, values map[string]any) {
+	row := make([]any, len(fields))
 	for i, f := range fields {
 		row[i] = values[f]
 	}
-	u.Out().Println(strings.Join(row, "\t"))
+	writePlainRow(ctx, fields, row...)
+}
+
+// writePlainRow writes one fixed-column row in the active tabular mode, for
+// commands without --fields. values must be aligned with columns.
+func writePlainRow(ctx context.Context, columns []string, values ...any) {
+	rows := outfmt.RowWriterFromContext(ctx)
+	if rows == nil {
+		rows = outfmt.NewRowWriter(ui.FromContext(ctx).Out().Writer(), outfmt.FromContext(ctx))
+	}
+	_ = rows.WriteRow(columns, values)
+}
+
+// plainText prepares free-form text for a --fields row. TSV cannot carry
+// tabs or newlines, so it gets the historical single-line truncation; the
+// other tabular modes quote or escape and keep the full text.
+func plainText(ctx context.Context, s string) string {
+	if outfmt.FromContext(ctx).Plain {
+		return ui.Truncate(s, 50)
+	}
+	return s
 }
 
 func formatBool(value bool) string {
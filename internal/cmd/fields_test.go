@@ -1,6 +1,15 @@
 package cmd
 
-import "testing"
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/johntheyoung/roadrunner/internal/fakeapi"
+	"github.com/johntheyoung/roadrunner/internal/outfmt"
+	"github.com/johntheyoung/roadrunner/internal/ui"
+)
 
 func TestResolveFields(t *testing.T) {
 	t.Parallel()
@@ -36,3 +45,91 @@ func TestResolveFields(t *testing.T) {
 		}
 	})
 }
+
+func TestMessagesListCSVKeepsFullText(t *testing.T) {
+	t.Setenv("BEEPER_TOKEN", "test-token")
+	t.Setenv("BEEPER_ACCESS_TOKEN", "")
+
+	fake := fakeapi.New(fakeapi.DefaultDataset())
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	defer fake.Close()
+
+	flags := &RootFlags{BaseURL: server.URL, Timeout: 5}
+	text := "first line\twith a tab\nsecond line, long enough to be truncated in TSV output"
+	captureOutput(t, func() {
+		send := MessagesSendCmd{ChatID: "!team:beeper.local", Text: text}
+		if err := send.Run(testJSONContext(t), flags); err != nil {
+			t.Fatalf("send Run() error = %v", err)
+		}
+	})
+
+	out, _ := captureOutput(t, func() {
+		testUI, err := ui.New(ui.Options{Color: "never"})
+		if err != nil {
+			t.Fatalf("ui.New() error = %v", err)
+		}
+		mode := outfmt.Mode{CSV: true}
+		ctx := ui.WithUI(context.Background(), testUI)
+		ctx = outfmt.WithMode(ctx, mode)
+		ctx = outfmt.WithRowWriter(ctx, outfmt.NewRowWriter(testUI.Out().Writer(), mode))
+
+		list := MessagesListCmd{ChatID: "!team:beeper.local", Direction: "before", Fields: []string{"id", "text"}}
+		if err := list.Run(ctx, flags); err != nil {
+			t.Fatalf("list Run() error = %v", err)
+		}
+	})
+
+	if !strings.HasPrefix(out, "id,text\n") || strings.Count(out, "id,text") != 1 {
+		t.Fatalf("expected a single CSV header, got %q", out)
+	}
+	if !strings.Contains(out, `"`+text+`"`) {
+		t.Fatalf("expected full quoted text in CSV output, got %q", out)
+	}
+}
+
+func TestTabularModesRenderFixedRows(t *testing.T) {
+	t.Setenv("BEEPER_TOKEN", "test-token")
+	t.Setenv("BEEPER_ACCESS_TOKEN", "")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	fake := fakeapi.New(fakeapi.DefaultDataset())
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	defer fake.Close()
+
+	run := func(args ...string) string {
+		t.Helper()
+		var out, errText string
+		var code int
+		withArgs(t, append([]string{"rr", "--base-url", server.URL}, args...), func() {
+			out, errText = captureOutput(t, func() {
+				code = Execute()
+			})
+		})
+		if code != 0 {
+			t.Fatalf("%v: exit code = %d, stderr = %q", args, code, errText)
+		}
+		return out
+	}
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--plain", "chats", "get", "!team:beeper.local"}, "!team:beeper.local\tTeam\tmatrix\n"},
+		{[]string{"--csv", "chats", "get", "!team:beeper.local"}, "id,title,account_id\n!team:beeper.local,Team,matrix\n"},
+		{[]string{"--markdown", "chats", "get", "!team:beeper.local"}, "| id | title | account_id |\n| --- | --- | --- |\n| !team:beeper.local | Team | matrix |\n"},
+		{[]string{"--yaml", "chats", "get", "!team:beeper.local"}, "- id: \"!team:beeper.local\"\n  title: Team\n  account_id: matrix\n"},
+	}
+	for _, tt := range tests {
+		if got := run(tt.args...); got != tt.want {
+			t.Errorf("%v = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+
+	yaml := run("--yaml", "chats", "search", "--fields", "id,unread_count,is_muted")
+	if !strings.Contains(yaml, "\n  is_muted: false\n") || strings.Contains(yaml, ": \"false\"") || strings.Contains(yaml, "unread_count: \"") {
+		t.Fatalf("expected typed YAML scalars, got %q", yaml)
+	}
+}
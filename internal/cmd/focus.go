@@ -35,7 +35,7 @@ func (c *FocusCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	timeout := time.Duration(flags.Timeout) * time.Second
-	client, err := beeperapi.NewClient(token, flags.BaseURL, timeout)
+	client, err := newAPIClient(ctx, token, flags.BaseURL, timeout)
 	if err != nil {
 		return err
 	}
@@ -56,8 +56,8 @@ func (c *FocusCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	// Plain output
-	if outfmt.IsPlain(ctx) {
-		u.Out().Printf("%t", resp.Success)
+	if outfmt.IsTabular(ctx) {
+		writePlainRow(ctx, []string{"success"}, resp.Success)
 		return nil
 	}
 
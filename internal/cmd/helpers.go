@@ -2,11 +2,80 @@ package cmd
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/johntheyoung/roadrunner/internal/beeperapi"
+	"github.com/johntheyoung/roadrunner/internal/cache"
+	"github.com/johntheyoung/roadrunner/internal/cassette"
+	"github.com/johntheyoung/roadrunner/internal/config"
+	"github.com/johntheyoung/roadrunner/internal/errfmt"
+	"github.com/johntheyoung/roadrunner/internal/phone"
 )
 
+// newAPIClient creates an API client, routing traffic through the
+// --record/--replay cassette when one is attached to ctx, or serving
+// repeated lookups from the local cache otherwise.
+func newAPIClient(ctx context.Context, token, baseURL string, timeout time.Duration) (*beeperapi.Client, error) {
+	c := cassette.FromContext(ctx)
+	if c == nil {
+		if store := cache.FromContext(ctx); store != nil {
+			return beeperapi.NewClient(token, baseURL, timeout, beeperapi.WithScopedCache(func(baseURL, token string) beeperapi.Cache {
+				return store.Scope(baseURL, token)
+			}))
+		}
+		return beeperapi.NewClient(token, baseURL, timeout)
+	}
+	return beeperapi.NewClient(token, baseURL, timeout,
+		beeperapi.WithHTTPClient(c.HTTPClient()),
+		beeperapi.WithEventsDialer(c),
+	)
+}
+
+// newClientFromFlags creates an API client from the configured token and
+// the root --base-url and --timeout flags.
+func newClientFromFlags(ctx context.Context, flags *RootFlags) (*beeperapi.Client, error) {
+	token, _, err := config.GetToken()
+	if err != nil {
+		return nil, err
+	}
+	timeout := time.Duration(flags.Timeout) * time.Second
+	return newAPIClient(ctx, token, flags.BaseURL, timeout)
+}
+
+// phoneRegion returns the region phone numbers without a country code are
+// read in: --region (BEEPER_REGION), else the config's default_region.
+func phoneRegion(flags *RootFlags) (string, error) {
+	region := strings.TrimSpace(flags.Region)
+	if region == "" {
+		region = config.GetDefaultRegion()
+	}
+	if err := phone.ValidateRegion(region); err != nil {
+		return "", errfmt.UsageError("%v", err)
+	}
+	return strings.ToUpper(region), nil
+}
+
+// normalizePhone returns number in E.164 form for region, as a usage error
+// when it is not a valid phone number.
+func normalizePhone(number, region string) (string, error) {
+	e164, err := phone.Normalize(number, region)
+	if err != nil {
+		return "", errfmt.UsageError("%v", err)
+	}
+	return e164, nil
+}
+
+// fetchConcurrency returns the --concurrency to use for fan-out and page
+// prefetching. Cassettes record and replay exchanges in sequence, so
+// --record/--replay always fetch serially.
+func fetchConcurrency(ctx context.Context, flags *RootFlags) int {
+	if cassette.FromContext(ctx) != nil {
+		return 1
+	}
+	return max(flags.Concurrency, 1)
+}
+
 // ValidateTokenResult holds the result of token validation.
 type ValidateTokenResult struct {
 	Valid                  bool   `json:"valid"`
@@ -27,7 +96,7 @@ type ValidateTokenResult struct {
 func ValidateToken(ctx context.Context, token, baseURL string, timeoutSec int) ValidateTokenResult {
 	timeout := time.Duration(timeoutSec) * time.Second
 
-	client, err := beeperapi.NewClient(token, baseURL, timeout)
+	client, err := newAPIClient(ctx, token, baseURL, timeout)
 	if err != nil {
 		return ValidateTokenResult{
 			Valid: false,
@@ -13,6 +13,7 @@ import (
 	"github.com/johntheyoung/roadrunner/internal/beeperapi"
 	"github.com/johntheyoung/roadrunner/internal/config"
 	"github.com/johntheyoung/roadrunner/internal/errfmt"
+	"github.com/johntheyoung/roadrunner/internal/media"
 	"github.com/johntheyoung/roadrunner/internal/outfmt"
 	"github.com/johntheyoung/roadrunner/internal/ui"
 )
@@ -29,29 +30,58 @@ type MessagesCmd struct {
 	Tail     MessagesTailCmd     `cmd:"" help:"Follow messages in a chat"`
 	Wait     MessagesWaitCmd     `cmd:"" help:"Wait for a matching message"`
 	Context  MessagesContextCmd  `cmd:"" help:"Fetch context around a message"`
+	Thread   MessagesThreadCmd   `cmd:"" help:"Show the reply thread containing a message"`
 }
 
 // MessagesListCmd lists messages in a chat.
 type MessagesListCmd struct {
-	ChatID        string   `arg:"" name:"chatID" help:"Chat ID to list messages from"`
-	Cursor        string   `help:"Pagination cursor (use sortKey from previous results)"`
-	Direction     string   `help:"Pagination direction: before|after" enum:"before,after," default:"before"`
-	All           bool     `help:"Fetch all pages automatically" name:"all"`
-	MaxItems      int      `help:"Maximum items to collect with --all (default 500, max 5000)" name:"max-items" default:"0"`
-	DownloadMedia bool     `help:"Download attachments for listed messages" name:"download-media"`
-	DownloadDir   string   `help:"Directory to save downloaded attachments" name:"download-dir" default:"."`
-	Fields        []string `help:"Comma-separated list of fields for --plain output" name:"fields" sep:","`
-	FailIfEmpty   bool     `help:"Exit with code 1 if no results" name:"fail-if-empty"`
+	ChatID         string   `arg:"" name:"chatID" help:"Chat ID to list messages from"`
+	Cursor         string   `help:"Pagination cursor (use sortKey from previous results)"`
+	Direction      string   `help:"Pagination direction: before|after" enum:"before,after," default:"before"`
+	All            bool     `help:"Fetch all pages automatically" name:"all"`
+	MaxItems       int      `help:"Maximum items to collect with --all (default 500, max 5000; unlimited when streaming)" name:"max-items" default:"0"`
+	MaxPages       int      `help:"Maximum pages to fetch with --all (default 1000 when streaming, otherwise unlimited)" name:"max-pages" default:"0"`
+	Checkpoint     string   `help:"Resume --all from a checkpoint file, saved after each page" name:"checkpoint" type:"path"`
+	DownloadMedia  bool     `help:"Download attachments for listed messages" name:"download-media"`
+	DownloadDir    string   `help:"Directory to save downloaded attachments" name:"download-dir" default:"."`
+	HydrateReplies bool     `help:"Embed a compact copy of the message each reply points to (reply_to)" name:"hydrate-replies"`
+	Fields         []string `help:"Comma-separated list of fields for --plain, --csv, --yaml, or --markdown output" name:"fields" sep:","`
+	FailIfEmpty    bool     `help:"Exit with code 1 if no results" name:"fail-if-empty"`
+	Contains       string   `help:"Only messages containing text (case-insensitive)" name:"contains"`
+	Regex          string   `help:"Only messages whose text matches a Go regular expression (use (?i) for case-insensitive)" name:"regex"`
+	Sender         string   `help:"Only messages from a sender ID or name" name:"sender"`
+	Since          string   `help:"Only messages at or after time (RFC3339 or duration)" name:"since"`
+	Until          string   `help:"Only messages at or before time (RFC3339 or duration)" name:"until"`
+	HasAttachment  bool     `help:"Only messages with attachments" name:"has-attachment"`
+	AttachmentType []string `help:"Only messages with an attachment of type: image|video|audio|file|gif|sticker|voice (repeatable)" name:"attachment-type" sep:","`
+	FromMe         bool     `help:"Only messages sent by you" name:"from-me"`
+	FromOthers     bool     `help:"Only messages sent by others" name:"from-others"`
+	Unread         bool     `help:"Only unread messages" name:"unread"`
+	Limit          int      `help:"Stop after this many matching messages (with --all, stops paging early)" name:"limit" default:"0"`
 }
 
 // Run executes the messages list command.
 func (c *MessagesListCmd) Run(ctx context.Context, flags *RootFlags) error {
 	u := ui.FromContext(ctx)
 	chatID := normalizeChatID(c.ChatID)
-	autoPageLimit, err := resolveAutoPageLimit(c.All, c.MaxItems)
+	limits, err := resolveAutoPageLimits(ctx, c.All, c.MaxItems, c.MaxPages)
 	if err != nil {
 		return err
 	}
+	filter, err := c.messageFilter()
+	if err != nil {
+		return err
+	}
+	if c.Limit < 0 || (c.All && !limits.Stream && c.Limit > maxAutoPageItems) {
+		return errfmt.UsageError("invalid --limit %d (expected 1-%d)", c.Limit, maxAutoPageItems)
+	}
+	// --limit caps --all like --max-items, but counts only matches.
+	limitFlag := "--max-items"
+	if c.All && c.Limit > 0 && (c.MaxItems == 0 || c.Limit < limits.MaxItems) {
+		limits.MaxItems = c.Limit
+		limitFlag = "--limit"
+	}
+	autoPageLimit := limits.MaxItems
 
 	token, _, err := config.GetToken()
 	if err != nil {
@@ -59,113 +89,148 @@ func (c *MessagesListCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	timeout := time.Duration(flags.Timeout) * time.Second
-	client, err := beeperapi.NewClient(token, flags.BaseURL, timeout)
+	client, err := newAPIClient(ctx, token, flags.BaseURL, timeout)
 	if err != nil {
 		return err
 	}
 
-	resp, err := client.Messages().List(ctx, chatID, beeperapi.MessageListParams{
-		Cursor:    c.Cursor,
-		Direction: c.Direction,
-	})
+	listPage := func(cursor string) (beeperapi.MessageListResult, error) {
+		page, err := client.Messages().List(ctx, chatID, beeperapi.MessageListParams{
+			Cursor:    cursor,
+			Direction: c.Direction,
+		})
+		page.Items = filter.apply(page.Items)
+		return page, err
+	}
+
+	scope := append([]string{chatID, c.Direction, fmt.Sprint(c.Limit)}, filter.scope(c.Since, c.Until)...)
+	checkpoint, err := openPageCheckpoint(c.Checkpoint, c.All, "messages list", scope...)
+	if err != nil {
+		return err
+	}
+	startCursor := checkpoint.resumeCursor(c.Cursor)
+	resp, err := listPage(startCursor)
 	if err != nil {
 		return err
 	}
 	capped := false
+	pages := 1
+	streamed := 0
 	if c.All {
-		items := make([]beeperapi.MessageItem, 0, len(resp.Items))
-		items = append(items, resp.Items...)
-		cursor := c.Cursor
-
-		for resp.HasMore {
-			if limitReached(len(items), autoPageLimit) {
-				capped = true
-				break
-			}
-
-			nextCursor := resp.NextCursor
-			if nextCursor == "" || nextCursor == cursor {
-				break
+		pager := autoPager[beeperapi.MessageItem]{
+			Limits:     limits,
+			Cursor:     startCursor,
+			Key:        func(item beeperapi.MessageItem) string { return item.ID },
+			Checkpoint: checkpoint,
+			Prefetch:   fetchConcurrency(ctx, flags) > 1,
+			Fetch: func(cursor string) (autoPage[beeperapi.MessageItem], error) {
+				page, err := listPage(cursor)
+				if err != nil {
+					return autoPage[beeperapi.MessageItem]{}, err
+				}
+				resp.HasMore = page.HasMore
+				resp.NextCursor = page.NextCursor
+				return autoPage[beeperapi.MessageItem]{
+					Items:   page.Items,
+					HasMore: page.HasMore,
+					Next:    page.NextCursor,
+				}, nil
+			},
+		}
+		if (c.DownloadMedia || c.HydrateReplies) && limits.Stream {
+			pager.Prepare = func(items []beeperapi.MessageItem) error {
+				if c.DownloadMedia {
+					if err := downloadMessageAttachments(ctx, client, items, c.DownloadDir); err != nil {
+						return err
+					}
+				}
+				if c.HydrateReplies {
+					return client.Messages().HydrateReplies(ctx, items, 0)
+				}
+				return nil
 			}
-			cursor = nextCursor
-
-			page, err := client.Messages().List(ctx, chatID, beeperapi.MessageListParams{
-				Cursor:    nextCursor,
-				Direction: c.Direction,
-			})
-			if err != nil {
-				return err
+		}
+		res, err := pager.run(ctx, autoPage[beeperapi.MessageItem]{
+			Items:   resp.Items,
+			HasMore: resp.HasMore,
+			Next:    resp.NextCursor,
+		})
+		if err != nil {
+			return err
+		}
+		capped, pages, streamed = res.Capped, res.Pages, res.Count
+		resp.Items = res.Items
+		if capped {
+			resp.HasMore = true
+			if cursor := lastSortKey(res.LastPage); cursor != "" {
+				resp.NextCursor = cursor
 			}
-
-			items = append(items, page.Items...)
-			resp.HasMore = page.HasMore
-			resp.NextCursor = page.NextCursor
 		}
+	}
 
-		if limitReached(len(items), autoPageLimit) {
-			if len(items) > autoPageLimit {
-				items = items[:autoPageLimit]
-			}
-			capped = true
-			resp.HasMore = true
-			resp.NextCursor = lastSortKey(items)
+	if !c.All && c.Limit > 0 && len(resp.Items) > c.Limit {
+		resp.Items = resp.Items[:c.Limit]
+		resp.HasMore = true
+		if cursor := lastSortKey(resp.Items); cursor != "" {
+			resp.NextCursor = cursor
 		}
-		resp.Items = items
 	}
 
-	if c.DownloadMedia {
+	if c.DownloadMedia && !limits.Stream {
 		if err := downloadMessageAttachments(ctx, client, resp.Items, c.DownloadDir); err != nil {
 			return err
 		}
 	}
+	if c.HydrateReplies && !limits.Stream {
+		if err := client.Messages().HydrateReplies(ctx, resp.Items, 0); err != nil {
+			return err
+		}
+	}
+
+	pagination := &outfmt.EnvelopePagination{
+		HasMore:    resp.HasMore,
+		Direction:  c.Direction,
+		NextCursor: resp.NextCursor,
+		AutoPaged:  c.All,
+		Capped:     capped,
+	}
+	if c.All {
+		pagination.MaxItems = autoPageLimit
+		pagination.Pages = pages
+		pagination.MaxPages = limits.MaxPages
+	}
+
+	if limits.Stream {
+		if err := writeStreamTrailer(ctx, "messages list", streamed, pagination); err != nil {
+			return err
+		}
+		return failIfEmpty(c.FailIfEmpty, streamed, "messages")
+	}
 
 	if err := failIfEmpty(c.FailIfEmpty, len(resp.Items), "messages"); err != nil {
 		return err
 	}
 
 	if outfmt.IsJSONL(ctx) {
-		return writeJSONLines(resp.Items)
+		if err := writeJSONLines(ctx, resp.Items); err != nil {
+			return err
+		}
+		return writeStreamTrailer(ctx, "messages list", len(resp.Items), pagination)
 	}
 
 	// JSON output
 	if outfmt.IsJSON(ctx) {
-		maxItems := 0
-		if c.All {
-			maxItems = autoPageLimit
-		}
-		return writeJSONWithPagination(ctx, resp, "messages list", &outfmt.EnvelopePagination{
-			HasMore:    resp.HasMore,
-			Direction:  c.Direction,
-			NextCursor: resp.NextCursor,
-			AutoPaged:  c.All,
-			Capped:     capped,
-			MaxItems:   maxItems,
-		})
+		return writeJSONWithPagination(ctx, resp, "messages list", pagination)
 	}
 
 	// Plain output (TSV)
-	if outfmt.IsPlain(ctx) {
-		fields, err := resolveFields(c.Fields, []string{"id", "sender_name", "timestamp", "text", "message_type", "linked_message_id", "chat_id", "sort_key", "account_id", "is_sender", "is_unread", "attachments_count", "reaction_keys", "downloaded_attachments"})
+	if outfmt.IsTabular(ctx) {
+		fields, err := resolveFields(c.Fields, replyFields(c.HydrateReplies, []string{"id", "sender_name", "timestamp", "text", "message_type", "linked_message_id", "chat_id", "sort_key", "account_id", "is_sender", "is_unread", "attachments_count", "reaction_keys", "downloaded_attachments"}))
 		if err != nil {
 			return err
 		}
 		for _, item := range resp.Items {
-			writePlainFields(u, fields, map[string]string{
-				"id":                     item.ID,
-				"account_id":             item.AccountID,
-				"chat_id":                item.ChatID,
-				"sender_name":            item.SenderName,
-				"timestamp":              item.Timestamp,
-				"text":                   ui.Truncate(item.Text, 50),
-				"message_type":           item.MessageType,
-				"linked_message_id":      item.LinkedMessageID,
-				"sort_key":               item.SortKey,
-				"is_sender":              formatBool(item.IsSender),
-				"is_unread":              formatBool(item.IsUnread),
-				"attachments_count":      fmt.Sprintf("%d", len(item.Attachments)),
-				"reaction_keys":          strings.Join(item.ReactionKeys, ","),
-				"downloaded_attachments": strings.Join(item.DownloadedAttachments, ","),
-			})
+			writePlainFields(ctx, fields, messagePlainValues(ctx, item))
 		}
 		return nil
 	}
@@ -186,13 +251,16 @@ func (c *MessagesListCmd) Run(ctx context.Context, flags *RootFlags) error {
 		}
 		text := ui.Truncate(item.Text, 60)
 		u.Out().Printf("  [%s] %s: %s", ts, item.SenderName, text)
+		if item.ReplyTo != nil {
+			u.Out().Dim(fmt.Sprintf("      ↪ %s: %s", item.ReplyTo.SenderName, ui.Truncate(item.ReplyTo.Text, 50)))
+		}
 	}
 
 	if resp.HasMore && resp.NextCursor != "" {
 		u.Out().Dim(fmt.Sprintf("\nMore messages available. Use --cursor=%q", resp.NextCursor))
 	}
 	if capped {
-		u.Out().Dim(autoPageStoppedMessage(autoPageLimit))
+		u.Out().Dim(autoPageStoppedMessageNamed(autoPageLimit, limitFlag))
 	}
 
 	return nil
@@ -213,9 +281,12 @@ type MessagesSearchCmd struct {
 	Cursor             string   `help:"Pagination cursor"`
 	Direction          string   `help:"Pagination direction: before|after" enum:"before,after," default:""`
 	Limit              int      `help:"Max results (1-20)" default:"20"`
-	Fields             []string `help:"Comma-separated list of fields for --plain output" name:"fields" sep:","`
+	Fields             []string `help:"Comma-separated list of fields for --plain, --csv, --yaml, or --markdown output" name:"fields" sep:","`
 	All                bool     `help:"Fetch all pages automatically" name:"all"`
-	MaxItems           int      `help:"Maximum items to collect with --all (default 500, max 5000)" name:"max-items" default:"0"`
+	MaxItems           int      `help:"Maximum items to collect with --all (default 500, max 5000; unlimited when streaming)" name:"max-items" default:"0"`
+	MaxPages           int      `help:"Maximum pages to fetch with --all (default 1000 when streaming, otherwise unlimited)" name:"max-pages" default:"0"`
+	Checkpoint         string   `help:"Resume --all from a checkpoint file, saved after each page" name:"checkpoint" type:"path"`
+	HydrateReplies     bool     `help:"Embed a compact copy of the message each reply points to (reply_to)" name:"hydrate-replies"`
 	FailIfEmpty        bool     `help:"Exit with code 1 if no results" name:"fail-if-empty"`
 }
 
@@ -231,12 +302,15 @@ type MessagesTailCmd struct {
 	StopAfter time.Duration `help:"Stop after duration (0=forever)" name:"stop-after" default:"0s"`
 }
 
-// MessagesContextCmd fetches messages around a sort key.
+// MessagesContextCmd fetches messages around an anchor message.
 type MessagesContextCmd struct {
-	ChatID  string `arg:"" name:"chatID" help:"Chat ID to fetch context from"`
-	SortKey string `arg:"" name:"sortKey" help:"Sort key of the anchor message"`
-	Before  int    `help:"Number of messages before the anchor" default:"10"`
-	After   int    `help:"Number of messages after the anchor" default:"0"`
+	ChatID    string `arg:"" name:"chatID" help:"Chat ID to fetch context from"`
+	SortKey   string `arg:"" optional:"" name:"sortKey" help:"Sort key of the anchor message"`
+	MessageID string `help:"Anchor on this message ID instead of a sort key" name:"message-id"`
+	At        string `help:"Anchor on the newest message at or before this time (RFC3339 or duration)" name:"at"`
+	Before    int    `help:"Number of messages before the anchor" default:"10"`
+	After     int    `help:"Number of messages after the anchor" default:"0"`
+	MaxPages  int    `help:"Maximum pages of chat history to scan for --message-id/--at (1-200)" name:"max-pages" default:"25"`
 }
 
 // MessagesWaitCmd waits for a message that matches filters.
@@ -253,6 +327,7 @@ type MessagesEditCmd struct {
 	ChatID          string `arg:"" optional:"" name:"chatID" help:"Chat ID containing the message"`
 	MessageID       string `arg:"" optional:"" name:"messageID" help:"Message ID to edit"`
 	Chat            string `help:"Exact chat title/display name or ID (alternative to chatID arg)" name:"chat"`
+	Match           string `help:"How --chat matches chat names: exact|prefix|fuzzy" name:"match" enum:"exact,prefix,fuzzy" default:"exact"`
 	Text            string `arg:"" optional:"" help:"Replacement message text"`
 	TextFile        string `help:"Read replacement text from file ('-' for stdin)" name:"text-file"`
 	Stdin           bool   `help:"Read replacement text from stdin" name:"stdin"`
@@ -280,10 +355,11 @@ func (c *MessagesSearchCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if c.Limit < 1 || c.Limit > 20 {
 		return errfmt.UsageError("invalid --limit %d (expected 1-20)", c.Limit)
 	}
-	autoPageLimit, err := resolveAutoPageLimit(c.All, c.MaxItems)
+	limits, err := resolveAutoPageLimits(ctx, c.All, c.MaxItems, c.MaxPages)
 	if err != nil {
 		return err
 	}
+	autoPageLimit := limits.MaxItems
 	allowedMedia := map[string]struct{}{
 		"any":   {},
 		"image": {},
@@ -321,130 +397,146 @@ func (c *MessagesSearchCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	timeout := time.Duration(flags.Timeout) * time.Second
-	client, err := beeperapi.NewClient(token, flags.BaseURL, timeout)
+	client, err := newAPIClient(ctx, token, flags.BaseURL, timeout)
 	if err != nil {
 		return err
 	}
 
-	resp, err := client.Messages().Search(ctx, beeperapi.MessageSearchParams{
-		Query:              c.Query,
-		AccountIDs:         c.AccountIDs,
-		ChatIDs:            normalizeChatIDs(c.ChatIDs),
-		ChatType:           c.ChatType,
-		Sender:             c.Sender,
-		MediaTypes:         c.MediaTypes,
-		DateAfter:          dateAfter,
-		DateBefore:         dateBefore,
-		IncludeMuted:       c.IncludeMuted,
-		ExcludeLowPriority: c.ExcludeLowPriority,
-		Cursor:             c.Cursor,
-		Direction:          c.Direction,
-		Limit:              c.Limit,
-	})
+	searchPage := func(cursor string) (beeperapi.MessageSearchResult, error) {
+		return client.Messages().Search(ctx, beeperapi.MessageSearchParams{
+			Query:              c.Query,
+			AccountIDs:         c.AccountIDs,
+			ChatIDs:            normalizeChatIDs(c.ChatIDs),
+			ChatType:           c.ChatType,
+			Sender:             c.Sender,
+			MediaTypes:         c.MediaTypes,
+			DateAfter:          dateAfter,
+			DateBefore:         dateBefore,
+			IncludeMuted:       c.IncludeMuted,
+			ExcludeLowPriority: c.ExcludeLowPriority,
+			Cursor:             cursor,
+			Direction:          c.Direction,
+			Limit:              c.Limit,
+		})
+	}
+
+	checkpoint, err := openPageCheckpoint(c.Checkpoint, c.All, "messages search",
+		c.Query,
+		strings.Join(c.AccountIDs, ","),
+		strings.Join(normalizeChatIDs(c.ChatIDs), ","),
+		c.ChatType,
+		c.Sender,
+		strings.Join(c.MediaTypes, ","),
+		c.DateAfter,
+		c.DateBefore,
+		formatBool(c.IncludeMuted == nil || *c.IncludeMuted),
+		formatBool(c.ExcludeLowPriority == nil || *c.ExcludeLowPriority),
+		fmt.Sprint(c.Limit),
+		c.Direction,
+	)
+	if err != nil {
+		return err
+	}
+	startCursor := checkpoint.resumeCursor(c.Cursor)
+	resp, err := searchPage(startCursor)
 	if err != nil {
 		return err
 	}
 	capped := false
+	pages := 1
+	streamed := 0
 	if c.All {
-		items := make([]beeperapi.MessageItem, 0, len(resp.Items))
-		items = append(items, resp.Items...)
-		lastCursor := c.Cursor
-		for resp.HasMore {
-			if limitReached(len(items), autoPageLimit) {
-				capped = true
-				break
-			}
-
-			nextCursor := nextSearchCursor(c.Direction, resp.OldestCursor, resp.NewestCursor)
-			if nextCursor == "" || nextCursor == lastCursor {
-				break
-			}
-			lastCursor = nextCursor
-
-			page, err := client.Messages().Search(ctx, beeperapi.MessageSearchParams{
-				Query:              c.Query,
-				AccountIDs:         c.AccountIDs,
-				ChatIDs:            normalizeChatIDs(c.ChatIDs),
-				ChatType:           c.ChatType,
-				Sender:             c.Sender,
-				MediaTypes:         c.MediaTypes,
-				DateAfter:          dateAfter,
-				DateBefore:         dateBefore,
-				IncludeMuted:       c.IncludeMuted,
-				ExcludeLowPriority: c.ExcludeLowPriority,
-				Cursor:             nextCursor,
-				Direction:          c.Direction,
-				Limit:              c.Limit,
-			})
-			if err != nil {
-				return err
-			}
-
-			items = append(items, page.Items...)
-			resp.HasMore = page.HasMore
-			resp.OldestCursor = page.OldestCursor
-			resp.NewestCursor = page.NewestCursor
+		pager := autoPager[beeperapi.MessageItem]{
+			Limits:     limits,
+			Cursor:     startCursor,
+			Key:        func(item beeperapi.MessageItem) string { return item.ChatID + "/" + item.ID },
+			Checkpoint: checkpoint,
+			Prefetch:   fetchConcurrency(ctx, flags) > 1,
+			Fetch: func(cursor string) (autoPage[beeperapi.MessageItem], error) {
+				page, err := searchPage(cursor)
+				if err != nil {
+					return autoPage[beeperapi.MessageItem]{}, err
+				}
+				resp.HasMore = page.HasMore
+				resp.OldestCursor = page.OldestCursor
+				resp.NewestCursor = page.NewestCursor
+				return autoPage[beeperapi.MessageItem]{
+					Items:   page.Items,
+					HasMore: page.HasMore,
+					Next:    nextSearchCursor(c.Direction, page.OldestCursor, page.NewestCursor),
+				}, nil
+			},
 		}
-		if limitReached(len(items), autoPageLimit) {
-			if len(items) > autoPageLimit {
-				items = items[:autoPageLimit]
+		if c.HydrateReplies && limits.Stream {
+			pager.Prepare = func(items []beeperapi.MessageItem) error {
+				return client.Messages().HydrateReplies(ctx, items, 0)
 			}
-			capped = true
 		}
-		resp.Items = items
+		res, err := pager.run(ctx, autoPage[beeperapi.MessageItem]{
+			Items:   resp.Items,
+			HasMore: resp.HasMore,
+			Next:    nextSearchCursor(c.Direction, resp.OldestCursor, resp.NewestCursor),
+		})
+		if err != nil {
+			return err
+		}
+		capped, pages, streamed = res.Capped, res.Pages, res.Count
+		resp.Items = res.Items
 		if capped {
 			resp.HasMore = true
 		}
 	}
+	if c.HydrateReplies && !limits.Stream {
+		if err := client.Messages().HydrateReplies(ctx, resp.Items, 0); err != nil {
+			return err
+		}
+	}
+
+	pagination := &outfmt.EnvelopePagination{
+		HasMore:      resp.HasMore,
+		Direction:    c.Direction,
+		OldestCursor: resp.OldestCursor,
+		NewestCursor: resp.NewestCursor,
+		AutoPaged:    c.All,
+		Capped:       capped,
+	}
+	if c.All {
+		pagination.MaxItems = autoPageLimit
+		pagination.Pages = pages
+		pagination.MaxPages = limits.MaxPages
+	}
+
+	if limits.Stream {
+		if err := writeStreamTrailer(ctx, "messages search", streamed, pagination); err != nil {
+			return err
+		}
+		return failIfEmpty(c.FailIfEmpty, streamed, "messages")
+	}
 
 	if err := failIfEmpty(c.FailIfEmpty, len(resp.Items), "messages"); err != nil {
 		return err
 	}
 
 	if outfmt.IsJSONL(ctx) {
-		return writeJSONLines(resp.Items)
+		if err := writeJSONLines(ctx, resp.Items); err != nil {
+			return err
+		}
+		return writeStreamTrailer(ctx, "messages search", len(resp.Items), pagination)
 	}
 
 	// JSON output
 	if outfmt.IsJSON(ctx) {
-		maxItems := 0
-		if c.All {
-			maxItems = autoPageLimit
-		}
-		return writeJSONWithPagination(ctx, resp, "messages search", &outfmt.EnvelopePagination{
-			HasMore:      resp.HasMore,
-			Direction:    c.Direction,
-			OldestCursor: resp.OldestCursor,
-			NewestCursor: resp.NewestCursor,
-			AutoPaged:    c.All,
-			Capped:       capped,
-			MaxItems:     maxItems,
-		})
+		return writeJSONWithPagination(ctx, resp, "messages search", pagination)
 	}
 
 	// Plain output (TSV)
-	if outfmt.IsPlain(ctx) {
-		fields, err := resolveFields(c.Fields, []string{"id", "chat_id", "sender_name", "text", "message_type", "linked_message_id", "timestamp", "sort_key", "account_id", "is_sender", "is_unread", "attachments_count", "reaction_keys", "downloaded_attachments"})
+	if outfmt.IsTabular(ctx) {
+		fields, err := resolveFields(c.Fields, replyFields(c.HydrateReplies, []string{"id", "chat_id", "sender_name", "text", "message_type", "linked_message_id", "timestamp", "sort_key", "account_id", "is_sender", "is_unread", "attachments_count", "reaction_keys", "downloaded_attachments"}))
 		if err != nil {
 			return err
 		}
 		for _, item := range resp.Items {
-			writePlainFields(u, fields, map[string]string{
-				"id":                     item.ID,
-				"account_id":             item.AccountID,
-				"chat_id":                item.ChatID,
-				"sender_name":            item.SenderName,
-				"timestamp":              item.Timestamp,
-				"text":                   ui.Truncate(item.Text, 50),
-				"message_type":           item.MessageType,
-				"linked_message_id":      item.LinkedMessageID,
-				"sort_key":               item.SortKey,
-				"is_sender":              formatBool(item.IsSender),
-				"is_unread":              formatBool(item.IsUnread),
-				"attachments_count":      fmt.Sprintf("%d", len(item.Attachments)),
-				"reaction_keys":          strings.Join(item.ReactionKeys, ","),
-				"downloaded_attachments": strings.Join(item.DownloadedAttachments, ","),
-			})
+			writePlainFields(ctx, fields, messagePlainValues(ctx, item))
 		}
 		return nil
 	}
@@ -466,6 +558,9 @@ func (c *MessagesSearchCmd) Run(ctx context.Context, flags *RootFlags) error {
 			}
 		}
 		text := ui.Truncate(item.Text, 50)
+		if item.ReplyTo != nil {
+			text += fmt.Sprintf(" (↪ %s: %s)", item.ReplyTo.SenderName, ui.Truncate(item.ReplyTo.Text, 30))
+		}
 		if _, err := fmt.Fprintf(w, "  [%s]\t%s:\t%s\n", ts, item.SenderName, text); err != nil {
 			return err
 		}
@@ -520,7 +615,7 @@ func (c *MessagesTailCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	timeout := time.Duration(flags.Timeout) * time.Second
-	client, err := beeperapi.NewClient(token, flags.BaseURL, timeout)
+	client, err := newAPIClient(ctx, token, flags.BaseURL, timeout)
 	if err != nil {
 		return err
 	}
@@ -567,11 +662,11 @@ func (c *MessagesTailCmd) Run(ctx context.Context, flags *RootFlags) error {
 				}
 				switch {
 				case outfmt.IsJSON(ctx):
-					if err := encoder.Encode(item); err != nil {
-						return fmt.Errorf("encode json: %w", err)
+					if err := writeJSONStream(ctx, encoder, item); err != nil {
+						return err
 					}
-				case outfmt.IsPlain(ctx):
-					u.Out().Printf("%s\t%s\t%s\t%s", item.ID, item.SenderName, item.Timestamp, ui.Truncate(item.Text, 50))
+				case outfmt.IsTabular(ctx):
+					writePlainRow(ctx, []string{"id", "sender_name", "timestamp", "text"}, item.ID, item.SenderName, item.Timestamp, plainText(ctx, item.Text))
 				default:
 					ts := ""
 					if item.Timestamp != "" {
@@ -610,7 +705,7 @@ func (c *MessagesWaitCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	timeout := time.Duration(flags.Timeout) * time.Second
-	client, err := beeperapi.NewClient(token, flags.BaseURL, timeout)
+	client, err := newAPIClient(ctx, token, flags.BaseURL, timeout)
 	if err != nil {
 		return err
 	}
@@ -747,6 +842,7 @@ func (c *MessagesEditCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if handled, err := handleDryRunWrite(ctx, flags, "messages edit", map[string]any{
 		"chat_id":    chatID,
 		"chat_query": chatQuery,
+		"match":      c.Match,
 		"message_id": messageID,
 		"text":       text,
 	}); handled {
@@ -759,12 +855,12 @@ func (c *MessagesEditCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	timeout := time.Duration(flags.Timeout) * time.Second
-	client, err := beeperapi.NewClient(token, flags.BaseURL, timeout)
+	client, err := newAPIClient(ctx, token, flags.BaseURL, timeout)
 	if err != nil {
 		return err
 	}
 	if chatQuery != "" {
-		chatID, err = resolveChatIDByQuery(ctx, client, chatQuery, applyAccountDefault(nil, flags.Account))
+		chatID, err = resolveChatIDByQuery(ctx, client, chatQuery, applyAccountDefault(nil, flags.Account), c.Match)
 		if err != nil {
 			return err
 		}
@@ -784,8 +880,8 @@ func (c *MessagesEditCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return writeJSON(ctx, resp, "messages edit")
 	}
 
-	if outfmt.IsPlain(ctx) {
-		u.Out().Printf("%s\t%s\t%t", resp.ChatID, resp.MessageID, resp.Success)
+	if outfmt.IsTabular(ctx) {
+		writePlainRow(ctx, []string{"chat_id", "message_id", "success"}, resp.ChatID, resp.MessageID, resp.Success)
 		return nil
 	}
 
@@ -836,7 +932,7 @@ func (c *MessagesReactCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	timeout := time.Duration(flags.Timeout) * time.Second
-	client, err := beeperapi.NewClient(token, flags.BaseURL, timeout)
+	client, err := newAPIClient(ctx, token, flags.BaseURL, timeout)
 	if err != nil {
 		return err
 	}
@@ -856,8 +952,8 @@ func (c *MessagesReactCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if outfmt.IsJSON(ctx) {
 		return writeJSON(ctx, result, "messages react")
 	}
-	if outfmt.IsPlain(ctx) {
-		u.Out().Printf("%s\t%s\t%s\ttrue", chatID, messageID, reactionKey)
+	if outfmt.IsTabular(ctx) {
+		writePlainRow(ctx, []string{"chat_id", "message_id", "reaction_key", "success"}, chatID, messageID, reactionKey, true)
 		return nil
 	}
 
@@ -904,7 +1000,7 @@ func (c *MessagesUnreactCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	timeout := time.Duration(flags.Timeout) * time.Second
-	client, err := beeperapi.NewClient(token, flags.BaseURL, timeout)
+	client, err := newAPIClient(ctx, token, flags.BaseURL, timeout)
 	if err != nil {
 		return err
 	}
@@ -924,8 +1020,8 @@ func (c *MessagesUnreactCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if outfmt.IsJSON(ctx) {
 		return writeJSON(ctx, result, "messages unreact")
 	}
-	if outfmt.IsPlain(ctx) {
-		u.Out().Printf("%s\t%s\t%s\ttrue", chatID, messageID, reactionKey)
+	if outfmt.IsTabular(ctx) {
+		writePlainRow(ctx, []string{"chat_id", "message_id", "reaction_key", "success"}, chatID, messageID, reactionKey, true)
 		return nil
 	}
 
@@ -944,78 +1040,85 @@ func (c *MessagesContextCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if c.Before < 0 || c.After < 0 {
 		return errfmt.UsageError("--before/--after must be >= 0")
 	}
-
-	token, _, err := config.GetToken()
-	if err != nil {
-		return err
+	anchors := 0
+	for _, v := range []string{c.SortKey, c.MessageID, c.At} {
+		if v != "" {
+			anchors++
+		}
 	}
-
-	timeout := time.Duration(flags.Timeout) * time.Second
-	client, err := beeperapi.NewClient(token, flags.BaseURL, timeout)
-	if err != nil {
-		return err
+	if anchors != 1 {
+		return errfmt.UsageError("provide exactly one of sortKey, --message-id, or --at")
 	}
-
-	beforeItems := []beeperapi.MessageItem{}
-	afterItems := []beeperapi.MessageItem{}
-
-	if c.Before > 0 {
-		resp, err := client.Messages().List(ctx, chatID, beeperapi.MessageListParams{
-			Cursor:    c.SortKey,
-			Direction: "before",
-		})
-		if err != nil {
-			return err
-		}
-		beforeItems = limitItems(resp.Items, c.Before)
+	if c.MaxPages < 1 || c.MaxPages > 200 {
+		return errfmt.UsageError("invalid --max-pages %d (expected 1-200)", c.MaxPages)
 	}
 
-	if c.After > 0 {
-		resp, err := client.Messages().List(ctx, chatID, beeperapi.MessageListParams{
-			Cursor:    c.SortKey,
-			Direction: "after",
-		})
+	params := beeperapi.MessageContextParams{
+		SortKey:   c.SortKey,
+		MessageID: c.MessageID,
+		Before:    c.Before,
+		After:     c.After,
+		MaxPages:  c.MaxPages,
+	}
+	if c.At != "" {
+		t, err := parseTime(c.At)
 		if err != nil {
-			return err
+			return errfmt.UsageError("invalid --at %q (expected RFC3339 or duration)", c.At)
 		}
-		afterItems = limitItems(resp.Items, c.After)
+		params.At = t
 	}
 
-	result := map[string]any{
-		"chat_id":  chatID,
-		"sort_key": c.SortKey,
-		"before":   beforeItems,
-		"after":    afterItems,
+	client, err := newClientFromFlags(ctx, flags)
+	if err != nil {
+		return err
+	}
+	result, err := client.Messages().Context(ctx, chatID, params)
+	if err != nil {
+		return err
 	}
 
 	if outfmt.IsJSON(ctx) {
 		return writeJSON(ctx, result, "messages context")
 	}
 
-	if outfmt.IsPlain(ctx) {
-		for _, item := range beforeItems {
-			u.Out().Printf("before\t%s\t%s\t%s\t%s", item.ID, item.SenderName, item.Timestamp, ui.Truncate(item.Text, 50))
+	if outfmt.IsTabular(ctx) {
+		row := func(section string, item beeperapi.MessageItem) {
+			writePlainRow(ctx, []string{"section", "id", "sender_name", "timestamp", "text"}, section, item.ID, item.SenderName, item.Timestamp, plainText(ctx, item.Text))
 		}
-		for _, item := range afterItems {
-			u.Out().Printf("after\t%s\t%s\t%s\t%s", item.ID, item.SenderName, item.Timestamp, ui.Truncate(item.Text, 50))
+		for _, item := range result.Before {
+			row("before", item)
+		}
+		if result.Anchor != nil {
+			row("anchor", *result.Anchor)
+		}
+		for _, item := range result.After {
+			row("after", item)
 		}
 		return nil
 	}
 
-	u.Out().Printf("Context around %s:", c.SortKey)
-	if len(beforeItems) == 0 {
+	if result.Anchor != nil {
+		u.Out().Printf("Context around %s (%s):", result.Anchor.ID, result.SortKey)
+	} else {
+		u.Out().Printf("Context around %s:", result.SortKey)
+	}
+	if len(result.Before) == 0 {
 		u.Out().Dim("No messages before")
 	} else {
 		u.Out().Printf("Before:")
-		for _, item := range beforeItems {
+		for _, item := range result.Before {
 			u.Out().Printf("  %s: %s", item.SenderName, ui.Truncate(item.Text, 60))
 		}
 	}
-	if len(afterItems) == 0 {
+	if result.Anchor != nil {
+		u.Out().Printf("Anchor:")
+		u.Out().Printf("  %s: %s", result.Anchor.SenderName, ui.Truncate(result.Anchor.Text, 60))
+	}
+	if len(result.After) == 0 {
 		u.Out().Dim("No messages after")
 	} else {
 		u.Out().Printf("After:")
-		for _, item := range afterItems {
+		for _, item := range result.After {
 			u.Out().Printf("  %s: %s", item.SenderName, ui.Truncate(item.Text, 60))
 		}
 	}
@@ -1027,6 +1130,9 @@ func (c *MessagesContextCmd) Run(ctx context.Context, flags *RootFlags) error {
 type MessagesSendCmd struct {
 	ChatID             string `arg:"" optional:"" name:"chatID" help:"Chat ID to send message to"`
 	Chat               string `help:"Exact chat title/display name or ID (alternative to chatID arg)" name:"chat"`
+	Match              string `help:"How --chat matches chat names: exact|prefix|fuzzy" name:"match" enum:"exact,prefix,fuzzy" default:"exact"`
+	Person             string `help:"Person to message across networks (see 'rr people'; alternative to chatID arg)" name:"person"`
+	PreferNetwork      string `help:"With --person, prefer contacts on this network or account ID" name:"prefer-network"`
 	Text               string `arg:"" optional:"" help:"Message text to send"`
 	ReplyToMessageID   string `help:"Message ID to reply to" name:"reply-to"`
 	TextFile           string `help:"Read message text from file ('-' for stdin)" name:"text-file"`
@@ -1045,6 +1151,7 @@ type MessagesSendCmd struct {
 type MessagesSendFileCmd struct {
 	ChatID             string `arg:"" optional:"" name:"chatID" help:"Chat ID to send message to"`
 	Chat               string `help:"Exact chat title/display name or ID (alternative to chatID arg)" name:"chat"`
+	Match              string `help:"How --chat matches chat names: exact|prefix|fuzzy" name:"match" enum:"exact,prefix,fuzzy" default:"exact"`
 	FilePath           string `arg:"" optional:"" name:"path" help:"Path to the file to upload and send"`
 	Text               string `arg:"" optional:"" help:"Optional message text"`
 	ReplyToMessageID   string `help:"Message ID to reply to" name:"reply-to"`
@@ -1059,17 +1166,42 @@ type MessagesSendFileCmd struct {
 	AttachmentDuration string `help:"Attachment duration override in seconds" name:"attachment-duration"`
 	AttachmentWidth    string `help:"Attachment width override in pixels (requires --attachment-height)" name:"attachment-width"`
 	AttachmentHeight   string `help:"Attachment height override in pixels (requires --attachment-width)" name:"attachment-height"`
+	StripMetadata      bool   `help:"Remove EXIF (including GPS location), XMP, and comments from JPEGs before upload" name:"strip-metadata"`
+	NoDetect           bool   `help:"Do not detect MIME type, dimensions, duration, or voice notes from the file" name:"no-detect"`
 }
 
 // Run executes the messages send command.
 func (c *MessagesSendCmd) Run(ctx context.Context, flags *RootFlags) error {
 	u := ui.FromContext(ctx)
-	chatID, chatQuery, err := resolveChatTargetInput(c.ChatID, c.Chat)
-	if err != nil {
-		return err
+	chatIDInput := c.ChatID
+	textInput := c.Text
+	person := strings.TrimSpace(c.Person)
+	preferNetwork := strings.TrimSpace(c.PreferNetwork)
+	// With --chat or --person, a lone positional is the message text
+	// unless it is clearly a chat ID, which conflicts with them.
+	if (c.Chat != "" || person != "") && strings.TrimSpace(textInput) == "" && strings.TrimSpace(chatIDInput) != "" &&
+		!beeperapi.LooksLikeChatID(strings.TrimLeft(chatIDInput, `\`)) {
+		textInput = chatIDInput
+		chatIDInput = ""
+	}
+	if preferNetwork != "" && person == "" {
+		return errfmt.UsageError("--prefer-network requires --person")
 	}
 
-	text, err := resolveTextInput(c.Text, c.TextFile, c.Stdin, false, "message text", "--text-file", "--stdin")
+	var chatID, chatQuery string
+	if person != "" {
+		if strings.TrimSpace(chatIDInput) != "" || strings.TrimSpace(c.Chat) != "" {
+			return errfmt.UsageError("cannot use --person with chatID argument or --chat")
+		}
+	} else {
+		var err error
+		chatID, chatQuery, err = resolveChatTargetInput(chatIDInput, c.Chat)
+		if err != nil {
+			return err
+		}
+	}
+
+	text, err := resolveTextInput(textInput, c.TextFile, c.Stdin, false, "message text", "--text-file", "--stdin")
 	if err != nil {
 		return err
 	}
@@ -1130,11 +1262,17 @@ func (c *MessagesSendCmd) Run(ctx context.Context, flags *RootFlags) error {
 			Height:   attachmentHeight,
 		}
 	}
-	if handled, err := handleDryRunWrite(ctx, flags, "messages send", map[string]any{
+	plan := map[string]any{
 		"chat_id":    chatID,
 		"chat_query": chatQuery,
+		"match":      c.Match,
 		"params":     params,
-	}); handled {
+	}
+	if person != "" {
+		plan["person"] = person
+		plan["prefer_network"] = preferNetwork
+	}
+	if handled, err := handleDryRunWrite(ctx, flags, "messages send", plan); handled {
 		return err
 	}
 
@@ -1144,15 +1282,22 @@ func (c *MessagesSendCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	timeout := time.Duration(flags.Timeout) * time.Second
-	client, err := beeperapi.NewClient(token, flags.BaseURL, timeout)
+	client, err := newAPIClient(ctx, token, flags.BaseURL, timeout)
 	if err != nil {
 		return err
 	}
 	if chatQuery != "" {
-		chatID, err = resolveChatIDByQuery(ctx, client, chatQuery, applyAccountDefault(nil, flags.Account))
+		chatID, err = resolveChatIDByQuery(ctx, client, chatQuery, applyAccountDefault(nil, flags.Account), c.Match)
+		if err != nil {
+			return err
+		}
+	}
+	if person != "" {
+		target, err := resolvePersonChat(ctx, client, flags, person, preferNetwork)
 		if err != nil {
 			return err
 		}
+		chatID = target.ChatID
 	}
 	if err := checkAndRememberNonIdempotentDuplicate(ctx, flags, "messages send", struct {
 		ChatID             string   `json:"chat_id"`
@@ -1191,8 +1336,8 @@ func (c *MessagesSendCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	// Plain output
-	if outfmt.IsPlain(ctx) {
-		u.Out().Printf("%s\t%s", resp.ChatID, resp.PendingMessageID)
+	if outfmt.IsTabular(ctx) {
+		writePlainRow(ctx, []string{"chat_id", "pending_message_id"}, resp.ChatID, resp.PendingMessageID)
 		return nil
 	}
 
@@ -1247,21 +1392,40 @@ func (c *MessagesSendFileCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if err := validateResourceID(c.ReplyToMessageID, "reply-to"); err != nil {
 		return err
 	}
+	meta := attachmentMeta{
+		uploadMimeType: c.MimeType,
+		attachmentType: c.AttachmentType,
+		duration:       attachmentDuration,
+		width:          attachmentWidth,
+		height:         attachmentHeight,
+	}
+	var detected *media.Info
+	if !c.NoDetect {
+		var info media.Info
+		meta, info, err = detectAttachmentMeta(filePath, meta)
+		if err != nil {
+			return err
+		}
+		detected = &info
+	}
 	if handled, err := handleDryRunWrite(ctx, flags, "messages send-file", map[string]any{
-		"chat_id":    chatID,
-		"chat_query": chatQuery,
-		"file_path":  filePath,
-		"text":       text,
-		"reply_to":   c.ReplyToMessageID,
-		"file_name":  c.FileName,
-		"mime_type":  c.MimeType,
+		"chat_id":        chatID,
+		"chat_query":     chatQuery,
+		"match":          c.Match,
+		"file_path":      filePath,
+		"text":           text,
+		"reply_to":       c.ReplyToMessageID,
+		"file_name":      c.FileName,
+		"mime_type":      meta.uploadMimeType,
+		"strip_metadata": c.StripMetadata,
+		"detected":       detected,
 		"attachment": map[string]any{
 			"file_name": c.AttachmentFileName,
 			"mime_type": c.AttachmentMimeType,
-			"type":      c.AttachmentType,
-			"duration":  attachmentDuration,
-			"width":     attachmentWidth,
-			"height":    attachmentHeight,
+			"type":      meta.attachmentType,
+			"duration":  meta.duration,
+			"width":     meta.width,
+			"height":    meta.height,
 		},
 	}); handled {
 		return err
@@ -1273,12 +1437,12 @@ func (c *MessagesSendFileCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	timeout := time.Duration(flags.Timeout) * time.Second
-	client, err := beeperapi.NewClient(token, flags.BaseURL, timeout)
+	client, err := newAPIClient(ctx, token, flags.BaseURL, timeout)
 	if err != nil {
 		return err
 	}
 	if chatQuery != "" {
-		chatID, err = resolveChatIDByQuery(ctx, client, chatQuery, applyAccountDefault(nil, flags.Account))
+		chatID, err = resolveChatIDByQuery(ctx, client, chatQuery, applyAccountDefault(nil, flags.Account), c.Match)
 		if err != nil {
 			return err
 		}
@@ -1296,27 +1460,30 @@ func (c *MessagesSendFileCmd) Run(ctx context.Context, flags *RootFlags) error {
 		AttachmentDuration *float64 `json:"attachment_duration,omitempty"`
 		AttachmentWidth    *float64 `json:"attachment_width,omitempty"`
 		AttachmentHeight   *float64 `json:"attachment_height,omitempty"`
+		StripMetadata      bool     `json:"strip_metadata,omitempty"`
 	}{
 		ChatID:             chatID,
 		FilePath:           filePath,
 		Text:               text,
 		ReplyToMessageID:   c.ReplyToMessageID,
 		FileName:           c.FileName,
-		MimeType:           c.MimeType,
+		MimeType:           meta.uploadMimeType,
 		AttachmentFileName: c.AttachmentFileName,
 		AttachmentMimeType: c.AttachmentMimeType,
-		AttachmentType:     c.AttachmentType,
-		AttachmentDuration: attachmentDuration,
-		AttachmentWidth:    attachmentWidth,
-		AttachmentHeight:   attachmentHeight,
+		AttachmentType:     meta.attachmentType,
+		AttachmentDuration: meta.duration,
+		AttachmentWidth:    meta.width,
+		AttachmentHeight:   meta.height,
+		StripMetadata:      c.StripMetadata,
 	}); err != nil {
 		return err
 	}
 
 	upload, err := client.Assets().Upload(ctx, beeperapi.AssetUploadParams{
-		FilePath: filePath,
-		FileName: c.FileName,
-		MimeType: c.MimeType,
+		FilePath:      filePath,
+		FileName:      c.FileName,
+		MimeType:      meta.uploadMimeType,
+		StripMetadata: c.StripMetadata,
 	})
 	if err != nil {
 		if beeperapi.IsUnsupportedRoute(err, "POST", "/assets/upload") {
@@ -1332,10 +1499,10 @@ func (c *MessagesSendFileCmd) Run(ctx context.Context, flags *RootFlags) error {
 			UploadID: upload.UploadID,
 			FileName: c.AttachmentFileName,
 			MimeType: c.AttachmentMimeType,
-			Type:     c.AttachmentType,
-			Duration: attachmentDuration,
-			Width:    attachmentWidth,
-			Height:   attachmentHeight,
+			Type:     meta.attachmentType,
+			Duration: meta.duration,
+			Width:    meta.width,
+			Height:   meta.height,
 		},
 	})
 	if err != nil {
@@ -1344,13 +1511,14 @@ func (c *MessagesSendFileCmd) Run(ctx context.Context, flags *RootFlags) error {
 
 	if outfmt.IsJSON(ctx) {
 		return writeJSON(ctx, map[string]any{
-			"upload":  upload,
-			"message": resp,
+			"upload":   upload,
+			"message":  resp,
+			"detected": detected,
 		}, "messages send-file")
 	}
 
-	if outfmt.IsPlain(ctx) {
-		u.Out().Printf("%s\t%s\t%s", resp.ChatID, resp.PendingMessageID, upload.UploadID)
+	if outfmt.IsTabular(ctx) {
+		writePlainRow(ctx, []string{"chat_id", "pending_message_id", "upload_id"}, resp.ChatID, resp.PendingMessageID, upload.UploadID)
 		return nil
 	}
 
@@ -1358,6 +1526,9 @@ func (c *MessagesSendFileCmd) Run(ctx context.Context, flags *RootFlags) error {
 	u.Out().Printf("Chat ID:    %s", resp.ChatID)
 	u.Out().Printf("Pending ID: %s", resp.PendingMessageID)
 	u.Out().Printf("Upload ID:  %s", upload.UploadID)
+	if detected != nil && detected.MimeType != "" {
+		u.Out().Printf("Detected:   %s", describeMediaInfo(*detected))
+	}
 
 	return nil
 }
@@ -1374,6 +1545,41 @@ func parseOptionalFloatFlag(value string, flagName string) (*float64, error) {
 	return &parsed, nil
 }
 
+// replyFields adds the reply_to_* plain fields to fields when replies are
+// hydrated.
+func replyFields(hydrated bool, fields []string) []string {
+	if !hydrated {
+		return fields
+	}
+	return append(fields, "reply_to_id", "reply_to_sender", "reply_to_text")
+}
+
+// messagePlainValues returns the plain output field values of a message.
+func messagePlainValues(ctx context.Context, item beeperapi.MessageItem) map[string]any {
+	values := map[string]any{
+		"id":                     item.ID,
+		"account_id":             item.AccountID,
+		"chat_id":                item.ChatID,
+		"sender_name":            item.SenderName,
+		"timestamp":              item.Timestamp,
+		"text":                   plainText(ctx, item.Text),
+		"message_type":           item.MessageType,
+		"linked_message_id":      item.LinkedMessageID,
+		"sort_key":               item.SortKey,
+		"is_sender":              item.IsSender,
+		"is_unread":              item.IsUnread,
+		"attachments_count":      len(item.Attachments),
+		"reaction_keys":          strings.Join(item.ReactionKeys, ","),
+		"downloaded_attachments": strings.Join(item.DownloadedAttachments, ","),
+	}
+	if item.ReplyTo != nil {
+		values["reply_to_id"] = item.ReplyTo.ID
+		values["reply_to_sender"] = item.ReplyTo.SenderName
+		values["reply_to_text"] = plainText(ctx, item.ReplyTo.Text)
+	}
+	return values
+}
+
 func firstSortKey(items []beeperapi.MessageItem) string {
 	for _, item := range items {
 		if item.SortKey != "" {
@@ -1392,13 +1598,6 @@ func lastSortKey(items []beeperapi.MessageItem) string {
 	return ""
 }
 
-func limitItems(items []beeperapi.MessageItem, limit int) []beeperapi.MessageItem {
-	if limit <= 0 || len(items) <= limit {
-		return items
-	}
-	return items[:limit]
-}
-
 func messageMatches(item beeperapi.MessageItem, contains, sender string, from, to *time.Time) bool {
 	if contains != "" {
 		if !strings.Contains(strings.ToLower(item.Text), strings.ToLower(contains)) {
@@ -1454,12 +1653,12 @@ func writeWaitResult(ctx context.Context, u *ui.UI, item beeperapi.MessageItem,
 	if outfmt.IsJSON(ctx) {
 		return writeJSON(ctx, item, "messages wait")
 	}
-	if outfmt.IsPlain(ctx) {
+	if outfmt.IsTabular(ctx) {
 		if includeChatID {
-			u.Out().Printf("%s\t%s\t%s\t%s\t%s", item.ID, item.ChatID, item.SenderName, item.Timestamp, ui.Truncate(item.Text, 50))
+			writePlainRow(ctx, []string{"id", "chat_id", "sender_name", "timestamp", "text"}, item.ID, item.ChatID, item.SenderName, item.Timestamp, plainText(ctx, item.Text))
 			return nil
 		}
-		u.Out().Printf("%s\t%s\t%s\t%s", item.ID, item.SenderName, item.Timestamp, ui.Truncate(item.Text, 50))
+		writePlainRow(ctx, []string{"id", "sender_name", "timestamp", "text"}, item.ID, item.SenderName, item.Timestamp, plainText(ctx, item.Text))
 		return nil
 	}
 
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/johntheyoung/roadrunner/internal/fakeapi"
+)
+
+func TestMessagesContextAnchors(t *testing.T) {
+	t.Setenv("BEEPER_TOKEN", "test-token")
+	t.Setenv("BEEPER_ACCESS_TOKEN", "")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	// 50 messages one minute apart, so windows and anchor scans cross the
+	// fake's 20-message pages.
+	ds := fakeapi.DefaultDataset()
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i := 1; i <= 50; i++ {
+		ds.Messages = append(ds.Messages, fakeapi.Message{
+			ID: fmt.Sprintf("$c%d", i), ChatID: "!team:beeper.local", SenderID: "@bob:beeper.local", SenderName: "Bob Example",
+			Timestamp: base.Add(time.Duration(i) * time.Minute), Text: fmt.Sprintf("line %d", i),
+		})
+	}
+
+	fake := fakeapi.New(ds)
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	defer fake.Close()
+
+	run := func(args ...string) (string, string, int) {
+		t.Helper()
+		var out, errText string
+		var code int
+		withArgs(t, append([]string{"rr", "--base-url", server.URL}, args...), func() {
+			out, errText = captureOutput(t, func() {
+				code = Execute()
+			})
+		})
+		return out, errText, code
+	}
+	type window struct {
+		SortKey string `json:"sort_key"`
+		Anchor  *struct {
+			ID string `json:"id"`
+		} `json:"anchor"`
+		Before []struct {
+			ID string `json:"id"`
+		} `json:"before"`
+		After []struct {
+			ID string `json:"id"`
+		} `json:"after"`
+	}
+	contextOf := func(args ...string) window {
+		t.Helper()
+		out, errText, code := run(append([]string{"--json", "messages", "context", "!team:beeper.local"}, args...)...)
+		if code != 0 {
+			t.Fatalf("context %v: exit code = %d, stderr = %q", args, code, errText)
+		}
+		var w window
+		if err := json.Unmarshal([]byte(out), &w); err != nil {
+			t.Fatalf("decode context %v: %v", args, err)
+		}
+		return w
+	}
+	ids := func(w window) string {
+		var parts []string
+		for _, m := range w.Before {
+			parts = append(parts, m.ID)
+		}
+		if w.Anchor != nil {
+			parts = append(parts, "["+w.Anchor.ID+"]")
+		}
+		for _, m := range w.After {
+			parts = append(parts, m.ID)
+		}
+		return strings.Join(parts, " ")
+	}
+
+	// $c5 is 45 messages back from the newest: the scan crosses three pages.
+	byID := contextOf("--message-id", "$c5", "--before", "2", "--after", "2")
+	if got := ids(byID); got != "$c3 $c4 [$c5] $c6 $c7" {
+		t.Fatalf("--message-id window = %q", got)
+	}
+
+	bySortKey := contextOf(byID.SortKey, "--before", "25", "--after", "0")
+	if bySortKey.Anchor == nil || bySortKey.Anchor.ID != "$c5" {
+		t.Fatalf("sortKey anchor = %+v, want $c5", bySortKey.Anchor)
+	}
+	if len(bySortKey.Before) != 6 || bySortKey.Before[0].ID != "$m2" || bySortKey.Before[5].ID != "$c4" {
+		t.Fatalf("sortKey before = %+v, want $m2..$c4 oldest first", bySortKey.Before)
+	}
+
+	byTime := contextOf("--at", "2026-03-01T12:20:30Z", "--before", "1", "--after", "25")
+	if byTime.Anchor == nil || byTime.Anchor.ID != "$c20" || len(byTime.After) != 25 || byTime.After[24].ID != "$c45" {
+		t.Fatalf("--at window = %q", ids(byTime))
+	}
+
+	_, errText, code := run("--json", "messages", "context", "!team:beeper.local", "--message-id", "$c1", "--max-pages", "1")
+	if code != 1 || !strings.Contains(errText, "message not found") {
+		t.Fatalf("anchor beyond --max-pages: exit code = %d, stderr = %q", code, errText)
+	}
+	for _, args := range [][]string{
+		{},
+		{"000000000003", "--message-id", "$c5"},
+		{"--at", "yesterday-ish"},
+	} {
+		_, _, code := run(append([]string{"messages", "context", "!team:beeper.local"}, args...)...)
+		if code != 2 {
+			t.Fatalf("context %v exit code = %d, want 2", args, code)
+		}
+	}
+}
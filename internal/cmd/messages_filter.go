@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/johntheyoung/roadrunner/internal/beeperapi"
+	"github.com/johntheyoung/roadrunner/internal/errfmt"
+)
+
+// attachmentKinds are the --attachment-type values.
+var attachmentKinds = []string{"image", "video", "audio", "file", "gif", "sticker", "voice"}
+
+// messageFilter holds the validated client-side filters of messages list.
+type messageFilter struct {
+	contains       string
+	regex          *regexp.Regexp
+	sender         string
+	since, until   *time.Time
+	hasAttachment  bool
+	attachmentType []string
+	fromMe         bool
+	fromOthers     bool
+	unread         bool
+}
+
+// messageFilter validates the filter flags.
+func (c *MessagesListCmd) messageFilter() (messageFilter, error) {
+	filter := messageFilter{
+		contains:      c.Contains,
+		sender:        c.Sender,
+		hasAttachment: c.HasAttachment,
+		fromMe:        c.FromMe,
+		fromOthers:    c.FromOthers,
+		unread:        c.Unread,
+	}
+	if c.FromMe && c.FromOthers {
+		return messageFilter{}, errfmt.UsageError("--from-me and --from-others are mutually exclusive")
+	}
+	if c.Regex != "" {
+		re, err := regexp.Compile(c.Regex)
+		if err != nil {
+			return messageFilter{}, errfmt.UsageError("invalid --regex %q: %v", c.Regex, err)
+		}
+		filter.regex = re
+	}
+	if c.Since != "" {
+		t, err := parseTime(c.Since)
+		if err != nil {
+			return messageFilter{}, errfmt.UsageError("invalid --since %q (expected RFC3339 or duration)", c.Since)
+		}
+		filter.since = &t
+	}
+	if c.Until != "" {
+		t, err := parseTime(c.Until)
+		if err != nil {
+			return messageFilter{}, errfmt.UsageError("invalid --until %q (expected RFC3339 or duration)", c.Until)
+		}
+		filter.until = &t
+	}
+	if filter.since != nil && filter.until != nil && filter.until.Before(*filter.since) {
+		return messageFilter{}, errfmt.UsageError("--until must not be before --since")
+	}
+	for _, kind := range c.AttachmentType {
+		kind = strings.ToLower(strings.TrimSpace(kind))
+		if !slices.Contains(attachmentKinds, kind) {
+			return messageFilter{}, errfmt.UsageError("invalid --attachment-type %q (expected %s)", kind, strings.Join(attachmentKinds, "|"))
+		}
+		filter.attachmentType = append(filter.attachmentType, kind)
+	}
+	return filter, nil
+}
+
+// scope identifies the filters in a --checkpoint, so a checkpoint is only
+// resumed with the filters it was written with. Relative --since/--until
+// durations are kept as given: they resolve to a new time on every run.
+func (f messageFilter) scope(since, until string) []string {
+	regex := ""
+	if f.regex != nil {
+		regex = f.regex.String()
+	}
+	return []string{
+		strings.ToLower(f.contains),
+		regex,
+		strings.ToLower(f.sender),
+		strings.TrimSpace(since),
+		strings.TrimSpace(until),
+		formatBool(f.hasAttachment),
+		strings.Join(slices.Compact(slices.Sorted(slices.Values(f.attachmentType))), ","),
+		formatBool(f.fromMe),
+		formatBool(f.fromOthers),
+		formatBool(f.unread),
+	}
+}
+
+// active reports whether any filter is set.
+func (f messageFilter) active() bool {
+	return f.contains != "" || f.regex != nil || f.sender != "" || f.since != nil || f.until != nil ||
+		f.hasAttachment || len(f.attachmentType) > 0 || f.fromMe || f.fromOthers || f.unread
+}
+
+// matches reports whether item passes every filter.
+func (f messageFilter) matches(item beeperapi.MessageItem) bool {
+	if !messageMatches(item, f.contains, f.sender, f.since, f.until) {
+		return false
+	}
+	if f.regex != nil && !f.regex.MatchString(item.Text) {
+		return false
+	}
+	fromMe := f.fromMe || strings.EqualFold(f.sender, "me")
+	fromOthers := f.fromOthers || strings.EqualFold(f.sender, "others")
+	if (fromMe && !item.IsSender) || (fromOthers && item.IsSender) {
+		return false
+	}
+	if f.unread && !item.IsUnread {
+		return false
+	}
+	if f.hasAttachment && len(item.Attachments) == 0 && !item.HasMedia {
+		return false
+	}
+	if len(f.attachmentType) > 0 {
+		found := false
+		for _, att := range item.Attachments {
+			// Gifs, stickers, and voice notes also match their base kind.
+			if slices.Contains(f.attachmentType, att.Kind()) || slices.Contains(f.attachmentType, att.BaseKind()) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// apply returns the items that match, reusing items' backing array.
+func (f messageFilter) apply(items []beeperapi.MessageItem) []beeperapi.MessageItem {
+	if !f.active() {
+		return items
+	}
+	out := items[:0]
+	for _, item := range items {
+		if f.matches(item) {
+			out = append(out, item)
+		}
+	}
+	return out
+}
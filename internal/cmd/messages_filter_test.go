@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/johntheyoung/roadrunner/internal/fakeapi"
+)
+
+func TestMessagesListFilters(t *testing.T) {
+	t.Setenv("BEEPER_TOKEN", "test-token")
+	t.Setenv("BEEPER_ACCESS_TOKEN", "")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	// 100 messages: every 10th is mine, every 25th has a voice note, and
+	// the newest five are unread.
+	ds := fakeapi.DefaultDataset()
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i := 1; i <= 100; i++ {
+		msg := fakeapi.Message{
+			ID: fmt.Sprintf("$l%d", i), ChatID: "!team:beeper.local", SenderID: "@bob:beeper.local", SenderName: "Bob Example",
+			Timestamp: base.Add(time.Duration(i) * time.Minute), Text: fmt.Sprintf("build %d passed", i),
+			IsUnread: i > 95,
+		}
+		if i%10 == 0 {
+			msg.SenderID, msg.SenderName, msg.IsSender = "@me:beeper.local", "Me", true
+			msg.Text = fmt.Sprintf("deploy %d", i)
+		}
+		if i%25 == 0 {
+			msg.Attachments = []fakeapi.Attachment{{Type: "audio", ID: fmt.Sprintf("mxc://beeper.local/v%d", i), MimeType: "audio/ogg", IsVoiceNote: true}}
+		}
+		ds.Messages = append(ds.Messages, msg)
+	}
+
+	fake := fakeapi.New(ds)
+	var listCalls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/messages") && r.Method == http.MethodGet {
+			listCalls.Add(1)
+		}
+		fake.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	defer fake.Close()
+
+	list := func(args ...string) []string {
+		t.Helper()
+		listCalls.Store(0)
+		var out, errText string
+		var code int
+		argv := append([]string{"rr", "--base-url", server.URL, "--json", "messages", "list", "!team:beeper.local"}, args...)
+		withArgs(t, argv, func() {
+			out, errText = captureOutput(t, func() {
+				code = Execute()
+			})
+		})
+		if code != 0 {
+			t.Fatalf("messages list %v: exit code = %d, stderr = %q", args, code, errText)
+		}
+		var resp struct {
+			Items []struct {
+				ID string `json:"id"`
+			} `json:"items"`
+		}
+		if err := json.Unmarshal([]byte(out), &resp); err != nil {
+			t.Fatalf("decode messages list %v: %v", args, err)
+		}
+		ids := make([]string, 0, len(resp.Items))
+		for _, item := range resp.Items {
+			ids = append(ids, item.ID)
+		}
+		return ids
+	}
+
+	tests := []struct {
+		args  []string
+		want  string
+		calls int32
+	}{
+		// Three matches are on the first two pages; paging stops there.
+		{[]string{"--all", "--from-me", "--limit", "3"}, "$l100 $l90 $l80", 2},
+		{[]string{"--all", "--regex", `^deploy [1-4]0$`}, "$l40 $l30 $l20 $l10", 6},
+		{[]string{"--all", "--attachment-type", "voice", "--sender", "me"}, "$l100 $l50", 6},
+		{[]string{"--all", "--has-attachment", "--from-others"}, "$l75 $l25", 6},
+		{[]string{"--unread", "--contains", "PASSED"}, "$l99 $l98 $l97 $l96", 1},
+		{[]string{"--all", "--since", "2026-03-01T13:35:00Z", "--until", "2026-03-01T13:37:00Z"}, "$l97 $l96 $l95", 6},
+		{[]string{"--limit", "2"}, "$l100 $l99", 1},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(list(tt.args...), " "); got != tt.want {
+			t.Errorf("messages list %v = %q, want %q", tt.args, got, tt.want)
+		}
+		if calls := listCalls.Load(); calls != tt.calls {
+			t.Errorf("messages list %v made %d list requests, want %d", tt.args, calls, tt.calls)
+		}
+	}
+
+	// A checkpoint only resumes with the filters it was written with.
+	checkpoint := filepath.Join(t.TempDir(), "list.checkpoint.json")
+	if ids := list("--all", "--checkpoint", checkpoint, "--contains", "Deploy", "--attachment-type", "voice,audio"); len(ids) != 2 {
+		t.Fatalf("checkpointed list = %d items, want 2", len(ids))
+	}
+	if ids := list("--all", "--checkpoint", checkpoint, "--contains", "deploy", "--attachment-type", "audio,voice"); len(ids) != 0 {
+		t.Fatalf("resumed list = %v, want nothing new", ids)
+	}
+
+	for _, args := range [][]string{
+		{"--from-me", "--from-others"},
+		{"--regex", "("},
+		{"--attachment-type", "pdf"},
+		{"--since", "2026-03-02", "--until", "2026-03-01"},
+		{"--limit", "-1"},
+		{"--all", "--checkpoint", checkpoint},
+		{"--all", "--checkpoint", checkpoint, "--contains", "deploy", "--limit", "5"},
+	} {
+		argv := append([]string{"rr", "--base-url", server.URL, "messages", "list", "!team:beeper.local"}, args...)
+		var code int
+		withArgs(t, argv, func() {
+			_, _ = captureOutput(t, func() {
+				code = Execute()
+			})
+		})
+		if code != 2 {
+			t.Errorf("messages list %v exit code = %d, want 2", args, code)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/johntheyoung/roadrunner/internal/beeperapi"
+	"github.com/johntheyoung/roadrunner/internal/errfmt"
+	"github.com/johntheyoung/roadrunner/internal/outfmt"
+	"github.com/johntheyoung/roadrunner/internal/ui"
+)
+
+// MessagesThreadCmd shows the reply thread containing a message.
+type MessagesThreadCmd struct {
+	ChatID    string `arg:"" name:"chatID" help:"Chat ID containing the message"`
+	MessageID string `arg:"" name:"messageID" help:"Message ID anywhere in the thread"`
+	MaxPages  int    `help:"Maximum pages of chat history to scan for the thread (1-200)" name:"max-pages" default:"25"`
+}
+
+// Run executes the messages thread command.
+func (c *MessagesThreadCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	chatID := normalizeChatID(c.ChatID)
+
+	if c.MaxPages < 1 || c.MaxPages > 200 {
+		return errfmt.UsageError("invalid --max-pages %d (expected 1-200)", c.MaxPages)
+	}
+
+	client, err := newClientFromFlags(ctx, flags)
+	if err != nil {
+		return err
+	}
+	thread, err := client.Messages().Thread(ctx, chatID, c.MessageID, beeperapi.ThreadParams{MaxPages: c.MaxPages})
+	if err != nil {
+		return err
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return writeJSON(ctx, thread, "messages thread")
+	}
+
+	if outfmt.IsTabular(ctx) {
+		walkThread(thread.Root, 0, func(msg beeperapi.ThreadMessage, depth int) {
+			writePlainRow(ctx, []string{"depth", "id", "linked_message_id", "sender_name", "timestamp", "text"}, depth, msg.ID, msg.LinkedMessageID, msg.SenderName, msg.Timestamp, plainText(ctx, msg.Text))
+		})
+		return nil
+	}
+
+	u.Out().Printf("Thread (%d messages):", thread.Count)
+	if thread.MissingParentID != "" {
+		u.Out().Dim(fmt.Sprintf("  Starts with a reply to %s, which was not found in the last %d pages (raise --max-pages)", thread.MissingParentID, thread.Pages))
+	}
+	walkThread(thread.Root, 0, func(msg beeperapi.ThreadMessage, depth int) {
+		ts := ""
+		if t, err := time.Parse(time.RFC3339, msg.Timestamp); err == nil {
+			ts = t.Format("Jan 2 15:04")
+		}
+		prefix := "  " + strings.Repeat("   ", depth)
+		if depth > 0 {
+			prefix += "↳ "
+		}
+		line := fmt.Sprintf("%s[%s] %s: %s", prefix, ts, msg.SenderName, ui.Truncate(msg.Text, 60))
+		if msg.ID == thread.MessageID {
+			line += "  ←"
+		}
+		u.Out().Println(line)
+	})
+	return nil
+}
+
+// walkThread calls fn for msg and its replies, depth first.
+func walkThread(msg beeperapi.ThreadMessage, depth int, fn func(beeperapi.ThreadMessage, int)) {
+	fn(msg, depth)
+	for _, reply := range msg.Replies {
+		walkThread(reply, depth+1, fn)
+	}
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/johntheyoung/roadrunner/internal/fakeapi"
+)
+
+func TestMessagesThreadAndHydrateReplies(t *testing.T) {
+	t.Setenv("BEEPER_TOKEN", "test-token")
+	t.Setenv("BEEPER_ACCESS_TOKEN", "")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	// $m2 <- $m3 <- $r1, plus $r2 replying to $m2 after enough filler that
+	// the root is a page further back than the newest reply.
+	ds := fakeapi.DefaultDataset()
+	base := time.Date(2026, 2, 11, 10, 0, 0, 0, time.UTC)
+	ds.Messages = append(ds.Messages, fakeapi.Message{
+		ID: "$r1", ChatID: "!team:beeper.local", SenderID: "@bob:beeper.local", SenderName: "Bob Example",
+		Timestamp: base, Text: "Anytime", LinkedMessageID: "$m3",
+	})
+	for i := 1; i <= 25; i++ {
+		ds.Messages = append(ds.Messages, fakeapi.Message{
+			ID: fmt.Sprintf("$f%d", i), ChatID: "!team:beeper.local", SenderID: "@bob:beeper.local", SenderName: "Bob Example",
+			Timestamp: base.Add(time.Duration(i) * time.Minute), Text: fmt.Sprintf("filler %d", i),
+		})
+	}
+	ds.Messages = append(ds.Messages, fakeapi.Message{
+		ID: "$r2", ChatID: "!team:beeper.local", SenderID: "@alice:beeper.local", SenderName: "Alice Example",
+		Timestamp: base.Add(time.Hour), Text: "Late to the party", LinkedMessageID: "$m2",
+	})
+
+	fake := fakeapi.New(ds)
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	defer fake.Close()
+
+	run := func(args ...string) (string, string, int) {
+		t.Helper()
+		var out, errText string
+		var code int
+		withArgs(t, append([]string{"rr", "--base-url", server.URL}, args...), func() {
+			out, errText = captureOutput(t, func() {
+				code = Execute()
+			})
+		})
+		return out, errText, code
+	}
+
+	out, errText, code := run("--json", "messages", "thread", "!team:beeper.local", "$r1")
+	if code != 0 {
+		t.Fatalf("messages thread exit code = %d, stderr = %q", code, errText)
+	}
+	type node struct {
+		ID      string `json:"id"`
+		Replies []node `json:"replies"`
+	}
+	var thread struct {
+		RootID string `json:"root_id"`
+		Count  int    `json:"count"`
+		Pages  int    `json:"pages"`
+		Root   node   `json:"root"`
+	}
+	if err := json.Unmarshal([]byte(out), &thread); err != nil {
+		t.Fatalf("decode thread: %v", err)
+	}
+	if thread.RootID != "$m2" || thread.Count != 4 || thread.Pages != 2 {
+		t.Fatalf("thread = %+v, want root $m2, 4 messages over 2 pages", thread)
+	}
+	root := thread.Root
+	if len(root.Replies) != 2 || root.Replies[0].ID != "$m3" || root.Replies[1].ID != "$r2" {
+		t.Fatalf("root replies = %+v, want [$m3 $r2]", root.Replies)
+	}
+	if replies := root.Replies[0].Replies; len(replies) != 1 || replies[0].ID != "$r1" {
+		t.Fatalf("$m3 replies = %+v, want [$r1]", replies)
+	}
+
+	out, errText, code = run("--plain", "messages", "thread", "!team:beeper.local", "$r1")
+	if code != 0 {
+		t.Fatalf("messages thread --plain exit code = %d, stderr = %q", code, errText)
+	}
+	var depths []string
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		cols := strings.Split(line, "\t")
+		depths = append(depths, cols[0]+":"+cols[1])
+	}
+	if got := strings.Join(depths, " "); got != "0:$m2 1:$m3 2:$r1 1:$r2" {
+		t.Fatalf("plain thread order = %q", got)
+	}
+
+	_, errText, code = run("--json", "messages", "thread", "!team:beeper.local", "$missing", "--max-pages", "1")
+	if code != 1 || !strings.Contains(errText, "message not found") {
+		t.Fatalf("missing message: exit code = %d, stderr = %q", code, errText)
+	}
+
+	out, errText, code = run("--json", "messages", "list", "!team:beeper.local", "--hydrate-replies")
+	if code != 0 {
+		t.Fatalf("messages list --hydrate-replies exit code = %d, stderr = %q", code, errText)
+	}
+	var list struct {
+		Items []struct {
+			ID      string `json:"id"`
+			ReplyTo *struct {
+				ID         string `json:"id"`
+				SenderName string `json:"sender_name"`
+				Text       string `json:"text"`
+			} `json:"reply_to"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal([]byte(out), &list); err != nil {
+		t.Fatalf("decode list: %v", err)
+	}
+	hydrated := 0
+	for _, item := range list.Items {
+		switch {
+		case item.ID == "$r2":
+			if item.ReplyTo == nil || item.ReplyTo.ID != "$m2" || item.ReplyTo.Text != "Standup notes are in the doc" {
+				t.Fatalf("$r2 reply_to = %+v, want $m2 from an older page", item.ReplyTo)
+			}
+			hydrated++
+		case item.ReplyTo != nil:
+			t.Fatalf("%s has unexpected reply_to %+v", item.ID, item.ReplyTo)
+		}
+	}
+	if hydrated != 1 {
+		t.Fatalf("hydrated items = %d, want 1 ($r2 on the first page)", hydrated)
+	}
+
+	out, _, _ = run("--json", "messages", "list", "!team:beeper.local")
+	if strings.Contains(out, "reply_to") {
+		t.Fatal("reply_to present without --hydrate-replies")
+	}
+}
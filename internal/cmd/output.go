@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"os"
 
 	"github.com/johntheyoung/roadrunner/internal/outfmt"
@@ -10,26 +12,46 @@ import (
 // writeJSON writes data as JSON, optionally wrapped in an envelope.
 // The command parameter is used for envelope metadata.
 func writeJSON(ctx context.Context, data any, command string) error {
-	if outfmt.IsEnvelope(ctx) {
-		return outfmt.WriteEnvelopeWithMetadata(os.Stdout, data, Version, command, nil, outfmt.RequestIDFromContext(ctx))
-	}
-	return outfmt.WriteJSON(os.Stdout, data)
+	return writeJSONWithPagination(ctx, data, command, nil)
 }
 
 // writeJSONWithPagination writes data as JSON with optional normalized pagination
 // metadata when envelope mode is enabled.
 func writeJSONWithPagination(ctx context.Context, data any, command string, pagination *outfmt.EnvelopePagination) error {
+	var doc any = data
 	if outfmt.IsEnvelope(ctx) {
-		return outfmt.WriteEnvelopeWithMetadata(os.Stdout, data, Version, command, pagination, outfmt.RequestIDFromContext(ctx))
+		doc = outfmt.NewEnvelope(data, Version, command, pagination, outfmt.RequestIDFromContext(ctx))
+	}
+	if t := outfmt.TransformFromContext(ctx); t != nil {
+		return t.Write(os.Stdout, doc, outfmt.IsJSONL(ctx))
 	}
-	return outfmt.WriteJSON(os.Stdout, data)
+	return outfmt.WriteJSON(os.Stdout, doc)
 }
 
-func writeJSONLines[T any](items []T) error {
+func writeJSONLines[T any](ctx context.Context, items []T) error {
+	t := outfmt.TransformFromContext(ctx)
 	for _, item := range items {
+		if t != nil {
+			if err := t.Write(os.Stdout, item, true); err != nil {
+				return err
+			}
+			continue
+		}
 		if err := outfmt.WriteJSONLine(os.Stdout, item); err != nil {
 			return err
 		}
 	}
 	return nil
 }
+
+// writeJSONStream writes one item of a long-running stream (tail commands)
+// as a single JSON line, applying --query/--template when set.
+func writeJSONStream(ctx context.Context, encoder *json.Encoder, v any) error {
+	if t := outfmt.TransformFromContext(ctx); t != nil {
+		return t.Write(os.Stdout, v, true)
+	}
+	if err := encoder.Encode(v); err != nil {
+		return fmt.Errorf("encode json: %w", err)
+	}
+	return nil
+}
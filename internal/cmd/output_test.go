@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/johntheyoung/roadrunner/internal/outfmt"
+)
+
+func TestWriteJSONAppliesQueryToEnvelope(t *testing.T) {
+	tr, err := outfmt.ParseTransform(".success, .data.items[0].id, .metadata.command", "")
+	if err != nil {
+		t.Fatalf("ParseTransform() error = %v", err)
+	}
+	ctx := outfmt.WithMode(context.Background(), outfmt.Mode{JSON: true})
+	ctx = outfmt.WithEnvelope(ctx, true)
+	ctx = outfmt.WithTransform(ctx, tr)
+
+	out, _ := captureOutput(t, func() {
+		data := map[string]any{"items": []map[string]string{{"id": "!a"}}}
+		if err := writeJSON(ctx, data, "chats list"); err != nil {
+			t.Fatalf("writeJSON() error = %v", err)
+		}
+	})
+	if out != "true\n!a\nchats list\n" {
+		t.Fatalf("output = %q", out)
+	}
+}
+
+func TestWriteJSONLinesAppliesTemplatePerItem(t *testing.T) {
+	tr, err := outfmt.ParseTransform("", "{{.id}}:{{.title}}{{nl}}")
+	if err != nil {
+		t.Fatalf("ParseTransform() error = %v", err)
+	}
+	ctx := outfmt.WithMode(context.Background(), outfmt.Mode{JSONL: true})
+	ctx = outfmt.WithTransform(ctx, tr)
+
+	type row struct {
+		ID    string `json:"id"`
+		Title string `json:"title"`
+	}
+	out, _ := captureOutput(t, func() {
+		if err := writeJSONLines(ctx, []row{{"!a", "Alice"}, {"!b", "Team"}}); err != nil {
+			t.Fatalf("writeJSONLines() error = %v", err)
+		}
+	})
+	if out != "!a:Alice\n!b:Team\n" {
+		t.Fatalf("output = %q", out)
+	}
+}
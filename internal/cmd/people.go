@@ -0,0 +1,564 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/johntheyoung/roadrunner/internal/beeperapi"
+	"github.com/johntheyoung/roadrunner/internal/config"
+	"github.com/johntheyoung/roadrunner/internal/errfmt"
+	"github.com/johntheyoung/roadrunner/internal/outfmt"
+	"github.com/johntheyoung/roadrunner/internal/people"
+	"github.com/johntheyoung/roadrunner/internal/ui"
+)
+
+// PeopleCmd is the parent command for cross-network people subcommands.
+type PeopleCmd struct {
+	Search PeopleSearchCmd `cmd:"" help:"Find people across accounts, linking contacts by email, phone, or username"`
+	Show   PeopleShowCmd   `cmd:"" help:"Show a person's contacts, direct chats, and recent messages across networks"`
+	List   PeopleListCmd   `cmd:"" help:"List people saved with manual links"`
+	Link   PeopleLinkCmd   `cmd:"" help:"Link a contact to a person (stored locally)"`
+	Unlink PeopleUnlinkCmd `cmd:"" help:"Unlink a contact from a person (stored locally)"`
+}
+
+// PeopleSearchCmd finds people across accounts.
+type PeopleSearchCmd struct {
+	Query       string   `arg:"" help:"Name, username, email, phone, or saved person name"`
+	AccountIDs  []string `help:"Filter by account IDs" name:"account-ids"`
+	Match       string   `help:"Match mode: exact|prefix|fuzzy (names and usernames only; IDs, emails, and phones stay exact)" name:"match" enum:"exact,prefix,fuzzy" default:"prefix"`
+	FailIfEmpty bool     `help:"Exit with code 1 if no results" name:"fail-if-empty"`
+}
+
+// Run executes the people search command.
+func (c *PeopleSearchCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+
+	query := strings.TrimSpace(c.Query)
+	if query == "" {
+		return errfmt.UsageError("query is required")
+	}
+	client, err := newClientFromFlags(ctx, flags)
+	if err != nil {
+		return err
+	}
+	params, err := peopleParams(ctx, flags, query, c.AccountIDs, c.Match)
+	if err != nil {
+		return err
+	}
+
+	found, err := client.Accounts().FindPeople(ctx, params)
+	if err != nil {
+		return err
+	}
+	if err := failIfEmpty(c.FailIfEmpty, len(found), "people"); err != nil {
+		return err
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return writeJSON(ctx, map[string]any{
+			"items": found,
+		}, "people search")
+	}
+
+	if outfmt.IsTabular(ctx) {
+		for _, p := range found {
+			for _, pc := range p.Contacts {
+				writePlainRow(ctx, []string{"name", "account_id", "network", "contact_id", "linked_by"}, p.Name, pc.AccountID, pc.Network, pc.ID, pc.LinkedBy)
+			}
+		}
+		return nil
+	}
+
+	if len(found) == 0 {
+		u.Out().Warn("No people found")
+		return nil
+	}
+	u.Out().Printf("People (%d):", len(found))
+	for _, p := range found {
+		u.Out().Printf("  %s", p.Name)
+		if err := writePersonContacts(p.Contacts, "    "); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PeopleShowCmd shows one person across networks.
+type PeopleShowCmd struct {
+	Query      string   `arg:"" help:"Name, username, email, phone, or saved person name"`
+	AccountIDs []string `help:"Filter by account IDs" name:"account-ids"`
+	Match      string   `help:"Match mode: exact|prefix|fuzzy (names and usernames only; IDs, emails, and phones stay exact)" name:"match" enum:"exact,prefix,fuzzy" default:"prefix"`
+	Limit      int      `help:"Recent messages to show across the person's direct chats (0-20)" default:"10"`
+}
+
+type personChat struct {
+	beeperapi.ChatSearchItem
+	ContactID string `json:"contact_id"`
+}
+
+// Run executes the people show command.
+func (c *PeopleShowCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+
+	query := strings.TrimSpace(c.Query)
+	if query == "" {
+		return errfmt.UsageError("query is required")
+	}
+	if c.Limit < 0 || c.Limit > 20 {
+		return errfmt.UsageError("--limit must be between 0 and 20")
+	}
+	client, err := newClientFromFlags(ctx, flags)
+	if err != nil {
+		return err
+	}
+	params, err := peopleParams(ctx, flags, query, c.AccountIDs, c.Match)
+	if err != nil {
+		return err
+	}
+
+	person, err := client.Accounts().ResolvePerson(ctx, params)
+	if err != nil {
+		return matchErrorCode(err)
+	}
+	chats, err := findPersonChats(ctx, client, fetchConcurrency(ctx, flags), person)
+	if err != nil {
+		return err
+	}
+	messages := []beeperapi.MessageItem{}
+	if c.Limit > 0 && len(chats) > 0 {
+		chatIDs := make([]string, 0, len(chats))
+		for _, chat := range chats {
+			chatIDs = append(chatIDs, chat.ID)
+		}
+		resp, err := client.Messages().Search(ctx, beeperapi.MessageSearchParams{ChatIDs: chatIDs, Limit: c.Limit})
+		if err != nil {
+			return err
+		}
+		messages = resp.Items
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return writeJSON(ctx, map[string]any{
+			"person":   person,
+			"chats":    chats,
+			"messages": messages,
+		}, "people show")
+	}
+
+	if outfmt.IsTabular(ctx) {
+		for _, pc := range person.Contacts {
+			writePlainRow(ctx, []string{"kind", "account_id", "network", "id", "linked_by"}, "contact", pc.AccountID, pc.Network, pc.ID, pc.LinkedBy)
+		}
+		for _, chat := range chats {
+			writePlainRow(ctx, []string{"kind", "account_id", "network", "id", "last_activity"}, "chat", chat.AccountID, chat.Network, chat.ID, chat.LastActivity)
+		}
+		for _, msg := range messages {
+			writePlainRow(ctx, []string{"kind", "account_id", "chat_id", "id", "text"}, "message", msg.AccountID, msg.ChatID, msg.ID, plainText(ctx, msg.Text))
+		}
+		return nil
+	}
+
+	u.Out().Printf("Person: %s", person.Name)
+	u.Out().Printf("Contacts (%d):", len(person.Contacts))
+	if err := writePersonContacts(person.Contacts, "  "); err != nil {
+		return err
+	}
+
+	u.Out().Println("")
+	if len(chats) == 0 {
+		u.Out().Dim(fmt.Sprintf("No direct chats. Use: rr messages send --person %q <text>", person.Name))
+		return nil
+	}
+	u.Out().Printf("Direct chats (%d):", len(chats))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, chat := range chats {
+		if _, err := fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", chat.Network, chat.AccountID, chat.ID, chat.LastActivity); err != nil {
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(messages) > 0 {
+		networks := make(map[string]string, len(chats))
+		for _, chat := range chats {
+			networks[chat.ID] = chat.Network
+		}
+		u.Out().Println("")
+		u.Out().Printf("Recent messages (%d):", len(messages))
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, msg := range messages {
+			ts := ""
+			if t, err := time.Parse(time.RFC3339, msg.Timestamp); err == nil {
+				ts = t.Format("Jan 2 15:04")
+			}
+			sender := msg.SenderName
+			if msg.IsSender {
+				sender = "me"
+			}
+			if _, err := fmt.Fprintf(w, "  [%s]\t%s\t%s:\t%s\n", ts, networks[msg.ChatID], sender, ui.Truncate(msg.Text, 50)); err != nil {
+				return err
+			}
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PeopleListCmd lists saved people.
+type PeopleListCmd struct{}
+
+// Run executes the people list command.
+func (c *PeopleListCmd) Run(ctx context.Context) error {
+	u := ui.FromContext(ctx)
+
+	store, err := openPeopleLinks()
+	if err != nil {
+		return err
+	}
+	links := store.Links()
+
+	if outfmt.IsJSON(ctx) {
+		return writeJSON(ctx, map[string]any{
+			"people": links,
+		}, "people list")
+	}
+
+	if outfmt.IsTabular(ctx) {
+		for _, link := range links {
+			for _, pc := range link.Contacts {
+				writePlainRow(ctx, []string{"name", "state", "account_id", "contact_id"}, link.Name, "linked", pc.AccountID, pc.ID)
+			}
+			for _, ref := range link.Unlinked {
+				writePlainRow(ctx, []string{"name", "state", "account_id", "contact_id"}, link.Name, "unlinked", ref.AccountID, ref.ContactID)
+			}
+		}
+		return nil
+	}
+
+	if len(links) == 0 {
+		u.Out().Warn("No saved people")
+		u.Out().Dim("Use: rr people link <name> <account-id> <contact>")
+		return nil
+	}
+	u.Out().Printf("Saved people (%d):", len(links))
+	for _, link := range links {
+		u.Out().Printf("  %s", link.Name)
+		for _, pc := range link.Contacts {
+			u.Out().Printf("    + %s/%s", pc.AccountID, pc.ID)
+		}
+		for _, ref := range link.Unlinked {
+			u.Out().Dim(fmt.Sprintf("    - %s/%s", ref.AccountID, ref.ContactID))
+		}
+	}
+	return nil
+}
+
+// PeopleLinkCmd links a contact to a person.
+type PeopleLinkCmd struct {
+	Name      string `arg:"" help:"Person name (e.g., \"Alice\")"`
+	AccountID string `arg:"" name:"accountID" help:"Account ID or alias of the contact"`
+	Contact   string `arg:"" help:"Contact ID, name, username, email, or phone"`
+	Match     string `help:"How the contact is matched: exact|prefix|fuzzy" name:"match" enum:"exact,prefix,fuzzy" default:"exact"`
+}
+
+// Run executes the people link command.
+func (c *PeopleLinkCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+
+	name := strings.TrimSpace(c.Name)
+	accountID := resolveAccount(c.AccountID, "")
+	query := strings.TrimSpace(c.Contact)
+	if name == "" || accountID == "" || query == "" {
+		return errfmt.UsageError("name, account ID, and contact are required")
+	}
+	if handled, err := handleDryRunWrite(ctx, flags, "people link", map[string]any{
+		"name":       name,
+		"account_id": accountID,
+		"contact":    query,
+		"match":      c.Match,
+	}); handled {
+		return err
+	}
+
+	region, err := phoneRegion(flags)
+	if err != nil {
+		return err
+	}
+	client, err := newClientFromFlags(ctx, flags)
+	if err != nil {
+		return err
+	}
+	contact, err := client.Accounts().MatchContact(ctx, beeperapi.ContactResolveParams{
+		AccountID: accountID,
+		Query:     query,
+		Match:     c.Match,
+		Region:    region,
+	})
+	if err != nil {
+		return matchErrorCode(err)
+	}
+	network := ""
+	if accounts, err := client.Accounts().List(ctx); err == nil {
+		for _, acct := range accounts {
+			if acct.ID == accountID {
+				network = acct.Network
+			}
+		}
+	}
+
+	store, err := openPeopleLinks()
+	if err != nil {
+		return err
+	}
+	// A new saved person starts with the contacts its name already
+	// resolves to, so linking extends that person instead of splitting it.
+	if _, saved := store.Get(name); !saved {
+		params, err := peopleParams(ctx, flags, name, nil, beeperapi.MatchPrefix)
+		if err != nil {
+			return err
+		}
+		person, err := client.Accounts().ResolvePerson(ctx, params)
+		if err != nil && !beeperapi.IsNoMatch(err) && !beeperapi.IsAmbiguous(err) {
+			return err
+		}
+		for _, pc := range person.Contacts {
+			store.Link(name, pc)
+		}
+	}
+	link := store.Link(name, beeperapi.PersonContact{AccountID: accountID, Network: network, Contact: contact})
+	if err := store.Save(); err != nil {
+		return err
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return writeJSON(ctx, map[string]any{
+			"success":    true,
+			"name":       link.Name,
+			"account_id": accountID,
+			"contact_id": contact.ID,
+			"person":     link,
+		}, "people link")
+	}
+
+	if outfmt.IsTabular(ctx) {
+		writePlainRow(ctx, []string{"name", "account_id", "contact_id"}, link.Name, accountID, contact.ID)
+		return nil
+	}
+
+	u.Out().Success(fmt.Sprintf("Linked %s/%s to %s", accountID, contact.ID, link.Name))
+	return nil
+}
+
+// PeopleUnlinkCmd unlinks a contact from a person.
+type PeopleUnlinkCmd struct {
+	Name      string `arg:"" help:"Person name"`
+	AccountID string `arg:"" name:"accountID" help:"Account ID or alias of the contact"`
+	ContactID string `arg:"" name:"contactID" help:"Contact ID to unlink"`
+}
+
+// Run executes the people unlink command.
+func (c *PeopleUnlinkCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+
+	name := strings.TrimSpace(c.Name)
+	accountID := resolveAccount(c.AccountID, "")
+	contactID := strings.TrimSpace(c.ContactID)
+	if name == "" || accountID == "" || contactID == "" {
+		return errfmt.UsageError("name, account ID, and contact ID are required")
+	}
+	if err := validateResourceID(contactID, "contactID"); err != nil {
+		return err
+	}
+	ref := beeperapi.PersonRef{AccountID: accountID, ContactID: contactID}
+	if handled, err := handleDryRunWrite(ctx, flags, "people unlink", map[string]any{
+		"name":       name,
+		"account_id": accountID,
+		"contact_id": contactID,
+	}); handled {
+		return err
+	}
+
+	store, err := openPeopleLinks()
+	if err != nil {
+		return err
+	}
+	link := store.Unlink(name, ref)
+	if err := store.Save(); err != nil {
+		return err
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return writeJSON(ctx, map[string]any{
+			"success":    true,
+			"name":       link.Name,
+			"account_id": accountID,
+			"contact_id": contactID,
+			"person":     link,
+		}, "people unlink")
+	}
+
+	if outfmt.IsTabular(ctx) {
+		writePlainRow(ctx, []string{"name", "account_id", "contact_id"}, link.Name, accountID, contactID)
+		return nil
+	}
+
+	u.Out().Success(fmt.Sprintf("Unlinked %s/%s from %s", accountID, contactID, link.Name))
+	return nil
+}
+
+// openPeopleLinks opens the local person links store in the config
+// directory.
+func openPeopleLinks() (*people.Store, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return nil, err
+	}
+	return people.Open(filepath.Join(dir, people.FileName))
+}
+
+// peopleParams builds people lookup params with the saved links.
+func peopleParams(ctx context.Context, flags *RootFlags, query string, accountIDs []string, match string) (beeperapi.PeopleParams, error) {
+	store, err := openPeopleLinks()
+	if err != nil {
+		return beeperapi.PeopleParams{}, err
+	}
+	return beeperapi.PeopleParams{
+		Query:       query,
+		AccountIDs:  applyAccountDefault(accountIDs, flags.Account),
+		Match:       match,
+		Links:       store.Links(),
+		Concurrency: fetchConcurrency(ctx, flags),
+	}, nil
+}
+
+// findPersonChats returns the direct chat with each of person's contacts,
+// most recently active first.
+func findPersonChats(ctx context.Context, client *beeperapi.Client, concurrency int, person beeperapi.Person) ([]personChat, error) {
+	found, err := beeperapi.FanOut(ctx, concurrency, person.Contacts, func(ctx context.Context, pc beeperapi.PersonContact) (*personChat, error) {
+		chat, ok, err := client.Chats().FindDirect(ctx, pc.AccountID, pc.Contact)
+		if err != nil || !ok {
+			return nil, err
+		}
+		return &personChat{ChatSearchItem: chat, ContactID: pc.ID}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	chats := []personChat{}
+	for _, chat := range found {
+		if chat != nil && !slices.ContainsFunc(chats, func(c personChat) bool { return c.ID == chat.ID }) {
+			chats = append(chats, *chat)
+		}
+	}
+	slices.SortStableFunc(chats, func(a, b personChat) int {
+		return strings.Compare(b.LastActivity, a.LastActivity)
+	})
+	return chats, nil
+}
+
+// personTarget is the chat messages send --person resolved to.
+type personTarget struct {
+	Person    string
+	AccountID string
+	ContactID string
+	ChatID    string
+	Status    string // existing|created
+}
+
+// resolvePersonChat picks the chat to message person in: the most recently
+// active direct chat with one of their messageable contacts, or a new
+// direct chat with the first one. Contacts on preferNetwork (a network
+// name or account ID) are tried first.
+func resolvePersonChat(ctx context.Context, client *beeperapi.Client, flags *RootFlags, query, preferNetwork string) (personTarget, error) {
+	params, err := peopleParams(ctx, flags, query, nil, beeperapi.MatchPrefix)
+	if err != nil {
+		return personTarget{}, err
+	}
+	person, err := client.Accounts().ResolvePerson(ctx, params)
+	if err != nil {
+		return personTarget{}, matchErrorCode(err)
+	}
+
+	var preferred, rest []beeperapi.PersonContact
+	for _, pc := range person.Contacts {
+		switch {
+		case pc.CannotMessage:
+		case preferNetwork != "" && (strings.EqualFold(pc.Network, preferNetwork) || pc.AccountID == resolveAccount(preferNetwork, "")):
+			preferred = append(preferred, pc)
+		default:
+			rest = append(rest, pc)
+		}
+	}
+	for _, group := range [][]beeperapi.PersonContact{preferred, rest} {
+		if len(group) == 0 {
+			continue
+		}
+		chats, err := findPersonChats(ctx, client, fetchConcurrency(ctx, flags), beeperapi.Person{Contacts: group})
+		if err != nil {
+			return personTarget{}, err
+		}
+		if len(chats) > 0 {
+			return personTarget{
+				Person:    person.Name,
+				AccountID: chats[0].AccountID,
+				ContactID: chats[0].ContactID,
+				ChatID:    chats[0].ID,
+				Status:    "existing",
+			}, nil
+		}
+		pc := group[0]
+		started, err := client.Chats().Start(ctx, beeperapi.ChatStartParams{
+			AccountID: pc.AccountID,
+			User: beeperapi.ChatStartUser{
+				ID:          pc.ID,
+				Email:       pc.Email,
+				FullName:    pc.FullName,
+				PhoneNumber: pc.PhoneNumber,
+				Username:    pc.Username,
+			},
+		})
+		if err != nil {
+			return personTarget{}, err
+		}
+		return personTarget{
+			Person:    person.Name,
+			AccountID: pc.AccountID,
+			ContactID: pc.ID,
+			ChatID:    started.ChatID,
+			Status:    started.Status,
+		}, nil
+	}
+	return personTarget{}, errfmt.WithCode(fmt.Errorf("%s has no contact that can be messaged", person.Name), errfmt.ExitFailure)
+}
+
+// writePersonContacts prints a person's contacts as an indented table.
+func writePersonContacts(contacts []beeperapi.PersonContact, indent string) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, pc := range contacts {
+		handle := pc.Username
+		if handle == "" {
+			handle = pc.PhoneNumber
+		}
+		if handle == "" {
+			handle = pc.Email
+		}
+		status := "via " + pc.LinkedBy
+		if pc.CannotMessage {
+			status += ", cannot-message"
+		}
+		if _, err := fmt.Fprintf(w, "%s%s\t%s\t%s\t%s\t%s\n", indent, pc.Network, pc.AccountID, pc.ID, handle, status); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/johntheyoung/roadrunner/internal/errfmt"
+	"github.com/johntheyoung/roadrunner/internal/fakeapi"
+)
+
+func TestPeopleShowAndSendByPerson(t *testing.T) {
+	t.Setenv("BEEPER_TOKEN", "test-token")
+	t.Setenv("BEEPER_ACCESS_TOKEN", "")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	ds := fakeapi.DefaultDataset()
+	waSelf := ds.Accounts[1].User
+	aliceWA := fakeapi.User{ID: "+14155550101", FullName: "Alice E", PhoneNumber: "+1 415 555 0101"}
+	ds.Contacts["whatsapp"] = append(ds.Contacts["whatsapp"], aliceWA)
+	ds.Chats = append(ds.Chats, fakeapi.Chat{
+		ID: "!alicewa:whatsapp.local", AccountID: "whatsapp", Title: "Alice E", Type: "single",
+		Participants: []fakeapi.User{waSelf, aliceWA}, LastActivity: time.Date(2026, 2, 10, 9, 0, 0, 0, time.UTC),
+	})
+
+	fake := fakeapi.New(ds)
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	defer fake.Close()
+
+	run := func(args ...string) (string, string, int) {
+		t.Helper()
+		var out, errText string
+		var code int
+		withArgs(t, append([]string{"rr", "--base-url", server.URL}, args...), func() {
+			out, errText = captureOutput(t, func() {
+				code = Execute()
+			})
+		})
+		return out, errText, code
+	}
+	mustRun := func(args ...string) string {
+		t.Helper()
+		out, errText, code := run(args...)
+		if code != 0 {
+			t.Fatalf("%v: exit code = %d, stderr = %q", args, code, errText)
+		}
+		return out
+	}
+
+	var show struct {
+		Person struct {
+			Name     string `json:"name"`
+			Contacts []struct {
+				AccountID string `json:"account_id"`
+				ID        string `json:"id"`
+				LinkedBy  string `json:"linked_by"`
+			} `json:"contacts"`
+		} `json:"person"`
+		Chats []struct {
+			ID string `json:"id"`
+		} `json:"chats"`
+		Messages []struct {
+			ID string `json:"id"`
+		} `json:"messages"`
+	}
+	if err := json.Unmarshal([]byte(mustRun("--json", "people", "show", "Alice")), &show); err != nil {
+		t.Fatalf("decode people show: %v", err)
+	}
+	if show.Person.Name != "Alice Example" || len(show.Person.Contacts) != 2 {
+		t.Fatalf("people show person = %+v", show.Person)
+	}
+	if len(show.Chats) != 2 || show.Chats[0].ID != "!alice:beeper.local" || show.Chats[1].ID != "!alicewa:whatsapp.local" {
+		t.Fatalf("people show chats = %+v", show.Chats)
+	}
+	if len(show.Messages) != 1 || show.Messages[0].ID != "$m4" {
+		t.Fatalf("people show messages = %+v", show.Messages)
+	}
+
+	var sent struct {
+		ChatID string `json:"chat_id"`
+	}
+	if err := json.Unmarshal([]byte(mustRun("--json", "messages", "send", "--person", "Alice", "Running late")), &sent); err != nil {
+		t.Fatalf("decode messages send: %v", err)
+	}
+	if sent.ChatID != "!alice:beeper.local" {
+		t.Fatalf("send --person chat = %q, want most recent DM", sent.ChatID)
+	}
+	if err := json.Unmarshal([]byte(mustRun("--json", "--force", "messages", "send", "--person", "Alice", "--prefer-network", "whatsapp", "Running late")), &sent); err != nil {
+		t.Fatalf("decode messages send: %v", err)
+	}
+	if sent.ChatID != "!alicewa:whatsapp.local" {
+		t.Fatalf("send --prefer-network whatsapp chat = %q", sent.ChatID)
+	}
+
+	mustRun("people", "link", "Alice", "whatsapp", "Carol Example")
+	if err := json.Unmarshal([]byte(mustRun("--json", "people", "show", "Alice", "--limit", "0")), &show); err != nil {
+		t.Fatalf("decode people show: %v", err)
+	}
+	if show.Person.Name != "Alice" || len(show.Person.Contacts) != 3 {
+		t.Fatalf("people show after link = %+v", show.Person)
+	}
+
+	mustRun("people", "unlink", "Alice", "whatsapp", "+14155550101")
+	if err := json.Unmarshal([]byte(mustRun("--json", "people", "show", "Alice", "--limit", "0")), &show); err != nil {
+		t.Fatalf("decode people show: %v", err)
+	}
+	for _, c := range show.Person.Contacts {
+		if c.ID == "+14155550101" {
+			t.Fatalf("unlinked contact still joined: %+v", show.Person)
+		}
+	}
+	if out := mustRun("--plain", "people", "list"); !strings.Contains(out, "Alice\tunlinked\twhatsapp\t+14155550101") {
+		t.Fatalf("people list = %q", out)
+	}
+
+	if _, errText, code := run("messages", "send", "--prefer-network", "signal", "hi"); code != errfmt.ExitUsageError || !strings.Contains(errText, "--prefer-network requires --person") {
+		t.Fatalf("--prefer-network without --person: code = %d, stderr = %q", code, errText)
+	}
+}
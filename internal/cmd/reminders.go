@@ -22,6 +22,7 @@ type RemindersCmd struct {
 type RemindersSetCmd struct {
 	ChatID                   string `arg:"" optional:"" name:"chatID" help:"Chat ID to set reminder for"`
 	Chat                     string `help:"Exact chat title/display name or ID (alternative to chatID arg)" name:"chat"`
+	Match                    string `help:"How --chat matches chat names: exact|prefix|fuzzy" name:"match" enum:"exact,prefix,fuzzy" default:"exact"`
 	At                       string `arg:"" optional:"" help:"When to remind (RFC3339 or relative like '1h', '30m', '2h30m')"`
 	DismissOnIncomingMessage bool   `help:"Cancel reminder if someone messages" name:"dismiss-on-message"`
 }
@@ -57,6 +58,7 @@ func (c *RemindersSetCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if handled, err := handleDryRunWrite(ctx, flags, "reminders set", map[string]any{
 		"chat_id":                     chatID,
 		"chat_query":                  chatQuery,
+		"match":                       c.Match,
 		"remind_at":                   remindAt.Format(time.RFC3339),
 		"dismiss_on_incoming_message": c.DismissOnIncomingMessage,
 	}); handled {
@@ -69,12 +71,12 @@ func (c *RemindersSetCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	timeout := time.Duration(flags.Timeout) * time.Second
-	client, err := beeperapi.NewClient(token, flags.BaseURL, timeout)
+	client, err := newAPIClient(ctx, token, flags.BaseURL, timeout)
 	if err != nil {
 		return err
 	}
 	if chatQuery != "" {
-		chatID, err = resolveChatIDByQuery(ctx, client, chatQuery, applyAccountDefault(nil, flags.Account))
+		chatID, err = resolveChatIDByQuery(ctx, client, chatQuery, applyAccountDefault(nil, flags.Account), c.Match)
 		if err != nil {
 			return err
 		}
@@ -97,8 +99,8 @@ func (c *RemindersSetCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	// Plain output
-	if outfmt.IsPlain(ctx) {
-		u.Out().Printf("%s\t%s", chatID, remindAt.Format(time.RFC3339))
+	if outfmt.IsTabular(ctx) {
+		writePlainRow(ctx, []string{"chat_id", "remind_at"}, chatID, remindAt.Format(time.RFC3339))
 		return nil
 	}
 
@@ -114,6 +116,7 @@ func (c *RemindersSetCmd) Run(ctx context.Context, flags *RootFlags) error {
 type RemindersClearCmd struct {
 	ChatID string `arg:"" optional:"" name:"chatID" help:"Chat ID to clear reminder from"`
 	Chat   string `help:"Exact chat title/display name or ID (alternative to chatID arg)" name:"chat"`
+	Match  string `help:"How --chat matches chat names: exact|prefix|fuzzy" name:"match" enum:"exact,prefix,fuzzy" default:"exact"`
 }
 
 // Run executes the reminders clear command.
@@ -126,6 +129,7 @@ func (c *RemindersClearCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if handled, err := handleDryRunWrite(ctx, flags, "reminders clear", map[string]any{
 		"chat_id":    chatID,
 		"chat_query": chatQuery,
+		"match":      c.Match,
 		"cleared":    true,
 	}); handled {
 		return err
@@ -142,12 +146,12 @@ func (c *RemindersClearCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	timeout := time.Duration(flags.Timeout) * time.Second
-	client, err := beeperapi.NewClient(token, flags.BaseURL, timeout)
+	client, err := newAPIClient(ctx, token, flags.BaseURL, timeout)
 	if err != nil {
 		return err
 	}
 	if chatQuery != "" {
-		chatID, err = resolveChatIDByQuery(ctx, client, chatQuery, applyAccountDefault(nil, flags.Account))
+		chatID, err = resolveChatIDByQuery(ctx, client, chatQuery, applyAccountDefault(nil, flags.Account), c.Match)
 		if err != nil {
 			return err
 		}
@@ -170,8 +174,8 @@ func (c *RemindersClearCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	// Plain output
-	if outfmt.IsPlain(ctx) {
-		u.Out().Printf("%s\tcleared", chatID)
+	if outfmt.IsTabular(ctx) {
+		writePlainRow(ctx, []string{"chat_id", "status"}, chatID, "cleared")
 		return nil
 	}
 
@@ -3,12 +3,16 @@ package cmd
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"runtime/debug"
 	"time"
 
 	"github.com/alecthomas/kong"
 
+	"github.com/johntheyoung/roadrunner/internal/beeperapi"
+	"github.com/johntheyoung/roadrunner/internal/cache"
+	"github.com/johntheyoung/roadrunner/internal/cassette"
 	"github.com/johntheyoung/roadrunner/internal/errfmt"
 	"github.com/johntheyoung/roadrunner/internal/outfmt"
 	"github.com/johntheyoung/roadrunner/internal/ui"
@@ -34,24 +38,37 @@ func init() {
 
 // RootFlags contains global flags available to all commands.
 type RootFlags struct {
-	Color          string           `help:"Color output: auto|always|never" default:"auto" env:"BEEPER_COLOR"`
-	JSON           bool             `help:"Output JSON to stdout (best for scripting)" env:"BEEPER_JSON"`
-	JSONL          bool             `help:"Output JSON Lines (one JSON object per line)" env:"BEEPER_JSONL"`
-	Plain          bool             `help:"Output stable TSV to stdout (no colors)" env:"BEEPER_PLAIN"`
-	Verbose        bool             `help:"Enable debug logging" short:"v"`
-	NoInput        bool             `help:"Never prompt; fail instead (useful for CI)" env:"BEEPER_NO_INPUT"`
-	Force          bool             `help:"Skip confirmations for destructive commands" short:"f"`
-	Timeout        int              `help:"Timeout for API calls in seconds (0=none)" default:"30" env:"BEEPER_TIMEOUT"`
-	BaseURL        string           `help:"API base URL" default:"http://localhost:23373" env:"BEEPER_URL"`
-	Version        kong.VersionFlag `help:"Show version and exit"`
-	EnableCommands []string         `help:"Comma-separated allowlist of top-level commands" env:"BEEPER_ENABLE_COMMANDS" sep:","`
-	Readonly       bool             `help:"Block data write operations" env:"BEEPER_READONLY"`
-	DryRun         bool             `help:"Validate and preview mutating operations without sending API requests" env:"BEEPER_DRY_RUN"`
-	Envelope       bool             `help:"Wrap JSON output in {success,data,error,metadata} envelope" env:"BEEPER_ENVELOPE"`
-	Agent          bool             `help:"Agent profile: forces JSON, envelope, no-input, readonly" env:"BEEPER_AGENT"`
-	RequestID      string           `help:"Optional request ID for envelope metadata (agent tracing)" env:"BEEPER_REQUEST_ID"`
-	DedupeWindow   time.Duration    `help:"Block duplicate non-idempotent writes with same --request-id and payload within this window (0 disables)" default:"0s" env:"BEEPER_DEDUPE_WINDOW"`
-	Account        string           `help:"Default account ID for commands" env:"BEEPER_ACCOUNT"`
+	Color          string                   `help:"Color output: auto|always|never" default:"auto" env:"BEEPER_COLOR"`
+	JSON           bool                     `help:"Output JSON to stdout (best for scripting)" env:"BEEPER_JSON"`
+	JSONL          bool                     `help:"Output JSON Lines (one JSON object per line)" env:"BEEPER_JSONL"`
+	Stream         bool                     `help:"Output JSON Lines as pages arrive, ending with a pagination trailer line" env:"BEEPER_STREAM"`
+	Plain          bool                     `help:"Output stable TSV to stdout (no colors)" env:"BEEPER_PLAIN"`
+	CSV            bool                     `help:"Output RFC 4180 CSV with a header row (respects --fields)" name:"csv" env:"BEEPER_CSV"`
+	YAML           bool                     `help:"Output YAML (respects --fields)" name:"yaml" env:"BEEPER_YAML"`
+	Markdown       bool                     `help:"Output a GitHub-flavored Markdown table (respects --fields)" env:"BEEPER_MARKDOWN"`
+	Query          string                   `help:"Filter JSON output with a jq-style expression (implies --json)" placeholder:"EXPR"`
+	Template       string                   `help:"Render JSON output with a Go text/template over JSON field names (implies --json)" placeholder:"TEMPLATE"`
+	Verbose        bool                     `help:"Enable debug logging" short:"v"`
+	NoInput        bool                     `help:"Never prompt; fail instead (useful for CI)" env:"BEEPER_NO_INPUT"`
+	Force          bool                     `help:"Skip confirmations for destructive commands" short:"f"`
+	Timeout        int                      `help:"Timeout for API calls in seconds (0=none)" default:"30" env:"BEEPER_TIMEOUT"`
+	Concurrency    int                      `help:"Maximum parallel API requests for per-account fan-out and page prefetching (1-16)" default:"4" env:"BEEPER_CONCURRENCY"`
+	BaseURL        string                   `help:"API base URL" default:"http://localhost:23373" env:"BEEPER_URL"`
+	Version        kong.VersionFlag         `help:"Show version and exit"`
+	EnableCommands []string                 `help:"Comma-separated allowlist of top-level commands" env:"BEEPER_ENABLE_COMMANDS" sep:","`
+	Readonly       bool                     `help:"Block data write operations" env:"BEEPER_READONLY"`
+	DryRun         bool                     `help:"Validate and preview mutating operations without sending API requests" env:"BEEPER_DRY_RUN"`
+	Envelope       bool                     `help:"Wrap JSON output in {success,data,error,metadata} envelope" env:"BEEPER_ENVELOPE"`
+	Agent          bool                     `help:"Agent profile: forces JSON, envelope, no-input, readonly" env:"BEEPER_AGENT"`
+	RequestID      string                   `help:"Optional request ID for envelope metadata (agent tracing)" env:"BEEPER_REQUEST_ID"`
+	DedupeWindow   time.Duration            `help:"Block duplicate non-idempotent writes with same --request-id and payload within this window (0 disables)" default:"0s" env:"BEEPER_DEDUPE_WINDOW"`
+	Account        string                   `help:"Default account ID for commands" env:"BEEPER_ACCOUNT"`
+	Region         string                   `help:"Country for phone numbers without a country code (ISO 3166, e.g. US; default: config default_region)" env:"BEEPER_REGION"`
+	Record         string                   `help:"Record API traffic (HTTP and websocket) to a cassette directory" placeholder:"DIR" env:"BEEPER_RECORD"`
+	Replay         string                   `help:"Replay API traffic from a cassette directory instead of contacting Desktop" placeholder:"DIR" env:"BEEPER_REPLAY"`
+	NoCache        bool                     `help:"Bypass the local cache of accounts, chat resolutions, and contact searches" env:"BEEPER_NO_CACHE"`
+	Refresh        bool                     `help:"Ignore cached entries and refetch, updating the cache" env:"BEEPER_REFRESH"`
+	CacheTTL       map[string]time.Duration `help:"Per-resource cache TTLs, e.g. chats=30s;accounts=1h (0 disables a resource)" name:"cache-ttl" placeholder:"RESOURCE=TTL" env:"BEEPER_CACHE_TTL"`
 }
 
 // CLI is the root command structure.
@@ -63,6 +80,7 @@ type CLI struct {
 	Events       EventsCmd       `cmd:"" help:"Manage websocket live events (experimental)"`
 	Accounts     AccountsCmd     `cmd:"" help:"Manage messaging accounts"`
 	Contacts     ContactsCmd     `cmd:"" help:"Manage contacts"`
+	People       PeopleCmd       `cmd:"" help:"Find people across networks"`
 	Assets       AssetsCmd       `cmd:"" help:"Manage assets"`
 	Chats        ChatsCmd        `cmd:"" help:"Manage chats"`
 	Messages     MessagesCmd     `cmd:"" help:"Manage messages"`
@@ -72,9 +90,11 @@ type CLI struct {
 	Unread       UnreadCmd       `cmd:"" help:"List unread chats"`
 	Focus        FocusCmd        `cmd:"" help:"Focus Beeper Desktop app"`
 	Doctor       DoctorCmd       `cmd:"" help:"Diagnose configuration and connectivity"`
+	Dev          DevCmd          `cmd:"" help:"Developer tools (fake API server)"`
 	Version      VersionCmd      `cmd:"" help:"Show version information"`
 	Describe     DescribeCmd     `cmd:"" help:"Describe command schema for runtime introspection"`
 	Capabilities CapabilitiesCmd `cmd:"" help:"Show CLI capabilities for agent discovery"`
+	Cache        CacheCmd        `cmd:"" help:"Inspect and clear the local API cache"`
 	Completion   CompletionCmd   `cmd:"" help:"Generate shell completions"`
 }
 
@@ -111,10 +131,14 @@ func Execute() int {
 	if cli.Agent {
 		cli.JSON = true
 		cli.JSONL = false
+		cli.Stream = false
 		cli.Envelope = true
 		cli.NoInput = true
 		cli.Readonly = true
 		cli.Plain = false // JSON takes precedence
+		cli.CSV = false
+		cli.YAML = false
+		cli.Markdown = false
 
 		// Agent mode requires --enable-commands for safety
 		if len(cli.EnableCommands) == 0 {
@@ -126,8 +150,32 @@ func Execute() int {
 		}
 	}
 
+	// --query/--template transform JSON output, so they imply --json
+	if cli.Query != "" || cli.Template != "" {
+		if cli.Plain || cli.CSV || cli.YAML || cli.Markdown {
+			_, _ = os.Stderr.WriteString("error: cannot use --query or --template with --plain, --csv, --yaml, or --markdown\n")
+			return errfmt.ExitUsageError
+		}
+		if !cli.JSONL && !cli.Stream {
+			cli.JSON = true
+		}
+	}
+	transform, err := outfmt.ParseTransform(cli.Query, cli.Template)
+	if err != nil {
+		_, _ = os.Stderr.WriteString("error: " + errfmt.Format(err) + "\n")
+		return errfmt.ExitUsageError
+	}
+
 	// Validate flag combinations
-	mode, err := outfmt.FromFlags(cli.JSON, cli.JSONL, cli.Plain)
+	mode, err := outfmt.FromFlagSet(outfmt.Flags{
+		JSON:     cli.JSON,
+		JSONL:    cli.JSONL,
+		Stream:   cli.Stream,
+		Plain:    cli.Plain,
+		CSV:      cli.CSV,
+		YAML:     cli.YAML,
+		Markdown: cli.Markdown,
+	})
 	if err != nil {
 		// Can't use envelope here - conflicting flags mean envelope state is ambiguous
 		_, _ = os.Stderr.WriteString("error: " + errfmt.Format(err) + "\n")
@@ -137,11 +185,19 @@ func Execute() int {
 		_, _ = os.Stderr.WriteString("error: cannot use --jsonl with --envelope\n")
 		return errfmt.ExitUsageError
 	}
+	if cli.Stream && cli.Envelope {
+		_, _ = os.Stderr.WriteString("error: cannot use --stream with --envelope\n")
+		return errfmt.ExitUsageError
+	}
+	if cli.Concurrency < 1 || cli.Concurrency > beeperapi.MaxConcurrency {
+		_, _ = os.Stderr.WriteString(fmt.Sprintf("error: invalid --concurrency %d (expected 1-%d)\n", cli.Concurrency, beeperapi.MaxConcurrency))
+		return errfmt.ExitUsageError
+	}
 
 	// Create UI (respects --color and NO_COLOR)
 	// Disable colors for JSON/Plain output
 	colorMode := cli.Color
-	if cli.JSON || cli.JSONL || cli.Plain {
+	if cli.JSON || cli.JSONL || cli.Stream || mode.IsTabular() {
 		colorMode = "never"
 	}
 
@@ -160,6 +216,12 @@ func Execute() int {
 	ctx = ui.WithUI(ctx, u)
 	ctx = outfmt.WithMode(ctx, mode)
 	ctx = outfmt.WithRequestID(ctx, cli.RequestID)
+	if mode.IsTabular() {
+		ctx = outfmt.WithRowWriter(ctx, outfmt.NewRowWriter(u.Out().Writer(), mode))
+	}
+	if transform != nil {
+		ctx = outfmt.WithTransform(ctx, transform)
+	}
 
 	// Validate command allowlist and readonly mode
 	command := normalizeCommand(kongCtx.Command())
@@ -180,6 +242,49 @@ func Execute() int {
 		return errfmt.ExitUsageError
 	}
 
+	// Route API traffic through a cassette for --record/--replay
+	tape, err := openCassette(&cli.RootFlags)
+	if err != nil {
+		if cli.JSON && cli.Envelope {
+			_ = outfmt.WriteEnvelopeErrorWithMetadata(os.Stdout, errfmt.ErrCodeValidation, errfmt.Format(err), errfmt.Hint(err), Version, command, cli.RequestID)
+		} else {
+			_, _ = os.Stderr.WriteString("error: " + errfmt.Format(err) + "\n")
+		}
+		return errfmt.ExitUsageError
+	}
+	if tape != nil {
+		ctx = cassette.WithCassette(ctx, tape)
+		defer func() {
+			if err := tape.Close(); err != nil {
+				u.Err().Warn("cassette: " + err.Error())
+			}
+		}()
+	}
+
+	// Serve repeated lookups from the local cache. Cassettes must see every
+	// request, so --record/--replay bypass it. Commands that write act on
+	// what they look up, so they refresh instead of trusting cached
+	// resolutions.
+	if tape == nil && !cli.NoCache {
+		cacheFlags := cli.RootFlags
+		cacheFlags.Refresh = cacheFlags.Refresh || dataWriteCommands[command]
+		store, err := openCache(&cacheFlags)
+		if err != nil {
+			if cli.JSON && cli.Envelope {
+				_ = outfmt.WriteEnvelopeErrorWithMetadata(os.Stdout, errfmt.ErrCodeValidation, errfmt.Format(err), errfmt.Hint(err), Version, command, cli.RequestID)
+			} else {
+				_, _ = os.Stderr.WriteString("error: " + errfmt.Format(err) + "\n")
+			}
+			return errfmt.ExitUsageError
+		}
+		ctx = cache.WithCache(ctx, store)
+		defer func() {
+			if err := store.Flush(); err != nil {
+				u.Err().Warn("cache: " + err.Error())
+			}
+		}()
+	}
+
 	// Add envelope context if enabled
 	ctx = outfmt.WithEnvelope(ctx, cli.Envelope && cli.JSON)
 
@@ -192,7 +297,7 @@ func Execute() int {
 		// Handle envelope mode errors to stdout
 		if cli.Envelope && cli.JSON {
 			code := errfmt.ErrorCode(err)
-			_ = outfmt.WriteEnvelopeErrorWithMetadata(os.Stdout, code, errfmt.Format(err), errfmt.Hint(err), Version, command, cli.RequestID)
+			_ = outfmt.WriteEnvelopeErrorWithDetails(os.Stdout, code, errfmt.Format(err), errfmt.Hint(err), Version, command, cli.RequestID, errfmt.Details(err))
 			var exitErr *errfmt.ExitError
 			if errors.As(err, &exitErr) {
 				return exitErr.Code
@@ -205,12 +310,14 @@ func Execute() int {
 		if errors.As(err, &exitErr) {
 			if exitErr.Err != nil {
 				u.Err().Error("error: " + errfmt.Format(exitErr.Err))
+				writeMatchCandidates(u, exitErr.Err)
 			}
 			return exitErr.Code
 		}
 
 		// Default error handling
 		u.Err().Error("error: " + errfmt.Format(err))
+		writeMatchCandidates(u, err)
 		return errfmt.ExitFailure
 	}
 
@@ -17,6 +17,14 @@ var dataWriteCommands = map[string]bool{
 	"chats create":         true,
 	"chats start":          true,
 	"chats archive":        true,
+	"chats alias set":      true,
+	"chats alias unset":    true,
+	"chats tag add":        true,
+	"chats tag remove":     true,
+	"chats note set":       true,
+	"contacts import":      true,
+	"people link":          true,
+	"people unlink":        true,
 	"reminders set":        true,
 	"reminders clear":      true,
 	"assets upload":        true,
@@ -27,9 +35,10 @@ var dataWriteCommands = map[string]bool{
 
 // exemptCommands are commands that bypass --readonly restrictions (local-only operations).
 var exemptCommands = map[string]bool{
-	"auth set":   true,
-	"auth clear": true,
-	"focus":      true,
+	"auth set":    true,
+	"auth clear":  true,
+	"cache clear": true,
+	"focus":       true,
 }
 
 // DataWriteCommandsList returns a sorted list of data write commands.
@@ -22,8 +22,9 @@ type SearchCmd struct {
 	MessagesLimit     int      `help:"Max messages per page when paging (1-20)" name:"messages-limit" default:"0"`
 	MessagesAll       bool     `help:"Fetch all message pages automatically" name:"messages-all"`
 	MessagesMaxItems  int      `help:"Maximum message items to collect with --messages-all (default 500, max 5000)" name:"messages-max-items" default:"0"`
+	Checkpoint        string   `help:"Resume --messages-all from a checkpoint file, saved after each page" name:"checkpoint" type:"path"`
 	FailIfEmpty       bool     `help:"Exit with code 1 if no results" name:"fail-if-empty"`
-	Fields            []string `help:"Comma-separated list of fields for --plain output" name:"fields" sep:","`
+	Fields            []string `help:"Comma-separated list of fields for --plain, --csv, --yaml, or --markdown output" name:"fields" sep:","`
 }
 
 // Run executes the search command.
@@ -36,6 +37,9 @@ func (c *SearchCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if err != nil {
 		return err
 	}
+	if c.Checkpoint != "" && !c.MessagesAll {
+		return errfmt.UsageError("--checkpoint requires --messages-all")
+	}
 	effectiveMessagesLimit := c.MessagesLimit
 	if c.MessagesAll && effectiveMessagesLimit == 0 {
 		effectiveMessagesLimit = 20
@@ -47,14 +51,19 @@ func (c *SearchCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	timeout := time.Duration(flags.Timeout) * time.Second
-	client, err := beeperapi.NewClient(token, flags.BaseURL, timeout)
+	client, err := newAPIClient(ctx, token, flags.BaseURL, timeout)
 	if err != nil {
 		return err
 	}
 
+	checkpoint, err := openPageCheckpoint(c.Checkpoint, c.MessagesAll, "search", c.Query, c.MessagesDirection, fmt.Sprint(effectiveMessagesLimit))
+	if err != nil {
+		return err
+	}
+	startCursor := checkpoint.resumeCursor(c.MessagesCursor)
 	resp, err := client.Search(ctx, beeperapi.SearchParams{
 		Query:             c.Query,
-		MessagesCursor:    c.MessagesCursor,
+		MessagesCursor:    startCursor,
 		MessagesDirection: c.MessagesDirection,
 		MessagesLimit:     effectiveMessagesLimit,
 	})
@@ -63,43 +72,42 @@ func (c *SearchCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 	capped := false
 	if c.MessagesAll {
-		items := make([]beeperapi.MessageItem, 0, len(resp.Messages.Items))
-		items = append(items, resp.Messages.Items...)
-		lastCursor := c.MessagesCursor
-		for resp.Messages.HasMore {
-			if limitReached(len(items), autoPageLimit) {
-				capped = true
-				break
-			}
-
-			nextCursor := nextSearchCursor(c.MessagesDirection, resp.Messages.OldestCursor, resp.Messages.NewestCursor)
-			if nextCursor == "" || nextCursor == lastCursor {
-				break
-			}
-			lastCursor = nextCursor
-
-			page, err := client.Messages().Search(ctx, beeperapi.MessageSearchParams{
-				Query:     c.Query,
-				Cursor:    nextCursor,
-				Direction: c.MessagesDirection,
-				Limit:     effectiveMessagesLimit,
-			})
-			if err != nil {
-				return err
-			}
-
-			items = append(items, page.Items...)
-			resp.Messages.HasMore = page.HasMore
-			resp.Messages.OldestCursor = page.OldestCursor
-			resp.Messages.NewestCursor = page.NewestCursor
+		pager := autoPager[beeperapi.MessageItem]{
+			Limits:     autoPageLimits{MaxItems: autoPageLimit},
+			Cursor:     startCursor,
+			Key:        func(item beeperapi.MessageItem) string { return item.ChatID + "/" + item.ID },
+			Checkpoint: checkpoint,
+			Prefetch:   fetchConcurrency(ctx, flags) > 1,
+			Fetch: func(cursor string) (autoPage[beeperapi.MessageItem], error) {
+				page, err := client.Messages().Search(ctx, beeperapi.MessageSearchParams{
+					Query:     c.Query,
+					Cursor:    cursor,
+					Direction: c.MessagesDirection,
+					Limit:     effectiveMessagesLimit,
+				})
+				if err != nil {
+					return autoPage[beeperapi.MessageItem]{}, err
+				}
+				resp.Messages.HasMore = page.HasMore
+				resp.Messages.OldestCursor = page.OldestCursor
+				resp.Messages.NewestCursor = page.NewestCursor
+				return autoPage[beeperapi.MessageItem]{
+					Items:   page.Items,
+					HasMore: page.HasMore,
+					Next:    nextSearchCursor(c.MessagesDirection, page.OldestCursor, page.NewestCursor),
+				}, nil
+			},
 		}
-		if limitReached(len(items), autoPageLimit) {
-			if len(items) > autoPageLimit {
-				items = items[:autoPageLimit]
-			}
-			capped = true
+		res, err := pager.run(ctx, autoPage[beeperapi.MessageItem]{
+			Items:   resp.Messages.Items,
+			HasMore: resp.Messages.HasMore,
+			Next:    nextSearchCursor(c.MessagesDirection, resp.Messages.OldestCursor, resp.Messages.NewestCursor),
+		})
+		if err != nil {
+			return err
 		}
-		resp.Messages.Items = items
+		capped = res.Capped
+		resp.Messages.Items = res.Items
 		if capped {
 			resp.Messages.HasMore = true
 		}
@@ -128,13 +136,13 @@ func (c *SearchCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	// Plain output (TSV)
-	if outfmt.IsPlain(ctx) {
+	if outfmt.IsTabular(ctx) {
 		fields, err := resolveFields(c.Fields, []string{"type", "id", "chat_id", "title", "text"})
 		if err != nil {
 			return err
 		}
 		for _, chat := range resp.Chats {
-			writePlainFields(u, fields, map[string]string{
+			writePlainFields(ctx, fields, map[string]any{
 				"type":    "chat",
 				"id":      chat.ID,
 				"chat_id": chat.ID,
@@ -143,7 +151,7 @@ func (c *SearchCmd) Run(ctx context.Context, flags *RootFlags) error {
 			})
 		}
 		for _, chat := range resp.InGroups {
-			writePlainFields(u, fields, map[string]string{
+			writePlainFields(ctx, fields, map[string]any{
 				"type":    "group",
 				"id":      chat.ID,
 				"chat_id": chat.ID,
@@ -152,12 +160,12 @@ func (c *SearchCmd) Run(ctx context.Context, flags *RootFlags) error {
 			})
 		}
 		for _, msg := range resp.Messages.Items {
-			writePlainFields(u, fields, map[string]string{
+			writePlainFields(ctx, fields, map[string]any{
 				"type":    "message",
 				"id":      msg.ID,
 				"chat_id": msg.ChatID,
 				"title":   "",
-				"text":    ui.Truncate(msg.Text, 50),
+				"text":    plainText(ctx, msg.Text),
 			})
 		}
 		return nil
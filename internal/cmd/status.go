@@ -2,7 +2,7 @@ package cmd
 
 import (
 	"context"
-	"fmt"
+	"slices"
 	"time"
 
 	"github.com/johntheyoung/roadrunner/internal/beeperapi"
@@ -14,7 +14,8 @@ import (
 // StatusCmd summarizes unread counts and chat state.
 type StatusCmd struct {
 	ByAccount bool     `help:"Group unread counts by account" name:"by-account"`
-	Fields    []string `help:"Comma-separated list of fields for --plain output" name:"fields" sep:","`
+	Tags      []string `help:"Only count chats carrying every given local tag (repeatable)" name:"tag"`
+	Fields    []string `help:"Comma-separated list of fields for --plain, --csv, --yaml, or --markdown output" name:"fields" sep:","`
 }
 
 type statusSummary struct {
@@ -43,13 +44,18 @@ type statusAccountSummary struct {
 func (c *StatusCmd) Run(ctx context.Context, flags *RootFlags) error {
 	u := ui.FromContext(ctx)
 
+	meta, tags, err := openChatTagFilter(c.Tags)
+	if err != nil {
+		return err
+	}
+
 	token, _, err := config.GetToken()
 	if err != nil {
 		return err
 	}
 
 	timeout := time.Duration(flags.Timeout) * time.Second
-	client, err := beeperapi.NewClient(token, flags.BaseURL, timeout)
+	client, err := newAPIClient(ctx, token, flags.BaseURL, timeout)
 	if err != nil {
 		return err
 	}
@@ -64,8 +70,10 @@ func (c *StatusCmd) Run(ctx context.Context, flags *RootFlags) error {
 		RemindersSupported: false,
 	}
 
+	accountIDs := make([]string, 0, len(accounts))
 	accountIndex := make(map[string]statusAccountSummary, len(accounts))
 	for _, acct := range accounts {
+		accountIDs = append(accountIDs, acct.ID)
 		accountIndex[acct.ID] = statusAccountSummary{
 			AccountID:   acct.ID,
 			DisplayName: acct.DisplayName,
@@ -73,52 +81,50 @@ func (c *StatusCmd) Run(ctx context.Context, flags *RootFlags) error {
 		}
 	}
 
-	cursor := ""
-	for {
-		resp, err := client.Chats().Search(ctx, beeperapi.ChatSearchParams{
-			Limit:     200,
-			Cursor:    cursor,
-			Direction: "before",
-		})
-		if err != nil {
-			return err
-		}
+	// One unscoped walk, split by account below, so chats of accounts that
+	// accounts list did not return still count. With --concurrency above 1
+	// the next page is requested while the current one is counted.
+	items, err := statusChats(ctx, client, fetchConcurrency(ctx, flags) > 1)
+	if err != nil {
+		return err
+	}
 
-		for _, chat := range resp.Items {
-			summary.Chats++
-			acct := accountIndex[chat.AccountID]
-			acct.AccountID = chat.AccountID
-			acct.Chats++
-			if chat.UnreadCount > 0 {
-				summary.UnreadChats++
-				summary.UnreadMessages += chat.UnreadCount
-				acct.UnreadChats++
-				acct.UnreadMessages += chat.UnreadCount
-			}
-			if chat.IsMuted {
-				summary.MutedChats++
-				acct.MutedChats++
-			}
-			if chat.IsArchived {
-				summary.ArchivedChats++
-				acct.ArchivedChats++
-			}
-			accountIndex[chat.AccountID] = acct
+	var extraAccounts []string
+	for _, chat := range tagChatSearchItems(meta, tags, items) {
+		summary.Chats++
+		acct, known := accountIndex[chat.AccountID]
+		if !known {
+			extraAccounts = append(extraAccounts, chat.AccountID)
 		}
-
-		if !resp.HasMore || resp.OldestCursor == "" {
-			break
+		acct.AccountID = chat.AccountID
+		acct.Chats++
+		if chat.UnreadCount > 0 {
+			summary.UnreadChats++
+			summary.UnreadMessages += chat.UnreadCount
+			acct.UnreadChats++
+			acct.UnreadMessages += chat.UnreadCount
+		}
+		if chat.IsMuted {
+			summary.MutedChats++
+			acct.MutedChats++
 		}
-		cursor = resp.OldestCursor
+		if chat.IsArchived {
+			summary.ArchivedChats++
+			acct.ArchivedChats++
+		}
+		accountIndex[chat.AccountID] = acct
 	}
 
 	if c.ByAccount {
+		// Accounts keep the order of accounts list; chats from accounts it
+		// did not return follow, sorted by ID.
+		slices.Sort(extraAccounts)
 		summary.AccountsSummary = make([]statusAccountSummary, 0, len(accountIndex))
-		for _, acct := range accountIndex {
-			if acct.AccountID == "" {
+		for _, id := range append(accountIDs, slices.Compact(extraAccounts)...) {
+			if id == "" {
 				continue
 			}
-			summary.AccountsSummary = append(summary.AccountsSummary, acct)
+			summary.AccountsSummary = append(summary.AccountsSummary, accountIndex[id])
 		}
 	}
 
@@ -126,22 +132,22 @@ func (c *StatusCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return writeJSON(ctx, summary, "status")
 	}
 
-	if outfmt.IsPlain(ctx) {
+	if outfmt.IsTabular(ctx) {
 		if c.ByAccount {
 			fields, err := resolveFields(c.Fields, []string{"account_id", "display_name", "network", "chats", "unread_chats", "unread_messages", "muted_chats", "archived_chats"})
 			if err != nil {
 				return err
 			}
 			for _, acct := range summary.AccountsSummary {
-				writePlainFields(u, fields, map[string]string{
+				writePlainFields(ctx, fields, map[string]any{
 					"account_id":      acct.AccountID,
 					"display_name":    acct.DisplayName,
 					"network":         acct.Network,
-					"chats":           fmt.Sprintf("%d", acct.Chats),
-					"unread_chats":    fmt.Sprintf("%d", acct.UnreadChats),
-					"unread_messages": fmt.Sprintf("%d", acct.UnreadMessages),
-					"muted_chats":     fmt.Sprintf("%d", acct.MutedChats),
-					"archived_chats":  fmt.Sprintf("%d", acct.ArchivedChats),
+					"chats":           acct.Chats,
+					"unread_chats":    acct.UnreadChats,
+					"unread_messages": acct.UnreadMessages,
+					"muted_chats":     acct.MutedChats,
+					"archived_chats":  acct.ArchivedChats,
 				})
 			}
 			return nil
@@ -150,16 +156,16 @@ func (c *StatusCmd) Run(ctx context.Context, flags *RootFlags) error {
 		if err != nil {
 			return err
 		}
-		values := map[string]string{
-			"accounts":            fmt.Sprintf("%d", summary.Accounts),
-			"chats":               fmt.Sprintf("%d", summary.Chats),
-			"unread_chats":        fmt.Sprintf("%d", summary.UnreadChats),
-			"unread_messages":     fmt.Sprintf("%d", summary.UnreadMessages),
-			"muted_chats":         fmt.Sprintf("%d", summary.MutedChats),
-			"archived_chats":      fmt.Sprintf("%d", summary.ArchivedChats),
-			"reminders_supported": formatBool(summary.RemindersSupported),
+		values := map[string]any{
+			"accounts":            summary.Accounts,
+			"chats":               summary.Chats,
+			"unread_chats":        summary.UnreadChats,
+			"unread_messages":     summary.UnreadMessages,
+			"muted_chats":         summary.MutedChats,
+			"archived_chats":      summary.ArchivedChats,
+			"reminders_supported": summary.RemindersSupported,
 		}
-		writePlainFields(u, fields, values)
+		writePlainFields(ctx, fields, values)
 		return nil
 	}
 
@@ -185,3 +191,17 @@ func (c *StatusCmd) Run(ctx context.Context, flags *RootFlags) error {
 
 	return nil
 }
+
+// statusChats walks every chat, 200 at a time, prefetching the next page
+// when prefetch is set.
+func statusChats(ctx context.Context, client *beeperapi.Client, prefetch bool) ([]beeperapi.ChatSearchItem, error) {
+	var items []beeperapi.ChatSearchItem
+	err := client.Chats().SearchEach(ctx, beeperapi.ChatSearchParams{
+		Limit:     200,
+		Direction: "before",
+	}, prefetch, func(page beeperapi.ChatSearchResult) error {
+		items = append(items, page.Items...)
+		return nil
+	})
+	return items, err
+}
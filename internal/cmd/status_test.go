@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/johntheyoung/roadrunner/internal/fakeapi"
+)
+
+func TestStatusByAccountPrefetchMatchesSerial(t *testing.T) {
+	t.Setenv("BEEPER_TOKEN", "test-token")
+	t.Setenv("BEEPER_ACCESS_TOKEN", "")
+
+	seed := fakeapi.Dataset{
+		Accounts: []fakeapi.Account{
+			{AccountID: "whatsapp", Network: "WhatsApp", User: fakeapi.User{ID: "@me:wa", IsSelf: true}},
+			{AccountID: "discord", Network: "Discord", User: fakeapi.User{ID: "@me:dc", IsSelf: true}},
+			{AccountID: "telegram", Network: "Telegram", User: fakeapi.User{ID: "@me:tg", IsSelf: true}},
+		},
+	}
+	for i := range 450 {
+		acct := seed.Accounts[i%3].AccountID
+		seed.Chats = append(seed.Chats, fakeapi.Chat{
+			ID:          fmt.Sprintf("!c%d:%s", i, acct),
+			AccountID:   acct,
+			Title:       fmt.Sprintf("Chat %d", i),
+			Type:        "group",
+			UnreadCount: int64(i % 4),
+			IsMuted:     i%5 == 0,
+		})
+	}
+	// Chats of an account that accounts list does not return still count.
+	for i := range 7 {
+		seed.Chats = append(seed.Chats, fakeapi.Chat{
+			ID:          fmt.Sprintf("!s%d:signal", i),
+			AccountID:   "signal",
+			Title:       fmt.Sprintf("Signal %d", i),
+			Type:        "single",
+			UnreadCount: 1,
+		})
+	}
+	fake := fakeapi.New(seed)
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	defer fake.Close()
+
+	run := func(concurrency int) statusSummary {
+		t.Helper()
+		out, _ := captureOutput(t, func() {
+			cmd := StatusCmd{ByAccount: true}
+			if err := cmd.Run(testJSONContext(t), &RootFlags{BaseURL: server.URL, Timeout: 5, Concurrency: concurrency}); err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+		})
+		var summary statusSummary
+		if err := json.Unmarshal([]byte(out), &summary); err != nil {
+			t.Fatalf("unmarshal output: %v\noutput: %s", err, out)
+		}
+		return summary
+	}
+
+	serial := run(1)
+	parallel := run(4)
+	if serial.Chats != 457 || serial.UnreadChats == 0 || serial.MutedChats == 0 {
+		t.Fatalf("serial summary = %+v", serial)
+	}
+	serialJSON, _ := json.Marshal(serial)
+	parallelJSON, _ := json.Marshal(parallel)
+	if string(serialJSON) != string(parallelJSON) {
+		t.Fatalf("prefetch summary differs:\nserial:   %s\nparallel: %s", serialJSON, parallelJSON)
+	}
+
+	var order []string
+	for _, acct := range parallel.AccountsSummary {
+		order = append(order, acct.AccountID)
+	}
+	if fmt.Sprint(order) != "[whatsapp discord telegram signal]" {
+		t.Fatalf("accounts order = %v, want accounts list order", order)
+	}
+}
@@ -6,6 +6,20 @@ import (
 	"testing"
 )
 
+// TestMain keeps Execute-based tests away from the real config directory and
+// off the local cache, whose entries could leak between test servers.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "rr-cmd-test-")
+	if err != nil {
+		panic(err)
+	}
+	_ = os.Setenv("XDG_CONFIG_HOME", dir)
+	_ = os.Setenv("BEEPER_NO_CACHE", "1")
+	code := m.Run()
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
+
 func captureOutput(t *testing.T, fn func()) (string, string) {
 	t.Helper()
 
@@ -18,11 +18,12 @@ import (
 type UnreadCmd struct {
 	AccountIDs   []string `help:"Filter by account IDs" name:"account-ids"`
 	Inbox        string   `help:"Filter by inbox: primary|low-priority|archive" enum:"primary,low-priority,archive," default:""`
+	Tags         []string `help:"Only chats carrying every given local tag (repeatable)" name:"tag"`
 	IncludeMuted *bool    `help:"Include muted chats (default true)" name:"include-muted"`
 	Limit        int      `help:"Max results (1-200)" default:"200"`
 	Cursor       string   `help:"Pagination cursor"`
 	Direction    string   `help:"Pagination direction: before|after" enum:"before,after," default:""`
-	Fields       []string `help:"Comma-separated list of fields for --plain output" name:"fields" sep:","`
+	Fields       []string `help:"Comma-separated list of fields for --plain, --csv, --yaml, or --markdown output" name:"fields" sep:","`
 	FailIfEmpty  bool     `help:"Exit with code 1 if no results" name:"fail-if-empty"`
 }
 
@@ -33,6 +34,10 @@ func (c *UnreadCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if c.Limit < 1 || c.Limit > 200 {
 		return errfmt.UsageError("invalid --limit %d (expected 1-200)", c.Limit)
 	}
+	meta, tags, err := openChatTagFilter(c.Tags)
+	if err != nil {
+		return err
+	}
 
 	token, _, err := config.GetToken()
 	if err != nil {
@@ -40,7 +45,7 @@ func (c *UnreadCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	timeout := time.Duration(flags.Timeout) * time.Second
-	client, err := beeperapi.NewClient(token, flags.BaseURL, timeout)
+	client, err := newAPIClient(ctx, token, flags.BaseURL, timeout)
 	if err != nil {
 		return err
 	}
@@ -57,6 +62,7 @@ func (c *UnreadCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if err != nil {
 		return err
 	}
+	resp.Items = tagChatSearchItems(meta, tags, resp.Items)
 
 	if err := failIfEmpty(c.FailIfEmpty, len(resp.Items), "unread chats"); err != nil {
 		return err
@@ -73,22 +79,22 @@ func (c *UnreadCmd) Run(ctx context.Context, flags *RootFlags) error {
 		})
 	}
 
-	if outfmt.IsPlain(ctx) {
+	if outfmt.IsTabular(ctx) {
 		fields, err := resolveFields(c.Fields, []string{"id", "title", "display_name", "account_id", "unread_count", "type", "network", "is_archived", "is_muted"})
 		if err != nil {
 			return err
 		}
 		for _, item := range resp.Items {
-			writePlainFields(u, fields, map[string]string{
+			writePlainFields(ctx, fields, map[string]any{
 				"id":           item.ID,
 				"title":        item.Title,
 				"display_name": item.DisplayName,
 				"account_id":   item.AccountID,
 				"type":         item.Type,
 				"network":      item.Network,
-				"unread_count": fmt.Sprintf("%d", item.UnreadCount),
-				"is_archived":  formatBool(item.IsArchived),
-				"is_muted":     formatBool(item.IsMuted),
+				"unread_count": item.UnreadCount,
+				"is_archived":  item.IsArchived,
+				"is_muted":     item.IsMuted,
 			})
 		}
 		return nil
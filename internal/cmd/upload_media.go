@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/johntheyoung/roadrunner/internal/media"
+)
+
+// attachmentMeta is the attachment metadata send-file sends: the user's
+// overrides, filled in from the file's headers where the user passed none.
+type attachmentMeta struct {
+	uploadMimeType string
+	attachmentType string
+	duration       *float64
+	width          *float64
+	height         *float64
+}
+
+// detectAttachmentMeta probes path and fills the fields of meta that are
+// unset. Only media MIME types are filled in; for other files the API's
+// own detection (which also uses the file name) is better.
+func detectAttachmentMeta(path string, meta attachmentMeta) (attachmentMeta, media.Info, error) {
+	info, err := media.Probe(path)
+	if err != nil {
+		return meta, media.Info{}, err
+	}
+	if meta.uploadMimeType == "" {
+		for _, prefix := range []string{"image/", "video/", "audio/"} {
+			if strings.HasPrefix(info.MimeType, prefix) {
+				meta.uploadMimeType = info.MimeType
+				break
+			}
+		}
+	}
+	if meta.width == nil && meta.height == nil && info.Width > 0 && info.Height > 0 {
+		width, height := float64(info.Width), float64(info.Height)
+		meta.width, meta.height = &width, &height
+	}
+	if meta.duration == nil && info.Duration > 0 {
+		duration := info.Duration
+		meta.duration = &duration
+	}
+	if meta.attachmentType == "" && info.VoiceNote {
+		meta.attachmentType = "voiceNote"
+	}
+	return meta, info, nil
+}
+
+// describeMediaInfo summarizes detected metadata for human output.
+func describeMediaInfo(info media.Info) string {
+	parts := []string{info.MimeType}
+	if info.Width > 0 && info.Height > 0 {
+		parts = append(parts, fmt.Sprintf("%dx%d", info.Width, info.Height))
+	}
+	if info.Duration > 0 {
+		parts = append(parts, fmt.Sprintf("%.1fs", info.Duration))
+	}
+	if info.VoiceNote {
+		parts = append(parts, "voice note")
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/johntheyoung/roadrunner/internal/fakeapi"
+)
+
+func TestSendFileDetectsMediaAndStripsMetadata(t *testing.T) {
+	t.Setenv("BEEPER_TOKEN", "test-token")
+	t.Setenv("BEEPER_ACCESS_TOKEN", "")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	fake := fakeapi.New(fakeapi.DefaultDataset())
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	defer fake.Close()
+
+	run := func(args ...string) (string, string, int) {
+		t.Helper()
+		var out, errText string
+		var code int
+		withArgs(t, append([]string{"rr", "--base-url", server.URL}, args...), func() {
+			out, errText = captureOutput(t, func() {
+				code = Execute()
+			})
+		})
+		return out, errText, code
+	}
+	mustRun := func(args ...string) string {
+		t.Helper()
+		out, errText, code := run(args...)
+		if code != 0 {
+			t.Fatalf("%v: exit code = %d, stderr = %q", args, code, errText)
+		}
+		return out
+	}
+	type attachment struct {
+		MimeType    string  `json:"mime_type"`
+		Duration    float64 `json:"duration"`
+		IsVoiceNote bool    `json:"is_voice_note"`
+		Width       int     `json:"width"`
+		Height      int     `json:"height"`
+	}
+	lastAttachment := func() attachment {
+		t.Helper()
+		var page struct {
+			Items []struct {
+				Attachments []attachment `json:"attachments"`
+			} `json:"items"`
+		}
+		if err := json.Unmarshal([]byte(mustRun("--json", "messages", "list", "!alice:beeper.local")), &page); err != nil {
+			t.Fatal(err)
+		}
+		if len(page.Items) == 0 || len(page.Items[0].Attachments) != 1 {
+			t.Fatalf("newest message has no attachment: %+v", page.Items)
+		}
+		return page.Items[0].Attachments[0]
+	}
+
+	dir := t.TempDir()
+	write := func(name string, data []byte) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	// A PNG without an extension: type and size come from the bytes.
+	var pngBuf bytes.Buffer
+	if err := png.Encode(&pngBuf, image.NewRGBA(image.Rect(0, 0, 3, 2))); err != nil {
+		t.Fatal(err)
+	}
+	shot := write("screenshot", pngBuf.Bytes())
+	mustRun("messages", "send-file", "!alice:beeper.local", shot)
+	if att := lastAttachment(); att.MimeType != "image/png" || att.Width != 3 || att.Height != 2 {
+		t.Fatalf("png attachment = %+v", att)
+	}
+
+	// Explicit flags win over detection; --no-detect sends none.
+	mustRun("messages", "send-file", "!alice:beeper.local", shot, "--attachment-width", "30", "--attachment-height", "20")
+	if att := lastAttachment(); att.Width != 30 || att.Height != 20 {
+		t.Fatalf("override attachment = %+v", att)
+	}
+	mustRun("messages", "send-file", "!alice:beeper.local", shot, "--no-detect")
+	if att := lastAttachment(); att.Width != 0 || att.Height != 0 {
+		t.Fatalf("--no-detect attachment = %+v", att)
+	}
+
+	// Opus in Ogg is sent as a voice note with its duration.
+	opusHead := append([]byte("OpusHead\x01\x01"), 0x38, 0x01, 0x80, 0xbb, 0x00, 0x00, 0x00, 0x00, 0x00)
+	oggPage := func(granule uint64, packet []byte) []byte {
+		page := binary.LittleEndian.AppendUint64([]byte("OggS\x00\x00"), granule)
+		page = append(page, make([]byte, 12)...)
+		return append(append(page, 1, byte(len(packet))), packet...)
+	}
+	voice := write("memo.ogg", append(oggPage(0, opusHead), oggPage(2*48000+312, make([]byte, 20))...))
+	mustRun("messages", "send-file", "!alice:beeper.local", voice)
+	if att := lastAttachment(); !att.IsVoiceNote || att.Duration != 2 {
+		t.Fatalf("voice attachment = %+v", att)
+	}
+
+	// --strip-metadata removes EXIF GPS data before upload.
+	var jpegBuf bytes.Buffer
+	if err := jpeg.Encode(&jpegBuf, image.NewGray(image.Rect(0, 0, 2, 2)), nil); err != nil {
+		t.Fatal(err)
+	}
+	exif := []byte("Exif\x00\x00II*\x00\x08\x00\x00\x00\x00\x00\x00\x00\x00\x00GPSLatitude 37.7749 N")
+	exifSegment := append([]byte{0xff, 0xe1, 0x00, byte(len(exif) + 2)}, exif...)
+	photo := write("home.jpg", append(append(jpegBuf.Bytes()[:2:2], exifSegment...), jpegBuf.Bytes()[2:]...))
+
+	out := mustRun("--dry-run", "--json", "messages", "send-file", "!alice:beeper.local", photo, "--strip-metadata")
+	if !strings.Contains(out, `"strip_metadata": true`) || !strings.Contains(out, `"mime_type": "image/jpeg"`) {
+		t.Fatalf("dry-run plan = %s", out)
+	}
+	var sent struct {
+		Upload struct {
+			SrcURL string `json:"src_url"`
+		} `json:"upload"`
+	}
+	if err := json.Unmarshal([]byte(mustRun("--json", "messages", "send-file", "!alice:beeper.local", photo, "--strip-metadata")), &sent); err != nil {
+		t.Fatal(err)
+	}
+	local, err := assetLocalPath(sent.Upload.SrcURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	uploaded, err := os.ReadFile(local)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(uploaded, []byte("GPSLatitude")) {
+		t.Fatal("uploaded JPEG still contains GPS metadata")
+	}
+	if _, err := jpeg.Decode(bytes.NewReader(uploaded)); err != nil {
+		t.Fatalf("uploaded JPEG does not decode: %v", err)
+	}
+}
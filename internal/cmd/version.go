@@ -11,7 +11,7 @@ import (
 
 // VersionCmd shows version information.
 type VersionCmd struct {
-	Fields []string `help:"Comma-separated list of fields for --plain output" name:"fields" sep:","`
+	Fields []string `help:"Comma-separated list of fields for --plain, --csv, --yaml, or --markdown output" name:"fields" sep:","`
 }
 
 // VersionString returns a human-readable version string.
@@ -44,17 +44,17 @@ func (c *VersionCmd) Run(ctx context.Context) error {
 			"version":  strings.TrimSpace(Version),
 			"commit":   strings.TrimSpace(Commit),
 			"date":     strings.TrimSpace(Date),
-			"features": []string{"enable-commands", "readonly", "dry-run", "envelope", "agent-mode", "error-hints", "request-id", "dedupe-guard", "retry-classes", "describe", "jsonl"},
+			"features": []string{"enable-commands", "readonly", "dry-run", "envelope", "agent-mode", "error-hints", "request-id", "dedupe-guard", "retry-classes", "describe", "jsonl", "stream", "checkpoint", "cassettes", "cache", "match-modes", "chat-tags", "people", "threads", "query", "template"},
 		}, "version")
 	}
 
 	// Plain output (TSV)
-	if outfmt.IsPlain(ctx) {
+	if outfmt.IsTabular(ctx) {
 		fields, err := resolveFields(c.Fields, []string{"version", "commit", "date"})
 		if err != nil {
 			return err
 		}
-		writePlainFields(u, fields, map[string]string{
+		writePlainFields(ctx, fields, map[string]any{
 			"version": strings.TrimSpace(Version),
 			"commit":  strings.TrimSpace(Commit),
 			"date":    strings.TrimSpace(Date),
@@ -6,12 +6,17 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 // Config represents the stored configuration.
 type Config struct {
 	Token          string            `json:"token,omitempty"`
 	AccountAliases map[string]string `json:"account_aliases,omitempty"`
+	ChatAliases    map[string]string `json:"chat_aliases,omitempty"`
+	// DefaultRegion is the ISO 3166 country code national phone numbers
+	// are dialed from, e.g. "US".
+	DefaultRegion string `json:"default_region,omitempty"`
 }
 
 // ErrNoToken is returned when no token is configured.
@@ -19,21 +24,27 @@ var ErrNoToken = errors.New("no token configured")
 
 func pruneEmptyConfigKeys(obj map[string]any) {
 	// Keep unrelated keys intact; only remove the keys we own when empty.
-	if v, ok := obj["token"]; ok {
-		s, _ := v.(string)
-		if s == "" {
-			delete(obj, "token")
+	for _, key := range []string{"token", "default_region"} {
+		if v, ok := obj[key]; ok {
+			s, _ := v.(string)
+			if s == "" {
+				delete(obj, key)
+			}
 		}
 	}
-	if v, ok := obj["account_aliases"]; ok {
+	for _, key := range []string{"account_aliases", "chat_aliases"} {
+		v, ok := obj[key]
+		if !ok {
+			continue
+		}
 		switch m := v.(type) {
 		case map[string]any:
 			if len(m) == 0 {
-				delete(obj, "account_aliases")
+				delete(obj, key)
 			}
 		case map[string]string:
 			if len(m) == 0 {
-				delete(obj, "account_aliases")
+				delete(obj, key)
 			}
 		}
 	}
@@ -100,6 +111,12 @@ func Save(cfg *Config) error {
 	} else {
 		obj["account_aliases"] = map[string]any{}
 	}
+	if len(cfg.ChatAliases) > 0 {
+		obj["chat_aliases"] = cfg.ChatAliases
+	} else {
+		obj["chat_aliases"] = map[string]any{}
+	}
+	obj["default_region"] = cfg.DefaultRegion
 	pruneEmptyConfigKeys(obj)
 
 	out, err := json.MarshalIndent(obj, "", "  ")
@@ -237,3 +254,63 @@ func ResolveAccountAlias(aliasOrID string) string {
 	}
 	return aliasOrID
 }
+
+// GetChatAliases returns all chat aliases.
+func GetChatAliases() (map[string]string, error) {
+	cfg, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.ChatAliases == nil {
+		return map[string]string{}, nil
+	}
+	return cfg.ChatAliases, nil
+}
+
+// SetChatAlias saves a chat alias. A leading "@" on alias is dropped.
+func SetChatAlias(alias, chatID string) error {
+	cfg, err := Load()
+	if err != nil {
+		return err
+	}
+	if cfg.ChatAliases == nil {
+		cfg.ChatAliases = make(map[string]string)
+	}
+	cfg.ChatAliases[strings.TrimPrefix(alias, "@")] = chatID
+	return Save(cfg)
+}
+
+// UnsetChatAlias removes a chat alias. A leading "@" on alias is dropped.
+func UnsetChatAlias(alias string) error {
+	cfg, err := Load()
+	if err != nil {
+		return err
+	}
+	if cfg.ChatAliases != nil {
+		delete(cfg.ChatAliases, strings.TrimPrefix(alias, "@"))
+	}
+	return Save(cfg)
+}
+
+// ResolveChatAlias resolves an alias, written as "name" or "@name", to a
+// chat ID. Returns the input unchanged if not found in aliases.
+func ResolveChatAlias(aliasOrID string) string {
+	cfg, err := Load()
+	if err != nil {
+		return aliasOrID
+	}
+	if resolved, ok := cfg.ChatAliases[strings.TrimPrefix(aliasOrID, "@")]; ok {
+		return resolved
+	}
+	return aliasOrID
+}
+
+// GetDefaultRegion returns the configured default phone region, or "" when
+// none is set or the config cannot be read.
+func GetDefaultRegion() string {
+	cfg, err := Load()
+	if err != nil {
+		return ""
+	}
+	return cfg.DefaultRegion
+}
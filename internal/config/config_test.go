@@ -119,3 +119,39 @@ func TestSavePreservesUnknownFields(t *testing.T) {
 		t.Fatalf("token = %#v, want %q", got["token"], "new")
 	}
 }
+
+func TestChatAliases(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", tmp)
+
+	if err := SetChatAlias("@oncall", "!ops:beeper.local"); err != nil {
+		t.Fatalf("SetChatAlias error: %v", err)
+	}
+	for _, in := range []string{"oncall", "@oncall"} {
+		if got := ResolveChatAlias(in); got != "!ops:beeper.local" {
+			t.Fatalf("ResolveChatAlias(%q) = %q", in, got)
+		}
+	}
+	if got := ResolveChatAlias("@other"); got != "@other" {
+		t.Fatalf("ResolveChatAlias(unknown) = %q", got)
+	}
+
+	if err := UnsetChatAlias("oncall"); err != nil {
+		t.Fatalf("UnsetChatAlias error: %v", err)
+	}
+	path, err := FilePath()
+	if err != nil {
+		t.Fatalf("FilePath() error: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile error: %v", err)
+	}
+	got := map[string]any{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if _, ok := got["chat_aliases"]; ok {
+		t.Fatalf("empty chat_aliases kept in config: %s", data)
+	}
+}
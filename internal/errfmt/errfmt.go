@@ -163,6 +163,7 @@ const (
 	ErrCodeValidation = "VALIDATION_ERROR"
 	ErrCodeConnection = "CONNECTION_ERROR"
 	ErrCodeInternal   = "INTERNAL_ERROR"
+	ErrCodeAmbiguous  = "AMBIGUOUS_MATCH"
 )
 
 // ErrorCode maps an error to an error code string.
@@ -183,6 +184,12 @@ func ErrorCode(err error) string {
 	if beeperapi.IsNotFound(err) {
 		return ErrCodeNotFound
 	}
+	if beeperapi.IsAmbiguous(err) {
+		return ErrCodeAmbiguous
+	}
+	if beeperapi.IsNoMatch(err) {
+		return ErrCodeNotFound
+	}
 	if beeperapi.IsAPIError(err) {
 		// Could check for rate limiting, but SDK doesn't expose status code directly
 		// For now, map unknown API errors to internal error
@@ -217,6 +224,22 @@ func ErrorCode(err error) string {
 	return ErrCodeInternal
 }
 
+// Details returns structured details for envelope errors: the ranked
+// candidates of an ambiguous chat or contact match. It returns nil for
+// other errors.
+func Details(err error) any {
+	var matchErr *beeperapi.MatchError
+	if !errors.As(err, &matchErr) || len(matchErr.Candidates) == 0 {
+		return nil
+	}
+	return map[string]any{
+		"resource":   matchErr.Resource,
+		"query":      matchErr.Query,
+		"match":      matchErr.Mode,
+		"candidates": matchErr.Candidates,
+	}
+}
+
 // Hint returns an optional actionable hint for known error patterns.
 func Hint(err error) string {
 	if err == nil {
@@ -246,15 +269,21 @@ func Hint(err error) string {
 	case strings.Contains(msg, "agent mode requires --enable-commands"):
 		return "Pass `--enable-commands` with an explicit allowlist, e.g. `--enable-commands=chats,messages,status`."
 	case strings.Contains(msg, "multiple chats matched"):
-		return "Use `rr chats resolve <query> --json` or pass an explicit chat ID to disambiguate."
+		return "Pass one of the candidate chat IDs, or narrow the query with `--match=exact`."
+	case strings.Contains(msg, "multiple contacts matched"):
+		return "Use one of the candidate contact IDs, or narrow the query with `--match=exact`."
 	case strings.Contains(msg, "no chat matched"):
-		return "Try `rr chats search <query> --scope=participants --json` to discover the chat ID."
+		return "Try `--match=fuzzy`, or `rr chats search <query> --scope=participants --json` to discover the chat ID."
 	case strings.Contains(msg, "attachment overrides require --attachment-upload-id"):
 		return "Upload first via `rr assets upload <path> --json`, then pass `--attachment-upload-id`."
 	case strings.Contains(msg, "message text or --attachment-upload-id is required"):
 		return "Provide message text or an uploaded attachment ID."
 	case strings.Contains(msg, "duplicate non-idempotent request blocked"):
 		return "Use a new `--request-id` for a deliberate retry, or pass `--force` to bypass the dedupe guard."
+	case strings.Contains(msg, "invalid phone number"):
+		return "Use international format like +14155550101, or pass `--region` (BEEPER_REGION, or `default_region` in config) for numbers without a country code."
+	case strings.Contains(msg, "unknown phone region"):
+		return "Pass a two-letter ISO 3166 country code to `--region`, e.g. `--region=US`."
 	case strings.Contains(msg, "requires --all"):
 		return "Add `--all` when using this max-items flag."
 	case strings.Contains(msg, "connection refused"),
@@ -2,8 +2,10 @@ package errfmt
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
+	"github.com/johntheyoung/roadrunner/internal/beeperapi"
 	"github.com/johntheyoung/roadrunner/internal/config"
 )
 
@@ -69,6 +71,10 @@ func TestHint_KnownUsagePatterns(t *testing.T) {
 			name: "max-items requires all",
 			err:  errors.New("--max-items requires --all"),
 		},
+		{
+			name: "invalid phone number",
+			err:  errors.New(`invalid phone number "5550100": too short for country code +1`),
+		},
 		{
 			name: "dedupe blocked",
 			err:  errors.New("duplicate non-idempotent request blocked"),
@@ -87,3 +93,43 @@ func TestHint_KnownUsagePatterns(t *testing.T) {
 		})
 	}
 }
+
+func TestErrorCodeAndDetails_MatchErrors(t *testing.T) {
+	noMatch := &beeperapi.MatchError{Resource: "chat", Query: "Alice", Mode: beeperapi.MatchExact}
+	if code := ErrorCode(WithCode(noMatch, ExitFailure)); code != ErrCodeNotFound {
+		t.Fatalf("no match code = %q, want %q", code, ErrCodeNotFound)
+	}
+	if details := Details(noMatch); details != nil {
+		t.Fatalf("no match details = %#v, want nil", details)
+	}
+
+	ambiguous := &beeperapi.MatchError{
+		Resource:   "chat",
+		Query:      "Alice",
+		Mode:       beeperapi.MatchFuzzy,
+		Ambiguous:  true,
+		Candidates: []beeperapi.MatchCandidate{{ID: "!a:x", Name: "Alice A", Score: 0.9}, {ID: "!b:x", Name: "Alice B", Score: 0.85}},
+	}
+	wrapped := WithCode(fmt.Errorf("resolve: %w", ambiguous), ExitFailure)
+	if code := ErrorCode(wrapped); code != ErrCodeAmbiguous {
+		t.Fatalf("ambiguous code = %q, want %q", code, ErrCodeAmbiguous)
+	}
+	details, ok := Details(wrapped).(map[string]any)
+	if !ok {
+		t.Fatalf("ambiguous details = %#v", Details(wrapped))
+	}
+	candidates, _ := details["candidates"].([]beeperapi.MatchCandidate)
+	if details["match"] != beeperapi.MatchFuzzy || len(candidates) != 2 || candidates[0].ID != "!a:x" {
+		t.Fatalf("ambiguous details = %#v", details)
+	}
+	if Hint(wrapped) == "" {
+		t.Fatal("expected hint for ambiguous match")
+	}
+}
+
+func TestErrorCode_MessageNotFound(t *testing.T) {
+	err := fmt.Errorf("%w: $x in !chat:x (scanned 25 pages)", beeperapi.ErrMessageNotFound)
+	if code := ErrorCode(err); code != ErrCodeNotFound {
+		t.Fatalf("message not found code = %q, want %q", code, ErrCodeNotFound)
+	}
+}
@@ -0,0 +1,97 @@
+// Package eventlog reads and writes recorded websocket event sessions and
+// replays them through a local /v1/ws endpoint.
+package eventlog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Frame is one recorded websocket frame. Data holds the raw JSON payload
+// exactly as received from the server.
+type Frame struct {
+	Time time.Time       `json:"t"`
+	Data json.RawMessage `json:"frame"`
+}
+
+// Writer appends frames to a JSONL recording.
+type Writer struct {
+	w     io.Writer
+	count int
+}
+
+// NewWriter returns a Writer that emits one frame per line to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Write records a raw frame received at the given time.
+func (w *Writer) Write(at time.Time, data []byte) error {
+	if !json.Valid(data) {
+		return fmt.Errorf("frame %d is not valid JSON", w.count+1)
+	}
+	line, err := json.Marshal(Frame{Time: at.UTC(), Data: json.RawMessage(data)})
+	if err != nil {
+		return fmt.Errorf("encode frame: %w", err)
+	}
+	line = append(line, '\n')
+	if _, err := w.w.Write(line); err != nil {
+		return err
+	}
+	w.count++
+	return nil
+}
+
+// Count returns the number of frames written.
+func (w *Writer) Count() int {
+	return w.count
+}
+
+// Read loads every frame from a JSONL recording. Blank lines are skipped.
+func Read(r io.Reader) ([]Frame, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	var frames []Frame
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var frame Frame
+		if err := json.Unmarshal(line, &frame); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		if len(frame.Data) == 0 {
+			return nil, fmt.Errorf("line %d: missing frame payload", lineNo)
+		}
+		frames = append(frames, frame)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return frames, nil
+}
+
+// Delays returns the wait before each frame relative to the previous one,
+// divided by speed. A speed of 0 disables delays.
+func Delays(frames []Frame, speed float64) []time.Duration {
+	delays := make([]time.Duration, len(frames))
+	if speed <= 0 {
+		return delays
+	}
+	for i := 1; i < len(frames); i++ {
+		gap := frames[i].Time.Sub(frames[i-1].Time)
+		if gap <= 0 {
+			continue
+		}
+		delays[i] = time.Duration(float64(gap) / speed)
+	}
+	return delays
+}
@@ -0,0 +1,117 @@
+package eventlog
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestWriteReadRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	start := time.Date(2026, 2, 11, 0, 0, 0, 0, time.UTC)
+	if err := w.Write(start, []byte(`{"type":"ready"}`)); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := w.Write(start.Add(1500*time.Millisecond), []byte(`{"type":"message.upserted","ids":["m1"]}`)); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := w.Write(start, []byte(`not json`)); err == nil {
+		t.Fatal("expected error for invalid JSON frame")
+	}
+	if w.Count() != 2 {
+		t.Fatalf("Count() = %d, want 2", w.Count())
+	}
+
+	frames, err := Read(strings.NewReader(buf.String() + "\n"))
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(frames) != 2 {
+		t.Fatalf("frames = %d, want 2", len(frames))
+	}
+	if string(frames[1].Data) != `{"type":"message.upserted","ids":["m1"]}` {
+		t.Fatalf("frame data = %s", frames[1].Data)
+	}
+	if !frames[0].Time.Equal(start) {
+		t.Fatalf("frame time = %s, want %s", frames[0].Time, start)
+	}
+}
+
+func TestReadRejectsMalformedLines(t *testing.T) {
+	t.Parallel()
+
+	if _, err := Read(strings.NewReader(`{"t":"2026-02-11T00:00:00Z"}`)); err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Fatalf("expected missing payload error, got %v", err)
+	}
+	if _, err := Read(strings.NewReader("{\"t\":\"2026-02-11T00:00:00Z\",\"frame\":{}}\n{")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Fatalf("expected parse error on line 2, got %v", err)
+	}
+}
+
+func TestDelaysScaleWithSpeed(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, 2, 11, 0, 0, 0, 0, time.UTC)
+	frames := []Frame{
+		{Time: start},
+		{Time: start.Add(2 * time.Second)},
+		{Time: start.Add(time.Second)}, // clock skew: never negative
+	}
+
+	got := Delays(frames, 4)
+	if got[0] != 0 || got[1] != 500*time.Millisecond || got[2] != 0 {
+		t.Fatalf("Delays(speed=4) = %v", got)
+	}
+	for _, d := range Delays(frames, 0) {
+		if d != 0 {
+			t.Fatalf("Delays(speed=0) should be zero, got %v", d)
+		}
+	}
+}
+
+func TestServerReplaysFramesAfterSubscribe(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, 2, 11, 0, 0, 0, 0, time.UTC)
+	replay := NewServer([]Frame{
+		{Time: start, Data: []byte(`{"type":"ready"}`)},
+		{Time: start.Add(time.Hour), Data: []byte(`{"type":"message.upserted","ids":["m1"]}`)},
+	}, 0)
+	server := httptest.NewServer(replay)
+	defer server.Close()
+	defer replay.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/v1/ws", nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	if err := conn.WriteJSON(map[string]any{"type": "subscriptions.set", "chatIDs": []string{"*"}}); err != nil {
+		t.Fatalf("write subscribe: %v", err)
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for _, want := range []string{`{"type":"ready"}`, `{"type":"message.upserted","ids":["m1"]}`} {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		if string(data) != want {
+			t.Fatalf("frame = %s, want %s", data, want)
+		}
+	}
+
+	_ = conn.Close()
+	select {
+	case <-replay.Sessions():
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected session to end after client disconnect")
+	}
+}
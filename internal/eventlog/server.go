@@ -0,0 +1,127 @@
+package eventlog
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// subscribeWait bounds how long a session waits for the client's first
+// message (normally subscriptions.set) before replay starts anyway.
+const subscribeWait = 500 * time.Millisecond
+
+// Server replays a recording to every client that connects to /v1/ws.
+// Any bearer token is accepted. After the last frame the connection stays
+// open until the client disconnects or the server is closed.
+type Server struct {
+	frames   []Frame
+	delays   []time.Duration
+	upgrader websocket.Upgrader
+
+	sessions  chan struct{}
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// NewServer returns a replay server. speed scales the recorded gaps between
+// frames (2 replays twice as fast); 0 sends frames without delay.
+func NewServer(frames []Frame, speed float64) *Server {
+	return &Server{
+		frames: frames,
+		delays: Delays(frames, speed),
+		upgrader: websocket.Upgrader{
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+		sessions: make(chan struct{}, 1),
+		closed:   make(chan struct{}),
+	}
+}
+
+// Sessions receives a value each time a client session ends.
+func (s *Server) Sessions() <-chan struct{} {
+	return s.sessions
+}
+
+// Close stops all active replay sessions.
+func (s *Server) Close() {
+	s.closeOnce.Do(func() { close(s.closed) })
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/v1/ws" {
+		http.NotFound(w, r)
+		return
+	}
+
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer func() { _ = conn.Close() }()
+	defer s.sessionDone()
+
+	subscribed := make(chan struct{})
+	disconnected := make(chan struct{})
+	go func() {
+		defer close(disconnected)
+		first := true
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+			if first {
+				first = false
+				close(subscribed)
+			}
+		}
+	}()
+
+	wait := time.NewTimer(subscribeWait)
+	select {
+	case <-subscribed:
+	case <-wait.C:
+	case <-disconnected:
+		wait.Stop()
+		return
+	case <-s.closed:
+		wait.Stop()
+		return
+	}
+	wait.Stop()
+
+	for i, frame := range s.frames {
+		if s.delays[i] > 0 {
+			timer := time.NewTimer(s.delays[i])
+			select {
+			case <-timer.C:
+			case <-disconnected:
+				timer.Stop()
+				return
+			case <-s.closed:
+				timer.Stop()
+				return
+			}
+		}
+		if err := conn.WriteMessage(websocket.TextMessage, frame.Data); err != nil {
+			return
+		}
+	}
+
+	select {
+	case <-disconnected:
+	case <-s.closed:
+		_ = conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseNormalClosure, "replay finished"),
+			time.Now().Add(time.Second))
+	}
+}
+
+func (s *Server) sessionDone() {
+	select {
+	case s.sessions <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,231 @@
+package fakeapi
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxUploadBytes bounds uploads kept in memory.
+const maxUploadBytes = 64 << 20
+
+// findAsset looks an asset up by mxc:// URL, upload ID, or file:// URL.
+func (s *Server) findAsset(ref string) (Asset, bool) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return Asset{}, false
+	}
+	for _, asset := range s.data.Assets {
+		if asset.URL == ref || uploadIDForURL(asset.URL) == ref {
+			return asset, true
+		}
+	}
+	if strings.HasPrefix(ref, "file://") && s.assetDir != "" {
+		if u, err := url.Parse(ref); err == nil {
+			rel, err := filepath.Rel(s.assetDir, filepath.FromSlash(u.Path))
+			if err == nil && !strings.HasPrefix(rel, "..") {
+				parts := strings.SplitN(filepath.ToSlash(rel), "/", 2)
+				for _, asset := range s.data.Assets {
+					if assetKey(asset.URL) == parts[0] {
+						return asset, true
+					}
+				}
+			}
+		}
+	}
+	return Asset{}, false
+}
+
+// materialize writes an asset under the server's temp dir and returns its
+// file:// URL, mirroring how Desktop downloads media to local disk.
+func (s *Server) materialize(asset Asset) (string, error) {
+	if s.assetDir == "" {
+		dir, err := os.MkdirTemp("", "rr-fakeapi-assets-")
+		if err != nil {
+			return "", fmt.Errorf("create asset dir: %w", err)
+		}
+		s.assetDir = dir
+	}
+	name := asset.FileName
+	if name == "" {
+		name = "asset"
+	}
+	dir := filepath.Join(s.assetDir, assetKey(asset.URL))
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("create asset dir: %w", err)
+	}
+	path := filepath.Join(dir, filepath.Base(name))
+	if err := os.WriteFile(path, asset.Content, 0o600); err != nil {
+		return "", fmt.Errorf("write asset: %w", err)
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String(), nil
+}
+
+func (s *Server) handleAssetDownload(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		URL string `json:"url"`
+	}
+	if err := decodeBody(r, &body); err != nil || body.URL == "" {
+		writeError(w, http.StatusBadRequest, "url is required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	asset, ok := s.findAsset(body.URL)
+	if !ok {
+		writeJSON(w, http.StatusOK, map[string]any{"error": "asset not found"})
+		return
+	}
+	srcURL, err := s.materialize(asset)
+	if err != nil {
+		writeJSON(w, http.StatusOK, map[string]any{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"srcURL": srcURL})
+}
+
+func (s *Server) handleAssetServe(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	asset, ok := s.findAsset(r.URL.Query().Get("url"))
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "asset not found")
+		return
+	}
+	contentType := asset.MimeType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", fmt.Sprint(len(asset.Content)))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(asset.Content)
+}
+
+func (s *Server) handleAssetUpload(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(maxUploadBytes); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid multipart body")
+		return
+	}
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "file is required")
+		return
+	}
+	defer func() { _ = file.Close() }()
+	content, err := io.ReadAll(io.LimitReader(file, maxUploadBytes))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "read upload")
+		return
+	}
+
+	fileName := firstNonEmpty(r.FormValue("fileName"), header.Filename)
+	mimeType := firstNonEmpty(r.FormValue("mimeType"), header.Header.Get("Content-Type"))
+	s.storeUpload(w, fileName, mimeType, content)
+}
+
+func (s *Server) handleAssetUploadBase64(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Content  string `json:"content"`
+		FileName string `json:"fileName"`
+		MimeType string `json:"mimeType"`
+	}
+	if err := decodeBody(r, &body); err != nil || body.Content == "" {
+		writeError(w, http.StatusBadRequest, "content is required")
+		return
+	}
+	content, err := base64.StdEncoding.DecodeString(body.Content)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "content is not valid base64")
+		return
+	}
+	s.storeUpload(w, body.FileName, body.MimeType, content)
+}
+
+func (s *Server) storeUpload(w http.ResponseWriter, fileName, mimeType string, content []byte) {
+	if mimeType == "" || mimeType == "application/octet-stream" {
+		if byExt := mime.TypeByExtension(filepath.Ext(fileName)); byExt != "" {
+			mimeType = byExt
+		} else {
+			mimeType = http.DetectContentType(content)
+		}
+	}
+	if i := strings.Index(mimeType, ";"); i >= 0 {
+		mimeType = strings.TrimSpace(mimeType[:i])
+	}
+
+	s.mu.Lock()
+	uploadID := s.newID("upload-")
+	asset := Asset{
+		URL:      "mxc://fakeapi.local/" + uploadID,
+		FileName: fileName,
+		MimeType: mimeType,
+		Content:  content,
+	}
+	s.data.Assets = append(s.data.Assets, asset)
+	srcURL, err := s.materialize(asset)
+	s.mu.Unlock()
+	if err != nil {
+		writeJSON(w, http.StatusOK, map[string]any{"error": err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"uploadID": uploadID,
+		"srcURL":   srcURL,
+		"fileName": fileName,
+		"mimeType": mimeType,
+		"fileSize": len(content),
+	})
+}
+
+// uploadIDForURL returns the upload ID for assets created by uploads.
+func uploadIDForURL(assetURL string) string {
+	const prefix = "mxc://fakeapi.local/"
+	if strings.HasPrefix(assetURL, prefix) {
+		return strings.TrimPrefix(assetURL, prefix)
+	}
+	return ""
+}
+
+// assetKey is a filesystem-safe directory name for an asset URL.
+func assetKey(assetURL string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(assetURL))
+}
+
+func attachmentType(mimeType string) string {
+	switch {
+	case strings.HasPrefix(mimeType, "image/"):
+		return "img"
+	case strings.HasPrefix(mimeType, "video/"):
+		return "video"
+	case strings.HasPrefix(mimeType, "audio/"):
+		return "audio"
+	default:
+		return "unknown"
+	}
+}
+
+func messageTypeForAttachment(att Attachment) string {
+	switch {
+	case att.IsSticker:
+		return "STICKER"
+	case att.IsVoiceNote:
+		return "VOICE"
+	case att.Type == "img":
+		return "IMAGE"
+	case att.Type == "video":
+		return "VIDEO"
+	case att.Type == "audio":
+		return "AUDIO"
+	default:
+		return "FILE"
+	}
+}
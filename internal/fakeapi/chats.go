@@ -0,0 +1,440 @@
+package fakeapi
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type chatParticipantsJSON struct {
+	Items   []User `json:"items"`
+	HasMore bool   `json:"hasMore"`
+	Total   int    `json:"total"`
+}
+
+type chatJSON struct {
+	ID                     string               `json:"id"`
+	AccountID              string               `json:"accountID"`
+	Network                string               `json:"network"`
+	Title                  string               `json:"title"`
+	Type                   string               `json:"type"`
+	Participants           chatParticipantsJSON `json:"participants"`
+	UnreadCount            int64                `json:"unreadCount"`
+	IsArchived             bool                 `json:"isArchived"`
+	IsMuted                bool                 `json:"isMuted"`
+	IsPinned               bool                 `json:"isPinned"`
+	LastActivity           *time.Time           `json:"lastActivity,omitempty"`
+	LastReadMessageSortKey string               `json:"lastReadMessageSortKey,omitempty"`
+	Preview                *Message             `json:"preview,omitempty"`
+}
+
+func (s *Server) chatJSON(chat Chat, withPreview bool) chatJSON {
+	out := chatJSON{
+		ID:          chat.ID,
+		AccountID:   chat.AccountID,
+		Title:       chat.Title,
+		Type:        chat.Type,
+		UnreadCount: chat.UnreadCount,
+		IsArchived:  chat.IsArchived,
+		IsMuted:     chat.IsMuted,
+		IsPinned:    chat.IsPinned,
+		Participants: chatParticipantsJSON{
+			Items: chat.Participants,
+			Total: len(chat.Participants),
+		},
+	}
+	if out.Participants.Items == nil {
+		out.Participants.Items = []User{}
+	}
+	if account, ok := s.account(chat.AccountID); ok {
+		out.Network = account.Network
+	}
+	if !chat.LastActivity.IsZero() {
+		lastActivity := chat.LastActivity
+		out.LastActivity = &lastActivity
+	}
+	if withPreview {
+		if msg, ok := s.latestMessage(chat.ID); ok {
+			out.Preview = &msg
+		}
+	}
+	return out
+}
+
+// sortedChats returns chats ordered by last activity, newest first.
+func (s *Server) sortedChats() []Chat {
+	chats := append([]Chat{}, s.data.Chats...)
+	sort.SliceStable(chats, func(i, j int) bool {
+		return chats[i].LastActivity.After(chats[j].LastActivity)
+	})
+	return chats
+}
+
+func (s *Server) findChat(chatID string) (*Chat, bool) {
+	for i := range s.data.Chats {
+		if s.data.Chats[i].ID == chatID {
+			return &s.data.Chats[i], true
+		}
+	}
+	return nil, false
+}
+
+func (s *Server) writeChatPage(w http.ResponseWriter, r *http.Request, chats []Chat, limit int, withPreview bool) {
+	ids := make([]string, len(chats))
+	for i, chat := range chats {
+		ids[i] = chat.ID
+	}
+	direction := r.URL.Query().Get("direction")
+	start, end := pageBounds(ids, r.URL.Query().Get("cursor"), direction, limit)
+
+	items := make([]chatJSON, 0, end-start)
+	for _, chat := range chats[start:end] {
+		items = append(items, s.chatJSON(chat, withPreview))
+	}
+	resp := map[string]any{
+		"items":   items,
+		"hasMore": hasMoreInDirection(len(ids), start, end, direction),
+	}
+	if len(items) > 0 {
+		resp["oldestCursor"] = items[len(items)-1].ID
+		resp["newestCursor"] = items[0].ID
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleChatsList(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	accountIDs := queryList(r, "accountIDs")
+	chats := make([]Chat, 0, len(s.data.Chats))
+	for _, chat := range s.sortedChats() {
+		if len(accountIDs) > 0 && !contains(accountIDs, chat.AccountID) {
+			continue
+		}
+		chats = append(chats, chat)
+	}
+	s.writeChatPage(w, r, chats, 25, true)
+}
+
+func (s *Server) handleChatsSearch(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	query := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("query")))
+	scope := r.URL.Query().Get("scope")
+	accountIDs := queryList(r, "accountIDs")
+	inbox := r.URL.Query().Get("inbox")
+	chatType := r.URL.Query().Get("type")
+	unreadOnly, _ := queryBool(r, "unreadOnly")
+	includeMuted, includeMutedSet := queryBool(r, "includeMuted")
+	after := queryTime(r, "lastActivityAfter")
+	before := queryTime(r, "lastActivityBefore")
+
+	chats := make([]Chat, 0, len(s.data.Chats))
+	for _, chat := range s.sortedChats() {
+		if len(accountIDs) > 0 && !contains(accountIDs, chat.AccountID) {
+			continue
+		}
+		if query != "" && !chatMatches(chat, query, scope) {
+			continue
+		}
+		switch inbox {
+		case "primary", "low-priority":
+			if chat.IsArchived {
+				continue
+			}
+		case "archive":
+			if !chat.IsArchived {
+				continue
+			}
+		}
+		if chatType == "single" || chatType == "group" {
+			if chat.Type != chatType {
+				continue
+			}
+		}
+		if unreadOnly && chat.UnreadCount == 0 {
+			continue
+		}
+		if includeMutedSet && !includeMuted && chat.IsMuted {
+			continue
+		}
+		if after != nil && !chat.LastActivity.After(*after) {
+			continue
+		}
+		if before != nil && !chat.LastActivity.Before(*before) {
+			continue
+		}
+		chats = append(chats, chat)
+	}
+	s.writeChatPage(w, r, chats, queryLimit(r, 50), false)
+}
+
+func chatMatches(chat Chat, query, scope string) bool {
+	if scope != "participants" && strings.Contains(strings.ToLower(chat.Title), query) {
+		return true
+	}
+	if scope == "titles" {
+		return false
+	}
+	for _, user := range chat.Participants {
+		if !user.IsSelf && userMatches(user, query) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) handleChatGet(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	chat, ok := s.findChat(r.PathValue("chatID"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "chat not found")
+		return
+	}
+	out := s.chatJSON(*chat, false)
+	if limit, err := strconv.Atoi(r.URL.Query().Get("maxParticipantCount")); err == nil && limit >= 0 && limit < len(out.Participants.Items) {
+		out.Participants.Items = out.Participants.Items[:limit]
+		out.Participants.HasMore = true
+	}
+	if msg, ok := s.latestMessage(chat.ID); ok && chat.UnreadCount == 0 {
+		out.LastReadMessageSortKey = msg.SortKey
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+type chatCreateBody struct {
+	AccountID      string   `json:"accountID"`
+	Mode           string   `json:"mode"`
+	Type           string   `json:"type"`
+	Title          string   `json:"title"`
+	ParticipantIDs []string `json:"participantIDs"`
+	MessageText    string   `json:"messageText"`
+	User           User     `json:"user"`
+	AllowInvite    *bool    `json:"allowInvite"`
+}
+
+func (s *Server) handleChatsCreate(w http.ResponseWriter, r *http.Request) {
+	var body chatCreateBody
+	if err := decodeBody(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	s.mu.Lock()
+	account, ok := s.account(body.AccountID)
+	if !ok {
+		s.mu.Unlock()
+		writeError(w, http.StatusNotFound, "account not found")
+		return
+	}
+
+	var (
+		chat   *Chat
+		status string
+	)
+	if body.Mode == "start" {
+		user, found := s.resolveStartUser(account.AccountID, body.User)
+		if !found {
+			s.mu.Unlock()
+			writeError(w, http.StatusNotFound, "user not found")
+			return
+		}
+		if existing, ok := s.findDirectChat(account.AccountID, user.ID); ok {
+			chat, status = existing, "existing"
+		} else {
+			chat, status = s.addChat(Chat{
+				AccountID:    account.AccountID,
+				Title:        firstNonEmpty(user.FullName, user.Username, user.ID),
+				Type:         "single",
+				Participants: []User{account.User, user},
+			}), "created"
+		}
+	} else {
+		if len(body.ParticipantIDs) == 0 {
+			s.mu.Unlock()
+			writeError(w, http.StatusBadRequest, "participantIDs is required")
+			return
+		}
+		participants := []User{account.User}
+		for _, id := range body.ParticipantIDs {
+			participants = append(participants, s.lookupUser(account.AccountID, id))
+		}
+		chatType := body.Type
+		if chatType == "" {
+			chatType = "single"
+			if len(body.ParticipantIDs) > 1 {
+				chatType = "group"
+			}
+		}
+		title := body.Title
+		if title == "" {
+			title = firstNonEmpty(participants[1].FullName, participants[1].ID)
+		}
+		chat = s.addChat(Chat{
+			AccountID:    account.AccountID,
+			Title:        title,
+			Type:         chatType,
+			Participants: participants,
+		})
+	}
+
+	chatID := chat.ID
+	var sent []Message
+	if body.MessageText != "" {
+		sent = append(sent, s.appendMessage(chat, Message{Text: body.MessageText}))
+	}
+	snapshot := s.chatJSON(*chat, false)
+	s.mu.Unlock()
+
+	if status != "existing" {
+		s.events.publish(chatID, "chat.upserted", []string{chatID}, snapshot)
+	}
+	for _, msg := range sent {
+		s.events.publish(chatID, "message.upserted", []string{msg.ID}, msg)
+	}
+
+	resp := map[string]any{"chatID": chatID}
+	if status != "" {
+		resp["status"] = status
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) addChat(chat Chat) *Chat {
+	chat.ID = "!" + s.newID("chat") + ":fakeapi.local"
+	chat.LastActivity = s.now().UTC()
+	s.data.Chats = append(s.data.Chats, chat)
+	return &s.data.Chats[len(s.data.Chats)-1]
+}
+
+func (s *Server) findDirectChat(accountID, userID string) (*Chat, bool) {
+	for i := range s.data.Chats {
+		chat := &s.data.Chats[i]
+		if chat.AccountID != accountID || chat.Type != "single" {
+			continue
+		}
+		for _, p := range chat.Participants {
+			if p.ID == userID {
+				return chat, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// resolveStartUser matches start-mode user hints against known contacts and
+// chat participants on the account.
+func (s *Server) resolveStartUser(accountID string, hint User) (User, bool) {
+	candidates := append([]User{}, s.data.Contacts[accountID]...)
+	for _, chat := range s.data.Chats {
+		if chat.AccountID == accountID {
+			candidates = append(candidates, chat.Participants...)
+		}
+	}
+	for _, user := range candidates {
+		if user.IsSelf {
+			continue
+		}
+		switch {
+		case hint.ID != "" && user.ID == hint.ID,
+			hint.PhoneNumber != "" && user.PhoneNumber == hint.PhoneNumber,
+			hint.Email != "" && strings.EqualFold(user.Email, hint.Email),
+			hint.Username != "" && strings.EqualFold(strings.TrimPrefix(user.Username, "@"), strings.TrimPrefix(hint.Username, "@")):
+			return user, true
+		}
+	}
+	if hint.ID != "" || hint.PhoneNumber != "" {
+		// Unknown but addressable: networks can start chats with new numbers.
+		id := firstNonEmpty(hint.ID, hint.PhoneNumber)
+		return User{ID: id, FullName: hint.FullName, PhoneNumber: hint.PhoneNumber, Email: hint.Email, Username: hint.Username}, true
+	}
+	return User{}, false
+}
+
+func (s *Server) lookupUser(accountID, userID string) User {
+	for _, user := range s.data.Contacts[accountID] {
+		if user.ID == userID {
+			return user
+		}
+	}
+	for _, chat := range s.data.Chats {
+		for _, user := range chat.Participants {
+			if user.ID == userID {
+				return user
+			}
+		}
+	}
+	return User{ID: userID}
+}
+
+func (s *Server) handleChatArchive(w http.ResponseWriter, r *http.Request) {
+	body := struct {
+		Archived *bool `json:"archived"`
+	}{}
+	_ = decodeBody(r, &body)
+	archived := true
+	if body.Archived != nil {
+		archived = *body.Archived
+	}
+
+	s.mu.Lock()
+	chat, ok := s.findChat(r.PathValue("chatID"))
+	if !ok {
+		s.mu.Unlock()
+		writeError(w, http.StatusNotFound, "chat not found")
+		return
+	}
+	chat.IsArchived = archived
+	snapshot := s.chatJSON(*chat, false)
+	s.mu.Unlock()
+
+	s.events.publish(snapshot.ID, "chat.upserted", []string{snapshot.ID}, snapshot)
+	writeJSON(w, http.StatusOK, map[string]any{"success": true})
+}
+
+func (s *Server) handleReminderSet(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Reminder *Reminder `json:"reminder"`
+	}
+	if err := decodeBody(r, &body); err != nil || body.Reminder == nil {
+		writeError(w, http.StatusBadRequest, "reminder is required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	chat, ok := s.findChat(r.PathValue("chatID"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "chat not found")
+		return
+	}
+	chat.Reminder = body.Reminder
+	writeJSON(w, http.StatusOK, map[string]any{"success": true})
+}
+
+func (s *Server) handleReminderClear(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	chat, ok := s.findChat(r.PathValue("chatID"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "chat not found")
+		return
+	}
+	chat.Reminder = nil
+	writeJSON(w, http.StatusOK, map[string]any{"success": true})
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
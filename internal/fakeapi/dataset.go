@@ -0,0 +1,265 @@
+package fakeapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Dataset is the seedable state served by Server. Its JSON form uses the
+// same camelCase field names as the Desktop API, so fixtures can be written
+// by hand or captured from real responses.
+type Dataset struct {
+	Accounts []Account         `json:"accounts"`
+	Contacts map[string][]User `json:"contacts,omitempty"` // keyed by accountID
+	Chats    []Chat            `json:"chats"`
+	Messages []Message         `json:"messages"`
+	Assets   []Asset           `json:"assets,omitempty"`
+}
+
+// Account is a connected chat account.
+type Account struct {
+	AccountID string `json:"accountID"`
+	Network   string `json:"network"`
+	User      User   `json:"user"`
+}
+
+// User is a person on a network.
+type User struct {
+	ID            string `json:"id"`
+	FullName      string `json:"fullName,omitempty"`
+	Username      string `json:"username,omitempty"`
+	Email         string `json:"email,omitempty"`
+	PhoneNumber   string `json:"phoneNumber,omitempty"`
+	ImgURL        string `json:"imgURL,omitempty"`
+	CannotMessage bool   `json:"cannotMessage,omitempty"`
+	IsSelf        bool   `json:"isSelf,omitempty"`
+}
+
+// Chat is a conversation. Type is "single" or "group".
+type Chat struct {
+	ID           string    `json:"id"`
+	AccountID    string    `json:"accountID"`
+	Title        string    `json:"title"`
+	Type         string    `json:"type"`
+	Participants []User    `json:"participants,omitempty"`
+	UnreadCount  int64     `json:"unreadCount,omitempty"`
+	IsArchived   bool      `json:"isArchived,omitempty"`
+	IsMuted      bool      `json:"isMuted,omitempty"`
+	IsPinned     bool      `json:"isPinned,omitempty"`
+	LastActivity time.Time `json:"lastActivity,omitempty"`
+	Reminder     *Reminder `json:"reminder,omitempty"`
+}
+
+// Reminder is a chat reminder set through the reminders route.
+type Reminder struct {
+	RemindAtMs               float64 `json:"remindAtMs"`
+	DismissOnIncomingMessage bool    `json:"dismissOnIncomingMessage,omitempty"`
+}
+
+// Message is a chat message. Type uses API values (TEXT, IMAGE, ...).
+type Message struct {
+	ID              string       `json:"id"`
+	AccountID       string       `json:"accountID"`
+	ChatID          string       `json:"chatID"`
+	SenderID        string       `json:"senderID"`
+	SenderName      string       `json:"senderName,omitempty"`
+	SortKey         string       `json:"sortKey"`
+	Timestamp       time.Time    `json:"timestamp"`
+	Text            string       `json:"text,omitempty"`
+	Type            string       `json:"type,omitempty"`
+	LinkedMessageID string       `json:"linkedMessageID,omitempty"`
+	IsSender        bool         `json:"isSender,omitempty"`
+	IsUnread        bool         `json:"isUnread,omitempty"`
+	Attachments     []Attachment `json:"attachments,omitempty"`
+	Reactions       []Reaction   `json:"reactions,omitempty"`
+}
+
+// Attachment is message media metadata.
+type Attachment struct {
+	Type        string          `json:"type"`
+	ID          string          `json:"id,omitempty"`
+	FileName    string          `json:"fileName,omitempty"`
+	FileSize    float64         `json:"fileSize,omitempty"`
+	MimeType    string          `json:"mimeType,omitempty"`
+	SrcURL      string          `json:"srcURL,omitempty"`
+	Duration    float64         `json:"duration,omitempty"`
+	IsGif       bool            `json:"isGif,omitempty"`
+	IsSticker   bool            `json:"isSticker,omitempty"`
+	IsVoiceNote bool            `json:"isVoiceNote,omitempty"`
+	PosterImg   string          `json:"posterImg,omitempty"`
+	Size        *AttachmentSize `json:"size,omitempty"`
+}
+
+// AttachmentSize is attachment pixel dimensions.
+type AttachmentSize struct {
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// Reaction is a reaction on a message.
+type Reaction struct {
+	ID            string `json:"id"`
+	ParticipantID string `json:"participantID"`
+	ReactionKey   string `json:"reactionKey"`
+	Emoji         bool   `json:"emoji,omitempty"`
+	ImgURL        string `json:"imgURL,omitempty"`
+}
+
+// Asset is downloadable media content. URL is usually an mxc:// URL;
+// Content is base64 in JSON.
+type Asset struct {
+	URL      string `json:"url"`
+	FileName string `json:"fileName,omitempty"`
+	MimeType string `json:"mimeType,omitempty"`
+	Content  []byte `json:"content"`
+}
+
+// LoadDataset reads a JSON dataset from path.
+func LoadDataset(path string) (Dataset, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Dataset{}, fmt.Errorf("read dataset: %w", err)
+	}
+	var ds Dataset
+	if err := json.Unmarshal(data, &ds); err != nil {
+		return Dataset{}, fmt.Errorf("parse dataset %s: %w", path, err)
+	}
+	return ds, nil
+}
+
+// DefaultDataset returns a small deterministic dataset with two accounts,
+// a direct chat, a group chat, and a handful of messages.
+func DefaultDataset() Dataset {
+	base := time.Date(2026, 2, 11, 9, 0, 0, 0, time.UTC)
+	self := User{ID: "@me:beeper.local", FullName: "Me", IsSelf: true}
+	alice := User{ID: "@alice:beeper.local", FullName: "Alice Example", Username: "alice", PhoneNumber: "+14155550101"}
+	bob := User{ID: "@bob:beeper.local", FullName: "Bob Example", Username: "bob", Email: "bob@example.com"}
+	carol := User{ID: "+14155550199", FullName: "Carol Example", PhoneNumber: "+14155550199"}
+	waSelf := User{ID: "+14155550100", FullName: "Me", PhoneNumber: "+14155550100", IsSelf: true}
+
+	return Dataset{
+		Accounts: []Account{
+			{AccountID: "matrix", Network: "Beeper", User: self},
+			{AccountID: "whatsapp", Network: "WhatsApp", User: waSelf},
+		},
+		Contacts: map[string][]User{
+			"matrix":   {alice, bob},
+			"whatsapp": {carol},
+		},
+		Chats: []Chat{
+			{
+				ID: "!alice:beeper.local", AccountID: "matrix", Title: "Alice Example", Type: "single",
+				Participants: []User{self, alice}, UnreadCount: 1, LastActivity: base.Add(3 * time.Minute),
+			},
+			{
+				ID: "!team:beeper.local", AccountID: "matrix", Title: "Team", Type: "group",
+				Participants: []User{self, alice, bob}, LastActivity: base.Add(2 * time.Minute),
+			},
+			{
+				ID: "!carol:whatsapp.local", AccountID: "whatsapp", Title: "Carol Example", Type: "single",
+				Participants: []User{waSelf, carol}, IsArchived: true, LastActivity: base,
+			},
+		},
+		Messages: []Message{
+			{ID: "$m1", ChatID: "!carol:whatsapp.local", SenderID: carol.ID, SenderName: carol.FullName, Timestamp: base, Text: "Lunch tomorrow?"},
+			{ID: "$m2", ChatID: "!team:beeper.local", SenderID: bob.ID, SenderName: bob.FullName, Timestamp: base.Add(time.Minute), Text: "Standup notes are in the doc"},
+			{ID: "$m3", ChatID: "!team:beeper.local", SenderID: self.ID, SenderName: self.FullName, Timestamp: base.Add(2 * time.Minute), Text: "Thanks!", IsSender: true, LinkedMessageID: "$m2"},
+			{
+				ID: "$m4", ChatID: "!alice:beeper.local", SenderID: alice.ID, SenderName: alice.FullName, Timestamp: base.Add(3 * time.Minute),
+				Text: "Photo from the trip", Type: "IMAGE", IsUnread: true,
+				Attachments: []Attachment{{Type: "img", ID: "mxc://beeper.local/trip", FileName: "trip.png", MimeType: "image/png", FileSize: 8, Size: &AttachmentSize{Width: 1, Height: 1}}},
+			},
+		},
+		Assets: []Asset{
+			{URL: "mxc://beeper.local/trip", FileName: "trip.png", MimeType: "image/png", Content: []byte("\x89PNG\r\n\x1a\n")},
+		},
+	}
+}
+
+// normalize fills derived fields so handlers can rely on them: message
+// account IDs, types, and sort keys, and chat last-activity times.
+func (ds *Dataset) normalize() {
+	chatAccounts := make(map[string]string, len(ds.Chats))
+	for _, chat := range ds.Chats {
+		chatAccounts[chat.ID] = chat.AccountID
+	}
+
+	// Explicit sort keys are kept only when every message has one;
+	// otherwise messages are ordered by timestamp and keys are assigned.
+	keyed := true
+	for _, msg := range ds.Messages {
+		if msg.SortKey == "" {
+			keyed = false
+			break
+		}
+	}
+	if keyed {
+		sort.SliceStable(ds.Messages, func(i, j int) bool {
+			return compareSortKeys(ds.Messages[i].SortKey, ds.Messages[j].SortKey) < 0
+		})
+	} else {
+		sort.SliceStable(ds.Messages, func(i, j int) bool {
+			return ds.Messages[i].Timestamp.Before(ds.Messages[j].Timestamp)
+		})
+		for i := range ds.Messages {
+			ds.Messages[i].SortKey = formatSortKey(int64(i + 1))
+		}
+	}
+
+	lastActivity := make(map[string]time.Time)
+	for i := range ds.Messages {
+		msg := &ds.Messages[i]
+		if msg.AccountID == "" {
+			msg.AccountID = chatAccounts[msg.ChatID]
+		}
+		if msg.Type == "" {
+			msg.Type = "TEXT"
+		}
+		if msg.Timestamp.After(lastActivity[msg.ChatID]) {
+			lastActivity[msg.ChatID] = msg.Timestamp
+		}
+	}
+
+	for i := range ds.Chats {
+		chat := &ds.Chats[i]
+		if chat.Type == "" {
+			chat.Type = "single"
+		}
+		if chat.LastActivity.IsZero() {
+			chat.LastActivity = lastActivity[chat.ID]
+		}
+	}
+}
+
+func formatSortKey(n int64) string {
+	return fmt.Sprintf("%012d", n)
+}
+
+// compareSortKeys orders numeric sort keys numerically and falls back to
+// string comparison otherwise.
+func compareSortKeys(a, b string) int {
+	ai, aErr := strconv.ParseInt(a, 10, 64)
+	bi, bErr := strconv.ParseInt(b, 10, 64)
+	if aErr == nil && bErr == nil {
+		switch {
+		case ai < bi:
+			return -1
+		case ai > bi:
+			return 1
+		default:
+			return 0
+		}
+	}
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
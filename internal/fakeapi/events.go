@@ -0,0 +1,168 @@
+package fakeapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// eventHub fans mutation events out to websocket subscribers.
+type eventHub struct {
+	mu     sync.Mutex
+	subs   map[*eventSub]struct{}
+	closed bool
+}
+
+// eventSub is one websocket connection. Writes are serialized by mu.
+type eventSub struct {
+	mu      sync.Mutex
+	conn    *websocket.Conn
+	chatIDs []string
+	seq     int64
+}
+
+func newEventHub() *eventHub {
+	return &eventHub{subs: make(map[*eventSub]struct{})}
+}
+
+func (h *eventHub) add(sub *eventSub) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return false
+	}
+	h.subs[sub] = struct{}{}
+	return true
+}
+
+func (h *eventHub) remove(sub *eventSub) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.subs, sub)
+}
+
+// publish sends an event to every subscriber of chatID (or of "*").
+// entry is encoded the way the Desktop API encodes entries: as a JSON object.
+func (h *eventHub) publish(chatID, eventType string, ids []string, entry any) {
+	var entries []map[string]any
+	if raw, err := json.Marshal(entry); err == nil {
+		var m map[string]any
+		if json.Unmarshal(raw, &m) == nil {
+			entries = []map[string]any{m}
+		}
+	}
+
+	h.mu.Lock()
+	subs := make([]*eventSub, 0, len(h.subs))
+	for sub := range h.subs {
+		subs = append(subs, sub)
+	}
+	h.mu.Unlock()
+
+	ts := time.Now().UnixMilli()
+	for _, sub := range subs {
+		sub.mu.Lock()
+		if sub.matches(chatID) {
+			sub.seq++
+			_ = sub.conn.WriteJSON(map[string]any{
+				"type":    eventType,
+				"seq":     sub.seq,
+				"ts":      ts,
+				"chatID":  chatID,
+				"ids":     ids,
+				"entries": entries,
+			})
+		}
+		sub.mu.Unlock()
+	}
+}
+
+// close disconnects all subscribers and rejects new ones.
+func (h *eventHub) close() {
+	h.mu.Lock()
+	h.closed = true
+	subs := h.subs
+	h.subs = make(map[*eventSub]struct{})
+	h.mu.Unlock()
+
+	for sub := range subs {
+		sub.mu.Lock()
+		_ = sub.conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+			time.Now().Add(time.Second))
+		_ = sub.conn.Close()
+		sub.mu.Unlock()
+	}
+}
+
+func (sub *eventSub) matches(chatID string) bool {
+	for _, id := range sub.chatIDs {
+		if id == "*" || id == chatID {
+			return true
+		}
+	}
+	return false
+}
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+func (s *Server) handleWebsocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	sub := &eventSub{conn: conn}
+	if !s.events.add(sub) {
+		_ = conn.Close()
+		return
+	}
+	defer func() {
+		s.events.remove(sub)
+		_ = conn.Close()
+	}()
+
+	sub.mu.Lock()
+	err = conn.WriteJSON(map[string]any{"type": "ready", "version": 1, "chatIDs": []string{}})
+	sub.mu.Unlock()
+	if err != nil {
+		return
+	}
+
+	for {
+		var msg struct {
+			Type      string   `json:"type"`
+			RequestID string   `json:"requestID"`
+			ChatIDs   []string `json:"chatIDs"`
+		}
+		if err := conn.ReadJSON(&msg); err != nil {
+			return
+		}
+
+		var reply map[string]any
+		switch msg.Type {
+		case "subscriptions.set":
+			chatIDs := msg.ChatIDs
+			if chatIDs == nil {
+				chatIDs = []string{}
+			}
+			reply = map[string]any{"type": "subscriptions.updated", "requestID": msg.RequestID, "chatIDs": chatIDs}
+			sub.mu.Lock()
+			sub.chatIDs = chatIDs
+			sub.mu.Unlock()
+		default:
+			reply = map[string]any{"type": "error", "requestID": msg.RequestID, "code": "INVALID_REQUEST", "message": "unknown message type"}
+		}
+
+		sub.mu.Lock()
+		err := conn.WriteJSON(reply)
+		sub.mu.Unlock()
+		if err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package fakeapi
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/johntheyoung/roadrunner/internal/beeperapi"
+)
+
+func newTestClient(t *testing.T, srv *Server) *beeperapi.Client {
+	t.Helper()
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+	t.Cleanup(srv.Close)
+
+	client, err := beeperapi.NewClient("test-token", ts.URL, 5*time.Second)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	return client
+}
+
+func TestServerServesDefaultDataset(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t, New(DefaultDataset()))
+	ctx := context.Background()
+
+	accounts, err := client.Accounts().List(ctx)
+	if err != nil {
+		t.Fatalf("Accounts().List() error = %v", err)
+	}
+	if len(accounts) != 2 || accounts[0].ID != "matrix" {
+		t.Fatalf("accounts = %#v", accounts)
+	}
+
+	chats, err := client.Chats().List(ctx, beeperapi.ChatListParams{})
+	if err != nil {
+		t.Fatalf("Chats().List() error = %v", err)
+	}
+	if len(chats.Items) != 3 || chats.Items[0].ID != "!alice:beeper.local" {
+		t.Fatalf("chats = %#v", chats.Items)
+	}
+	if chats.Items[0].Preview != "Photo from the trip" {
+		t.Fatalf("preview = %q", chats.Items[0].Preview)
+	}
+
+	found, err := client.Chats().Search(ctx, beeperapi.ChatSearchParams{Query: "team"})
+	if err != nil {
+		t.Fatalf("Chats().Search() error = %v", err)
+	}
+	if len(found.Items) != 1 || found.Items[0].Type != "group" {
+		t.Fatalf("search = %#v", found.Items)
+	}
+
+	msgs, err := client.Messages().Search(ctx, beeperapi.MessageSearchParams{Query: "standup"})
+	if err != nil {
+		t.Fatalf("Messages().Search() error = %v", err)
+	}
+	if len(msgs.Items) != 1 || msgs.Items[0].ID != "$m2" {
+		t.Fatalf("message search = %#v", msgs.Items)
+	}
+}
+
+func TestServerSendReactAndList(t *testing.T) {
+	t.Parallel()
+
+	srv := New(DefaultDataset())
+	client := newTestClient(t, srv)
+	ctx := context.Background()
+
+	sent, err := client.Messages().Send(ctx, "!team:beeper.local", beeperapi.SendParams{Text: "hello", ReplyToMessageID: "$m2"})
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if sent.PendingMessageID == "" {
+		t.Fatal("expected pending message ID")
+	}
+	if err := client.Messages().React(ctx, "!team:beeper.local", sent.PendingMessageID, "👍"); err != nil {
+		t.Fatalf("React() error = %v", err)
+	}
+
+	list, err := client.Messages().List(ctx, "!team:beeper.local", beeperapi.MessageListParams{})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(list.Items) != 3 || list.Items[0].ID != sent.PendingMessageID {
+		t.Fatalf("list = %#v", list.Items)
+	}
+	if got := list.Items[0]; got.Text != "hello" || !got.IsSender || len(got.Reactions) != 1 {
+		t.Fatalf("sent message = %#v", got)
+	}
+
+	older, err := client.Messages().List(ctx, "!team:beeper.local", beeperapi.MessageListParams{Cursor: list.Items[1].SortKey, Direction: "before"})
+	if err != nil {
+		t.Fatalf("List(before) error = %v", err)
+	}
+	if len(older.Items) != 1 || older.Items[0].ID != "$m2" {
+		t.Fatalf("older = %#v", older.Items)
+	}
+
+	snap := srv.Snapshot()
+	if last := snap.Messages[len(snap.Messages)-1]; last.LinkedMessageID != "$m2" {
+		t.Fatalf("snapshot last message = %#v", last)
+	}
+}
+
+func TestServerPublishesWebsocketEvents(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t, New(DefaultDataset()))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := client.Events().Connect(ctx)
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	if evt, err := conn.ReadEvent(ctx); err != nil || evt.Type != "ready" {
+		t.Fatalf("first event = %#v, err = %v", evt, err)
+	}
+	if err := conn.SetSubscriptions(ctx, "sub-1", []string{"!alice:beeper.local"}); err != nil {
+		t.Fatalf("SetSubscriptions() error = %v", err)
+	}
+	if evt, err := conn.ReadEvent(ctx); err != nil || evt.Type != "subscriptions.updated" || evt.RequestID != "sub-1" {
+		t.Fatalf("subscription ack = %#v, err = %v", evt, err)
+	}
+
+	if _, err := client.Messages().Send(ctx, "!team:beeper.local", beeperapi.SendParams{Text: "not subscribed"}); err != nil {
+		t.Fatalf("Send(team) error = %v", err)
+	}
+	if _, err := client.Messages().Send(ctx, "!alice:beeper.local", beeperapi.SendParams{Text: "hi alice"}); err != nil {
+		t.Fatalf("Send(alice) error = %v", err)
+	}
+
+	evt, err := conn.ReadEvent(ctx)
+	if err != nil {
+		t.Fatalf("ReadEvent() error = %v", err)
+	}
+	decoded := evt.Decode()
+	if evt.Type != beeperapi.EventTypeMessageUpserted || evt.Seq != 1 || evt.ChatID != "!alice:beeper.local" {
+		t.Fatalf("event = %#v", evt)
+	}
+	if len(decoded.Messages) != 1 || decoded.Messages[0].Text != "hi alice" {
+		t.Fatalf("decoded = %#v", decoded.Messages)
+	}
+}
+
+func TestServerAssetsRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t, New(DefaultDataset()))
+	ctx := context.Background()
+
+	path := filepath.Join(t.TempDir(), "note.txt")
+	if err := os.WriteFile(path, []byte("hello asset"), 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	uploaded, err := client.Assets().Upload(ctx, beeperapi.AssetUploadParams{FilePath: path})
+	if err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+	if uploaded.UploadID == "" || uploaded.FileSize != int64(len("hello asset")) {
+		t.Fatalf("upload = %#v", uploaded)
+	}
+
+	sent, err := client.Messages().Send(ctx, "!alice:beeper.local", beeperapi.SendParams{
+		Attachment: &beeperapi.SendAttachmentParams{UploadID: uploaded.UploadID},
+	})
+	if err != nil {
+		t.Fatalf("Send(attachment) error = %v", err)
+	}
+	list, err := client.Messages().List(ctx, "!alice:beeper.local", beeperapi.MessageListParams{})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if list.Items[0].ID != sent.PendingMessageID || len(list.Items[0].Attachments) != 1 {
+		t.Fatalf("attachment message = %#v", list.Items[0])
+	}
+
+	var buf bytes.Buffer
+	if _, err := client.Assets().Serve(ctx, "mxc://beeper.local/trip", &buf); err != nil {
+		t.Fatalf("Serve() error = %v", err)
+	}
+	if buf.String() != "\x89PNG\r\n\x1a\n" {
+		t.Fatalf("served = %q", buf.String())
+	}
+
+	src, err := client.Assets().Download(ctx, "mxc://beeper.local/trip")
+	if err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	if filepath.Ext(src) != ".png" {
+		t.Fatalf("download srcURL = %q", src)
+	}
+}
+
+func TestServerRequireToken(t *testing.T) {
+	t.Parallel()
+
+	srv := New(DefaultDataset())
+	srv.RequireToken("other-token")
+	client := newTestClient(t, srv)
+
+	if _, err := client.Accounts().List(context.Background()); err == nil {
+		t.Fatal("expected unauthorized error")
+	}
+}
+
+func TestNormalizeAssignsSortKeysByTimestamp(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	ds := Dataset{
+		Chats: []Chat{{ID: "c1", AccountID: "a"}},
+		Messages: []Message{
+			{ID: "late", ChatID: "c1", Timestamp: base.Add(time.Hour)},
+			{ID: "early", ChatID: "c1", Timestamp: base},
+		},
+	}
+	ds.normalize()
+
+	if ds.Messages[0].ID != "early" || ds.Messages[0].SortKey != formatSortKey(1) {
+		t.Fatalf("messages = %#v", ds.Messages)
+	}
+	if ds.Messages[1].AccountID != "a" || ds.Messages[1].Type != "TEXT" {
+		t.Fatalf("normalized message = %#v", ds.Messages[1])
+	}
+	if !ds.Chats[0].LastActivity.Equal(base.Add(time.Hour)) || ds.Chats[0].Type != "single" {
+		t.Fatalf("chat = %#v", ds.Chats[0])
+	}
+}
@@ -0,0 +1,427 @@
+package fakeapi
+
+import (
+	"net/http"
+	"strings"
+)
+
+// chatMessages returns messages in a chat, oldest first.
+func (s *Server) chatMessages(chatID string) []Message {
+	var out []Message
+	for _, msg := range s.data.Messages {
+		if msg.ChatID == chatID {
+			out = append(out, msg)
+		}
+	}
+	return out
+}
+
+func (s *Server) latestMessage(chatID string) (Message, bool) {
+	for i := len(s.data.Messages) - 1; i >= 0; i-- {
+		if s.data.Messages[i].ChatID == chatID {
+			return s.data.Messages[i], true
+		}
+	}
+	return Message{}, false
+}
+
+func (s *Server) findMessage(chatID, messageID string) (*Message, bool) {
+	for i := range s.data.Messages {
+		msg := &s.data.Messages[i]
+		if msg.ChatID == chatID && msg.ID == messageID {
+			return msg, true
+		}
+	}
+	return nil, false
+}
+
+// appendMessage records a message sent by the account owner and returns it.
+func (s *Server) appendMessage(chat *Chat, msg Message) Message {
+	account, _ := s.account(chat.AccountID)
+	now := s.now().UTC()
+
+	msg.ID = "$" + s.newID("msg")
+	msg.ChatID = chat.ID
+	msg.AccountID = chat.AccountID
+	msg.SenderID = account.User.ID
+	msg.SenderName = firstNonEmpty(account.User.FullName, account.User.ID)
+	msg.SortKey = s.nextSortKey()
+	msg.Timestamp = now
+	msg.IsSender = true
+	if msg.Type == "" {
+		msg.Type = "TEXT"
+	}
+
+	s.data.Messages = append(s.data.Messages, msg)
+	chat.LastActivity = now
+	chat.UnreadCount = 0
+	return msg
+}
+
+// handleMessagesList pages a chat's messages by sort key. "before" pages are
+// newest first; "after" pages are oldest first, matching the Desktop API.
+func (s *Server) handleMessagesList(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	chatID := r.PathValue("chatID")
+	if _, ok := s.findChat(chatID); !ok {
+		writeError(w, http.StatusNotFound, "chat not found")
+		return
+	}
+
+	const pageSize = 20
+	cursor := r.URL.Query().Get("cursor")
+	direction := r.URL.Query().Get("direction")
+	messages := s.chatMessages(chatID)
+
+	var items []Message
+	hasMore := false
+	if direction == "after" {
+		for _, msg := range messages {
+			if cursor != "" && compareSortKeys(msg.SortKey, cursor) <= 0 {
+				continue
+			}
+			if len(items) == pageSize {
+				hasMore = true
+				break
+			}
+			items = append(items, msg)
+		}
+	} else {
+		for i := len(messages) - 1; i >= 0; i-- {
+			msg := messages[i]
+			if cursor != "" && compareSortKeys(msg.SortKey, cursor) >= 0 {
+				continue
+			}
+			if len(items) == pageSize {
+				hasMore = true
+				break
+			}
+			items = append(items, msg)
+		}
+	}
+	if items == nil {
+		items = []Message{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"items": items, "hasMore": hasMore})
+}
+
+type messageSendBody struct {
+	Text             string `json:"text"`
+	ReplyToMessageID string `json:"replyToMessageID"`
+	Attachment       *struct {
+		UploadID string          `json:"uploadID"`
+		FileName string          `json:"fileName"`
+		MimeType string          `json:"mimeType"`
+		Type     string          `json:"type"`
+		Duration float64         `json:"duration"`
+		Size     *AttachmentSize `json:"size"`
+	} `json:"attachment"`
+}
+
+func (s *Server) handleMessageSend(w http.ResponseWriter, r *http.Request) {
+	var body messageSendBody
+	if err := decodeBody(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if strings.TrimSpace(body.Text) == "" && body.Attachment == nil {
+		writeError(w, http.StatusBadRequest, "text or attachment is required")
+		return
+	}
+
+	s.mu.Lock()
+	chat, ok := s.findChat(r.PathValue("chatID"))
+	if !ok {
+		s.mu.Unlock()
+		writeError(w, http.StatusNotFound, "chat not found")
+		return
+	}
+
+	msg := Message{Text: body.Text, LinkedMessageID: body.ReplyToMessageID}
+	if body.Attachment != nil {
+		asset, ok := s.findAsset(body.Attachment.UploadID)
+		if !ok {
+			s.mu.Unlock()
+			writeError(w, http.StatusBadRequest, "unknown uploadID")
+			return
+		}
+		att := Attachment{
+			Type:     attachmentType(firstNonEmpty(body.Attachment.MimeType, asset.MimeType)),
+			ID:       asset.URL,
+			FileName: firstNonEmpty(body.Attachment.FileName, asset.FileName),
+			MimeType: firstNonEmpty(body.Attachment.MimeType, asset.MimeType),
+			FileSize: float64(len(asset.Content)),
+			Duration: body.Attachment.Duration,
+			Size:     body.Attachment.Size,
+		}
+		if body.Attachment.Type == "voiceNote" {
+			att.IsVoiceNote = true
+		}
+		if body.Attachment.Type == "sticker" {
+			att.IsSticker = true
+		}
+		msg.Attachments = []Attachment{att}
+		msg.Type = messageTypeForAttachment(att)
+	}
+	sent := s.appendMessage(chat, msg)
+	s.mu.Unlock()
+
+	s.events.publish(sent.ChatID, "message.upserted", []string{sent.ID}, sent)
+	writeJSON(w, http.StatusOK, map[string]any{
+		"chatID":           sent.ChatID,
+		"pendingMessageID": sent.ID,
+	})
+}
+
+func (s *Server) handleMessageEdit(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Text string `json:"text"`
+	}
+	if err := decodeBody(r, &body); err != nil || strings.TrimSpace(body.Text) == "" {
+		writeError(w, http.StatusBadRequest, "text is required")
+		return
+	}
+
+	s.mu.Lock()
+	msg, ok := s.findMessage(r.PathValue("chatID"), r.PathValue("messageID"))
+	if !ok {
+		s.mu.Unlock()
+		writeError(w, http.StatusNotFound, "message not found")
+		return
+	}
+	if !msg.IsSender {
+		s.mu.Unlock()
+		writeError(w, http.StatusForbidden, "only your own messages can be edited")
+		return
+	}
+	msg.Text = body.Text
+	updated := *msg
+	s.mu.Unlock()
+
+	s.events.publish(updated.ChatID, "message.upserted", []string{updated.ID}, updated)
+	writeJSON(w, http.StatusOK, map[string]any{
+		"chatID":    updated.ChatID,
+		"messageID": updated.ID,
+		"success":   true,
+	})
+}
+
+func (s *Server) handleReactionAdd(w http.ResponseWriter, r *http.Request) {
+	s.updateReaction(w, r, true)
+}
+
+func (s *Server) handleReactionRemove(w http.ResponseWriter, r *http.Request) {
+	s.updateReaction(w, r, false)
+}
+
+func (s *Server) updateReaction(w http.ResponseWriter, r *http.Request, add bool) {
+	reactionKey := r.URL.Query().Get("reactionKey")
+	if reactionKey == "" {
+		var body struct {
+			ReactionKey string `json:"reactionKey"`
+		}
+		_ = decodeBody(r, &body)
+		reactionKey = body.ReactionKey
+	}
+	if reactionKey == "" {
+		writeError(w, http.StatusBadRequest, "reactionKey is required")
+		return
+	}
+
+	s.mu.Lock()
+	msg, ok := s.findMessage(r.PathValue("chatID"), r.PathValue("messageID"))
+	if !ok {
+		s.mu.Unlock()
+		writeError(w, http.StatusNotFound, "message not found")
+		return
+	}
+	account, _ := s.account(msg.AccountID)
+	reactionID := account.User.ID + reactionKey
+
+	kept := msg.Reactions[:0]
+	for _, reaction := range msg.Reactions {
+		if reaction.ID != reactionID {
+			kept = append(kept, reaction)
+		}
+	}
+	msg.Reactions = kept
+	if add {
+		msg.Reactions = append(msg.Reactions, Reaction{
+			ID:            reactionID,
+			ParticipantID: account.User.ID,
+			ReactionKey:   reactionKey,
+			Emoji:         !isShortcode(reactionKey),
+		})
+	}
+	updated := *msg
+	updated.Reactions = append([]Reaction{}, msg.Reactions...)
+	s.mu.Unlock()
+
+	s.events.publish(updated.ChatID, "message.upserted", []string{updated.ID}, updated)
+	writeJSON(w, http.StatusOK, map[string]any{"success": true})
+}
+
+func isShortcode(key string) bool {
+	for _, r := range key {
+		if r > 127 {
+			return false
+		}
+	}
+	return true
+}
+
+// searchMessages returns matching messages newest first.
+func (s *Server) searchMessages(r *http.Request) []Message {
+	query := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("query")))
+	accountIDs := queryList(r, "accountIDs")
+	chatIDs := queryList(r, "chatIDs")
+	chatType := r.URL.Query().Get("chatType")
+	sender := r.URL.Query().Get("sender")
+	mediaTypes := queryList(r, "mediaTypes")
+	dateAfter := queryTime(r, "dateAfter")
+	dateBefore := queryTime(r, "dateBefore")
+	includeMuted, includeMutedSet := queryBool(r, "includeMuted")
+
+	var out []Message
+	for i := len(s.data.Messages) - 1; i >= 0; i-- {
+		msg := s.data.Messages[i]
+		chat, ok := s.findChat(msg.ChatID)
+		if !ok {
+			continue
+		}
+		if query != "" && !strings.Contains(strings.ToLower(msg.Text), query) {
+			continue
+		}
+		if len(accountIDs) > 0 && !contains(accountIDs, msg.AccountID) {
+			continue
+		}
+		if len(chatIDs) > 0 && !contains(chatIDs, msg.ChatID) {
+			continue
+		}
+		if (chatType == "single" || chatType == "group") && chat.Type != chatType {
+			continue
+		}
+		switch sender {
+		case "":
+		case "me":
+			if !msg.IsSender {
+				continue
+			}
+		case "others":
+			if msg.IsSender {
+				continue
+			}
+		default:
+			if msg.SenderID != sender {
+				continue
+			}
+		}
+		if len(mediaTypes) > 0 && !contains(mediaTypes, "any") && !messageHasMediaType(msg, mediaTypes) {
+			continue
+		}
+		if len(mediaTypes) > 0 && contains(mediaTypes, "any") && len(msg.Attachments) == 0 {
+			continue
+		}
+		if dateAfter != nil && !msg.Timestamp.After(*dateAfter) {
+			continue
+		}
+		if dateBefore != nil && !msg.Timestamp.Before(*dateBefore) {
+			continue
+		}
+		if includeMutedSet && !includeMuted && chat.IsMuted {
+			continue
+		}
+		out = append(out, msg)
+	}
+	return out
+}
+
+func messageHasMediaType(msg Message, mediaTypes []string) bool {
+	for _, att := range msg.Attachments {
+		kind := "file"
+		switch att.Type {
+		case "img":
+			kind = "image"
+		case "video":
+			kind = "video"
+		}
+		if contains(mediaTypes, kind) {
+			return true
+		}
+	}
+	if contains(mediaTypes, "link") && (strings.Contains(msg.Text, "http://") || strings.Contains(msg.Text, "https://")) {
+		return true
+	}
+	return false
+}
+
+func (s *Server) messageSearchPage(r *http.Request, fallbackLimit int) map[string]any {
+	matches := s.searchMessages(r)
+	ids := make([]string, len(matches))
+	for i, msg := range matches {
+		ids[i] = msg.SortKey
+	}
+	direction := r.URL.Query().Get("direction")
+	start, end := pageBounds(ids, r.URL.Query().Get("cursor"), direction, queryLimit(r, fallbackLimit))
+	items := append([]Message{}, matches[start:end]...)
+
+	resp := map[string]any{
+		"items":        items,
+		"hasMore":      hasMoreInDirection(len(ids), start, end, direction),
+		"oldestCursor": "",
+		"newestCursor": "",
+	}
+	if len(items) > 0 {
+		resp["oldestCursor"] = items[len(items)-1].SortKey
+		resp["newestCursor"] = items[0].SortKey
+	}
+	return resp
+}
+
+func (s *Server) handleMessagesSearch(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, http.StatusOK, s.messageSearchPage(r, 20))
+}
+
+// handleSearch serves GET /v1/search: chats by title, groups by participant,
+// and the first page of matching messages.
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	query := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("query")))
+	chats := []chatJSON{}
+	inGroups := []chatJSON{}
+	for _, chat := range s.sortedChats() {
+		if query == "" {
+			continue
+		}
+		if strings.Contains(strings.ToLower(chat.Title), query) {
+			chats = append(chats, s.chatJSON(chat, false))
+			continue
+		}
+		if chat.Type == "group" && chatMatches(chat, query, "participants") {
+			inGroups = append(inGroups, s.chatJSON(chat, false))
+		}
+	}
+
+	messages := s.messageSearchPage(r, 20)
+	referenced := map[string]chatJSON{}
+	for _, msg := range messages["items"].([]Message) {
+		if chat, ok := s.findChat(msg.ChatID); ok {
+			referenced[chat.ID] = s.chatJSON(*chat, false)
+		}
+	}
+	messages["chats"] = referenced
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"results": map[string]any{
+			"chats":     chats,
+			"in_groups": inGroups,
+			"messages":  messages,
+		},
+	})
+}
@@ -0,0 +1,395 @@
+// Package fakeapi is an in-memory emulation of the Beeper Desktop API routes
+// used by rr. It is meant for integration tests and for running automations
+// end-to-end without a live Desktop.
+package fakeapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Server serves a Dataset over HTTP. All state lives in memory; mutating
+// routes (send, edit, react, archive, ...) update it and broadcast websocket
+// events to /v1/ws subscribers.
+type Server struct {
+	mu       sync.Mutex
+	data     Dataset
+	token    string
+	nextSeq  int64
+	nextID   int64
+	now      func() time.Time
+	assetDir string
+
+	mux    *http.ServeMux
+	events *eventHub
+}
+
+// New returns a Server seeded with a copy of data.
+func New(data Dataset) *Server {
+	data = cloneDataset(data)
+	data.normalize()
+
+	s := &Server{
+		data:   data,
+		now:    time.Now,
+		mux:    http.NewServeMux(),
+		events: newEventHub(),
+	}
+	s.nextSeq = int64(len(data.Messages))
+	for _, msg := range data.Messages {
+		if n, err := strconv.ParseInt(msg.SortKey, 10, 64); err == nil && n > s.nextSeq {
+			s.nextSeq = n
+		}
+	}
+	s.routes()
+	return s
+}
+
+// RequireToken makes every route reject requests without this bearer token.
+// By default any token (or none) is accepted.
+func (s *Server) RequireToken(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = token
+}
+
+// Snapshot returns a copy of the current state.
+func (s *Server) Snapshot() Dataset {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return cloneDataset(s.data)
+}
+
+// Close disconnects websocket subscribers and removes materialized asset files.
+func (s *Server) Close() {
+	s.events.close()
+	s.mu.Lock()
+	dir := s.assetDir
+	s.assetDir = ""
+	s.mu.Unlock()
+	if dir != "" {
+		_ = os.RemoveAll(dir)
+	}
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	token := s.token
+	s.mu.Unlock()
+	if token != "" && r.Header.Get("Authorization") != "Bearer "+token && r.URL.Path != "/oauth/introspect" {
+		writeError(w, http.StatusUnauthorized, "invalid or missing access token")
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) routes() {
+	s.mux.HandleFunc("GET /v1/info", s.handleInfo)
+	s.mux.HandleFunc("POST /oauth/introspect", s.handleIntrospect)
+	s.mux.HandleFunc("POST /v1/focus", s.handleFocus)
+	s.mux.HandleFunc("GET /v1/search", s.handleSearch)
+
+	s.mux.HandleFunc("GET /v1/accounts", s.handleAccounts)
+	s.mux.HandleFunc("GET /v1/accounts/{accountID}/contacts", s.handleContactsSearch)
+	s.mux.HandleFunc("GET /v1/accounts/{accountID}/contacts/list", s.handleContactsList)
+
+	s.mux.HandleFunc("GET /v1/chats", s.handleChatsList)
+	s.mux.HandleFunc("POST /v1/chats", s.handleChatsCreate)
+	s.mux.HandleFunc("GET /v1/chats/search", s.handleChatsSearch)
+	s.mux.HandleFunc("GET /v1/chats/{chatID}", s.handleChatGet)
+	s.mux.HandleFunc("POST /v1/chats/{chatID}/archive", s.handleChatArchive)
+	s.mux.HandleFunc("POST /v1/chats/{chatID}/reminders", s.handleReminderSet)
+	s.mux.HandleFunc("DELETE /v1/chats/{chatID}/reminders", s.handleReminderClear)
+
+	s.mux.HandleFunc("GET /v1/chats/{chatID}/messages", s.handleMessagesList)
+	s.mux.HandleFunc("POST /v1/chats/{chatID}/messages", s.handleMessageSend)
+	s.mux.HandleFunc("PUT /v1/chats/{chatID}/messages/{messageID}", s.handleMessageEdit)
+	s.mux.HandleFunc("POST /v1/chats/{chatID}/messages/{messageID}/reactions", s.handleReactionAdd)
+	s.mux.HandleFunc("DELETE /v1/chats/{chatID}/messages/{messageID}/reactions", s.handleReactionRemove)
+	s.mux.HandleFunc("GET /v1/messages/search", s.handleMessagesSearch)
+
+	s.mux.HandleFunc("POST /v1/assets/download", s.handleAssetDownload)
+	s.mux.HandleFunc("GET /v1/assets/serve", s.handleAssetServe)
+	s.mux.HandleFunc("POST /v1/assets/upload", s.handleAssetUpload)
+	s.mux.HandleFunc("POST /v1/assets/upload/base64", s.handleAssetUploadBase64)
+
+	s.mux.HandleFunc("GET /v1/ws", s.handleWebsocket)
+}
+
+func (s *Server) handleInfo(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"name":    "Beeper Desktop (fakeapi)",
+		"version": "fakeapi",
+		"runtime": "fakeapi",
+		"endpoints": map[string]string{
+			"api": "/v1",
+			"ws":  "/v1/ws",
+		},
+	})
+}
+
+func (s *Server) handleIntrospect(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid form body")
+		return
+	}
+	token := r.PostForm.Get("token")
+
+	s.mu.Lock()
+	required := s.token
+	s.mu.Unlock()
+
+	active := token != "" && (required == "" || token == required)
+	if !active {
+		writeJSON(w, http.StatusOK, map[string]any{"active": false})
+		return
+	}
+	now := s.now()
+	writeJSON(w, http.StatusOK, map[string]any{
+		"active":     true,
+		"scope":      "read write",
+		"client_id":  "rr-fakeapi",
+		"sub":        "fakeapi-user",
+		"token_type": "Bearer",
+		"iss":        "fakeapi",
+		"iat":        now.Unix(),
+		"exp":        now.Add(24 * time.Hour).Unix(),
+	})
+}
+
+func (s *Server) handleFocus(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		ChatID string `json:"chatID"`
+	}
+	_ = decodeBody(r, &body)
+	if body.ChatID != "" {
+		s.mu.Lock()
+		_, ok := s.findChat(body.ChatID)
+		s.mu.Unlock()
+		if !ok {
+			writeError(w, http.StatusNotFound, "chat not found")
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"success": true})
+}
+
+func (s *Server) handleAccounts(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	accounts := s.data.Accounts
+	if accounts == nil {
+		accounts = []Account{}
+	}
+	writeJSON(w, http.StatusOK, accounts)
+}
+
+func (s *Server) handleContactsSearch(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	accountID := r.PathValue("accountID")
+	if !s.hasAccount(accountID) {
+		writeError(w, http.StatusNotFound, "account not found")
+		return
+	}
+	query := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("query")))
+	items := []User{}
+	for _, user := range s.data.Contacts[accountID] {
+		if query == "" || userMatches(user, query) {
+			items = append(items, user)
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"items": items})
+}
+
+func (s *Server) handleContactsList(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	accountID := r.PathValue("accountID")
+	if !s.hasAccount(accountID) {
+		writeError(w, http.StatusNotFound, "account not found")
+		return
+	}
+	contacts := s.data.Contacts[accountID]
+	ids := make([]string, len(contacts))
+	for i, user := range contacts {
+		ids[i] = user.ID
+	}
+	start, end := pageBounds(ids, r.URL.Query().Get("cursor"), r.URL.Query().Get("direction"), queryLimit(r, 50))
+	page := append([]User{}, contacts[start:end]...)
+	resp := map[string]any{
+		"items":   page,
+		"hasMore": hasMoreInDirection(len(ids), start, end, r.URL.Query().Get("direction")),
+	}
+	if len(page) > 0 {
+		resp["oldestCursor"] = page[len(page)-1].ID
+		resp["newestCursor"] = page[0].ID
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) hasAccount(accountID string) bool {
+	for _, account := range s.data.Accounts {
+		if account.AccountID == accountID {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) account(accountID string) (Account, bool) {
+	for _, account := range s.data.Accounts {
+		if account.AccountID == accountID {
+			return account, true
+		}
+	}
+	return Account{}, false
+}
+
+func (s *Server) newID(prefix string) string {
+	s.nextID++
+	return fmt.Sprintf("%s%d", prefix, s.nextID)
+}
+
+func (s *Server) nextSortKey() string {
+	s.nextSeq++
+	return formatSortKey(s.nextSeq)
+}
+
+func userMatches(user User, query string) bool {
+	for _, field := range []string{user.ID, user.FullName, user.Username, user.Email, user.PhoneNumber} {
+		if field != "" && strings.Contains(strings.ToLower(field), query) {
+			return true
+		}
+	}
+	return false
+}
+
+// pageBounds returns the slice bounds for a page of ids ordered newest
+// first. "before" pages move toward older items (after the cursor in the
+// slice), "after" pages toward newer ones.
+func pageBounds(ids []string, cursor, direction string, limit int) (int, int) {
+	if cursor == "" {
+		return 0, min(limit, len(ids))
+	}
+	pos := -1
+	for i, id := range ids {
+		if id == cursor {
+			pos = i
+			break
+		}
+	}
+	if pos < 0 {
+		return 0, 0
+	}
+	if direction == "after" {
+		start := max(0, pos-limit)
+		return start, pos
+	}
+	start := pos + 1
+	return start, min(start+limit, len(ids))
+}
+
+func hasMoreInDirection(total, start, end int, direction string) bool {
+	if direction == "after" {
+		return start > 0
+	}
+	return end < total
+}
+
+func queryLimit(r *http.Request, fallback int) int {
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil && n > 0 {
+			return n
+		}
+	}
+	return fallback
+}
+
+func queryBool(r *http.Request, key string) (bool, bool) {
+	raw := r.URL.Query().Get(key)
+	if raw == "" {
+		return false, false
+	}
+	v, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, false
+	}
+	return v, true
+}
+
+func queryTime(r *http.Request, key string) *time.Time {
+	raw := r.URL.Query().Get(key)
+	if raw == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+func queryList(r *http.Request, key string) []string {
+	var values []string
+	for _, raw := range r.URL.Query()[key] {
+		for _, v := range strings.Split(raw, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func decodeBody(r *http.Request, dst any) error {
+	if r.Body == nil {
+		return nil
+	}
+	return json.NewDecoder(r.Body).Decode(dst)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]any{
+		"code":    strings.ReplaceAll(strings.ToUpper(http.StatusText(status)), " ", "_"),
+		"message": message,
+	})
+}
+
+func cloneDataset(data Dataset) Dataset {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return Dataset{}
+	}
+	var out Dataset
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return Dataset{}
+	}
+	return out
+}
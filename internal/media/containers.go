@@ -0,0 +1,265 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+)
+
+// mp4Movie is what readMP4 found in an MP4/QuickTime file.
+type mp4Movie struct {
+	duration      float64
+	width, height int
+	hasVideo      bool
+}
+
+// mp4Containers are the boxes readMP4 descends into on the way to the
+// movie header, track headers, and handlers.
+var mp4Containers = map[string]bool{"moov": true, "trak": true, "mdia": true}
+
+// readMP4 reads the duration from the movie header (mvhd) and the display
+// size of the first video track (tkhd of a trak whose hdlr is "vide").
+func readMP4(r io.ReaderAt, size int64) mp4Movie {
+	var movie mp4Movie
+	var track struct {
+		width, height int
+		handler       string
+	}
+	var walk func(off, end int64, depth int)
+	walk = func(off, end int64, depth int) {
+		for off+8 <= end && depth < 8 {
+			var hdr [16]byte
+			if _, err := r.ReadAt(hdr[:8], off); err != nil {
+				return
+			}
+			boxSize := int64(binary.BigEndian.Uint32(hdr[:4]))
+			typ := string(hdr[4:8])
+			headerLen := int64(8)
+			switch boxSize {
+			case 0:
+				boxSize = end - off
+			case 1:
+				if _, err := r.ReadAt(hdr[8:16], off+8); err != nil {
+					return
+				}
+				boxSize = int64(binary.BigEndian.Uint64(hdr[8:16]))
+				headerLen = 16
+			}
+			if boxSize < headerLen || off+boxSize > end {
+				return
+			}
+			body := io.NewSectionReader(r, off+headerLen, boxSize-headerLen)
+
+			switch {
+			case typ == "trak":
+				track.width, track.height, track.handler = 0, 0, ""
+				walk(off+headerLen, off+boxSize, depth+1)
+				if track.handler == "vide" && !movie.hasVideo {
+					movie.hasVideo = true
+					movie.width, movie.height = track.width, track.height
+				}
+			case mp4Containers[typ]:
+				walk(off+headerLen, off+boxSize, depth+1)
+			case typ == "mvhd":
+				movie.duration = mvhdDuration(body)
+			case typ == "tkhd":
+				track.width, track.height = tkhdSize(body)
+			case typ == "hdlr":
+				var buf [12]byte
+				if _, err := body.ReadAt(buf[:], 0); err == nil {
+					track.handler = string(buf[8:12])
+				}
+			}
+			off += boxSize
+		}
+	}
+	walk(0, size, 0)
+	return movie
+}
+
+// mvhdDuration returns the movie duration in seconds.
+func mvhdDuration(body *io.SectionReader) float64 {
+	var buf [32]byte
+	if _, err := body.ReadAt(buf[:], 0); err != nil {
+		return 0
+	}
+	var timescale uint32
+	var duration uint64
+	if buf[0] == 1 {
+		timescale = binary.BigEndian.Uint32(buf[20:24])
+		duration = binary.BigEndian.Uint64(buf[24:32])
+	} else {
+		timescale = binary.BigEndian.Uint32(buf[12:16])
+		duration = uint64(binary.BigEndian.Uint32(buf[16:20]))
+	}
+	if timescale == 0 || duration == math.MaxUint32 || duration == math.MaxUint64 {
+		return 0
+	}
+	return float64(duration) / float64(timescale)
+}
+
+// tkhdSize returns a track's display size, stored as 16.16 fixed point at
+// the end of the track header.
+func tkhdSize(body *io.SectionReader) (width, height int) {
+	var version [1]byte
+	if _, err := body.ReadAt(version[:], 0); err != nil {
+		return 0, 0
+	}
+	off := int64(76)
+	if version[0] == 1 {
+		off = 88
+	}
+	var buf [8]byte
+	if _, err := body.ReadAt(buf[:], off); err != nil {
+		return 0, 0
+	}
+	return int(binary.BigEndian.Uint32(buf[:4]) >> 16), int(binary.BigEndian.Uint32(buf[4:]) >> 16)
+}
+
+// oggStream is what readOgg found in an Ogg file.
+type oggStream struct {
+	duration float64
+	opus     bool
+}
+
+// oggTailLen is how much of the end of an Ogg file readOgg searches for
+// the last page; Ogg pages are at most about 64 KiB.
+const oggTailLen = 80 << 10
+
+// readOgg computes the duration from the granule position of the last
+// page: samples for Opus (always 48 kHz, minus the encoder pre-skip) and
+// Vorbis (at the rate in its identification header).
+func readOgg(r io.ReaderAt, size int64, head []byte) oggStream {
+	var stream oggStream
+	var rate float64
+	var preSkip int64
+	if i := bytes.Index(head, []byte("OpusHead")); i >= 0 && len(head) >= i+12 {
+		stream.opus = true
+		rate = 48000
+		preSkip = int64(binary.LittleEndian.Uint16(head[i+10:]))
+	} else if i := bytes.Index(head, []byte("\x01vorbis")); i >= 0 && len(head) >= i+16 {
+		rate = float64(binary.LittleEndian.Uint32(head[i+12:]))
+	}
+	if rate == 0 {
+		return stream
+	}
+
+	tailLen := min(size, oggTailLen)
+	tail := make([]byte, tailLen)
+	if _, err := r.ReadAt(tail, size-tailLen); err != nil && err != io.EOF {
+		return stream
+	}
+	for end := len(tail); ; {
+		i := bytes.LastIndex(tail[:end], []byte("OggS"))
+		if i < 0 || len(tail) < i+14 {
+			return stream
+		}
+		// Pages that end no packet carry a granule position of -1.
+		if granule := int64(binary.LittleEndian.Uint64(tail[i+6:])); granule > 0 {
+			stream.duration = math.Max(float64(granule-preSkip), 0) / rate
+			return stream
+		}
+		end = i
+	}
+}
+
+// EBML element IDs read by webmDuration.
+const (
+	ebmlSegmentID   = 0x18538067
+	ebmlInfoID      = 0x1549a966
+	ebmlTimescaleID = 0x2ad7b1
+	ebmlDurationID  = 0x4489
+	ebmlClusterID   = 0x1f43b675
+)
+
+// webmDuration reads Segment/Info/Duration from a WebM or Matroska header,
+// scaled by the TimecodeScale (default 1 ms).
+func webmDuration(head []byte) float64 {
+	timescale := 1e6
+	duration := 0.0
+	pos := 0
+	end := len(head)
+scan:
+	for pos < end {
+		id, n := ebmlVint(head[pos:], true)
+		if n == 0 {
+			break
+		}
+		size, m := ebmlVint(head[pos+n:], false)
+		if m == 0 {
+			break
+		}
+		dataStart := pos + n + m
+		if dataStart > end {
+			break
+		}
+		dataEnd := end
+		if size >= 0 && dataStart+int(size) <= end {
+			dataEnd = dataStart + int(size)
+		}
+		switch id {
+		case ebmlSegmentID, ebmlInfoID:
+			// Descend: continue with the first child.
+			pos = dataStart
+			continue
+		case ebmlTimescaleID:
+			timescale = float64(ebmlUint(head[dataStart:dataEnd]))
+		case ebmlDurationID:
+			data := head[dataStart:dataEnd]
+			switch len(data) {
+			case 4:
+				duration = float64(math.Float32frombits(binary.BigEndian.Uint32(data)))
+			case 8:
+				duration = math.Float64frombits(binary.BigEndian.Uint64(data))
+			}
+		case ebmlClusterID:
+			// Media data; Info always comes before it.
+			break scan
+		}
+		if size < 0 {
+			break
+		}
+		pos = dataEnd
+	}
+	return duration * timescale / 1e9
+}
+
+// ebmlVint decodes a variable-length integer. Element IDs keep their
+// length marker bit; sizes drop it, and an all-ones size (unknown) is -1.
+// n is 0 when b is too short or malformed.
+func ebmlVint(b []byte, keepMarker bool) (value int64, n int) {
+	if len(b) == 0 || b[0] == 0 {
+		return 0, 0
+	}
+	n = 1
+	for mask := byte(0x80); b[0]&mask == 0; mask >>= 1 {
+		n++
+	}
+	if len(b) < n {
+		return 0, 0
+	}
+	first := int64(b[0])
+	if !keepMarker {
+		first &= int64(0xff >> n)
+	}
+	value = first
+	allOnes := first == int64(0xff>>n)
+	for _, c := range b[1:n] {
+		value = value<<8 | int64(c)
+		allOnes = allOnes && c == 0xff
+	}
+	if !keepMarker && allOnes {
+		return -1, n
+	}
+	return value, n
+}
+
+// ebmlUint decodes a big-endian unsigned integer element.
+func ebmlUint(b []byte) uint64 {
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+	return v
+}
@@ -0,0 +1,164 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+)
+
+// ErrNotJPEG is returned by StripJPEGMetadata for content that is not a
+// JPEG.
+var ErrNotJPEG = errors.New("not a JPEG")
+
+// JPEG markers handled by StripJPEGMetadata.
+const (
+	jpegSOI  = 0xd8
+	jpegSOS  = 0xda
+	jpegAPP0 = 0xe0
+	jpegAPP1 = 0xe1
+	jpegAPP2 = 0xe2
+	jpegAPPE = 0xee // Adobe: color transform, needed to decode CMYK/YCCK
+	jpegAPPF = 0xef
+	jpegCOM  = 0xfe
+)
+
+// exifHeader starts an APP1 segment that holds EXIF data.
+var exifHeader = []byte("Exif\x00\x00")
+
+// StripJPEGMetadata returns data without its EXIF (camera, GPS location,
+// timestamps, thumbnail), XMP, IPTC, and comment segments. The JFIF
+// header, ICC color profile, and Adobe segment are kept so the image
+// decodes and renders the same, and a non-default EXIF orientation is
+// kept in a minimal EXIF segment so the image is not shown rotated.
+// Image data is copied unchanged.
+func StripJPEGMetadata(data []byte) ([]byte, error) {
+	if len(data) < 4 || data[0] != 0xff || data[1] != jpegSOI {
+		return nil, ErrNotJPEG
+	}
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:2]...)
+	pos := 2
+	for pos < len(data) {
+		if data[pos] != 0xff {
+			return nil, errors.New("malformed JPEG: expected marker")
+		}
+		// Markers may be preceded by any number of 0xff fill bytes.
+		for pos+1 < len(data) && data[pos+1] == 0xff {
+			pos++
+		}
+		if pos+1 >= len(data) {
+			return nil, errors.New("malformed JPEG: truncated marker")
+		}
+		marker := data[pos+1]
+		if marker == jpegSOS {
+			// Entropy-coded data follows; everything after is image data.
+			return append(out, data[pos:]...), nil
+		}
+		if marker == 0x01 || (marker >= 0xd0 && marker <= 0xd7) {
+			out = append(out, data[pos:pos+2]...)
+			pos += 2
+			continue
+		}
+		if pos+4 > len(data) {
+			return nil, errors.New("malformed JPEG: truncated segment")
+		}
+		end := pos + 2 + int(binary.BigEndian.Uint16(data[pos+2:]))
+		if end > len(data) || end < pos+4 {
+			return nil, errors.New("malformed JPEG: segment overruns file")
+		}
+		segment := data[pos:end]
+		payload := segment[4:]
+		pos = end
+
+		switch {
+		case marker == jpegAPP1:
+			if bytes.HasPrefix(payload, exifHeader) {
+				if orientation := exifOrientation(payload[len(exifHeader):]); orientation > 1 {
+					out = append(out, orientationSegment(orientation)...)
+				}
+			}
+		case marker == jpegAPP2:
+			if bytes.HasPrefix(payload, []byte("ICC_PROFILE\x00")) {
+				out = append(out, segment...)
+			}
+		case marker == jpegAPP0, marker == jpegAPPE:
+			out = append(out, segment...)
+		case marker > jpegAPP2 && marker <= jpegAPPF, marker == jpegCOM:
+			// IPTC (APP13), vendor data, and comments.
+		default:
+			out = append(out, segment...)
+		}
+	}
+	return nil, errors.New("malformed JPEG: no image data")
+}
+
+// jpegOrientation returns the EXIF orientation (1-8) found in the
+// segments at the start of a JPEG, or 0.
+func jpegOrientation(head []byte) int {
+	pos := 2
+	for pos+4 <= len(head) && head[pos] == 0xff {
+		marker := head[pos+1]
+		if marker == jpegSOS {
+			return 0
+		}
+		end := pos + 2 + int(binary.BigEndian.Uint16(head[pos+2:]))
+		if end > len(head) || end < pos+4 {
+			return 0
+		}
+		if payload := head[pos+4 : end]; marker == jpegAPP1 && bytes.HasPrefix(payload, exifHeader) {
+			return exifOrientation(payload[len(exifHeader):])
+		}
+		pos = end
+	}
+	return 0
+}
+
+// exifOrientation reads the Orientation tag (0x0112) from the first IFD of
+// a TIFF-structured EXIF block, or returns 0.
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 0
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 0
+	}
+	count := int(order.Uint16(tiff[ifd:]))
+	for i := range count {
+		entry := ifd + 2 + 12*i
+		if entry+12 > len(tiff) {
+			return 0
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			if v := int(order.Uint16(tiff[entry+8:])); v >= 1 && v <= 8 {
+				return v
+			}
+			return 0
+		}
+	}
+	return 0
+}
+
+// orientationSegment is an APP1 EXIF segment holding only an orientation.
+func orientationSegment(orientation int) []byte {
+	tiff := []byte{
+		'M', 'M', 0x00, 0x2a, 0x00, 0x00, 0x00, 0x08, // big-endian, IFD0 at 8
+		0x00, 0x01, // one entry
+		0x01, 0x12, 0x00, 0x03, 0x00, 0x00, 0x00, 0x01, // Orientation, SHORT, count 1
+		0x00, byte(orientation), 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, // no next IFD
+	}
+	length := 2 + len(exifHeader) + len(tiff)
+	segment := []byte{0xff, jpegAPP1, byte(length >> 8), byte(length)}
+	segment = append(segment, exifHeader...)
+	return append(segment, tiff...)
+}
@@ -0,0 +1,171 @@
+// Package media inspects files before upload: it sniffs the MIME type from
+// magic bytes, reads image and video dimensions and audio/video durations
+// from file headers, and strips identifying metadata from JPEGs. Probing
+// never decodes pixel or sample data, so it is cheap on large files.
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	_ "image/gif"  // register GIF for image.DecodeConfig
+	_ "image/jpeg" // register JPEG for image.DecodeConfig
+	_ "image/png"  // register PNG for image.DecodeConfig
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// sniffLen is how much of a file MIME sniffing and header parsing read.
+const sniffLen = 64 << 10
+
+// Info is what Probe learned about a file. Zero fields are unknown.
+type Info struct {
+	MimeType string  `json:"mime_type,omitempty"`
+	Width    int     `json:"width,omitempty"`
+	Height   int     `json:"height,omitempty"`
+	Duration float64 `json:"duration,omitempty"` // seconds
+	// VoiceNote is set for Opus audio in an Ogg container, the format
+	// chat apps record voice messages in.
+	VoiceNote bool `json:"voice_note,omitempty"`
+}
+
+// Probe inspects the file at path. Unrecognized or malformed files are not
+// an error: Info holds whatever could be read. Errors are I/O errors.
+func Probe(path string) (Info, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Info{}, fmt.Errorf("open %s: %w", path, err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	stat, err := f.Stat()
+	if err != nil {
+		return Info{}, fmt.Errorf("stat %s: %w", path, err)
+	}
+	return ProbeReader(f, stat.Size())
+}
+
+// ProbeReader is Probe for size bytes of r.
+func ProbeReader(r io.ReaderAt, size int64) (Info, error) {
+	head := make([]byte, min(size, sniffLen))
+	if _, err := r.ReadAt(head, 0); err != nil && err != io.EOF {
+		return Info{}, fmt.Errorf("read header: %w", err)
+	}
+
+	info := Info{MimeType: DetectMimeType(head)}
+	switch info.MimeType {
+	case "image/jpeg", "image/png", "image/gif":
+		if cfg, _, err := image.DecodeConfig(io.NewSectionReader(r, 0, size)); err == nil {
+			info.Width, info.Height = cfg.Width, cfg.Height
+		}
+		// Viewers rotate JPEGs by their EXIF orientation; 5-8 swap axes.
+		if info.MimeType == "image/jpeg" && jpegOrientation(head) >= 5 {
+			info.Width, info.Height = info.Height, info.Width
+		}
+	case "image/webp":
+		info.Width, info.Height = webpSize(head)
+	case "video/mp4", "audio/mp4", "video/quicktime":
+		movie := readMP4(r, size)
+		info.Duration = movie.duration
+		if movie.hasVideo {
+			info.Width, info.Height = movie.width, movie.height
+		}
+	case "audio/ogg", "video/ogg":
+		stream := readOgg(r, size, head)
+		info.Duration = stream.duration
+		info.VoiceNote = stream.opus
+	case "video/webm", "video/x-matroska":
+		info.Duration = webmDuration(head)
+	}
+	return info, nil
+}
+
+// DetectMimeType returns the MIME type of content from its leading bytes,
+// covering the media formats chat networks accept and falling back to
+// http.DetectContentType. MP4 and Ogg are refined to audio or video by
+// their brand and codec headers.
+func DetectMimeType(head []byte) string {
+	switch {
+	case bytes.HasPrefix(head, []byte("\xff\xd8\xff")):
+		return "image/jpeg"
+	case bytes.HasPrefix(head, []byte("\x89PNG\r\n\x1a\n")):
+		return "image/png"
+	case bytes.HasPrefix(head, []byte("GIF87a")), bytes.HasPrefix(head, []byte("GIF89a")):
+		return "image/gif"
+	case len(head) >= 12 && string(head[:4]) == "RIFF":
+		switch string(head[8:12]) {
+		case "WEBP":
+			return "image/webp"
+		case "WAVE":
+			return "audio/wav"
+		case "AVI ":
+			return "video/x-msvideo"
+		}
+	case len(head) >= 12 && string(head[4:8]) == "ftyp":
+		switch string(head[8:12]) {
+		case "M4A ", "M4B ", "M4P ", "F4A ":
+			return "audio/mp4"
+		case "heic", "heix", "heim", "heis", "mif1", "msf1":
+			return "image/heic"
+		case "avif", "avis":
+			return "image/avif"
+		case "qt  ":
+			return "video/quicktime"
+		}
+		return "video/mp4"
+	case bytes.HasPrefix(head, []byte("OggS")):
+		switch {
+		case bytes.Contains(head, []byte("OpusHead")), bytes.Contains(head, []byte("\x01vorbis")), bytes.Contains(head, []byte("\x7fFLAC")):
+			return "audio/ogg"
+		case bytes.Contains(head, []byte("\x80theora")):
+			return "video/ogg"
+		}
+		return "application/ogg"
+	case bytes.HasPrefix(head, []byte("\x1a\x45\xdf\xa3")):
+		if bytes.Contains(head[:min(len(head), 64)], []byte("webm")) {
+			return "video/webm"
+		}
+		return "video/x-matroska"
+	case bytes.HasPrefix(head, []byte("fLaC")):
+		return "audio/flac"
+	case bytes.HasPrefix(head, []byte("ID3")), len(head) >= 2 && head[0] == 0xff && head[1]&0xe6 == 0xe2:
+		return "audio/mpeg"
+	case bytes.HasPrefix(head, []byte("#!AMR")):
+		return "audio/amr"
+	}
+	mimeType := http.DetectContentType(head)
+	if i := strings.IndexByte(mimeType, ';'); i >= 0 {
+		mimeType = mimeType[:i]
+	}
+	return mimeType
+}
+
+// webpSize reads the canvas size from a WebP's first chunk.
+func webpSize(head []byte) (width, height int) {
+	if len(head) < 30 {
+		return 0, 0
+	}
+	data := head[20:]
+	switch string(head[12:16]) {
+	case "VP8X":
+		width = 1 + (int(data[4]) | int(data[5])<<8 | int(data[6])<<16)
+		height = 1 + (int(data[7]) | int(data[8])<<8 | int(data[9])<<16)
+		return width, height
+	case "VP8 ":
+		if !bytes.Equal(data[3:6], []byte{0x9d, 0x01, 0x2a}) {
+			return 0, 0
+		}
+		return int(binary.LittleEndian.Uint16(data[6:]) & 0x3fff), int(binary.LittleEndian.Uint16(data[8:]) & 0x3fff)
+	case "VP8L":
+		if data[0] != 0x2f {
+			return 0, 0
+		}
+		bits := binary.LittleEndian.Uint32(data[1:])
+		return 1 + int(bits&0x3fff), 1 + int(bits>>14&0x3fff)
+	}
+	return 0, 0
+}
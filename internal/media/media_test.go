@@ -0,0 +1,203 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func box(typ string, payload ...[]byte) []byte {
+	body := bytes.Join(payload, nil)
+	out := binary.BigEndian.AppendUint32(nil, uint32(8+len(body)))
+	return append(append(out, typ...), body...)
+}
+
+func testMP4(brand, handler string, width, height int) []byte {
+	mvhd := make([]byte, 100)
+	binary.BigEndian.PutUint32(mvhd[12:], 1000) // timescale
+	binary.BigEndian.PutUint32(mvhd[16:], 2500) // duration
+	tkhd := make([]byte, 84)
+	binary.BigEndian.PutUint32(tkhd[76:], uint32(width)<<16)
+	binary.BigEndian.PutUint32(tkhd[80:], uint32(height)<<16)
+	hdlr := append(make([]byte, 8), handler...)
+	hdlr = append(hdlr, make([]byte, 13)...)
+	return append(
+		box("ftyp", []byte(brand), make([]byte, 4)),
+		box("moov", box("mvhd", mvhd), box("trak", box("tkhd", tkhd), box("mdia", box("hdlr", hdlr))))...,
+	)
+}
+
+func oggPage(granule int64, packet []byte) []byte {
+	page := []byte("OggS\x00\x00")
+	page = binary.LittleEndian.AppendUint64(page, uint64(granule))
+	page = append(page, make([]byte, 12)...) // serial, sequence, CRC
+	page = append(page, 1, byte(len(packet)))
+	return append(page, packet...)
+}
+
+func testOpus(seconds int) []byte {
+	head := []byte("OpusHead\x01\x01")
+	head = binary.LittleEndian.AppendUint16(head, 312) // pre-skip
+	head = binary.LittleEndian.AppendUint32(head, 48000)
+	head = append(head, 0, 0, 0)
+	out := oggPage(0, head)
+	out = append(out, oggPage(0, []byte("OpusTags"))...)
+	out = append(out, oggPage(int64(seconds*48000+312), make([]byte, 40))...)
+	// A trailing page that ends no packet has no granule position.
+	return append(out, oggPage(-1, make([]byte, 10))...)
+}
+
+func testWebM(durationMs float64) []byte {
+	el := func(id []byte, payload []byte) []byte {
+		return append(append(append([]byte{}, id...), 0x80|byte(len(payload))), payload...)
+	}
+	dur := binary.BigEndian.AppendUint64(nil, math.Float64bits(durationMs))
+	info := append(el([]byte{0x2a, 0xd7, 0xb1}, []byte{0x0f, 0x42, 0x40}), el([]byte{0x44, 0x89}, dur)...)
+	out := el([]byte{0x1a, 0x45, 0xdf, 0xa3}, el([]byte{0x42, 0x82}, []byte("webm")))
+	out = append(out, 0x18, 0x53, 0x80, 0x67, 0x01, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff) // Segment, unknown size
+	out = append(out, el([]byte{0x16, 0x54, 0xae, 0x6b}, []byte{0xec, 0x81, 0x00})...)        // Tracks
+	out = append(out, el([]byte{0x15, 0x49, 0xa9, 0x66}, info)...)
+	return append(out, 0x1f, 0x43, 0xb6, 0x75, 0x01, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff) // Cluster
+}
+
+func testJPEG(t *testing.T, width, height, orientation int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, width, height)), nil); err != nil {
+		t.Fatal(err)
+	}
+	// EXIF with orientation and a GPS IFD, an XMP packet, and a comment.
+	tiff := []byte("II*\x00\x08\x00\x00\x00\x02\x00")
+	tiff = append(tiff, 0x12, 0x01, 0x03, 0x00, 0x01, 0x00, 0x00, 0x00, byte(orientation), 0x00, 0x00, 0x00)
+	tiff = append(tiff, 0x25, 0x88, 0x04, 0x00, 0x01, 0x00, 0x00, 0x00, 0x26, 0x00, 0x00, 0x00)
+	tiff = append(tiff, 0x00, 0x00, 0x00, 0x00)
+	tiff = append(tiff, "GPSLatitude 37.7749 N"...)
+	segment := func(marker byte, payload []byte) []byte {
+		return append([]byte{0xff, marker, byte((len(payload) + 2) >> 8), byte(len(payload) + 2)}, payload...)
+	}
+	data := buf.Bytes()
+	out := append([]byte{}, data[:2]...)
+	out = append(out, segment(jpegAPP1, append([]byte("Exif\x00\x00"), tiff...))...)
+	out = append(out, segment(jpegAPP1, []byte("http://ns.adobe.com/xap/1.0/\x00<x:xmpmeta>GPS home</x:xmpmeta>"))...)
+	out = append(out, segment(jpegCOM, []byte("shot at home"))...)
+	return append(out, data[2:]...)
+}
+
+func TestDetectMimeType(t *testing.T) {
+	var pngBuf bytes.Buffer
+	if err := png.Encode(&pngBuf, image.NewGray(image.Rect(0, 0, 1, 1))); err != nil {
+		t.Fatal(err)
+	}
+	cases := map[string][]byte{
+		"image/png":       pngBuf.Bytes(),
+		"image/jpeg":      testJPEG(t, 1, 1, 1),
+		"image/gif":       []byte("GIF89a\x01\x00\x01\x00"),
+		"image/webp":      []byte("RIFF\x00\x00\x00\x00WEBPVP8L"),
+		"audio/wav":       []byte("RIFF\x00\x00\x00\x00WAVEfmt "),
+		"video/mp4":       testMP4("isom", "vide", 2, 2),
+		"audio/mp4":       testMP4("M4A ", "soun", 0, 0),
+		"video/quicktime": testMP4("qt  ", "vide", 2, 2),
+		"image/heic":      box("ftyp", []byte("heic"), make([]byte, 4)),
+		"audio/ogg":       testOpus(1),
+		"video/webm":      testWebM(1),
+		"audio/mpeg":      []byte("ID3\x04\x00\x00\x00\x00\x00\x00"),
+		"audio/flac":      []byte("fLaC\x00\x00\x00\x22"),
+		"application/pdf": []byte("%PDF-1.7\n"),
+		"text/plain":      []byte("hello"),
+	}
+	for want, data := range cases {
+		if got := DetectMimeType(data); got != want {
+			t.Errorf("DetectMimeType(%q...) = %q, want %q", data[:min(len(data), 12)], got, want)
+		}
+	}
+}
+
+func TestProbe(t *testing.T) {
+	dir := t.TempDir()
+	var pngBuf bytes.Buffer
+	if err := png.Encode(&pngBuf, image.NewRGBA(image.Rect(0, 0, 3, 2))); err != nil {
+		t.Fatal(err)
+	}
+	vp8l := []byte("RIFF\x00\x00\x00\x00WEBPVP8L\x00\x00\x00\x00\x2f")
+	vp8l = binary.LittleEndian.AppendUint32(vp8l, uint32(99)|uint32(49)<<14) // 100x50
+	vp8l = append(vp8l, make([]byte, 8)...)
+
+	cases := []struct {
+		name string
+		data []byte
+		want Info
+	}{
+		{"image.bin", pngBuf.Bytes(), Info{MimeType: "image/png", Width: 3, Height: 2}},
+		{"upright.jpg", testJPEG(t, 4, 2, 1), Info{MimeType: "image/jpeg", Width: 4, Height: 2}},
+		{"rotated.jpg", testJPEG(t, 4, 2, 6), Info{MimeType: "image/jpeg", Width: 2, Height: 4}},
+		{"image.webp", vp8l, Info{MimeType: "image/webp", Width: 100, Height: 50}},
+		{"clip.mp4", testMP4("isom", "vide", 640, 360), Info{MimeType: "video/mp4", Width: 640, Height: 360, Duration: 2.5}},
+		{"song.m4a", testMP4("M4A ", "soun", 0, 0), Info{MimeType: "audio/mp4", Duration: 2.5}},
+		{"voice.ogg", testOpus(3), Info{MimeType: "audio/ogg", Duration: 3, VoiceNote: true}},
+		{"clip.webm", testWebM(1500), Info{MimeType: "video/webm", Duration: 1.5}},
+		{"notes.txt", []byte("plain text"), Info{MimeType: "text/plain"}},
+		{"empty", nil, Info{MimeType: "text/plain"}},
+	}
+	for _, tc := range cases {
+		path := filepath.Join(dir, tc.name)
+		if err := os.WriteFile(path, tc.data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		got, err := Probe(path)
+		if err != nil {
+			t.Fatalf("Probe(%s) error = %v", tc.name, err)
+		}
+		if got != tc.want {
+			t.Errorf("Probe(%s) = %+v, want %+v", tc.name, got, tc.want)
+		}
+	}
+
+	if _, err := Probe(filepath.Join(dir, "missing")); err == nil {
+		t.Error("Probe(missing) succeeded")
+	}
+}
+
+func TestStripJPEGMetadata(t *testing.T) {
+	original := testJPEG(t, 4, 2, 6)
+	stripped, err := StripJPEGMetadata(original)
+	if err != nil {
+		t.Fatalf("StripJPEGMetadata() error = %v", err)
+	}
+	for _, leak := range []string{"GPS", "xmpmeta", "shot at home"} {
+		if bytes.Contains(stripped, []byte(leak)) {
+			t.Errorf("stripped JPEG still contains %q", leak)
+		}
+	}
+	if got := jpegOrientation(stripped); got != 6 {
+		t.Errorf("stripped orientation = %d, want 6", got)
+	}
+	img, err := jpeg.Decode(bytes.NewReader(stripped))
+	if err != nil {
+		t.Fatalf("decode stripped JPEG: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 4 || b.Dy() != 2 {
+		t.Errorf("stripped bounds = %v", b)
+	}
+
+	// Upright images lose the EXIF segment entirely.
+	upright, err := StripJPEGMetadata(testJPEG(t, 4, 2, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(upright, exifHeader) {
+		t.Error("upright JPEG kept an EXIF segment")
+	}
+
+	if _, err := StripJPEGMetadata([]byte("\x89PNG\r\n\x1a\n")); err != ErrNotJPEG {
+		t.Errorf("StripJPEGMetadata(png) error = %v, want ErrNotJPEG", err)
+	}
+	if _, err := StripJPEGMetadata(original[:40]); err == nil {
+		t.Error("StripJPEGMetadata(truncated) succeeded")
+	}
+}
@@ -19,6 +19,7 @@ type EnvelopeError struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
 	Hint    string `json:"hint,omitempty"`
+	Details any    `json:"details,omitempty"`
 }
 
 // EnvelopeMeta contains metadata about the response.
@@ -41,6 +42,8 @@ type EnvelopePagination struct {
 	AutoPaged    bool   `json:"auto_paged"`
 	Capped       bool   `json:"capped"`
 	MaxItems     int    `json:"max_items,omitempty"`
+	Pages        int    `json:"pages,omitempty"`
+	MaxPages     int    `json:"max_pages,omitempty"`
 }
 
 // Error codes
@@ -50,6 +53,7 @@ const (
 	ErrCodeValidation = "VALIDATION_ERROR"
 	ErrCodeConnection = "CONNECTION_ERROR"
 	ErrCodeInternal   = "INTERNAL_ERROR"
+	ErrCodeAmbiguous  = "AMBIGUOUS_MATCH"
 )
 
 type envelopeCtxKey struct{}
@@ -81,7 +85,12 @@ func WriteEnvelopeWithPagination(w io.Writer, data any, version, command string,
 
 // WriteEnvelopeWithMetadata writes a success envelope with optional pagination and request metadata.
 func WriteEnvelopeWithMetadata(w io.Writer, data any, version, command string, pagination *EnvelopePagination, requestID string) error {
-	env := Envelope{
+	return WriteJSON(w, NewEnvelope(data, version, command, pagination, requestID))
+}
+
+// NewEnvelope builds a success envelope with optional pagination and request metadata.
+func NewEnvelope(data any, version, command string, pagination *EnvelopePagination, requestID string) Envelope {
+	return Envelope{
 		Success: true,
 		Data:    data,
 		Metadata: &EnvelopeMeta{
@@ -92,7 +101,6 @@ func WriteEnvelopeWithMetadata(w io.Writer, data any, version, command string, p
 			RequestID:  requestID,
 		},
 	}
-	return WriteJSON(w, env)
 }
 
 // WriteEnvelopeError writes an error envelope to w.
@@ -107,12 +115,19 @@ func WriteEnvelopeErrorWithHint(w io.Writer, code, message, hint, version, comma
 
 // WriteEnvelopeErrorWithMetadata writes an error envelope with optional hint and request metadata.
 func WriteEnvelopeErrorWithMetadata(w io.Writer, code, message, hint, version, command, requestID string) error {
+	return WriteEnvelopeErrorWithDetails(w, code, message, hint, version, command, requestID, nil)
+}
+
+// WriteEnvelopeErrorWithDetails writes an error envelope with structured
+// error details, such as the candidates of an ambiguous match.
+func WriteEnvelopeErrorWithDetails(w io.Writer, code, message, hint, version, command, requestID string, details any) error {
 	env := Envelope{
 		Success: false,
 		Error: &EnvelopeError{
 			Code:    code,
 			Message: message,
 			Hint:    hint,
+			Details: details,
 		},
 		Metadata: &EnvelopeMeta{
 			Timestamp: time.Now().UTC().Format(time.RFC3339),
@@ -9,9 +9,24 @@ import (
 
 // Mode represents the output mode.
 type Mode struct {
-	JSON  bool
-	JSONL bool
-	Plain bool
+	JSON     bool
+	JSONL    bool
+	Stream   bool
+	Plain    bool
+	CSV      bool
+	YAML     bool
+	Markdown bool
+}
+
+// Flags holds the raw output-mode flag values.
+type Flags struct {
+	JSON     bool
+	JSONL    bool
+	Stream   bool
+	Plain    bool
+	CSV      bool
+	YAML     bool
+	Markdown bool
 }
 
 // ParseError is returned for invalid flag combinations.
@@ -24,21 +39,23 @@ func (e *ParseError) Error() string { return e.Msg }
 // FromFlags creates a Mode from flag values.
 // Returns error if more than one output mode is set.
 func FromFlags(jsonOut bool, jsonlOut bool, plainOut bool) (Mode, error) {
+	return FromFlagSet(Flags{JSON: jsonOut, JSONL: jsonlOut, Plain: plainOut})
+}
+
+// FromFlagSet creates a Mode from all output-mode flags.
+// Returns error if more than one output mode is set.
+func FromFlagSet(f Flags) (Mode, error) {
 	count := 0
-	if jsonOut {
-		count++
-	}
-	if jsonlOut {
-		count++
-	}
-	if plainOut {
-		count++
+	for _, set := range []bool{f.JSON, f.JSONL, f.Stream, f.Plain, f.CSV, f.YAML, f.Markdown} {
+		if set {
+			count++
+		}
 	}
 	if count > 1 {
-		return Mode{}, &ParseError{Msg: "cannot combine output modes; use only one of --json, --jsonl, or --plain"}
+		return Mode{}, &ParseError{Msg: "cannot combine output modes; use only one of --json, --jsonl, --stream, --plain, --csv, --yaml, or --markdown"}
 	}
 
-	return Mode{JSON: jsonOut, JSONL: jsonlOut, Plain: plainOut}, nil
+	return Mode{JSON: f.JSON, JSONL: f.JSONL, Stream: f.Stream, Plain: f.Plain, CSV: f.CSV, YAML: f.YAML, Markdown: f.Markdown}, nil
 }
 
 type ctxKey struct{}
@@ -63,23 +80,41 @@ func FromContext(ctx context.Context) Mode {
 // IsJSON returns true if JSON output mode is enabled.
 func IsJSON(ctx context.Context) bool {
 	m := FromContext(ctx)
-	return m.JSON || m.JSONL
+	return m.JSON || m.JSONL || m.Stream
 }
 
-// IsJSONL returns true if JSONL output mode is enabled.
+// IsJSONL returns true if JSONL output mode is enabled. --stream is JSONL
+// with a trailing pagination summary line.
 func IsJSONL(ctx context.Context) bool {
-	return FromContext(ctx).JSONL
+	m := FromContext(ctx)
+	return m.JSONL || m.Stream
+}
+
+// IsStream returns true if --stream output mode is enabled.
+func IsStream(ctx context.Context) bool {
+	return FromContext(ctx).Stream
 }
 
-// IsPlain returns true if plain output mode is enabled.
+// IsPlain returns true if TSV output (--plain) is enabled.
 func IsPlain(ctx context.Context) bool {
 	return FromContext(ctx).Plain
 }
 
+// IsTabular returns true if a tabular output mode is enabled: --plain (TSV),
+// --csv, --yaml, or --markdown. Rows for all of them go through RowWriter.
+func IsTabular(ctx context.Context) bool {
+	return FromContext(ctx).IsTabular()
+}
+
+// IsTabular reports whether m renders --fields rows.
+func (m Mode) IsTabular() bool {
+	return m.Plain || m.CSV || m.YAML || m.Markdown
+}
+
 // IsHuman returns true if human-readable output (default) is enabled.
 func IsHuman(ctx context.Context) bool {
 	m := FromContext(ctx)
-	return !m.JSON && !m.JSONL && !m.Plain
+	return !m.JSON && !m.JSONL && !m.Stream && !m.IsTabular()
 }
 
 // WithRequestID attaches an optional request ID to context for output metadata.
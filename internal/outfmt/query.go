@@ -0,0 +1,1310 @@
+package outfmt
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Query is a compiled jq-style filter. It supports the subset of jq that
+// covers everyday CLI scripting:
+//
+//	.  .foo  .foo.bar  ."key"  .[0]  .[-1]  .[2:5]  .[]  .foo?
+//	a | b    a, b    [ ... ]    {id, name: .title}    (a)
+//	== != < <= > >=   and  or  not   a // b
+//	select(f) map(f) sort_by(f) length keys has(k) contains(x)
+//	startswith(s) endswith(s) test(re) join(s) first last sort unique
+//	reverse add type tostring tonumber ascii_downcase ascii_upcase empty
+//
+// Queries run against JSON-shaped values (the output of encoding/json
+// decoding into any), so field names are the JSON names.
+type Query struct {
+	src string
+	run filter
+}
+
+// filter maps one input value to a stream of outputs.
+type filter func(v any) ([]any, error)
+
+// ParseQuery compiles a query expression.
+func ParseQuery(src string) (*Query, error) {
+	p := &queryParser{src: src}
+	if err := p.lex(); err != nil {
+		return nil, fmt.Errorf("invalid --query: %w", err)
+	}
+	f, err := p.parsePipe()
+	if err != nil {
+		return nil, fmt.Errorf("invalid --query: %w", err)
+	}
+	if !p.done() {
+		return nil, fmt.Errorf("invalid --query: unexpected %q", p.peek().text)
+	}
+	return &Query{src: src, run: f}, nil
+}
+
+// String returns the query source.
+func (q *Query) String() string {
+	return q.src
+}
+
+// Run evaluates the query against a JSON-shaped value.
+func (q *Query) Run(v any) ([]any, error) {
+	out, err := q.run(v)
+	if err != nil {
+		return nil, fmt.Errorf("query %s: %w", q.src, err)
+	}
+	return out, nil
+}
+
+// ToJSONValue converts v to the JSON-shaped form queries operate on.
+// Numbers are kept as json.Number so large IDs survive unchanged.
+func ToJSONValue(v any) (any, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("encode json: %w", err)
+	}
+	dec := json.NewDecoder(strings.NewReader(string(raw)))
+	dec.UseNumber()
+	var out any
+	if err := dec.Decode(&out); err != nil {
+		return nil, fmt.Errorf("decode json: %w", err)
+	}
+	return out, nil
+}
+
+// --- lexer ---
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokDot
+	tokIdent
+	tokString
+	tokNumber
+	tokPunct
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+type queryParser struct {
+	src    string
+	tokens []token
+	i      int
+}
+
+func (p *queryParser) lex() error {
+	s := p.src
+	for i := 0; i < len(s); {
+		c := rune(s[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '.':
+			if i+1 < len(s) && s[i+1] == '.' {
+				return fmt.Errorf("recursive descent (..) is not supported")
+			}
+			p.tokens = append(p.tokens, token{tokDot, ".", i})
+			i++
+		case c == '"':
+			j := i + 1
+			for j < len(s) && s[j] != '"' {
+				if s[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(s) {
+				return fmt.Errorf("unterminated string at %d", i)
+			}
+			text, err := strconv.Unquote(s[i : j+1])
+			if err != nil {
+				return fmt.Errorf("invalid string at %d: %w", i, err)
+			}
+			p.tokens = append(p.tokens, token{tokString, text, i})
+			i = j + 1
+		case c >= '0' && c <= '9':
+			j := i
+			for j < len(s) && (s[j] >= '0' && s[j] <= '9' || s[j] == '.' || s[j] == 'e' || s[j] == 'E') {
+				j++
+			}
+			p.tokens = append(p.tokens, token{tokNumber, s[i:j], i})
+			i = j
+		case c == '_' || unicode.IsLetter(c):
+			j := i
+			for j < len(s) && (s[j] == '_' || unicode.IsLetter(rune(s[j])) || unicode.IsDigit(rune(s[j]))) {
+				j++
+			}
+			p.tokens = append(p.tokens, token{tokIdent, s[i:j], i})
+			i = j
+		case strings.ContainsRune("[](){}:;,|?", c):
+			if c == '?' && i+1 < len(s) && s[i+1] == '/' {
+				return fmt.Errorf("unsupported operator at %d", i)
+			}
+			p.tokens = append(p.tokens, token{tokPunct, string(c), i})
+			i++
+		default:
+			for _, op := range []string{"==", "!=", "<=", ">=", "//", "<", ">", "-"} {
+				if strings.HasPrefix(s[i:], op) {
+					p.tokens = append(p.tokens, token{tokOp, op, i})
+					i += len(op)
+					goto next
+				}
+			}
+			return fmt.Errorf("unexpected character %q at %d", c, i)
+		next:
+		}
+	}
+	return nil
+}
+
+func (p *queryParser) peek() token {
+	if p.i < len(p.tokens) {
+		return p.tokens[p.i]
+	}
+	return token{kind: tokEOF, text: "end of query", pos: len(p.src)}
+}
+
+func (p *queryParser) next() token {
+	t := p.peek()
+	if p.i < len(p.tokens) {
+		p.i++
+	}
+	return t
+}
+
+func (p *queryParser) done() bool {
+	return p.i >= len(p.tokens)
+}
+
+func (p *queryParser) is(kind tokenKind, text string) bool {
+	t := p.peek()
+	return t.kind == kind && t.text == text
+}
+
+func (p *queryParser) accept(kind tokenKind, text string) bool {
+	if p.is(kind, text) {
+		p.i++
+		return true
+	}
+	return false
+}
+
+func (p *queryParser) expect(kind tokenKind, text string) error {
+	if !p.accept(kind, text) {
+		return fmt.Errorf("expected %q, got %q", text, p.peek().text)
+	}
+	return nil
+}
+
+// --- parser ---
+
+func (p *queryParser) parsePipe() (filter, error) {
+	left, err := p.parseComma()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept(tokPunct, "|") {
+		right, err := p.parseComma()
+		if err != nil {
+			return nil, err
+		}
+		left = pipe(left, right)
+	}
+	return left, nil
+}
+
+func pipe(left, right filter) filter {
+	return func(v any) ([]any, error) {
+		ins, err := left(v)
+		if err != nil {
+			return nil, err
+		}
+		var out []any
+		for _, in := range ins {
+			res, err := right(in)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, res...)
+		}
+		return out, nil
+	}
+}
+
+func (p *queryParser) parseComma() (filter, error) {
+	first, err := p.parseAlt()
+	if err != nil {
+		return nil, err
+	}
+	parts := []filter{first}
+	for p.accept(tokPunct, ",") {
+		f, err := p.parseAlt()
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, f)
+	}
+	if len(parts) == 1 {
+		return first, nil
+	}
+	return func(v any) ([]any, error) {
+		var out []any
+		for _, f := range parts {
+			res, err := f(v)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, res...)
+		}
+		return out, nil
+	}, nil
+}
+
+// parseAlt handles a // b: the truthy outputs of a, or else b.
+func (p *queryParser) parseAlt() (filter, error) {
+	left, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept(tokOp, "//") {
+		right, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(v any) ([]any, error) {
+			res, err := l(v)
+			var kept []any
+			if err == nil {
+				for _, x := range res {
+					if truthy(x) {
+						kept = append(kept, x)
+					}
+				}
+			}
+			if len(kept) > 0 {
+				return kept, nil
+			}
+			return right(v)
+		}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseOr() (filter, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept(tokIdent, "or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = logical(left, right, true)
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseAnd() (filter, error) {
+	left, err := p.parseCompare()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept(tokIdent, "and") {
+		right, err := p.parseCompare()
+		if err != nil {
+			return nil, err
+		}
+		left = logical(left, right, false)
+	}
+	return left, nil
+}
+
+func logical(left, right filter, isOr bool) filter {
+	return func(v any) ([]any, error) {
+		ls, err := left(v)
+		if err != nil {
+			return nil, err
+		}
+		var out []any
+		for _, l := range ls {
+			if truthy(l) == isOr {
+				out = append(out, isOr)
+				continue
+			}
+			rs, err := right(v)
+			if err != nil {
+				return nil, err
+			}
+			for _, r := range rs {
+				out = append(out, truthy(r))
+			}
+		}
+		return out, nil
+	}
+}
+
+func (p *queryParser) parseCompare() (filter, error) {
+	left, err := p.parsePostfix()
+	if err != nil {
+		return nil, err
+	}
+	t := p.peek()
+	if t.kind != tokOp || t.text == "//" || t.text == "-" {
+		return left, nil
+	}
+	p.next()
+	right, err := p.parsePostfix()
+	if err != nil {
+		return nil, err
+	}
+	op := t.text
+	return func(v any) ([]any, error) {
+		ls, err := left(v)
+		if err != nil {
+			return nil, err
+		}
+		rs, err := right(v)
+		if err != nil {
+			return nil, err
+		}
+		var out []any
+		for _, l := range ls {
+			for _, r := range rs {
+				c := compareValues(l, r)
+				var ok bool
+				switch op {
+				case "==":
+					ok = c == 0
+				case "!=":
+					ok = c != 0
+				case "<":
+					ok = c < 0
+				case "<=":
+					ok = c <= 0
+				case ">":
+					ok = c > 0
+				case ">=":
+					ok = c >= 0
+				}
+				out = append(out, ok)
+			}
+		}
+		return out, nil
+	}, nil
+}
+
+func (p *queryParser) parsePostfix() (filter, error) {
+	f, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.is(tokDot, "."):
+			p.next()
+			t := p.peek()
+			if t.kind != tokIdent && t.kind != tokString {
+				if p.is(tokPunct, "[") {
+					continue
+				}
+				return nil, fmt.Errorf("expected field name after '.' at %d", t.pos)
+			}
+			p.next()
+			f = pipe(f, fieldFilter(t.text))
+		case p.is(tokPunct, "["):
+			idx, err := p.parseBracket()
+			if err != nil {
+				return nil, err
+			}
+			f = pipe(f, idx)
+		case p.is(tokPunct, "?"):
+			p.next()
+			f = optional(f)
+		default:
+			return f, nil
+		}
+	}
+}
+
+func optional(f filter) filter {
+	return func(v any) ([]any, error) {
+		out, err := f(v)
+		if err != nil {
+			return nil, nil
+		}
+		return out, nil
+	}
+}
+
+func (p *queryParser) parsePrimary() (filter, error) {
+	t := p.peek()
+	switch {
+	case t.kind == tokDot:
+		p.next()
+		n := p.peek()
+		if n.kind == tokIdent || n.kind == tokString {
+			p.next()
+			return fieldFilter(n.text), nil
+		}
+		if p.is(tokPunct, "[") {
+			return p.parseBracket()
+		}
+		return identity, nil
+	case t.kind == tokString:
+		p.next()
+		return constant(t.text), nil
+	case t.kind == tokNumber || (t.kind == tokOp && t.text == "-"):
+		p.next()
+		text := t.text
+		if t.text == "-" {
+			n := p.next()
+			if n.kind != tokNumber {
+				return nil, fmt.Errorf("expected number after '-' at %d", t.pos)
+			}
+			text = "-" + n.text
+		}
+		if _, err := strconv.ParseFloat(text, 64); err != nil {
+			return nil, fmt.Errorf("invalid number %q", text)
+		}
+		return constant(json.Number(text)), nil
+	case t.kind == tokPunct && t.text == "(":
+		p.next()
+		f, err := p.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+		return f, p.expect(tokPunct, ")")
+	case t.kind == tokPunct && t.text == "[":
+		p.next()
+		if p.accept(tokPunct, "]") {
+			return constant([]any{}), nil
+		}
+		f, err := p.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokPunct, "]"); err != nil {
+			return nil, err
+		}
+		return func(v any) ([]any, error) {
+			items, err := f(v)
+			if err != nil {
+				return nil, err
+			}
+			if items == nil {
+				items = []any{}
+			}
+			return []any{items}, nil
+		}, nil
+	case t.kind == tokPunct && t.text == "{":
+		return p.parseObject()
+	case t.kind == tokIdent:
+		p.next()
+		return p.parseFunction(t)
+	default:
+		return nil, fmt.Errorf("unexpected %q at %d", t.text, t.pos)
+	}
+}
+
+// parseBracket parses [] / [expr] / [from:to] after the opening bracket.
+func (p *queryParser) parseBracket() (filter, error) {
+	if err := p.expect(tokPunct, "["); err != nil {
+		return nil, err
+	}
+	if p.accept(tokPunct, "]") {
+		return iterate, nil
+	}
+
+	var from, to filter
+	var err error
+	if !p.is(tokPunct, ":") {
+		if from, err = p.parsePipe(); err != nil {
+			return nil, err
+		}
+	}
+	if p.accept(tokPunct, ":") {
+		if !p.is(tokPunct, "]") {
+			if to, err = p.parsePipe(); err != nil {
+				return nil, err
+			}
+		}
+		if err := p.expect(tokPunct, "]"); err != nil {
+			return nil, err
+		}
+		return sliceFilter(from, to), nil
+	}
+	if err := p.expect(tokPunct, "]"); err != nil {
+		return nil, err
+	}
+	return func(v any) ([]any, error) {
+		keys, err := from(v)
+		if err != nil {
+			return nil, err
+		}
+		var out []any
+		for _, k := range keys {
+			res, err := indexValue(v, k)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, res)
+		}
+		return out, nil
+	}, nil
+}
+
+func (p *queryParser) parseObject() (filter, error) {
+	if err := p.expect(tokPunct, "{"); err != nil {
+		return nil, err
+	}
+	type field struct {
+		key   string
+		value filter
+	}
+	var fields []field
+	for !p.accept(tokPunct, "}") {
+		if len(fields) > 0 {
+			if err := p.expect(tokPunct, ","); err != nil {
+				return nil, err
+			}
+		}
+		t := p.next()
+		if t.kind != tokIdent && t.kind != tokString {
+			return nil, fmt.Errorf("expected object key at %d", t.pos)
+		}
+		value := fieldFilter(t.text)
+		if p.accept(tokPunct, ":") {
+			var err error
+			if value, err = p.parseAlt(); err != nil {
+				return nil, err
+			}
+		}
+		fields = append(fields, field{key: t.text, value: value})
+	}
+
+	return func(v any) ([]any, error) {
+		objects := []map[string]any{{}}
+		for _, fld := range fields {
+			values, err := fld.value(v)
+			if err != nil {
+				return nil, err
+			}
+			var next []map[string]any
+			for _, obj := range objects {
+				for _, val := range values {
+					cp := make(map[string]any, len(obj)+1)
+					for k, x := range obj {
+						cp[k] = x
+					}
+					cp[fld.key] = val
+					next = append(next, cp)
+				}
+			}
+			objects = next
+		}
+		out := make([]any, len(objects))
+		for i, obj := range objects {
+			out[i] = obj
+		}
+		return out, nil
+	}, nil
+}
+
+func (p *queryParser) parseFunction(name token) (filter, error) {
+	var args []filter
+	if p.accept(tokPunct, "(") {
+		for {
+			arg, err := p.parsePipe()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if p.accept(tokPunct, ")") {
+				break
+			}
+			if err := p.expect(tokPunct, ";"); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	arity := func(n int) error {
+		if len(args) != n {
+			return fmt.Errorf("%s expects %d argument(s)", name.text, n)
+		}
+		return nil
+	}
+
+	switch name.text {
+	case "true", "false":
+		return constant(name.text == "true"), arity(0)
+	case "null":
+		return constant(nil), arity(0)
+	case "empty":
+		return func(any) ([]any, error) { return nil, nil }, arity(0)
+	case "not":
+		return unary(func(v any) (any, error) { return !truthy(v), nil }), arity(0)
+	case "length":
+		return unary(length), arity(0)
+	case "keys":
+		return unary(keys), arity(0)
+	case "type":
+		return unary(func(v any) (any, error) { return typeName(v), nil }), arity(0)
+	case "tostring":
+		return unary(func(v any) (any, error) { return toString(v), nil }), arity(0)
+	case "tonumber":
+		return unary(toNumber), arity(0)
+	case "ascii_downcase", "ascii_upcase":
+		upper := name.text == "ascii_upcase"
+		return unary(func(v any) (any, error) {
+			s, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s cannot be applied to %s", name.text, typeName(v))
+			}
+			if upper {
+				return strings.ToUpper(s), nil
+			}
+			return strings.ToLower(s), nil
+		}), arity(0)
+	case "first", "last":
+		last := name.text == "last"
+		return unary(func(v any) (any, error) {
+			arr, ok := v.([]any)
+			if !ok {
+				return nil, fmt.Errorf("%s cannot be applied to %s", name.text, typeName(v))
+			}
+			if len(arr) == 0 {
+				return nil, nil
+			}
+			if last {
+				return arr[len(arr)-1], nil
+			}
+			return arr[0], nil
+		}), arity(0)
+	case "reverse":
+		return unary(func(v any) (any, error) {
+			arr, err := asArray(v, name.text)
+			if err != nil {
+				return nil, err
+			}
+			out := make([]any, len(arr))
+			for i, x := range arr {
+				out[len(arr)-1-i] = x
+			}
+			return out, nil
+		}), arity(0)
+	case "sort", "unique":
+		unique := name.text == "unique"
+		return unary(func(v any) (any, error) {
+			arr, err := asArray(v, name.text)
+			if err != nil {
+				return nil, err
+			}
+			out := append([]any{}, arr...)
+			sort.SliceStable(out, func(i, j int) bool { return compareValues(out[i], out[j]) < 0 })
+			if unique {
+				deduped := out[:0]
+				for i, x := range out {
+					if i == 0 || compareValues(x, deduped[len(deduped)-1]) != 0 {
+						deduped = append(deduped, x)
+					}
+				}
+				out = deduped
+			}
+			return out, nil
+		}), arity(0)
+	case "add":
+		return unary(add), arity(0)
+	case "select":
+		if err := arity(1); err != nil {
+			return nil, err
+		}
+		cond := args[0]
+		return func(v any) ([]any, error) {
+			res, err := cond(v)
+			if err != nil {
+				return nil, err
+			}
+			var out []any
+			for _, r := range res {
+				if truthy(r) {
+					out = append(out, v)
+				}
+			}
+			return out, nil
+		}, nil
+	case "map":
+		if err := arity(1); err != nil {
+			return nil, err
+		}
+		return func(v any) ([]any, error) {
+			items, err := iterate(v)
+			if err != nil {
+				return nil, err
+			}
+			out := []any{}
+			for _, item := range items {
+				res, err := args[0](item)
+				if err != nil {
+					return nil, err
+				}
+				out = append(out, res...)
+			}
+			return []any{out}, nil
+		}, nil
+	case "sort_by":
+		if err := arity(1); err != nil {
+			return nil, err
+		}
+		return unary(func(v any) (any, error) {
+			arr, err := asArray(v, name.text)
+			if err != nil {
+				return nil, err
+			}
+			type keyed struct {
+				key  []any
+				item any
+			}
+			items := make([]keyed, len(arr))
+			for i, x := range arr {
+				k, err := args[0](x)
+				if err != nil {
+					return nil, err
+				}
+				items[i] = keyed{key: k, item: x}
+			}
+			sort.SliceStable(items, func(i, j int) bool { return compareValues(items[i].key, items[j].key) < 0 })
+			out := make([]any, len(items))
+			for i, it := range items {
+				out[i] = it.item
+			}
+			return out, nil
+		}), nil
+	case "has", "contains", "startswith", "endswith", "test", "join":
+		if err := arity(1); err != nil {
+			return nil, err
+		}
+		fn := name.text
+		return func(v any) ([]any, error) {
+			params, err := args[0](v)
+			if err != nil {
+				return nil, err
+			}
+			var out []any
+			for _, param := range params {
+				res, err := stringFunc(fn, v, param)
+				if err != nil {
+					return nil, err
+				}
+				out = append(out, res)
+			}
+			return out, nil
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown function %q at %d", name.text, name.pos)
+	}
+}
+
+// --- evaluation helpers ---
+
+func identity(v any) ([]any, error) {
+	return []any{v}, nil
+}
+
+func constant(c any) filter {
+	return func(any) ([]any, error) { return []any{c}, nil }
+}
+
+func unary(fn func(any) (any, error)) filter {
+	return func(v any) ([]any, error) {
+		res, err := fn(v)
+		if err != nil {
+			return nil, err
+		}
+		return []any{res}, nil
+	}
+}
+
+func fieldFilter(name string) filter {
+	return func(v any) ([]any, error) {
+		res, err := indexValue(v, name)
+		if err != nil {
+			return nil, err
+		}
+		return []any{res}, nil
+	}
+}
+
+func iterate(v any) ([]any, error) {
+	switch x := v.(type) {
+	case []any:
+		return x, nil
+	case map[string]any:
+		keys := sortedKeys(x)
+		out := make([]any, len(keys))
+		for i, k := range keys {
+			out[i] = x[k]
+		}
+		return out, nil
+	case nil:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("cannot iterate over %s", typeName(v))
+	}
+}
+
+func indexValue(v, key any) (any, error) {
+	switch k := key.(type) {
+	case string:
+		switch x := v.(type) {
+		case map[string]any:
+			return x[k], nil
+		case nil:
+			return nil, nil
+		default:
+			return nil, fmt.Errorf("cannot index %s with %q", typeName(v), k)
+		}
+	case json.Number, float64:
+		n, _ := toFloat(k)
+		switch x := v.(type) {
+		case []any:
+			i := int(n)
+			if i < 0 {
+				i += len(x)
+			}
+			if i < 0 || i >= len(x) {
+				return nil, nil
+			}
+			return x[i], nil
+		case nil:
+			return nil, nil
+		default:
+			return nil, fmt.Errorf("cannot index %s with a number", typeName(v))
+		}
+	default:
+		return nil, fmt.Errorf("cannot index with %s", typeName(key))
+	}
+}
+
+func sliceFilter(from, to filter) filter {
+	bound := func(f filter, v any, fallback int) (int, error) {
+		if f == nil {
+			return fallback, nil
+		}
+		res, err := f(v)
+		if err != nil {
+			return 0, err
+		}
+		if len(res) != 1 {
+			return 0, fmt.Errorf("slice bounds must be single numbers")
+		}
+		n, ok := toFloat(res[0])
+		if !ok {
+			return 0, fmt.Errorf("slice bounds must be numbers")
+		}
+		return int(n), nil
+	}
+	return func(v any) ([]any, error) {
+		var n int
+		switch x := v.(type) {
+		case []any:
+			n = len(x)
+		case string:
+			n = len(x)
+		case nil:
+			return []any{nil}, nil
+		default:
+			return nil, fmt.Errorf("cannot slice %s", typeName(v))
+		}
+		start, err := bound(from, v, 0)
+		if err != nil {
+			return nil, err
+		}
+		end, err := bound(to, v, n)
+		if err != nil {
+			return nil, err
+		}
+		if start < 0 {
+			start += n
+		}
+		if end < 0 {
+			end += n
+		}
+		start = max(0, min(start, n))
+		end = max(start, min(end, n))
+		if s, ok := v.(string); ok {
+			return []any{s[start:end]}, nil
+		}
+		return []any{v.([]any)[start:end]}, nil
+	}
+}
+
+func stringFunc(fn string, v, param any) (any, error) {
+	switch fn {
+	case "has":
+		switch x := v.(type) {
+		case map[string]any:
+			k, ok := param.(string)
+			if !ok {
+				return nil, fmt.Errorf("has on an object needs a string key")
+			}
+			_, found := x[k]
+			return found, nil
+		case []any:
+			n, ok := toFloat(param)
+			if !ok {
+				return nil, fmt.Errorf("has on an array needs a number")
+			}
+			return n >= 0 && int(n) < len(x), nil
+		default:
+			return nil, fmt.Errorf("has cannot be applied to %s", typeName(v))
+		}
+	case "contains":
+		return containsValue(v, param), nil
+	case "join":
+		arr, err := asArray(v, fn)
+		if err != nil {
+			return nil, err
+		}
+		sep, ok := param.(string)
+		if !ok {
+			return nil, fmt.Errorf("join separator must be a string")
+		}
+		parts := make([]string, len(arr))
+		for i, x := range arr {
+			if x != nil {
+				parts[i] = toString(x)
+			}
+		}
+		return strings.Join(parts, sep), nil
+	}
+
+	s, ok := v.(string)
+	if !ok {
+		return nil, fmt.Errorf("%s cannot be applied to %s", fn, typeName(v))
+	}
+	arg, ok := param.(string)
+	if !ok {
+		return nil, fmt.Errorf("%s needs a string argument", fn)
+	}
+	switch fn {
+	case "startswith":
+		return strings.HasPrefix(s, arg), nil
+	case "endswith":
+		return strings.HasSuffix(s, arg), nil
+	default: // test
+		re, err := regexp.Compile(arg)
+		if err != nil {
+			return nil, fmt.Errorf("test: %w", err)
+		}
+		return re.MatchString(s), nil
+	}
+}
+
+func containsValue(v, want any) bool {
+	switch x := v.(type) {
+	case string:
+		w, ok := want.(string)
+		return ok && strings.Contains(x, w)
+	case []any:
+		ws, ok := want.([]any)
+		if !ok {
+			ws = []any{want}
+		}
+		for _, w := range ws {
+			found := false
+			for _, item := range x {
+				if containsValue(item, w) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+		return true
+	case map[string]any:
+		wm, ok := want.(map[string]any)
+		if !ok {
+			return false
+		}
+		for k, w := range wm {
+			item, ok := x[k]
+			if !ok || !containsValue(item, w) {
+				return false
+			}
+		}
+		return true
+	default:
+		return compareValues(v, want) == 0
+	}
+}
+
+func length(v any) (any, error) {
+	switch x := v.(type) {
+	case nil:
+		return json.Number("0"), nil
+	case string:
+		return json.Number(strconv.Itoa(len([]rune(x)))), nil
+	case []any:
+		return json.Number(strconv.Itoa(len(x))), nil
+	case map[string]any:
+		return json.Number(strconv.Itoa(len(x))), nil
+	case bool:
+		return nil, fmt.Errorf("boolean has no length")
+	default:
+		n, _ := toFloat(v)
+		return json.Number(formatNumber(math.Abs(n))), nil
+	}
+}
+
+func keys(v any) (any, error) {
+	switch x := v.(type) {
+	case map[string]any:
+		ks := sortedKeys(x)
+		out := make([]any, len(ks))
+		for i, k := range ks {
+			out[i] = k
+		}
+		return out, nil
+	case []any:
+		out := make([]any, len(x))
+		for i := range x {
+			out[i] = json.Number(strconv.Itoa(i))
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("%s has no keys", typeName(v))
+	}
+}
+
+func add(v any) (any, error) {
+	arr, err := asArray(v, "add")
+	if err != nil {
+		return nil, err
+	}
+	var acc any
+	for _, x := range arr {
+		switch a := acc.(type) {
+		case nil:
+			acc = x
+		case string:
+			s, ok := x.(string)
+			if !ok {
+				return nil, fmt.Errorf("cannot add %s to string", typeName(x))
+			}
+			acc = a + s
+		case []any:
+			b, ok := x.([]any)
+			if !ok {
+				return nil, fmt.Errorf("cannot add %s to array", typeName(x))
+			}
+			acc = append(append([]any{}, a...), b...)
+		default:
+			an, aok := toFloat(a)
+			bn, bok := toFloat(x)
+			if !aok || !bok {
+				return nil, fmt.Errorf("cannot add %s and %s", typeName(a), typeName(x))
+			}
+			acc = json.Number(formatNumber(an + bn))
+		}
+	}
+	return acc, nil
+}
+
+func toNumber(v any) (any, error) {
+	switch x := v.(type) {
+	case json.Number:
+		return x, nil
+	case float64:
+		return json.Number(formatNumber(x)), nil
+	case string:
+		if _, err := strconv.ParseFloat(x, 64); err != nil {
+			return nil, fmt.Errorf("cannot parse %q as a number", x)
+		}
+		return json.Number(x), nil
+	default:
+		return nil, fmt.Errorf("%s cannot be parsed as a number", typeName(v))
+	}
+}
+
+func asArray(v any, fn string) ([]any, error) {
+	arr, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%s cannot be applied to %s", fn, typeName(v))
+	}
+	return arr, nil
+}
+
+func truthy(v any) bool {
+	switch x := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return x
+	default:
+		return true
+	}
+}
+
+func typeName(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number, float64, int, int64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+func toFloat(v any) (float64, bool) {
+	switch x := v.(type) {
+	case json.Number:
+		f, err := x.Float64()
+		return f, err == nil
+	case float64:
+		return x, true
+	case int:
+		return float64(x), true
+	case int64:
+		return float64(x), true
+	default:
+		return 0, false
+	}
+}
+
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// toString renders scalars as their text and other values as JSON.
+func toString(v any) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case json.Number:
+		return x.String()
+	default:
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(raw)
+	}
+}
+
+// typeRank orders types like jq: null < false < true < numbers < strings
+// < arrays < objects.
+func typeRank(v any) int {
+	switch x := v.(type) {
+	case nil:
+		return 0
+	case bool:
+		if x {
+			return 2
+		}
+		return 1
+	case json.Number, float64, int, int64:
+		return 3
+	case string:
+		return 4
+	case []any:
+		return 5
+	default:
+		return 6
+	}
+}
+
+func compareValues(a, b any) int {
+	ra, rb := typeRank(a), typeRank(b)
+	if ra != rb {
+		return cmpInt(ra, rb)
+	}
+	switch x := a.(type) {
+	case string:
+		return strings.Compare(x, b.(string))
+	case []any:
+		y := b.([]any)
+		for i := 0; i < len(x) && i < len(y); i++ {
+			if c := compareValues(x[i], y[i]); c != 0 {
+				return c
+			}
+		}
+		return cmpInt(len(x), len(y))
+	case map[string]any:
+		y := b.(map[string]any)
+		ka, kb := sortedKeys(x), sortedKeys(y)
+		if c := compareValues(toAnySlice(ka), toAnySlice(kb)); c != 0 {
+			return c
+		}
+		for _, k := range ka {
+			if c := compareValues(x[k], y[k]); c != 0 {
+				return c
+			}
+		}
+		return 0
+	default:
+		if ra == 3 {
+			fa, _ := toFloat(a)
+			fb, _ := toFloat(b)
+			switch {
+			case fa < fb:
+				return -1
+			case fa > fb:
+				return 1
+			}
+		}
+		return 0
+	}
+}
+
+func cmpInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func sortedKeys(m map[string]any) []string {
+	ks := make([]string, 0, len(m))
+	for k := range m {
+		ks = append(ks, k)
+	}
+	sort.Strings(ks)
+	return ks
+}
+
+func toAnySlice(s []string) []any {
+	out := make([]any, len(s))
+	for i, x := range s {
+		out[i] = x
+	}
+	return out
+}
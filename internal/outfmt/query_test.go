@@ -0,0 +1,173 @@
+package outfmt
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const queryDoc = `{
+  "items": [
+    {"id": "!a", "title": "Alice", "unread_count": 2, "is_muted": false, "tags": ["x", "y"]},
+    {"id": "!b", "title": "Team", "unread_count": 0, "is_muted": true, "tags": []},
+    {"id": "!c", "title": "Carol", "unread_count": 5, "is_muted": false, "tags": ["y"]}
+  ],
+  "has_more": true,
+  "big": 1771234567890123456
+}`
+
+func runQuery(t *testing.T, expr string) string {
+	t.Helper()
+	q, err := ParseQuery(expr)
+	if err != nil {
+		t.Fatalf("ParseQuery(%q) error = %v", expr, err)
+	}
+	var doc any
+	dec := json.NewDecoder(strings.NewReader(queryDoc))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		t.Fatalf("decode doc: %v", err)
+	}
+	out, err := q.Run(doc)
+	if err != nil {
+		t.Fatalf("Run(%q) error = %v", expr, err)
+	}
+	parts := make([]string, len(out))
+	for i, v := range out {
+		raw, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+		parts[i] = string(raw)
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestQuery(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{".has_more", `true`},
+		{".items[0].id", `"!a"`},
+		{".items[-1].title", `"Carol"`},
+		{".items[].id", `"!a" "!b" "!c"`},
+		{".items | length", `3`},
+		{".items[1:].id?", ``},
+		{"[.items[1:][] | .id]", `["!b","!c"]`},
+		{".items[] | select(.unread_count > 0) | .id", `"!a" "!c"`},
+		{".items[] | select(.is_muted | not) | .title", `"Alice" "Carol"`},
+		{".items[] | select(.title | startswith(\"T\")) | .id", `"!b"`},
+		{".items[] | select(.tags | contains([\"y\"])) | .id", `"!a" "!c"`},
+		{".items[] | select(.unread_count >= 2 and .title != \"Carol\") | .id", `"!a"`},
+		{".items[] | {id, name: .title}", `{"id":"!a","name":"Alice"} {"id":"!b","name":"Team"} {"id":"!c","name":"Carol"}`},
+		{"[.items[].unread_count] | add", `7`},
+		{".items | map(.id) | join(\",\")", `"!a,!b,!c"`},
+		{".items | sort_by(.unread_count) | reverse | first | .id", `"!c"`},
+		{".missing // \"default\"", `"default"`},
+		{".big", `1771234567890123456`},
+		{"keys", `["big","has_more","items"]`},
+		{".items[0] | has(\"tags\"), has(\"nope\")", `true false`},
+		{".items[].title | test(\"^[AC]\")", `true false true`},
+		{".items[0].id, .has_more", `"!a" true`},
+	}
+
+	for _, tt := range tests {
+		if got := runQuery(t, tt.expr); got != tt.want {
+			t.Errorf("query %q = %s, want %s", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	for _, expr := range []string{".items[", "select(", ".foo bar", "nosuchfn", "..", `"open`} {
+		if _, err := ParseQuery(expr); err == nil {
+			t.Errorf("ParseQuery(%q) expected error", expr)
+		}
+	}
+}
+
+func TestQueryRuntimeError(t *testing.T) {
+	q, err := ParseQuery(".items.id")
+	if err != nil {
+		t.Fatalf("ParseQuery() error = %v", err)
+	}
+	if _, err := q.Run(map[string]any{"items": []any{}}); err == nil || !strings.Contains(err.Error(), "cannot index array") {
+		t.Fatalf("Run() error = %v", err)
+	}
+}
+
+func TestTransformWrite(t *testing.T) {
+	type item struct {
+		ID    string `json:"id"`
+		Title string `json:"title"`
+	}
+	data := struct {
+		Items []item `json:"items"`
+	}{Items: []item{{ID: "!a", Title: "Alice"}, {ID: "!b", Title: "Team"}}}
+
+	tests := []struct {
+		name     string
+		query    string
+		template string
+		compact  bool
+		want     string
+	}{
+		{name: "raw strings", query: ".items[].id", want: "!a\n!b\n"},
+		{name: "compact objects", query: ".items[0]", compact: true, want: "{\"id\":\"!a\",\"title\":\"Alice\"}\n"},
+		{name: "template uses json keys", template: `{{range .items}}{{.id}} {{.title}}{{"\n"}}{{end}}`, want: "!a Alice\n!b Team\n"},
+		{name: "template after query", query: ".items[]", template: `{{.id}}={{.title | upper}}{{nl}}`, want: "!a=ALICE\n!b=TEAM\n"},
+		{name: "template funcs", template: `{{json (index .items 0)}}`, want: `{"id":"!a","title":"Alice"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr, err := ParseTransform(tt.query, tt.template)
+			if err != nil {
+				t.Fatalf("ParseTransform() error = %v", err)
+			}
+			var buf bytes.Buffer
+			if err := tr.Write(&buf, data, tt.compact); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Fatalf("Write() = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestTransformTemplateOptionalField(t *testing.T) {
+	type item struct {
+		ID          string `json:"id"`
+		DisplayName string `json:"display_name,omitempty"`
+	}
+	data := struct {
+		Items []item `json:"items"`
+	}{Items: []item{{ID: "!a", DisplayName: "Alice"}, {ID: "!b"}}}
+
+	for _, tt := range []struct{ query, template, want string }{
+		{template: `{{range .items}}{{.id}}={{or .display_name "-"}}{{nl}}{{end}}`, want: "!a=Alice\n!b=-\n"},
+		{query: ".items[]", template: `{{with .display_name}}{{.}}{{nl}}{{end}}`, want: "Alice\n"},
+	} {
+		tr, err := ParseTransform(tt.query, tt.template)
+		if err != nil {
+			t.Fatalf("ParseTransform(%q, %q) error = %v", tt.query, tt.template, err)
+		}
+		var buf bytes.Buffer
+		if err := tr.Write(&buf, data, false); err != nil || buf.String() != tt.want {
+			t.Errorf("Write(%q, %q) = %q, %v; want %q", tt.query, tt.template, buf.String(), err, tt.want)
+		}
+	}
+}
+
+func TestParseTransformEmpty(t *testing.T) {
+	tr, err := ParseTransform("  ", "")
+	if err != nil || tr != nil {
+		t.Fatalf("ParseTransform(empty) = %v, %v; want nil, nil", tr, err)
+	}
+	if _, err := ParseTransform("", "{{.Broken"); err == nil {
+		t.Fatal("expected template parse error")
+	}
+}
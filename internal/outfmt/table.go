@@ -0,0 +1,197 @@
+package outfmt
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// RowWriter renders --fields rows in the active tabular mode: TSV for
+// --plain, RFC 4180 CSV for --csv, a YAML sequence of mappings for --yaml,
+// and a GitHub-flavored Markdown table for --markdown. CSV and Markdown
+// print a header row before the first row and whenever the field set
+// changes, so a RowWriter must be shared across all rows of a command.
+//
+// Values are strings, bools, or numbers. YAML writes bools and numbers as
+// plain scalars and quotes strings that would read as one; the other modes
+// print every value as text.
+type RowWriter struct {
+	w      io.Writer
+	mode   Mode
+	header []string
+}
+
+// NewRowWriter creates a RowWriter for mode writing to w.
+func NewRowWriter(w io.Writer, mode Mode) *RowWriter {
+	return &RowWriter{w: w, mode: mode}
+}
+
+// WriteRow writes a single row. values must be aligned with fields.
+func (r *RowWriter) WriteRow(fields []string, values []any) error {
+	if r.mode.YAML {
+		return r.writeYAML(fields, values)
+	}
+	cells := make([]string, len(values))
+	for i, v := range values {
+		cells[i] = cellText(v)
+	}
+	switch {
+	case r.mode.CSV:
+		return r.writeCSV(fields, cells)
+	case r.mode.Markdown:
+		return r.writeMarkdown(fields, cells)
+	default:
+		_, err := fmt.Fprintln(r.w, strings.Join(cells, "\t"))
+		return err
+	}
+}
+
+// cellText formats a row value as text; nil is empty.
+func cellText(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// headerChanged records fields as the current header and reports whether
+// a new header row is needed.
+func (r *RowWriter) headerChanged(fields []string) bool {
+	if r.header != nil && slices.Equal(r.header, fields) {
+		return false
+	}
+	r.header = slices.Clone(fields)
+	return true
+}
+
+func (r *RowWriter) writeCSV(fields []string, values []string) error {
+	cw := csv.NewWriter(r.w)
+	if r.headerChanged(fields) {
+		if err := cw.Write(fields); err != nil {
+			return err
+		}
+	}
+	if err := cw.Write(values); err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func (r *RowWriter) writeMarkdown(fields []string, values []string) error {
+	var b strings.Builder
+	if r.headerChanged(fields) {
+		b.WriteString(markdownRow(fields))
+		sep := make([]string, len(fields))
+		for i := range sep {
+			sep[i] = "---"
+		}
+		b.WriteString(markdownRow(sep))
+	}
+	b.WriteString(markdownRow(values))
+	_, err := io.WriteString(r.w, b.String())
+	return err
+}
+
+func markdownRow(cells []string) string {
+	escaped := make([]string, len(cells))
+	for i, cell := range cells {
+		cell = strings.ReplaceAll(cell, "\\", "\\\\")
+		cell = strings.ReplaceAll(cell, "|", "\\|")
+		cell = strings.ReplaceAll(cell, "\r\n", "<br>")
+		cell = strings.ReplaceAll(cell, "\n", "<br>")
+		escaped[i] = strings.ReplaceAll(cell, "\t", " ")
+	}
+	return "| " + strings.Join(escaped, " | ") + " |\n"
+}
+
+func (r *RowWriter) writeYAML(fields []string, values []any) error {
+	var b strings.Builder
+	for i, field := range fields {
+		if i == 0 {
+			b.WriteString("- ")
+		} else {
+			b.WriteString("  ")
+		}
+		b.WriteString(yamlScalar(field))
+		b.WriteString(": ")
+		b.WriteString(yamlValue(values[i]))
+		b.WriteString("\n")
+	}
+	_, err := io.WriteString(r.w, b.String())
+	return err
+}
+
+// yamlValue returns v as a YAML scalar: bools and numbers plain, nil as
+// null, and anything else as a string.
+func yamlValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(v)
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(v)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	default:
+		return yamlScalar(cellText(v))
+	}
+}
+
+// yamlScalar returns s as a YAML string scalar, double-quoting it whenever
+// a plain scalar would be ambiguous (numbers, booleans, null) or invalid.
+func yamlScalar(s string) string {
+	if yamlNeedsQuotes(s) {
+		raw, _ := json.Marshal(s) // JSON strings are valid YAML double-quoted scalars
+		return string(raw)
+	}
+	return s
+}
+
+func yamlNeedsQuotes(s string) bool {
+	if s == "" || strings.TrimSpace(s) != s {
+		return true
+	}
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "on", "off", "y", "n", "null", "~", ".nan", ".inf", "-.inf", "+.inf":
+		return true
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return true
+	}
+	if strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`+.0123456789") {
+		return true
+	}
+	if strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":") {
+		return true
+	}
+	for _, r := range s {
+		if r < 0x20 || r == 0x7f {
+			return true
+		}
+	}
+	return false
+}
+
+type rowWriterCtxKey struct{}
+
+// WithRowWriter attaches a shared RowWriter to a context.
+func WithRowWriter(ctx context.Context, r *RowWriter) context.Context {
+	return context.WithValue(ctx, rowWriterCtxKey{}, r)
+}
+
+// RowWriterFromContext retrieves the shared RowWriter, or nil.
+func RowWriterFromContext(ctx context.Context) *RowWriter {
+	r, _ := ctx.Value(rowWriterCtxKey{}).(*RowWriter)
+	return r
+}
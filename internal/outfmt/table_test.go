@@ -0,0 +1,109 @@
+package outfmt
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRowWriter(t *testing.T) {
+	fields := []string{"id", "text"}
+	rows := [][]any{
+		{"!a", "hello\tworld"},
+		{"!b", "line one\nline \"two\", | pipe"},
+	}
+
+	tests := []struct {
+		name string
+		mode Mode
+		want string
+	}{
+		{
+			name: "tsv",
+			mode: Mode{Plain: true},
+			want: "!a\thello\tworld\n!b\tline one\nline \"two\", | pipe\n",
+		},
+		{
+			name: "csv",
+			mode: Mode{CSV: true},
+			want: "id,text\n!a,hello\tworld\n!b,\"line one\nline \"\"two\"\", | pipe\"\n",
+		},
+		{
+			name: "markdown",
+			mode: Mode{Markdown: true},
+			want: "| id | text |\n| --- | --- |\n| !a | hello world |\n| !b | line one<br>line \"two\", \\| pipe |\n",
+		},
+		{
+			name: "yaml",
+			mode: Mode{YAML: true},
+			want: "- id: \"!a\"\n  text: \"hello\\tworld\"\n- id: \"!b\"\n  text: \"line one\\nline \\\"two\\\", | pipe\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := NewRowWriter(&buf, tt.mode)
+			for _, row := range rows {
+				if err := w.WriteRow(fields, row); err != nil {
+					t.Fatalf("WriteRow() error = %v", err)
+				}
+			}
+			if buf.String() != tt.want {
+				t.Fatalf("output = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRowWriterRepeatsHeaderOnFieldChange(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewRowWriter(&buf, Mode{CSV: true})
+	_ = w.WriteRow([]string{"a"}, []any{"1"})
+	_ = w.WriteRow([]string{"a"}, []any{2})
+	_ = w.WriteRow([]string{"b"}, []any{true})
+	if want := "a\n1\n2\nb\ntrue\n"; buf.String() != want {
+		t.Fatalf("output = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestRowWriterYAMLTypes(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewRowWriter(&buf, Mode{YAML: true})
+	fields := []string{"id", "unread_count", "is_muted", "size", "valid", "title"}
+	if err := w.WriteRow(fields, []any{"42", 3, true, int64(1024), nil, "false"}); err != nil {
+		t.Fatalf("WriteRow() error = %v", err)
+	}
+	want := "- id: \"42\"\n  unread_count: 3\n  is_muted: true\n  size: 1024\n  valid: null\n  title: \"false\"\n"
+	if buf.String() != want {
+		t.Fatalf("output = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestYAMLScalar(t *testing.T) {
+	tests := map[string]string{
+		"Alice":       "Alice",
+		"":            `""`,
+		"true":        `"true"`,
+		"42":          `"42"`,
+		"null":        `"null"`,
+		"a: b":        `"a: b"`,
+		" padded":     `" padded"`,
+		"2026-02-11":  `"2026-02-11"`,
+		"Team @ work": "Team @ work",
+	}
+	for in, want := range tests {
+		if got := yamlScalar(in); got != want {
+			t.Errorf("yamlScalar(%q) = %s, want %s", in, got, want)
+		}
+	}
+}
+
+func TestFromFlagSet(t *testing.T) {
+	mode, err := FromFlagSet(Flags{CSV: true})
+	if err != nil || !mode.CSV || !mode.IsTabular() {
+		t.Fatalf("FromFlagSet(CSV) = %+v, %v", mode, err)
+	}
+	if _, err := FromFlagSet(Flags{Plain: true, Markdown: true}); err == nil {
+		t.Fatal("FromFlagSet(Plain, Markdown) expected error")
+	}
+}
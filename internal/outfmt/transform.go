@@ -0,0 +1,143 @@
+package outfmt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+)
+
+// Transform reshapes JSON output in-process: Query (--query) filters the
+// JSON document jq-style, and Template (--template) renders it with Go
+// text/template. Both see the JSON form of the output, so keys are the JSON
+// field names. When both are set the template runs on each query result.
+type Transform struct {
+	Query    *Query
+	Template *template.Template
+}
+
+// ParseTransform compiles --query and --template values. It returns nil
+// when both are empty.
+func ParseTransform(query, tmpl string) (*Transform, error) {
+	if strings.TrimSpace(query) == "" && tmpl == "" {
+		return nil, nil
+	}
+	t := &Transform{}
+	if strings.TrimSpace(query) != "" {
+		q, err := ParseQuery(query)
+		if err != nil {
+			return nil, err
+		}
+		t.Query = q
+	}
+	if tmpl != "" {
+		parsed, err := template.New("output").Funcs(templateFuncs).Parse(tmpl)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --template: %w", err)
+		}
+		t.Template = parsed
+	}
+	return t, nil
+}
+
+type transformCtxKey struct{}
+
+// WithTransform attaches an output transform to a context.
+func WithTransform(ctx context.Context, t *Transform) context.Context {
+	return context.WithValue(ctx, transformCtxKey{}, t)
+}
+
+// TransformFromContext retrieves the output transform, or nil.
+func TransformFromContext(ctx context.Context) *Transform {
+	t, _ := ctx.Value(transformCtxKey{}).(*Transform)
+	return t
+}
+
+// Write renders v through the transform. Query results that are strings
+// print raw (like jq -r); other results print as JSON, indented unless
+// compact is set. Template output is written as-is.
+func (t *Transform) Write(w io.Writer, v any, compact bool) error {
+	doc, err := ToJSONValue(v)
+	if err != nil {
+		return err
+	}
+	values := []any{doc}
+	if t.Query != nil {
+		if values, err = t.Query.Run(doc); err != nil {
+			return err
+		}
+	}
+
+	for _, value := range values {
+		if t.Template != nil {
+			var buf bytes.Buffer
+			if err := t.Template.Execute(&buf, value); err != nil {
+				return fmt.Errorf("render --template: %w", err)
+			}
+			if _, err := w.Write(buf.Bytes()); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := writeQueryResult(w, value, compact); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeQueryResult(w io.Writer, v any, compact bool) error {
+	if s, ok := v.(string); ok {
+		_, err := fmt.Fprintln(w, s)
+		return err
+	}
+	if compact {
+		return WriteJSONLine(w, v)
+	}
+	return WriteJSON(w, v)
+}
+
+var templateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(raw), nil
+	},
+	"join": func(sep string, items any) (string, error) {
+		doc, err := ToJSONValue(items)
+		if err != nil {
+			return "", err
+		}
+		arr, ok := doc.([]any)
+		if !ok {
+			return "", fmt.Errorf("join expects a list, got %s", typeName(doc))
+		}
+		parts := make([]string, len(arr))
+		for i, x := range arr {
+			if x != nil {
+				parts[i] = toString(x)
+			}
+		}
+		return strings.Join(parts, sep), nil
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"truncate": func(n int, s string) string {
+		if utf8.RuneCountInString(s) <= n {
+			return s
+		}
+		runes := []rune(s)
+		if n <= 1 {
+			return string(runes[:max(n, 0)])
+		}
+		return string(runes[:n-1]) + "…"
+	},
+	"tab": func() string { return "\t" },
+	"nl":  func() string { return "\n" },
+}
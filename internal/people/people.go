@@ -0,0 +1,156 @@
+// Package people stores manual person links: contacts on different
+// accounts that rr should treat as the same person even when they share no
+// email, phone number, or username, and contacts that should never be
+// joined to a person automatically.
+package people
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/johntheyoung/roadrunner/internal/beeperapi"
+)
+
+// FileName is the links file name inside the config directory.
+const FileName = "people.json"
+
+// Store holds person links loaded from a file.
+type Store struct {
+	path   string
+	people []beeperapi.PersonLink
+}
+
+type storeFile struct {
+	People []beeperapi.PersonLink `json:"people"`
+}
+
+// Open loads the store at path. A missing file is an empty store.
+func Open(path string) (*Store, error) {
+	s := &Store{path: path}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("read people links: %w", err)
+	}
+	var file storeFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse people links %s: %w", path, err)
+	}
+	s.people = file.People
+	return s, nil
+}
+
+// Links returns every saved person, sorted by name.
+func (s *Store) Links() []beeperapi.PersonLink {
+	return slices.Clone(s.people)
+}
+
+// Get returns the saved person called name, ignoring case.
+func (s *Store) Get(name string) (beeperapi.PersonLink, bool) {
+	if i := s.index(name); i >= 0 {
+		return s.people[i], true
+	}
+	return beeperapi.PersonLink{}, false
+}
+
+// Link adds contact to the person called name, creating the person if
+// needed. The contact is moved out of any other saved person and is no
+// longer excluded from name. It returns the updated person.
+func (s *Store) Link(name string, contact beeperapi.PersonContact) beeperapi.PersonLink {
+	ref := contact.Ref()
+	contact.LinkedBy = "manual"
+	for i := range s.people {
+		s.people[i].Contacts = slices.DeleteFunc(s.people[i].Contacts, func(c beeperapi.PersonContact) bool {
+			return c.Ref() == ref
+		})
+	}
+	i := s.index(name)
+	if i < 0 {
+		s.people = append(s.people, beeperapi.PersonLink{Name: strings.TrimSpace(name)})
+		i = len(s.people) - 1
+	}
+	p := &s.people[i]
+	p.Unlinked = slices.DeleteFunc(p.Unlinked, func(r beeperapi.PersonRef) bool { return r == ref })
+	p.Contacts = append(p.Contacts, contact)
+	slices.SortFunc(p.Contacts, func(a, b beeperapi.PersonContact) int {
+		return strings.Compare(a.AccountID+"\x00"+a.ID, b.AccountID+"\x00"+b.ID)
+	})
+	linked := *p
+	s.prune()
+	return linked
+}
+
+// Unlink removes ref from the person called name and records it as
+// excluded, so shared identifiers no longer join it to that person. It
+// returns the updated person.
+func (s *Store) Unlink(name string, ref beeperapi.PersonRef) beeperapi.PersonLink {
+	i := s.index(name)
+	if i < 0 {
+		s.people = append(s.people, beeperapi.PersonLink{Name: strings.TrimSpace(name)})
+		i = len(s.people) - 1
+	}
+	p := &s.people[i]
+	p.Contacts = slices.DeleteFunc(p.Contacts, func(c beeperapi.PersonContact) bool { return c.Ref() == ref })
+	if !slices.Contains(p.Unlinked, ref) {
+		p.Unlinked = append(p.Unlinked, ref)
+	}
+	unlinked := *p
+	s.prune()
+	return unlinked
+}
+
+// index returns the position of the person called name, or -1.
+func (s *Store) index(name string) int {
+	name = strings.TrimSpace(name)
+	return slices.IndexFunc(s.people, func(p beeperapi.PersonLink) bool {
+		return strings.EqualFold(p.Name, name)
+	})
+}
+
+// prune drops people left without links or exclusions and keeps the rest
+// sorted by name.
+func (s *Store) prune() {
+	s.people = slices.DeleteFunc(s.people, func(p beeperapi.PersonLink) bool {
+		return len(p.Contacts) == 0 && len(p.Unlinked) == 0
+	})
+	slices.SortFunc(s.people, func(a, b beeperapi.PersonLink) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+}
+
+// Save writes the store atomically with owner-only permissions.
+func (s *Store) Save() error {
+	data, err := json.MarshalIndent(storeFile{People: s.people}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode people links: %w", err)
+	}
+
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("create people links dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(s.path)+".*")
+	if err != nil {
+		return fmt.Errorf("write people links: %w", err)
+	}
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("write people links: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("write people links: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("write people links: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package people
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/johntheyoung/roadrunner/internal/beeperapi"
+)
+
+func TestStoreLinkAndUnlink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	alice := beeperapi.PersonContact{AccountID: "signal", Contact: beeperapi.Contact{ID: "sig-1"}}
+	work := beeperapi.PersonContact{AccountID: "slack", Contact: beeperapi.Contact{ID: "U1"}}
+	s.Link("Alice", alice)
+	s.Link("alice", work)
+	s.Link("Work Alice", work)
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	links := reopened.Links()
+	if len(links) != 2 || links[0].Name != "Alice" || len(links[0].Contacts) != 1 || links[1].Name != "Work Alice" {
+		t.Fatalf("Links() = %+v, want the slack contact moved to Work Alice", links)
+	}
+	if links[0].Contacts[0].LinkedBy != "manual" {
+		t.Fatalf("LinkedBy = %q, want manual", links[0].Contacts[0].LinkedBy)
+	}
+
+	link := reopened.Unlink("Alice", alice.Ref())
+	if len(link.Contacts) != 0 || len(link.Unlinked) != 1 {
+		t.Fatalf("Unlink() = %+v", link)
+	}
+	reopened.Link("Alice", alice)
+	if link, _ := reopened.Get("ALICE"); len(link.Unlinked) != 0 || len(link.Contacts) != 1 {
+		t.Fatalf("relink = %+v, want exclusion cleared", link)
+	}
+}
@@ -0,0 +1,295 @@
+// Package phone normalizes phone numbers to E.164 ("+14155550101") so the
+// numbers users type, in national or international formats, reach the API
+// and contact matching in one canonical form. It checks country calling
+// codes and number lengths; it does not know numbering plans in detail.
+package phone
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ErrNoCountryCode is wrapped by Normalize errors for national numbers
+// when no default region is known.
+var ErrNoCountryCode = errors.New("no country code (start with + or set a default region)")
+
+// maxDigits is the E.164 limit on country code plus national number.
+const maxDigits = 15
+
+// minMatchDigits is the fewest national digits Match compares; shorter
+// numbers are too likely to collide.
+const minMatchDigits = 7
+
+// region is a country's calling code and national trunk prefix, which
+// national formats put in front of area codes and E.164 drops.
+type region struct {
+	code  string
+	trunk string
+}
+
+// regions maps ISO 3166-1 alpha-2 codes to calling codes. Countries whose
+// leading 0 is part of the number (Italy, Spain, ...) have no trunk prefix.
+var regions = map[string]region{
+	"AE": {"971", "0"}, "AR": {"54", "0"}, "AT": {"43", "0"}, "AU": {"61", "0"},
+	"BD": {"880", "0"}, "BE": {"32", "0"}, "BG": {"359", "0"}, "BR": {"55", "0"},
+	"CA": {"1", "1"}, "CH": {"41", "0"}, "CL": {"56", ""}, "CN": {"86", "0"},
+	"CO": {"57", ""}, "CZ": {"420", ""}, "DE": {"49", "0"}, "DK": {"45", ""},
+	"EE": {"372", ""}, "EG": {"20", "0"}, "ES": {"34", ""}, "FI": {"358", "0"},
+	"FR": {"33", "0"}, "GB": {"44", "0"}, "GR": {"30", ""}, "HK": {"852", ""},
+	"HR": {"385", "0"}, "HU": {"36", "06"}, "ID": {"62", "0"}, "IE": {"353", "0"},
+	"IL": {"972", "0"}, "IN": {"91", "0"}, "IS": {"354", ""}, "IT": {"39", ""},
+	"JP": {"81", "0"}, "KE": {"254", "0"}, "KR": {"82", "0"}, "LT": {"370", "8"},
+	"LU": {"352", ""}, "LV": {"371", ""}, "MA": {"212", "0"}, "MX": {"52", ""},
+	"MY": {"60", "0"}, "NG": {"234", "0"}, "NL": {"31", "0"}, "NO": {"47", ""},
+	"NZ": {"64", "0"}, "PE": {"51", "0"}, "PH": {"63", "0"}, "PK": {"92", "0"},
+	"PL": {"48", ""}, "PR": {"1", "1"}, "PT": {"351", ""}, "RO": {"40", "0"},
+	"RS": {"381", "0"}, "RU": {"7", "8"}, "SA": {"966", "0"}, "SE": {"46", "0"},
+	"SG": {"65", ""}, "SI": {"386", "0"}, "SK": {"421", "0"}, "TH": {"66", "0"},
+	"TR": {"90", "0"}, "TW": {"886", "0"}, "UA": {"380", "0"}, "US": {"1", "1"},
+	"VE": {"58", "0"}, "VN": {"84", "0"}, "ZA": {"27", "0"},
+}
+
+// countryCodes lists the assigned calling codes. They are prefix-free, so
+// at most one of a number's first one to three digits is a code.
+var countryCodes = toSet(
+	"1", "7",
+	"20", "27", "30", "31", "32", "33", "34", "36", "39", "40", "41", "43", "44", "45", "46", "47", "48", "49",
+	"51", "52", "53", "54", "55", "56", "57", "58", "60", "61", "62", "63", "64", "65", "66",
+	"81", "82", "84", "86", "90", "91", "92", "93", "94", "95", "98",
+	"211", "212", "213", "216", "218", "220", "221", "222", "223", "224", "225", "226", "227", "228", "229",
+	"230", "231", "232", "233", "234", "235", "236", "237", "238", "239", "240", "241", "242", "243", "244",
+	"245", "246", "247", "248", "249", "250", "251", "252", "253", "254", "255", "256", "257", "258",
+	"260", "261", "262", "263", "264", "265", "266", "267", "268", "269", "290", "291", "297", "298", "299",
+	"350", "351", "352", "353", "354", "355", "356", "357", "358", "359",
+	"370", "371", "372", "373", "374", "375", "376", "377", "378", "379", "380", "381", "382", "383",
+	"385", "386", "387", "389", "420", "421", "423",
+	"500", "501", "502", "503", "504", "505", "506", "507", "508", "509",
+	"590", "591", "592", "593", "594", "595", "596", "597", "598", "599",
+	"670", "672", "673", "674", "675", "676", "677", "678", "679", "680", "681", "682", "683",
+	"685", "686", "687", "688", "689", "690", "691", "692",
+	"800", "808", "850", "852", "853", "855", "856", "870", "878", "880", "881", "882", "883", "886", "888",
+	"960", "961", "962", "963", "964", "965", "966", "967", "968", "970", "971", "972", "973", "974",
+	"975", "976", "977", "979", "992", "993", "994", "995", "996", "998",
+)
+
+// nationalLengths bounds national number lengths for calling codes with
+// fixed-length plans. Other codes allow 4 digits up to the E.164 limit.
+var nationalLengths = map[string][2]int{
+	"1":  {10, 10},
+	"7":  {10, 10},
+	"33": {9, 9},
+	"34": {9, 9},
+	"44": {9, 10},
+	"52": {10, 10},
+	"61": {9, 9},
+	"81": {9, 10},
+	"91": {10, 10},
+}
+
+func toSet(values ...string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
+
+// ValidateRegion returns an error unless region is empty or a known ISO
+// 3166-1 alpha-2 code (any case).
+func ValidateRegion(region string) error {
+	if region == "" {
+		return nil
+	}
+	if _, ok := regions[strings.ToUpper(region)]; !ok {
+		return fmt.Errorf("unknown phone region %q (expected an ISO 3166 country code such as US or GB)", region)
+	}
+	return nil
+}
+
+// Normalize returns number in E.164 form. Numbers starting with + or an
+// international prefix (00, or 011 in North America) keep their country
+// code; national numbers take region's calling code and lose its trunk
+// prefix. Separators (spaces, dashes, dots, slashes, parentheses) and a
+// tel: prefix are ignored. Letters, unknown country codes, and numbers too
+// short or long for their country are errors.
+func Normalize(number, region string) (string, error) {
+	code, national, err := split(number, region)
+	if err != nil {
+		return "", err
+	}
+	return "+" + code + national, nil
+}
+
+// Match reports whether a and b are the same phone number. When one of
+// them has no country code and region is unknown, its digits are compared
+// with the other's national number, so "+1 (555) 010-0000" matches
+// "5550100000" without a region.
+func Match(a, b, region string) bool {
+	codeA, nationalA, errA := split(a, region)
+	codeB, nationalB, errB := split(b, region)
+	switch {
+	case errA == nil && errB == nil:
+		return codeA+nationalA == codeB+nationalB
+	case errA == nil:
+		return matchNational(b, errB, codeA, nationalA)
+	case errB == nil:
+		return matchNational(a, errA, codeB, nationalB)
+	}
+	keysA, keysB := nationalKeys(a, errA), nationalKeys(b, errB)
+	for _, ka := range keysA {
+		for _, kb := range keysB {
+			if ka == kb {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// LooksLike reports whether s is plausibly a phone number: separators,
+// an optional leading +, and at least 7 digits.
+func LooksLike(s string) bool {
+	digits, _, err := scan(s)
+	return err == nil && len(digits) >= minMatchDigits
+}
+
+// National returns the national number of an E.164 number, or "" when
+// e164 is not one.
+func National(e164 string) string {
+	if !strings.HasPrefix(e164, "+") {
+		return ""
+	}
+	_, national, err := split(e164, "")
+	if err != nil {
+		return ""
+	}
+	return national
+}
+
+// matchNational reports whether number, which failed to parse with err,
+// is the national form of the number with code and national.
+func matchNational(number string, err error, code, national string) bool {
+	for _, key := range nationalKeys(number, err) {
+		if key == national || key == code+national {
+			return true
+		}
+	}
+	return false
+}
+
+// nationalKeys returns the digit strings a number without a country code
+// is compared by: its digits with and without a leading 0. Numbers that
+// failed to parse for other reasons, or are too short, have none.
+func nationalKeys(number string, err error) []string {
+	if !errors.Is(err, ErrNoCountryCode) {
+		return nil
+	}
+	digits, _, _ := scan(number)
+	if len(digits) < minMatchDigits {
+		return nil
+	}
+	keys := []string{digits}
+	if trimmed := strings.TrimPrefix(digits, "0"); trimmed != digits && len(trimmed) >= minMatchDigits {
+		keys = append(keys, trimmed)
+	}
+	return keys
+}
+
+// split parses number into its calling code and national number.
+func split(number, regionCode string) (code, national string, err error) {
+	digits, plus, err := scan(number)
+	if err != nil {
+		return "", "", err
+	}
+	if digits == "" {
+		return "", "", invalid(number, "no digits")
+	}
+	if err := ValidateRegion(regionCode); err != nil {
+		return "", "", err
+	}
+	reg, hasRegion := regions[strings.ToUpper(regionCode)]
+
+	international := plus
+	switch {
+	case international:
+	case strings.HasPrefix(digits, "00"):
+		digits, international = digits[2:], true
+	case hasRegion && reg.code == "1" && strings.HasPrefix(digits, "011"):
+		digits, international = digits[3:], true
+	}
+
+	if international {
+		for n := 1; n <= 3 && n < len(digits); n++ {
+			if countryCodes[digits[:n]] {
+				code, national = digits[:n], digits[n:]
+				break
+			}
+		}
+		if code == "" {
+			return "", "", invalid(number, "unknown country code")
+		}
+	} else {
+		if !hasRegion {
+			return "", "", fmt.Errorf("invalid phone number %q: %w", number, ErrNoCountryCode)
+		}
+		code, national = reg.code, digits
+		// North American numbers carry the 1 trunk prefix only when dialed
+		// with 11 digits; elsewhere the prefix always precedes area codes.
+		if reg.trunk != "" && strings.HasPrefix(national, reg.trunk) && (reg.code != "1" || len(national) == 11) {
+			national = national[len(reg.trunk):]
+		}
+	}
+
+	if err := checkLength(number, code, national); err != nil {
+		return "", "", err
+	}
+	return code, national, nil
+}
+
+// checkLength validates the national number length for code.
+func checkLength(number, code, national string) error {
+	lo, hi := 4, maxDigits-len(code)
+	if bounds, ok := nationalLengths[code]; ok {
+		lo, hi = bounds[0], bounds[1]
+	}
+	switch {
+	case len(national) < lo:
+		return invalid(number, fmt.Sprintf("too short for country code +%s", code))
+	case len(national) > hi:
+		return invalid(number, fmt.Sprintf("too long for country code +%s", code))
+	case code == "1" && (national[0] == '0' || national[0] == '1'):
+		return invalid(number, "North American area codes cannot start with 0 or 1")
+	}
+	return nil
+}
+
+// scan strips separators and a tel: prefix from number, returning its
+// digits and whether it started with +.
+func scan(number string) (digits string, plus bool, err error) {
+	s := strings.TrimSpace(number)
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "tel:"), "TEL:")
+	if s == "" {
+		return "", false, invalid(number, "empty")
+	}
+	var b strings.Builder
+	for i, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '+' && i == 0:
+			plus = true
+		case r == ' ' || r == '-' || r == '.' || r == '/' || r == '(' || r == ')':
+		case unicode.IsLetter(r):
+			return "", false, invalid(number, "contains letters")
+		default:
+			return "", false, invalid(number, fmt.Sprintf("unexpected character %q", r))
+		}
+	}
+	return b.String(), plus, nil
+}
+
+func invalid(number, reason string) error {
+	return fmt.Errorf("invalid phone number %q: %s", number, reason)
+}
@@ -0,0 +1,92 @@
+package phone
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		number, region, want string
+	}{
+		{"+1 (415) 555-0101", "", "+14155550101"},
+		{"tel:+14155550101", "", "+14155550101"},
+		{"415.555.0101", "US", "+14155550101"},
+		{"1-415-555-0101", "us", "+14155550101"},
+		{"011 44 20 7946 0958", "US", "+442079460958"},
+		{"0044 20 7946 0958", "", "+442079460958"},
+		{"020 7946 0958", "GB", "+442079460958"},
+		{"07700 900123", "GB", "+447700900123"},
+		{"06 1234 5678", "IT", "+390612345678"},
+		{"8 (912) 345-67-89", "RU", "+79123456789"},
+		{"+683 4002", "", "+6834002"},
+	}
+	for _, tt := range tests {
+		got, err := Normalize(tt.number, tt.region)
+		if err != nil || got != tt.want {
+			t.Errorf("Normalize(%q, %q) = %q, %v; want %q", tt.number, tt.region, got, err, tt.want)
+		}
+	}
+}
+
+func TestNormalizeErrors(t *testing.T) {
+	tests := []struct {
+		number, region, want string
+	}{
+		{"", "US", "empty"},
+		{"call me", "US", "contains letters"},
+		{"415#555", "US", "unexpected character"},
+		{"5550100", "US", "too short"},
+		{"+1 415 555 01011", "", "too long"},
+		{"+1 015 555 0101", "", "area codes"},
+		{"+28 1234 5678", "", "unknown country code"},
+		{"+44 20 7946 095812", "", "too long"},
+		{"4155550101", "XX", "unknown phone region"},
+	}
+	for _, tt := range tests {
+		_, err := Normalize(tt.number, tt.region)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Normalize(%q, %q) error = %v, want %q", tt.number, tt.region, err, tt.want)
+		}
+	}
+
+	if _, err := Normalize("4155550101", ""); !errors.Is(err, ErrNoCountryCode) {
+		t.Errorf("Normalize without region error = %v, want ErrNoCountryCode", err)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		a, b, region string
+		want         bool
+	}{
+		{"+1 (555) 010-0000", "5550100000", "", true},
+		{"+1 (555) 010-0000", "5550100000", "US", true},
+		{"+1 (555) 010-0000", "+15550100000", "", true},
+		{"+44 7700 900123", "07700 900123", "", true},
+		{"+44 7700 900123", "07700 900123", "GB", true},
+		{"+1 555 010 0000", "+44 555 010 0000", "", false},
+		{"+1 555 010 0000", "555 010 0001", "", false},
+		{"5550100000", "555-010-0000", "", true},
+		{"alice", "alice", "", false},
+		{"12345", "12345", "", false},
+	}
+	for _, tt := range tests {
+		if got := Match(tt.a, tt.b, tt.region); got != tt.want {
+			t.Errorf("Match(%q, %q, %q) = %v, want %v", tt.a, tt.b, tt.region, got, tt.want)
+		}
+	}
+}
+
+func TestLooksLikeAndNational(t *testing.T) {
+	if !LooksLike("+1 (555) 010-0000") || LooksLike("Alice") || LooksLike("12345") {
+		t.Fatal("LooksLike misclassified input")
+	}
+	if got := National("+15550100000"); got != "5550100000" {
+		t.Fatalf("National() = %q", got)
+	}
+	if got := National("5550100000"); got != "" {
+		t.Fatalf("National(national) = %q, want empty", got)
+	}
+}
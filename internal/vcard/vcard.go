@@ -0,0 +1,250 @@
+// Package vcard reads and writes the subset of vCard (RFC 6350) rr needs to
+// move contacts between accounts: names, phone numbers, emails, photos, and
+// Beeper usernames and IDs carried in X-BEEPER-* properties. Parsing also
+// accepts vCard 3.0 exports from phones and address books.
+package vcard
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Extension properties for Beeper identifiers.
+const (
+	PropUsername  = "X-BEEPER-USERNAME"
+	PropAccountID = "X-BEEPER-ACCOUNT-ID"
+	PropContactID = "X-BEEPER-CONTACT-ID"
+)
+
+// maxLineOctets is the folding width from RFC 6350 section 3.2.
+const maxLineOctets = 75
+
+// Card is one contact.
+type Card struct {
+	FullName  string
+	Phones    []string
+	Emails    []string
+	Username  string
+	AccountID string
+	ContactID string
+	// Photo is a URI: a data: URI for embedded images or a link.
+	Photo string
+}
+
+// Write encodes cards as vCard 4.0 with CRLF line endings and folded long
+// lines.
+func Write(w io.Writer, cards ...Card) error {
+	bw := bufio.NewWriter(w)
+	for _, c := range cards {
+		lines := []string{"BEGIN:VCARD", "VERSION:4.0", "FN:" + escape(c.FullName)}
+		for _, phone := range c.Phones {
+			lines = append(lines, "TEL;VALUE=uri:tel:"+strings.ReplaceAll(phone, " ", ""))
+		}
+		for _, email := range c.Emails {
+			lines = append(lines, "EMAIL:"+escape(email))
+		}
+		if c.Photo != "" {
+			lines = append(lines, "PHOTO:"+c.Photo)
+		}
+		if c.Username != "" {
+			lines = append(lines, PropUsername+":"+escape(c.Username))
+		}
+		if c.AccountID != "" {
+			lines = append(lines, PropAccountID+":"+escape(c.AccountID))
+		}
+		if c.ContactID != "" {
+			lines = append(lines, PropContactID+":"+escape(c.ContactID))
+		}
+		lines = append(lines, "END:VCARD")
+		for _, line := range lines {
+			if _, err := bw.WriteString(fold(line)); err != nil {
+				return err
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+// Parse decodes every card in r. Unknown properties are ignored; a card
+// without FN takes its name from N.
+func Parse(r io.Reader) ([]Card, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var cards []Card
+	var cur *Card
+	var structured string
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		name, params, value, ok := splitLine(line)
+		if !ok {
+			return nil, fmt.Errorf("line %d: missing ':' in %q", i+1, line)
+		}
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VCARD"):
+			if cur != nil {
+				return nil, fmt.Errorf("line %d: BEGIN:VCARD inside a card", i+1)
+			}
+			cur, structured = &Card{}, ""
+			continue
+		case name == "END" && strings.EqualFold(value, "VCARD"):
+			if cur == nil {
+				return nil, fmt.Errorf("line %d: END:VCARD without BEGIN", i+1)
+			}
+			if cur.FullName == "" {
+				cur.FullName = structured
+			}
+			cards = append(cards, *cur)
+			cur = nil
+			continue
+		case cur == nil:
+			return nil, fmt.Errorf("line %d: property %s outside a card", i+1, name)
+		}
+
+		switch name {
+		case "FN":
+			cur.FullName = strings.TrimSpace(unescape(value))
+		case "N":
+			structured = nameFromN(value)
+		case "TEL":
+			if v := strings.TrimSpace(strings.TrimPrefix(unescape(value), "tel:")); v != "" {
+				cur.Phones = append(cur.Phones, v)
+			}
+		case "EMAIL":
+			if v := strings.TrimSpace(strings.TrimPrefix(unescape(value), "mailto:")); v != "" {
+				cur.Emails = append(cur.Emails, v)
+			}
+		case "PHOTO":
+			cur.Photo = photoURI(params, value)
+		case PropUsername:
+			cur.Username = strings.TrimSpace(unescape(value))
+		case PropAccountID:
+			cur.AccountID = strings.TrimSpace(unescape(value))
+		case PropContactID:
+			cur.ContactID = strings.TrimSpace(unescape(value))
+		}
+	}
+	if cur != nil {
+		return nil, fmt.Errorf("unterminated card (missing END:VCARD)")
+	}
+	return cards, nil
+}
+
+// unfold joins continuation lines (starting with a space or tab) onto the
+// previous line.
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 32*1024*1024)
+	for sc.Scan() {
+		line := strings.TrimSuffix(sc.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("read vcard: %w", err)
+	}
+	return lines, nil
+}
+
+// splitLine splits "group.NAME;PARAM=x:value" into the upper-cased name
+// without its group, the raw parameters, and the value.
+func splitLine(line string) (name string, params []string, value string, ok bool) {
+	head, value, ok := strings.Cut(line, ":")
+	if !ok {
+		return "", nil, "", false
+	}
+	parts := strings.Split(head, ";")
+	name = strings.ToUpper(strings.TrimSpace(parts[0]))
+	if _, after, grouped := strings.Cut(name, "."); grouped {
+		name = after
+	}
+	return name, parts[1:], value, true
+}
+
+// nameFromN builds "Given Family" from a structured N value
+// (family;given;additional;prefix;suffix).
+func nameFromN(value string) string {
+	parts := strings.Split(value, ";")
+	var names []string
+	for _, i := range []int{3, 1, 2, 0, 4} {
+		if i < len(parts) {
+			if p := strings.TrimSpace(unescape(parts[i])); p != "" {
+				names = append(names, p)
+			}
+		}
+	}
+	return strings.Join(names, " ")
+}
+
+// photoURI normalizes a PHOTO value to a URI, converting vCard 3.0 inline
+// base64 (ENCODING=b;TYPE=JPEG) to a data: URI.
+func photoURI(params []string, value string) string {
+	inline := false
+	mediaType := "image/jpeg"
+	for _, p := range params {
+		k, v, _ := strings.Cut(p, "=")
+		switch strings.ToUpper(k) {
+		case "ENCODING":
+			inline = strings.EqualFold(v, "b") || strings.EqualFold(v, "base64")
+		case "TYPE":
+			mediaType = "image/" + strings.ToLower(v)
+		}
+	}
+	if inline {
+		return "data:" + mediaType + ";base64," + value
+	}
+	return value
+}
+
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`, ",", `\,`, ";", `\;`).Replace(s)
+}
+
+func unescape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// fold splits line into CRLF-terminated chunks of at most maxLineOctets,
+// never inside a UTF-8 sequence.
+func fold(line string) string {
+	var b strings.Builder
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = maxLineOctets - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+	return b.String()
+}
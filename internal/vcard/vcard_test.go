@@ -0,0 +1,73 @@
+package vcard
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWriteParseRoundTrip(t *testing.T) {
+	cards := []Card{
+		{
+			FullName:  "Zoë Example, Jr.",
+			Phones:    []string{"+14155550101"},
+			Emails:    []string{"zoe@example.com"},
+			Username:  "zoe",
+			AccountID: "matrix",
+			ContactID: "@zoe:beeper.local",
+			Photo:     "data:image/png;base64," + strings.Repeat("QUJD", 40),
+		},
+		{FullName: "Bob", Emails: []string{"bob@example.com"}},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, cards...); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n") {
+		if len(line) > maxLineOctets {
+			t.Fatalf("unfolded line of %d octets: %q", len(line), line)
+		}
+	}
+	if !strings.Contains(buf.String(), "VERSION:4.0\r\n") || !strings.Contains(buf.String(), `FN:Zoë Example\, Jr.`) {
+		t.Fatalf("Write() = %q", buf.String())
+	}
+
+	got, err := Parse(&buf)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, cards) {
+		t.Fatalf("Parse() = %+v, want %+v", got, cards)
+	}
+}
+
+func TestParseVCard3(t *testing.T) {
+	in := "BEGIN:VCARD\nVERSION:3.0\nN:Example;Carol;;;\nitem1.TEL;TYPE=CELL:+1 415 555 0199\nEMAIL;TYPE=INTERNET:carol@example.com\nPHOTO;ENCODING=b;TYPE=PNG:iVBO\n Rw==\nX-UNKNOWN:ignored\nEND:VCARD\n"
+	got, err := Parse(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	want := []Card{{
+		FullName: "Carol Example",
+		Phones:   []string{"+1 415 555 0199"},
+		Emails:   []string{"carol@example.com"},
+		Photo:    "data:image/png;base64,iVBORw==",
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Parse() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, in := range []string{
+		"BEGIN:VCARD\nFN:A\n",
+		"FN:A\n",
+		"BEGIN:VCARD\nnot a property\nEND:VCARD\n",
+	} {
+		if _, err := Parse(strings.NewReader(in)); err == nil {
+			t.Errorf("Parse(%q) succeeded", in)
+		}
+	}
+}
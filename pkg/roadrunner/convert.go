@@ -0,0 +1,316 @@
+package roadrunner
+
+import (
+	"iter"
+
+	"github.com/johntheyoung/roadrunner/internal/beeperapi"
+)
+
+// The converters below copy between the public types and their internal
+// counterparts. Types with the same fields convert directly, so a field
+// added on one side only fails to compile here instead of being dropped.
+
+func convertSlice[T, U any](in []T, f func(T) U) []U {
+	if in == nil {
+		return nil
+	}
+	out := make([]U, len(in))
+	for i, v := range in {
+		out[i] = f(v)
+	}
+	return out
+}
+
+// mapSeq converts the items of an internal iterator.
+func mapSeq[T, U any](seq iter.Seq2[T, error], f func(T) U) iter.Seq2[U, error] {
+	return func(yield func(U, error) bool) {
+		for v, err := range seq {
+			if err != nil {
+				var zero U
+				yield(zero, publicError(err))
+				return
+			}
+			if !yield(f(v), nil) {
+				return
+			}
+		}
+	}
+}
+
+func accountFromAPI(a beeperapi.Account) Account { return Account(a) }
+
+func contactFromAPI(c beeperapi.Contact) Contact { return Contact(c) }
+
+func contactListResultFromAPI(r beeperapi.ContactListResult) ContactListResult {
+	return ContactListResult{
+		Items:        convertSlice(r.Items, contactFromAPI),
+		HasMore:      r.HasMore,
+		OldestCursor: r.OldestCursor,
+		NewestCursor: r.NewestCursor,
+	}
+}
+
+func matchCandidateFromAPI(c beeperapi.MatchCandidate) MatchCandidate {
+	return MatchCandidate{
+		ID:           c.ID,
+		Name:         c.Name,
+		AccountID:    c.AccountID,
+		Score:        c.Score,
+		MatchedOn:    c.MatchedOn,
+		LastActivity: c.LastActivity,
+	}
+}
+
+func personFromAPI(p beeperapi.Person) Person {
+	return Person{
+		Name:     p.Name,
+		Contacts: convertSlice(p.Contacts, personContactFromAPI),
+		Saved:    p.Saved,
+		Score:    p.Score,
+	}
+}
+
+func personContactFromAPI(c beeperapi.PersonContact) PersonContact {
+	return PersonContact{
+		AccountID: c.AccountID,
+		Network:   c.Network,
+		Contact:   Contact(c.Contact),
+		LinkedBy:  c.LinkedBy,
+	}
+}
+
+func personContactToAPI(c PersonContact) beeperapi.PersonContact {
+	return beeperapi.PersonContact{
+		AccountID: c.AccountID,
+		Network:   c.Network,
+		Contact:   beeperapi.Contact(c.Contact),
+		LinkedBy:  c.LinkedBy,
+	}
+}
+
+func personLinkToAPI(l PersonLink) beeperapi.PersonLink {
+	return beeperapi.PersonLink{
+		Name:     l.Name,
+		Contacts: convertSlice(l.Contacts, personContactToAPI),
+		Unlinked: convertSlice(l.Unlinked, func(r PersonRef) beeperapi.PersonRef { return beeperapi.PersonRef(r) }),
+	}
+}
+
+func peopleParamsToAPI(p PeopleParams) beeperapi.PeopleParams {
+	return beeperapi.PeopleParams{
+		Query:       p.Query,
+		AccountIDs:  p.AccountIDs,
+		Match:       p.Match,
+		Links:       convertSlice(p.Links, personLinkToAPI),
+		Concurrency: p.Concurrency,
+	}
+}
+
+func chatListItemFromAPI(c beeperapi.ChatListItem) ChatListItem { return ChatListItem(c) }
+
+func chatListResultFromAPI(r beeperapi.ChatListResult) ChatListResult {
+	return ChatListResult{
+		Items:        convertSlice(r.Items, chatListItemFromAPI),
+		HasMore:      r.HasMore,
+		OldestCursor: r.OldestCursor,
+		NewestCursor: r.NewestCursor,
+	}
+}
+
+func chatSearchItemFromAPI(c beeperapi.ChatSearchItem) ChatSearchItem {
+	return ChatSearchItem{
+		ID:           c.ID,
+		Title:        c.Title,
+		DisplayName:  c.DisplayName,
+		AccountID:    c.AccountID,
+		Type:         c.Type,
+		Network:      c.Network,
+		UnreadCount:  c.UnreadCount,
+		IsArchived:   c.IsArchived,
+		IsMuted:      c.IsMuted,
+		LastActivity: c.LastActivity,
+		Tags:         c.Tags,
+		Note:         c.Note,
+	}
+}
+
+func chatSearchItemToAPI(c ChatSearchItem) beeperapi.ChatSearchItem {
+	return beeperapi.ChatSearchItem{
+		ID:           c.ID,
+		Title:        c.Title,
+		DisplayName:  c.DisplayName,
+		AccountID:    c.AccountID,
+		Type:         c.Type,
+		Network:      c.Network,
+		UnreadCount:  c.UnreadCount,
+		IsArchived:   c.IsArchived,
+		IsMuted:      c.IsMuted,
+		LastActivity: c.LastActivity,
+		Tags:         c.Tags,
+		Note:         c.Note,
+	}
+}
+
+func chatSearchResultFromAPI(r beeperapi.ChatSearchResult) ChatSearchResult {
+	return ChatSearchResult{
+		Items:        convertSlice(r.Items, chatSearchItemFromAPI),
+		HasMore:      r.HasMore,
+		OldestCursor: r.OldestCursor,
+		NewestCursor: r.NewestCursor,
+	}
+}
+
+func chatStartParamsToAPI(p ChatStartParams) beeperapi.ChatStartParams {
+	return beeperapi.ChatStartParams{
+		AccountID:   p.AccountID,
+		User:        beeperapi.ChatStartUser(p.User),
+		AllowInvite: p.AllowInvite,
+		MessageText: p.MessageText,
+	}
+}
+
+func messageItemFromAPI(m beeperapi.MessageItem) MessageItem {
+	return MessageItem{
+		ID:              m.ID,
+		AccountID:       m.AccountID,
+		ChatID:          m.ChatID,
+		SenderID:        m.SenderID,
+		SenderName:      m.SenderName,
+		Text:            m.Text,
+		MessageType:     m.MessageType,
+		Timestamp:       m.Timestamp,
+		SortKey:         m.SortKey,
+		LinkedMessageID: m.LinkedMessageID,
+		IsSender:        m.IsSender,
+		IsUnread:        m.IsUnread,
+		HasMedia:        m.HasMedia,
+		Attachments: convertSlice(m.Attachments, func(a beeperapi.MessageAttachment) MessageAttachment {
+			return MessageAttachment(a)
+		}),
+		Reactions: convertSlice(m.Reactions, func(r beeperapi.MessageReaction) MessageReaction {
+			return MessageReaction(r)
+		}),
+		ReactionKeys:          m.ReactionKeys,
+		DownloadedAttachments: m.DownloadedAttachments,
+		ReplyTo:               (*MessageReply)(m.ReplyTo),
+	}
+}
+
+func messageItemToAPI(m MessageItem) beeperapi.MessageItem {
+	return beeperapi.MessageItem{
+		ID:              m.ID,
+		AccountID:       m.AccountID,
+		ChatID:          m.ChatID,
+		SenderID:        m.SenderID,
+		SenderName:      m.SenderName,
+		Text:            m.Text,
+		MessageType:     m.MessageType,
+		Timestamp:       m.Timestamp,
+		SortKey:         m.SortKey,
+		LinkedMessageID: m.LinkedMessageID,
+		IsSender:        m.IsSender,
+		IsUnread:        m.IsUnread,
+		HasMedia:        m.HasMedia,
+		Attachments: convertSlice(m.Attachments, func(a MessageAttachment) beeperapi.MessageAttachment {
+			return beeperapi.MessageAttachment(a)
+		}),
+		Reactions: convertSlice(m.Reactions, func(r MessageReaction) beeperapi.MessageReaction {
+			return beeperapi.MessageReaction(r)
+		}),
+		ReactionKeys:          m.ReactionKeys,
+		DownloadedAttachments: m.DownloadedAttachments,
+		ReplyTo:               (*beeperapi.MessageReply)(m.ReplyTo),
+	}
+}
+
+func messageListResultFromAPI(r beeperapi.MessageListResult) MessageListResult {
+	return MessageListResult{
+		Items:      convertSlice(r.Items, messageItemFromAPI),
+		HasMore:    r.HasMore,
+		NextCursor: r.NextCursor,
+	}
+}
+
+func messageSearchResultFromAPI(r beeperapi.MessageSearchResult) MessageSearchResult {
+	return MessageSearchResult{
+		Items:        convertSlice(r.Items, messageItemFromAPI),
+		HasMore:      r.HasMore,
+		OldestCursor: r.OldestCursor,
+		NewestCursor: r.NewestCursor,
+	}
+}
+
+func sendParamsToAPI(p SendParams) beeperapi.SendParams {
+	return beeperapi.SendParams{
+		Text:             p.Text,
+		ReplyToMessageID: p.ReplyToMessageID,
+		Attachment:       (*beeperapi.SendAttachmentParams)(p.Attachment),
+	}
+}
+
+func threadMessageFromAPI(m beeperapi.ThreadMessage) ThreadMessage {
+	return ThreadMessage{
+		MessageItem: messageItemFromAPI(m.MessageItem),
+		Replies:     convertSlice(m.Replies, threadMessageFromAPI),
+	}
+}
+
+func threadFromAPI(t beeperapi.Thread) Thread {
+	return Thread{
+		ChatID:          t.ChatID,
+		MessageID:       t.MessageID,
+		RootID:          t.RootID,
+		Count:           t.Count,
+		MissingParentID: t.MissingParentID,
+		Pages:           t.Pages,
+		Root:            threadMessageFromAPI(t.Root),
+	}
+}
+
+func messageContextFromAPI(c beeperapi.MessageContext) MessageContext {
+	out := MessageContext{
+		ChatID:  c.ChatID,
+		SortKey: c.SortKey,
+		Before:  convertSlice(c.Before, messageItemFromAPI),
+		After:   convertSlice(c.After, messageItemFromAPI),
+	}
+	if c.Anchor != nil {
+		anchor := messageItemFromAPI(*c.Anchor)
+		out.Anchor = &anchor
+	}
+	return out
+}
+
+func sharedLinksResultFromAPI(r beeperapi.SharedLinksResult) SharedLinksResult {
+	return SharedLinksResult{
+		ChatID:   r.ChatID,
+		Items:    convertSlice(r.Items, func(l beeperapi.SharedLink) SharedLink { return SharedLink(l) }),
+		Scanned:  r.Scanned,
+		Pages:    r.Pages,
+		Complete: r.Complete,
+	}
+}
+
+func sharedMediaResultFromAPI(r beeperapi.SharedMediaResult) SharedMediaResult {
+	return SharedMediaResult{
+		ChatID:   r.ChatID,
+		Items:    convertSlice(r.Items, func(m beeperapi.SharedMedia) SharedMedia { return SharedMedia(m) }),
+		Scanned:  r.Scanned,
+		Pages:    r.Pages,
+		Complete: r.Complete,
+	}
+}
+
+func searchResultFromAPI(r beeperapi.SearchResult) SearchResult {
+	searchChat := func(c beeperapi.SearchChat) SearchChat { return SearchChat(c) }
+	return SearchResult{
+		Chats:    convertSlice(r.Chats, searchChat),
+		InGroups: convertSlice(r.InGroups, searchChat),
+		Messages: SearchMessages{
+			Items:        convertSlice(r.Messages.Items, messageItemFromAPI),
+			HasMore:      r.Messages.HasMore,
+			OldestCursor: r.Messages.OldestCursor,
+			NewestCursor: r.Messages.NewestCursor,
+		},
+	}
+}
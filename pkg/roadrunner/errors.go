@@ -0,0 +1,114 @@
+package roadrunner
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/johntheyoung/roadrunner/internal/beeperapi"
+)
+
+// MatchError is returned by the Resolve and Match methods when a query has
+// no single match.
+type MatchError struct {
+	Resource   string // chat|contact|person
+	Query      string
+	Mode       string // exact|prefix|fuzzy
+	Ambiguous  bool
+	Candidates []MatchCandidate
+}
+
+func (e *MatchError) Error() string {
+	return (&beeperapi.MatchError{Resource: e.Resource, Query: e.Query, Ambiguous: e.Ambiguous}).Error()
+}
+
+// EventsHandshakeError is returned when opening /v1/ws fails during the
+// HTTP handshake.
+type EventsHandshakeError struct {
+	StatusCode int
+	Status     string
+	Err        error
+}
+
+func (e *EventsHandshakeError) Error() string {
+	if e == nil {
+		return ""
+	}
+	return (*beeperapi.EventsHandshakeError)(e).Error()
+}
+
+// Unwrap returns the underlying dial error.
+func (e *EventsHandshakeError) Unwrap() error {
+	if e == nil {
+		return nil
+	}
+	return e.Err
+}
+
+// publicError replaces the internal error types callers can match with
+// errors.As by their public copies. Other errors are returned unchanged.
+func publicError(err error) error {
+	switch err := err.(type) {
+	case *beeperapi.MatchError:
+		return &MatchError{
+			Resource:   err.Resource,
+			Query:      err.Query,
+			Mode:       err.Mode,
+			Ambiguous:  err.Ambiguous,
+			Candidates: convertSlice(err.Candidates, matchCandidateFromAPI),
+		}
+	case *beeperapi.EventsHandshakeError:
+		return (*EventsHandshakeError)(err)
+	}
+	return err
+}
+
+// ErrMessageNotFound is returned by MessagesService.Thread when the message
+// is not in the scanned chat history.
+var ErrMessageNotFound = beeperapi.ErrMessageNotFound
+
+// FormatError converts API errors to readable messages, using the API's
+// error message when the response has one.
+func FormatError(err error) string { return beeperapi.FormatError(err) }
+
+// IsAPIError reports whether err came from a Desktop API response.
+func IsAPIError(err error) bool { return beeperapi.IsAPIError(err) }
+
+// IsNotFound reports whether err is a 404 API response or
+// ErrMessageNotFound.
+func IsNotFound(err error) bool { return beeperapi.IsNotFound(err) }
+
+// IsUnauthorized reports whether err is a 401 API response.
+func IsUnauthorized(err error) bool { return beeperapi.IsUnauthorized(err) }
+
+// IsUnsupportedRoute reports whether err shows that the route for method and
+// a path containing pathContains does not exist on this Desktop API version.
+func IsUnsupportedRoute(err error, method, pathContains string) bool {
+	return beeperapi.IsUnsupportedRoute(err, method, pathContains)
+}
+
+// IsEventsUnsupported reports whether /v1/ws is unavailable on this build.
+func IsEventsUnsupported(err error) bool {
+	var hsErr *EventsHandshakeError
+	return errors.As(err, &hsErr) &&
+		(hsErr.StatusCode == http.StatusNotFound || hsErr.StatusCode == http.StatusMethodNotAllowed)
+}
+
+// IsEventsUnauthorized reports whether the websocket handshake was rejected
+// for the token.
+func IsEventsUnauthorized(err error) bool {
+	var hsErr *EventsHandshakeError
+	return errors.As(err, &hsErr) && hsErr.StatusCode == http.StatusUnauthorized
+}
+
+// IsNoMatch reports whether err is a MatchError for a query with no match.
+func IsNoMatch(err error) bool {
+	var matchErr *MatchError
+	return errors.As(err, &matchErr) && !matchErr.Ambiguous
+}
+
+// IsAmbiguous reports whether err is a MatchError for a query with several
+// matches.
+func IsAmbiguous(err error) bool {
+	var matchErr *MatchError
+	return errors.As(err, &matchErr) && matchErr.Ambiguous
+}
@@ -0,0 +1,113 @@
+package roadrunner
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/johntheyoung/roadrunner/internal/beeperapi"
+)
+
+// Known websocket domain event types.
+const (
+	EventTypeMessageUpserted = "message.upserted"
+	EventTypeMessageDeleted  = "message.deleted"
+	EventTypeChatUpserted    = "chat.upserted"
+	EventTypeChatDeleted     = "chat.deleted"
+
+	// EventTypeMessageBackfill is emitted by rr (not the API) for messages
+	// recovered after a reconnect. Entries have the same shape as
+	// message.upserted entries.
+	EventTypeMessageBackfill = "message.backfill"
+)
+
+// Event is a normalized payload for both control and domain messages.
+type Event struct {
+	Type      string           `json:"type"`
+	Version   int64            `json:"version,omitempty"`
+	RequestID string           `json:"requestID,omitempty"`
+	Code      string           `json:"code,omitempty"`
+	Message   string           `json:"message,omitempty"`
+	ChatIDs   []string         `json:"chatIDs,omitempty"`
+	Seq       int64            `json:"seq,omitempty"`
+	TS        int64            `json:"ts,omitempty"`
+	ChatID    string           `json:"chatID,omitempty"`
+	IDs       []string         `json:"ids,omitempty"`
+	Entries   []map[string]any `json:"entries,omitempty"`
+	Raw       map[string]any   `json:"-"`
+}
+
+// IsControlMessage reports whether the event is one of the protocol control messages.
+func (e Event) IsControlMessage() bool { return beeperapi.Event(e).IsControlMessage() }
+
+// Decode converts raw entries into typed items based on the event type.
+// It never fails: entries that do not decode are kept in Unknown.
+func (e Event) Decode() DecodedEvent {
+	decoded := beeperapi.Event(e).Decode()
+	return DecodedEvent{
+		Event:      e,
+		Messages:   convertSlice(decoded.Messages, messageItemFromAPI),
+		Chats:      convertSlice(decoded.Chats, chatListItemFromAPI),
+		DeletedIDs: decoded.DeletedIDs,
+		Unknown:    decoded.Unknown,
+	}
+}
+
+// DecodedEvent is an Event with its entries decoded into typed items.
+type DecodedEvent struct {
+	Event
+	Messages   []MessageItem
+	Chats      []ChatListItem
+	DeletedIDs []string
+	// Unknown holds entries of unknown event types, or entries that could
+	// not be decoded, as raw maps.
+	Unknown []map[string]any
+}
+
+// EventsDialer opens /v1/ws connections in place of the default dialer.
+// ObserveEventsFrame is called with every raw frame read from a connection
+// it returned, so implementations can record sessions.
+type EventsDialer interface {
+	DialEvents(ctx context.Context, dialer *websocket.Dialer, url string, header http.Header) (*websocket.Conn, *http.Response, error)
+	ObserveEventsFrame(conn *websocket.Conn, frame []byte)
+}
+
+// EventsService opens experimental WebSocket live event connections.
+type EventsService struct {
+	s *beeperapi.EventsService
+}
+
+// Connect opens an authenticated websocket to /v1/ws.
+func (s *EventsService) Connect(ctx context.Context) (*EventsConnection, error) {
+	conn, err := s.s.Connect(ctx)
+	if err != nil {
+		return nil, publicError(err)
+	}
+	return &EventsConnection{conn: conn}, nil
+}
+
+// EventsConnection is an open /v1/ws connection.
+type EventsConnection struct {
+	conn *beeperapi.EventsConnection
+}
+
+// SetSubscriptions replaces current websocket subscriptions.
+func (c *EventsConnection) SetSubscriptions(ctx context.Context, requestID string, chatIDs []string) error {
+	return publicError(c.conn.SetSubscriptions(ctx, requestID, chatIDs))
+}
+
+// ReadEvent reads one websocket event payload.
+func (c *EventsConnection) ReadEvent(ctx context.Context) (Event, error) {
+	evt, err := c.conn.ReadEvent(ctx)
+	return Event(evt), publicError(err)
+}
+
+// ReadRaw reads one websocket frame without decoding it.
+func (c *EventsConnection) ReadRaw(ctx context.Context) ([]byte, error) {
+	frame, err := c.conn.ReadRaw(ctx)
+	return frame, publicError(err)
+}
+
+// Close closes the websocket connection.
+func (c *EventsConnection) Close() error { return c.conn.Close() }
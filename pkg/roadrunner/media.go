@@ -0,0 +1,33 @@
+package roadrunner
+
+import "github.com/johntheyoung/roadrunner/internal/media"
+
+// MediaInfo is what ProbeMedia reads from a file's headers: MIME type,
+// dimensions, duration in seconds, and whether it is an Opus voice note.
+type MediaInfo struct {
+	MimeType  string  `json:"mime_type,omitempty"`
+	Width     int     `json:"width,omitempty"`
+	Height    int     `json:"height,omitempty"`
+	Duration  float64 `json:"duration,omitempty"`
+	VoiceNote bool    `json:"voice_note,omitempty"`
+}
+
+// ErrNotJPEG is returned by StripJPEGMetadata for content that is not a
+// JPEG.
+var ErrNotJPEG = media.ErrNotJPEG
+
+// ProbeMedia inspects the file at path without decoding it, for filling
+// SendAttachmentParams before an upload. Unrecognized files are not an
+// error.
+func ProbeMedia(path string) (MediaInfo, error) {
+	info, err := media.Probe(path)
+	return MediaInfo(info), err
+}
+
+// DetectMimeType returns the MIME type of content from its leading bytes.
+func DetectMimeType(head []byte) string { return media.DetectMimeType(head) }
+
+// StripJPEGMetadata returns a JPEG without its EXIF (including GPS
+// location), XMP, IPTC, and comment segments, keeping a non-default
+// orientation. AssetUploadParams.StripMetadata applies it on upload.
+func StripJPEGMetadata(data []byte) ([]byte, error) { return media.StripJPEGMetadata(data) }
@@ -0,0 +1,31 @@
+package roadrunner
+
+import "github.com/johntheyoung/roadrunner/internal/beeperapi"
+
+// Match modes for ChatsService.Resolve and AccountsService.MatchContact.
+const (
+	MatchExact  = "exact"
+	MatchPrefix = "prefix"
+	MatchFuzzy  = "fuzzy"
+)
+
+// ValidateMatchMode returns an error unless mode is empty or a known match
+// mode.
+func ValidateMatchMode(mode string) error { return beeperapi.ValidateMatchMode(mode) }
+
+// LooksLikeChatID reports whether value is a Matrix-style chat ID such as
+// "!abc123:beeper.local".
+func LooksLikeChatID(value string) bool { return beeperapi.LooksLikeChatID(value) }
+
+// ChatMatches reports whether chat's ID, title, or display name equals query,
+// ignoring case. It is the rule ChatsService.ResolveID applies.
+func ChatMatches(chat ChatSearchItem, query string) bool {
+	return beeperapi.ChatMatches(chatSearchItemToAPI(chat), query)
+}
+
+// ContactMatches reports whether contact's ID, full name, username, email,
+// or phone number equals query, ignoring case. It is the rule
+// AccountsService.ResolveContact applies.
+func ContactMatches(contact Contact, query string) bool {
+	return beeperapi.ContactMatches(beeperapi.Contact(contact), query)
+}
@@ -0,0 +1,172 @@
+// Package roadrunner is the public Go API of rr: a client for the Beeper
+// Desktop API with the same normalization the CLI applies. Chats carry
+// derived display names, networks fall back to the account list, messages
+// are flattened into MessageItem, and API errors can be classified.
+//
+// # Compatibility
+//
+// This package follows semantic versioning. Within a major version, exported
+// identifiers are not removed or renamed, function signatures do not change,
+// and struct fields and JSON tags are only added. Identifiers marked
+// Experimental may still change in minor releases. Everything under
+// internal/ remains private to rr.
+//
+// # Usage
+//
+//	client, err := roadrunner.NewClient(token, "http://localhost:23373", 30*time.Second)
+//	if err != nil {
+//		return err
+//	}
+//	for chat, err := range client.Chats().All(ctx, roadrunner.ChatListParams{}) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(chat.ID, chat.Title)
+//	}
+package roadrunner
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/johntheyoung/roadrunner/internal/beeperapi"
+)
+
+// Client is a Beeper Desktop API client. Services are reached through
+// Accounts, Chats, Messages, Assets, Connect, Events, and Reminders.
+type Client struct {
+	c *beeperapi.Client
+}
+
+// ClientOption customizes a Client created by NewClient.
+type ClientOption struct {
+	apply beeperapi.ClientOption
+}
+
+// NewClient creates a client for the Desktop API at baseURL. A zero timeout
+// disables per-request timeouts. Like rr, the BEEPER_TOKEN and
+// BEEPER_ACCESS_TOKEN environment variables override token, and BEEPER_URL
+// and BEEPER_DESKTOP_BASE_URL override baseURL.
+func NewClient(token, baseURL string, timeout time.Duration, options ...ClientOption) (*Client, error) {
+	apiOptions := make([]beeperapi.ClientOption, 0, len(options))
+	for _, option := range options {
+		if option.apply != nil {
+			apiOptions = append(apiOptions, option.apply)
+		}
+	}
+	c, err := beeperapi.NewClient(token, baseURL, timeout, apiOptions...)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{c: c}, nil
+}
+
+// WithHTTPClient routes API requests through hc.
+func WithHTTPClient(hc *http.Client) ClientOption {
+	return ClientOption{apply: beeperapi.WithHTTPClient(hc)}
+}
+
+// WithEventsDialer opens /v1/ws connections through d.
+func WithEventsDialer(d EventsDialer) ClientOption {
+	return ClientOption{apply: beeperapi.WithEventsDialer(d)}
+}
+
+// WithCache serves account lists, chat name resolution, and contact searches
+// from c when it has fresh entries, and stores new results in it.
+func WithCache(c Cache) ClientOption {
+	return ClientOption{apply: beeperapi.WithCache(c)}
+}
+
+// Accounts returns the accounts service.
+func (c *Client) Accounts() *AccountsService {
+	return &AccountsService{s: c.c.Accounts()}
+}
+
+// Chats returns the chats service.
+func (c *Client) Chats() *ChatsService {
+	return &ChatsService{s: c.c.Chats()}
+}
+
+// Messages returns the messages service.
+func (c *Client) Messages() *MessagesService {
+	return &MessagesService{s: c.c.Messages()}
+}
+
+// Assets returns the assets service.
+func (c *Client) Assets() *AssetsService {
+	return &AssetsService{s: c.c.Assets()}
+}
+
+// Connect returns the connect/discovery service.
+func (c *Client) Connect() *ConnectService {
+	return &ConnectService{s: c.c.Connect()}
+}
+
+// Events returns the experimental WebSocket events service.
+func (c *Client) Events() *EventsService {
+	return &EventsService{s: c.c.Events()}
+}
+
+// Reminders returns the reminders service.
+func (c *Client) Reminders() *RemindersService {
+	return &RemindersService{s: c.c.Reminders()}
+}
+
+// Focus brings Beeper Desktop to the foreground, optionally navigating to a chat.
+func (c *Client) Focus(ctx context.Context, params FocusParams) (FocusResult, error) {
+	result, err := c.c.Focus(ctx, beeperapi.FocusParams(params))
+	return FocusResult(result), publicError(err)
+}
+
+// Search performs a global search across chats and messages.
+func (c *Client) Search(ctx context.Context, params SearchParams) (SearchResult, error) {
+	result, err := c.c.Search(ctx, beeperapi.SearchParams(params))
+	return searchResultFromAPI(result), publicError(err)
+}
+
+// NextCursor returns the cursor that continues a listing in direction
+// ("before" or "after") from a page's oldest and newest cursors.
+func NextCursor(direction, oldestCursor, newestCursor string) string {
+	return beeperapi.NextCursor(direction, oldestCursor, newestCursor)
+}
+
+// ExtractURLs returns the http(s) and www. links in message text, in order
+// of appearance, with trailing punctuation trimmed.
+func ExtractURLs(text string) []string {
+	return beeperapi.ExtractURLs(text)
+}
+
+// MaxConcurrency bounds how many requests FanOut keeps in flight.
+const MaxConcurrency = 16
+
+// DefaultThreadMaxPages bounds how many pages of chat history
+// MessagesService.Thread and HydrateReplies scan when no limit is given.
+const DefaultThreadMaxPages = 25
+
+// DefaultContextMaxPages bounds how many pages of chat history
+// MessagesService.Context scans to locate an anchor.
+const DefaultContextMaxPages = 25
+
+// DefaultSharedMaxPages bounds how many pages of chat history
+// MessagesService.Links and Media scan when no limit is given.
+const DefaultSharedMaxPages = 50
+
+// FanOut calls fn for every key with at most concurrency calls in flight and
+// returns results in key order. The first error cancels the remaining calls.
+func FanOut[K, V any](ctx context.Context, concurrency int, keys []K, fn func(ctx context.Context, key K) (V, error)) ([]V, error) {
+	return beeperapi.FanOut(ctx, concurrency, keys, fn)
+}
+
+// Prefetch is a request running in the background.
+type Prefetch[T any] struct {
+	p *beeperapi.Prefetch[T]
+}
+
+// StartPrefetch runs fn in a new goroutine; call Wait for its result.
+func StartPrefetch[T any](fn func() (T, error)) *Prefetch[T] {
+	return &Prefetch[T]{p: beeperapi.StartPrefetch(fn)}
+}
+
+// Wait blocks until the prefetch finishes and returns its result.
+func (p *Prefetch[T]) Wait() (T, error) { return p.p.Wait() }
@@ -0,0 +1,106 @@
+package roadrunner_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/johntheyoung/roadrunner/internal/fakeapi"
+	"github.com/johntheyoung/roadrunner/pkg/roadrunner"
+)
+
+func newFakeClient(t *testing.T) *roadrunner.Client {
+	t.Helper()
+	t.Setenv("BEEPER_TOKEN", "")
+	t.Setenv("BEEPER_ACCESS_TOKEN", "")
+	t.Setenv("BEEPER_URL", "")
+	t.Setenv("BEEPER_DESKTOP_BASE_URL", "")
+
+	fake := fakeapi.New(fakeapi.DefaultDataset())
+	server := httptest.NewServer(fake)
+	t.Cleanup(func() {
+		server.Close()
+		fake.Close()
+	})
+
+	client, err := roadrunner.NewClient("test-token", server.URL, 0)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	return client
+}
+
+func TestChatsAllNormalizesDisplayNames(t *testing.T) {
+	client := newFakeClient(t)
+
+	names := map[string]string{}
+	for chat, err := range client.Chats().All(context.Background(), roadrunner.ChatListParams{}) {
+		if err != nil {
+			t.Fatalf("All() error = %v", err)
+		}
+		names[chat.ID] = chat.DisplayName
+	}
+	if names["!alice:beeper.local"] != "Alice Example" {
+		t.Fatalf("display names = %v", names)
+	}
+	if names["!team:beeper.local"] != "" {
+		t.Fatalf("group chat display name = %q, want empty", names["!team:beeper.local"])
+	}
+}
+
+func TestResolveHelpers(t *testing.T) {
+	client := newFakeClient(t)
+	ctx := context.Background()
+
+	id, err := client.Chats().ResolveID(ctx, "team", nil)
+	if err != nil || id != "!team:beeper.local" {
+		t.Fatalf("ResolveID(team) = %q, %v", id, err)
+	}
+	_, err = client.Chats().ResolveID(ctx, "nobody here", nil)
+	if !roadrunner.IsNoMatch(err) {
+		t.Fatalf("ResolveID(nobody) error = %v, want no match", err)
+	}
+	var matchErr *roadrunner.MatchError
+	if !errors.As(err, &matchErr) || matchErr.Resource != "chat" || matchErr.Query != "nobody here" {
+		t.Fatalf("ResolveID(nobody) error = %#v, want *roadrunner.MatchError", err)
+	}
+
+	contact, err := client.Accounts().ResolveContact(ctx, "matrix", "alice")
+	if err != nil || contact.ID != "@alice:beeper.local" {
+		t.Fatalf("ResolveContact(alice) = %+v, %v", contact, err)
+	}
+}
+
+func TestErrorClassification(t *testing.T) {
+	client := newFakeClient(t)
+
+	_, err := client.Chats().Get(context.Background(), "!missing:beeper.local", roadrunner.ChatGetParams{})
+	if !roadrunner.IsNotFound(err) || !roadrunner.IsAPIError(err) {
+		t.Fatalf("Get(missing) error = %v, want API 404", err)
+	}
+	if msg := roadrunner.FormatError(err); msg == "" {
+		t.Fatal("FormatError() returned empty message")
+	}
+	if roadrunner.IsUnauthorized(fmt.Errorf("plain error")) {
+		t.Fatal("IsUnauthorized(plain error) = true")
+	}
+}
+
+func TestMessageAttachmentKind(t *testing.T) {
+	cases := []struct {
+		att  roadrunner.MessageAttachment
+		want string
+	}{
+		{roadrunner.MessageAttachment{Type: "img"}, "image"},
+		{roadrunner.MessageAttachment{Type: "img", IsGif: true}, "gif"},
+		{roadrunner.MessageAttachment{Type: "audio", IsVoiceNote: true}, "voice"},
+		{roadrunner.MessageAttachment{Type: "unknown"}, "file"},
+	}
+	for _, tc := range cases {
+		if got := tc.att.Kind(); got != tc.want {
+			t.Errorf("Kind(%+v) = %q, want %q", tc.att, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,322 @@
+package roadrunner
+
+import (
+	"context"
+	"io"
+	"iter"
+
+	"github.com/johntheyoung/roadrunner/internal/beeperapi"
+)
+
+// AccountsService reads accounts, contacts, and people.
+type AccountsService struct {
+	s *beeperapi.AccountsService
+}
+
+// List retrieves all accounts.
+func (s *AccountsService) List(ctx context.Context) ([]Account, error) {
+	accounts, err := s.s.List(ctx)
+	return convertSlice(accounts, accountFromAPI), publicError(err)
+}
+
+// SearchContacts finds contacts on a specific account.
+func (s *AccountsService) SearchContacts(ctx context.Context, accountID string, query string) ([]Contact, error) {
+	contacts, err := s.s.SearchContacts(ctx, accountID, query)
+	return convertSlice(contacts, contactFromAPI), publicError(err)
+}
+
+// ListContacts lists contacts on a specific account with cursor pagination.
+func (s *AccountsService) ListContacts(ctx context.Context, accountID string, params ContactListParams) (ContactListResult, error) {
+	result, err := s.s.ListContacts(ctx, accountID, beeperapi.ContactListParams(params))
+	return contactListResultFromAPI(result), publicError(err)
+}
+
+// AllContacts iterates over every contact of an account, starting at
+// params.Cursor.
+func (s *AccountsService) AllContacts(ctx context.Context, accountID string, params ContactListParams) iter.Seq2[Contact, error] {
+	return mapSeq(s.s.AllContacts(ctx, accountID, beeperapi.ContactListParams(params)), contactFromAPI)
+}
+
+// FindPeople searches every account's contacts for params.Query, follows
+// shared emails, phone numbers, and usernames to the same person's
+// contacts on other accounts, applies params.Links, and groups the result
+// into people, best match first.
+func (s *AccountsService) FindPeople(ctx context.Context, params PeopleParams) ([]Person, error) {
+	people, err := s.s.FindPeople(ctx, peopleParamsToAPI(params))
+	return convertSlice(people, personFromAPI), publicError(err)
+}
+
+// ResolvePerson returns the single person params.Query names. It returns a
+// *MatchError, with ranked candidates when ambiguous, if no single person
+// matches.
+func (s *AccountsService) ResolvePerson(ctx context.Context, params PeopleParams) (Person, error) {
+	person, err := s.s.ResolvePerson(ctx, peopleParamsToAPI(params))
+	return personFromAPI(person), publicError(err)
+}
+
+// ResolveContact returns the single contact of accountID that exactly
+// matches query. Duplicate entries for the same person count once. It
+// returns a *MatchError when no contact or several contacts match.
+func (s *AccountsService) ResolveContact(ctx context.Context, accountID, query string) (Contact, error) {
+	contact, err := s.s.ResolveContact(ctx, accountID, query)
+	return Contact(contact), publicError(err)
+}
+
+// MatchContact returns the contact params.Query resolves to in params.Match
+// mode. Names and usernames match by prefix or fuzzily in those modes; IDs,
+// emails, and phone numbers must always match exactly. Duplicate entries
+// for the same person count once. It returns a *MatchError, with ranked
+// candidates when ambiguous, if no single contact matches.
+func (s *AccountsService) MatchContact(ctx context.Context, params ContactResolveParams) (Contact, error) {
+	contact, err := s.s.MatchContact(ctx, beeperapi.ContactResolveParams(params))
+	return Contact(contact), publicError(err)
+}
+
+// ChatsService reads, creates, and resolves chats.
+type ChatsService struct {
+	s *beeperapi.ChatsService
+}
+
+// List retrieves chats with cursor-based pagination.
+func (s *ChatsService) List(ctx context.Context, params ChatListParams) (ChatListResult, error) {
+	result, err := s.s.List(ctx, beeperapi.ChatListParams(params))
+	return chatListResultFromAPI(result), publicError(err)
+}
+
+// Search retrieves chats matching a query.
+func (s *ChatsService) Search(ctx context.Context, params ChatSearchParams) (ChatSearchResult, error) {
+	result, err := s.s.Search(ctx, beeperapi.ChatSearchParams(params))
+	return chatSearchResultFromAPI(result), publicError(err)
+}
+
+// Get retrieves details for a single chat by ID.
+func (s *ChatsService) Get(ctx context.Context, chatID string, params ChatGetParams) (ChatDetail, error) {
+	chat, err := s.s.Get(ctx, chatID, beeperapi.ChatGetParams(params))
+	return ChatDetail(chat), publicError(err)
+}
+
+// Create creates a new chat.
+func (s *ChatsService) Create(ctx context.Context, params ChatCreateParams) (ChatCreateResult, error) {
+	result, err := s.s.Create(ctx, beeperapi.ChatCreateParams(params))
+	return ChatCreateResult(result), publicError(err)
+}
+
+// Start resolves or creates a direct chat from merged contact data.
+func (s *ChatsService) Start(ctx context.Context, params ChatStartParams) (ChatStartResult, error) {
+	result, err := s.s.Start(ctx, chatStartParamsToAPI(params))
+	return ChatStartResult(result), publicError(err)
+}
+
+// Archive archives or unarchives a chat.
+func (s *ChatsService) Archive(ctx context.Context, chatID string, archived bool) error {
+	return publicError(s.s.Archive(ctx, chatID, archived))
+}
+
+// All iterates over every chat matching params, starting at params.Cursor.
+func (s *ChatsService) All(ctx context.Context, params ChatListParams) iter.Seq2[ChatListItem, error] {
+	return mapSeq(s.s.All(ctx, beeperapi.ChatListParams(params)), chatListItemFromAPI)
+}
+
+// SearchAll iterates over every chat search result, starting at params.Cursor.
+func (s *ChatsService) SearchAll(ctx context.Context, params ChatSearchParams) iter.Seq2[ChatSearchItem, error] {
+	return mapSeq(s.s.SearchAll(ctx, beeperapi.ChatSearchParams(params)), chatSearchItemFromAPI)
+}
+
+// SearchEach pages through chat search results in order, calling fn for each
+// page. With prefetch set, the next page is requested while fn handles the
+// current one. Paging follows OldestCursor, so params.Direction should be
+// empty or "before".
+func (s *ChatsService) SearchEach(ctx context.Context, params ChatSearchParams, prefetch bool, fn func(page ChatSearchResult) error) error {
+	return publicError(s.s.SearchEach(ctx, beeperapi.ChatSearchParams(params), prefetch, func(page beeperapi.ChatSearchResult) error {
+		return fn(chatSearchResultFromAPI(page))
+	}))
+}
+
+// FindDirect returns the most recently active direct chat with contact on
+// accountID, or false when there is none.
+func (s *ChatsService) FindDirect(ctx context.Context, accountID string, contact Contact) (ChatSearchItem, bool, error) {
+	chat, ok, err := s.s.FindDirect(ctx, accountID, beeperapi.Contact(contact))
+	return chatSearchItemFromAPI(chat), ok, publicError(err)
+}
+
+// ResolveID returns the ID of the single chat whose ID, title, or display
+// name exactly matches query, searching all pages. A query that already
+// looks like a chat ID is returned as is. Resolved IDs are kept in the
+// client's Cache when one is set. It returns a *MatchError when no
+// chat or several chats match.
+func (s *ChatsService) ResolveID(ctx context.Context, query string, accountIDs []string) (string, error) {
+	id, err := s.s.ResolveID(ctx, query, accountIDs)
+	return id, publicError(err)
+}
+
+// Resolve returns the chat params.Query resolves to in params.Match mode.
+// Exact mode matches the ID, title, or display name. Prefix mode also
+// accepts names or words starting with the query. Fuzzy mode ranks chats
+// by edit distance, word overlap, participant names, and recent activity,
+// and picks the best one only when it clearly leads. Resolutions are kept
+// in the client's Cache when one is set. It returns a *MatchError, with
+// ranked candidates when ambiguous, if no single chat matches.
+func (s *ChatsService) Resolve(ctx context.Context, params ChatResolveParams) (MatchCandidate, error) {
+	match, err := s.s.Resolve(ctx, beeperapi.ChatResolveParams(params))
+	return matchCandidateFromAPI(match), publicError(err)
+}
+
+// MessagesService reads, sends, and edits messages.
+type MessagesService struct {
+	s *beeperapi.MessagesService
+}
+
+// List retrieves messages for a chat with cursor-based pagination.
+func (s *MessagesService) List(ctx context.Context, chatID string, params MessageListParams) (MessageListResult, error) {
+	result, err := s.s.List(ctx, chatID, beeperapi.MessageListParams(params))
+	return messageListResultFromAPI(result), publicError(err)
+}
+
+// Send sends a text message to a chat.
+func (s *MessagesService) Send(ctx context.Context, chatID string, params SendParams) (SendResult, error) {
+	result, err := s.s.Send(ctx, chatID, sendParamsToAPI(params))
+	return SendResult(result), publicError(err)
+}
+
+// Edit updates the text content of an existing message.
+func (s *MessagesService) Edit(ctx context.Context, chatID, messageID string, params EditParams) (EditResult, error) {
+	result, err := s.s.Edit(ctx, chatID, messageID, beeperapi.EditParams(params))
+	return EditResult(result), publicError(err)
+}
+
+// React adds a reaction to a message.
+func (s *MessagesService) React(ctx context.Context, chatID, messageID, reactionKey string) error {
+	return publicError(s.s.React(ctx, chatID, messageID, reactionKey))
+}
+
+// Unreact removes a reaction from a message.
+func (s *MessagesService) Unreact(ctx context.Context, chatID, messageID, reactionKey string) error {
+	return publicError(s.s.Unreact(ctx, chatID, messageID, reactionKey))
+}
+
+// Search retrieves messages matching a query.
+func (s *MessagesService) Search(ctx context.Context, params MessageSearchParams) (MessageSearchResult, error) {
+	result, err := s.s.Search(ctx, beeperapi.MessageSearchParams(params))
+	return messageSearchResultFromAPI(result), publicError(err)
+}
+
+// All iterates over the messages of a chat in params.Direction, starting at
+// params.Cursor.
+func (s *MessagesService) All(ctx context.Context, chatID string, params MessageListParams) iter.Seq2[MessageItem, error] {
+	return mapSeq(s.s.All(ctx, chatID, beeperapi.MessageListParams(params)), messageItemFromAPI)
+}
+
+// SearchAll iterates over every message search result, starting at
+// params.Cursor.
+func (s *MessagesService) SearchAll(ctx context.Context, params MessageSearchParams) iter.Seq2[MessageItem, error] {
+	return mapSeq(s.s.SearchAll(ctx, beeperapi.MessageSearchParams(params)), messageItemFromAPI)
+}
+
+// Context returns the messages around an anchor. A MessageID or At anchor
+// is located by paging the chat's history back from the newest message;
+// ErrMessageNotFound is returned when it is not within params.MaxPages
+// pages. A SortKey anchor needs no scan; Anchor is nil when the message
+// with that sort key cannot be read back.
+func (s *MessagesService) Context(ctx context.Context, chatID string, params MessageContextParams) (MessageContext, error) {
+	result, err := s.s.Context(ctx, chatID, beeperapi.MessageContextParams(params))
+	return messageContextFromAPI(result), publicError(err)
+}
+
+// Links lists the URLs shared in a chat's messages.
+func (s *MessagesService) Links(ctx context.Context, chatID string, params SharedParams) (SharedLinksResult, error) {
+	result, err := s.s.Links(ctx, chatID, beeperapi.SharedParams(params))
+	return sharedLinksResultFromAPI(result), publicError(err)
+}
+
+// Media lists the attachments shared in a chat's messages.
+func (s *MessagesService) Media(ctx context.Context, chatID string, params SharedParams) (SharedMediaResult, error) {
+	result, err := s.s.Media(ctx, chatID, beeperapi.SharedParams(params))
+	return sharedMediaResultFromAPI(result), publicError(err)
+}
+
+// Thread reconstructs the reply tree around messageID: it follows
+// LinkedMessageID up to the root and collects every reply below it. Chat
+// history is scanned newest first, so once the root is found all of its
+// replies have been seen. It returns ErrMessageNotFound when messageID is
+// not within params.MaxPages pages.
+func (s *MessagesService) Thread(ctx context.Context, chatID, messageID string, params ThreadParams) (Thread, error) {
+	thread, err := s.s.Thread(ctx, chatID, messageID, beeperapi.ThreadParams(params))
+	return threadFromAPI(thread), publicError(err)
+}
+
+// HydrateReplies sets ReplyTo on each item that replies to another message.
+// Parents are taken from items when present; otherwise each chat's history
+// is scanned back from its newest unresolved reply, up to maxPages pages
+// per chat (default DefaultThreadMaxPages). Parents that are not found
+// leave ReplyTo nil.
+func (s *MessagesService) HydrateReplies(ctx context.Context, items []MessageItem, maxPages int) error {
+	apiItems := convertSlice(items, messageItemToAPI)
+	if err := s.s.HydrateReplies(ctx, apiItems, maxPages); err != nil {
+		return publicError(err)
+	}
+	for i := range items {
+		items[i].ReplyTo = (*MessageReply)(apiItems[i].ReplyTo)
+	}
+	return nil
+}
+
+// AssetsService downloads, streams, and uploads files.
+type AssetsService struct {
+	s *beeperapi.AssetsService
+}
+
+// Download retrieves a local file URL for an asset.
+func (s *AssetsService) Download(ctx context.Context, url string) (string, error) {
+	path, err := s.s.Download(ctx, url)
+	return path, publicError(err)
+}
+
+// Serve streams an asset response to the provided writer.
+func (s *AssetsService) Serve(ctx context.Context, url string, dst io.Writer) (AssetServeResult, error) {
+	result, err := s.s.Serve(ctx, url, dst)
+	return AssetServeResult(result), publicError(err)
+}
+
+// Upload stores a local file in temporary upload storage and returns an upload ID.
+func (s *AssetsService) Upload(ctx context.Context, params AssetUploadParams) (AssetUploadResult, error) {
+	result, err := s.s.Upload(ctx, beeperapi.AssetUploadParams(params))
+	return AssetUploadResult(result), publicError(err)
+}
+
+// UploadBase64 uploads a file payload encoded as base64.
+func (s *AssetsService) UploadBase64(ctx context.Context, params AssetUploadBase64Params) (AssetUploadResult, error) {
+	result, err := s.s.UploadBase64(ctx, beeperapi.AssetUploadBase64Params(params))
+	return AssetUploadResult(result), publicError(err)
+}
+
+// ConnectService reads server metadata and checks tokens.
+type ConnectService struct {
+	s *beeperapi.ConnectService
+}
+
+// Info retrieves connect metadata for the running Beeper Desktop API server.
+func (s *ConnectService) Info(ctx context.Context) (ConnectInfo, error) {
+	info, err := s.s.Info(ctx)
+	return ConnectInfo(info), publicError(err)
+}
+
+// Introspect checks token activity and metadata via OAuth introspection.
+func (s *ConnectService) Introspect(ctx context.Context, token string) (TokenIntrospection, error) {
+	result, err := s.s.Introspect(ctx, token)
+	return TokenIntrospection(result), publicError(err)
+}
+
+// RemindersService sets and clears chat reminders.
+type RemindersService struct {
+	s *beeperapi.RemindersService
+}
+
+// Set creates a reminder for a chat.
+func (s *RemindersService) Set(ctx context.Context, chatID string, params SetParams) error {
+	return publicError(s.s.Set(ctx, chatID, beeperapi.SetParams(params)))
+}
+
+// Clear removes a reminder from a chat.
+func (s *RemindersService) Clear(ctx context.Context, chatID string) error {
+	return publicError(s.s.Clear(ctx, chatID))
+}
@@ -0,0 +1,622 @@
+package roadrunner
+
+import (
+	"time"
+
+	"github.com/johntheyoung/roadrunner/internal/beeperapi"
+)
+
+// The types below are rr's normalized API shapes. They are declared here,
+// not aliased from internal packages, so the public API only changes when
+// this package does. JSON tags match rr's --json output.
+
+// Account is a connected messaging account.
+type Account struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"display_name"`
+	Network     string `json:"network"`
+}
+
+// Contact is a contact of one account.
+type Contact struct {
+	ID            string `json:"id"`
+	FullName      string `json:"full_name,omitempty"`
+	Username      string `json:"username,omitempty"`
+	Email         string `json:"email,omitempty"`
+	PhoneNumber   string `json:"phone_number,omitempty"`
+	CannotMessage bool   `json:"cannot_message,omitempty"`
+	ImgURL        string `json:"img_url,omitempty"`
+}
+
+// ContactListParams pages through an account's contacts.
+type ContactListParams struct {
+	Cursor    string
+	Direction string // before|after
+}
+
+// ContactListResult is one page of contacts.
+type ContactListResult struct {
+	Items        []Contact `json:"items"`
+	HasMore      bool      `json:"has_more"`
+	OldestCursor string    `json:"oldest_cursor,omitempty"`
+	NewestCursor string    `json:"newest_cursor,omitempty"`
+}
+
+// ChatResolveParams selects the chat ChatsService.Resolve looks for.
+type ChatResolveParams struct {
+	Query      string
+	AccountIDs []string
+	Match      string // exact|prefix|fuzzy (default exact)
+}
+
+// ContactResolveParams selects the contact AccountsService.MatchContact
+// looks for.
+type ContactResolveParams struct {
+	AccountID string
+	Query     string
+	Match     string // exact|prefix|fuzzy (default exact)
+	// Region is the ISO 3166 country code for phone numbers without a
+	// country code. Without one, such numbers match by national number.
+	Region string
+}
+
+// MatchCandidate is a chat or contact a query resolved to, or one of the
+// ranked candidates of an ambiguous query.
+type MatchCandidate struct {
+	ID           string  `json:"id"`
+	Name         string  `json:"name"`
+	AccountID    string  `json:"account_id,omitempty"`
+	Score        float64 `json:"score"`
+	MatchedOn    string  `json:"matched_on"` // title|display_name|participant|full_name|username|email|phone_number|id
+	LastActivity string  `json:"last_activity,omitempty"`
+}
+
+// Person groups the contacts of one person across accounts.
+type Person struct {
+	Name     string          `json:"name"`
+	Contacts []PersonContact `json:"contacts"`
+	// Saved is set for people saved with manual links.
+	Saved bool    `json:"saved,omitempty"`
+	Score float64 `json:"score"`
+}
+
+// PersonContact is one account's contact for a person.
+type PersonContact struct {
+	AccountID string `json:"account_id"`
+	Network   string `json:"network,omitempty"`
+	Contact
+	// LinkedBy says why the contact belongs to the person:
+	// query|email|phone_number|username|manual.
+	LinkedBy string `json:"linked_by"`
+}
+
+// PersonLink is a manual link: contacts that belong to the person Name, and
+// contacts that do not.
+type PersonLink struct {
+	Name     string          `json:"name"`
+	Contacts []PersonContact `json:"contacts,omitempty"`
+	Unlinked []PersonRef     `json:"unlinked,omitempty"`
+}
+
+// PersonRef names a contact of an account.
+type PersonRef struct {
+	AccountID string `json:"account_id"`
+	ContactID string `json:"contact_id"`
+}
+
+// PeopleParams selects the people AccountsService.FindPeople looks for.
+type PeopleParams struct {
+	Query      string
+	AccountIDs []string // defaults to every account
+	Match      string   // exact|prefix|fuzzy (default prefix)
+	Links      []PersonLink
+	// Concurrency bounds parallel contact searches (default 1).
+	Concurrency int
+}
+
+// ChatListParams pages through chats.
+type ChatListParams struct {
+	AccountIDs []string
+	Cursor     string
+	Direction  string // before|after
+}
+
+// ChatListResult is one page of chats.
+type ChatListResult struct {
+	Items        []ChatListItem `json:"items"`
+	HasMore      bool           `json:"has_more"`
+	OldestCursor string         `json:"oldest_cursor,omitempty"`
+	NewestCursor string         `json:"newest_cursor,omitempty"`
+}
+
+// ChatListItem is a chat in a chat listing.
+type ChatListItem struct {
+	ID           string `json:"id"`
+	Title        string `json:"title"`
+	DisplayName  string `json:"display_name,omitempty"`
+	AccountID    string `json:"account_id"`
+	LastActivity string `json:"last_activity,omitempty"`
+	Preview      string `json:"preview,omitempty"`
+
+	// Tags and Note are local chat metadata (rr chats tag/note) filled in
+	// by rr; the API never sets them.
+	Tags []string `json:"tags,omitempty"`
+	Note string   `json:"note,omitempty"`
+}
+
+// ChatSearchParams filters a chat search.
+type ChatSearchParams struct {
+	Query              string
+	AccountIDs         []string
+	Inbox              string // primary|low-priority|archive
+	UnreadOnly         bool
+	IncludeMuted       *bool
+	LastActivityAfter  *time.Time
+	LastActivityBefore *time.Time
+	Type               string // direct|group|any
+	Scope              string // titles|participants
+	Limit              int
+	Cursor             string
+	Direction          string // before|after
+}
+
+// ChatSearchResult is one page of chat search results.
+type ChatSearchResult struct {
+	Items        []ChatSearchItem `json:"items"`
+	HasMore      bool             `json:"has_more"`
+	OldestCursor string           `json:"oldest_cursor,omitempty"`
+	NewestCursor string           `json:"newest_cursor,omitempty"`
+}
+
+// ChatSearchItem is a chat in chat search results.
+type ChatSearchItem struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	DisplayName string `json:"display_name,omitempty"`
+	AccountID   string `json:"account_id"`
+	Type        string `json:"type"`
+	Network     string `json:"network"`
+	UnreadCount int64  `json:"unread_count"`
+	IsArchived  bool   `json:"is_archived"`
+	IsMuted     bool   `json:"is_muted"`
+	// LastActivity is RFC 3339, when known.
+	LastActivity string `json:"last_activity,omitempty"`
+	// Tags and Note are local chat metadata filled in by rr.
+	Tags []string `json:"tags,omitempty"`
+	Note string   `json:"note,omitempty"`
+}
+
+// ChatDetail is a single chat.
+type ChatDetail struct {
+	ID                     string `json:"id"`
+	Title                  string `json:"title"`
+	DisplayName            string `json:"display_name,omitempty"`
+	AccountID              string `json:"account_id"`
+	Network                string `json:"network"`
+	Type                   string `json:"type"`
+	UnreadCount            int64  `json:"unread_count"`
+	IsArchived             bool   `json:"is_archived"`
+	IsMuted                bool   `json:"is_muted"`
+	IsPinned               bool   `json:"is_pinned"`
+	LastActivity           string `json:"last_activity,omitempty"`
+	LastReadMessageSortKey string `json:"last_read_message_sort_key,omitempty"`
+	LocalChatID            string `json:"local_chat_id,omitempty"`
+	Preview                string `json:"preview,omitempty"`
+	ParticipantsTotal      int64  `json:"participants_total"`
+	ParticipantsReturned   int    `json:"participants_returned"`
+	ParticipantsHasMore    bool   `json:"participants_has_more"`
+	// Tags and Note are local chat metadata filled in by rr.
+	Tags []string `json:"tags,omitempty"`
+	Note string   `json:"note,omitempty"`
+}
+
+// ChatGetParams customizes ChatsService.Get.
+type ChatGetParams struct {
+	MaxParticipantCount *int
+}
+
+// ChatCreateParams describes a chat to create.
+type ChatCreateParams struct {
+	AccountID      string
+	ParticipantIDs []string
+	Type           string // single|group
+	Title          string
+	MessageText    string
+}
+
+// ChatCreateResult identifies a created chat.
+type ChatCreateResult struct {
+	ChatID string `json:"chat_id"`
+	Status string `json:"status,omitempty"`
+}
+
+// ChatStartUser is the merged contact data ChatsService.Start resolves.
+type ChatStartUser struct {
+	ID          string
+	Email       string
+	FullName    string
+	PhoneNumber string
+	Username    string
+}
+
+// ChatStartParams describes the direct chat ChatsService.Start resolves
+// or creates.
+type ChatStartParams struct {
+	AccountID   string
+	User        ChatStartUser
+	AllowInvite *bool
+	MessageText string
+}
+
+// ChatStartResult identifies the started chat.
+type ChatStartResult struct {
+	ChatID string `json:"chat_id"`
+	Status string `json:"status,omitempty"`
+}
+
+// SetParams describes a chat reminder.
+type SetParams struct {
+	RemindAt                 time.Time
+	DismissOnIncomingMessage bool
+}
+
+// MessageListParams pages through a chat's messages.
+type MessageListParams struct {
+	Cursor    string
+	Direction string // before|after
+}
+
+// MessageListResult is one page of a chat's messages.
+type MessageListResult struct {
+	Items      []MessageItem `json:"items"`
+	HasMore    bool          `json:"has_more"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
+
+// MessageSearchParams filters a message search.
+type MessageSearchParams struct {
+	Query              string
+	AccountIDs         []string
+	ChatIDs            []string
+	ChatType           string // group|single
+	Sender             string // me|others|<user-id>
+	MediaTypes         []string
+	DateAfter          *time.Time
+	DateBefore         *time.Time
+	IncludeMuted       *bool
+	ExcludeLowPriority *bool
+	Cursor             string
+	Direction          string // before|after
+	Limit              int
+}
+
+// MessageSearchResult is one page of message search results.
+type MessageSearchResult struct {
+	Items        []MessageItem `json:"items"`
+	HasMore      bool          `json:"has_more"`
+	OldestCursor string        `json:"oldest_cursor,omitempty"`
+	NewestCursor string        `json:"newest_cursor,omitempty"`
+}
+
+// MessageItem is a message flattened for display and scripting.
+type MessageItem struct {
+	ID                    string              `json:"id"`
+	AccountID             string              `json:"account_id,omitempty"`
+	ChatID                string              `json:"chat_id"`
+	SenderID              string              `json:"sender_id,omitempty"`
+	SenderName            string              `json:"sender_name,omitempty"`
+	Text                  string              `json:"text,omitempty"`
+	MessageType           string              `json:"message_type,omitempty"`
+	Timestamp             string              `json:"timestamp,omitempty"`
+	SortKey               string              `json:"sort_key,omitempty"`
+	LinkedMessageID       string              `json:"linked_message_id,omitempty"`
+	IsSender              bool                `json:"is_sender,omitempty"`
+	IsUnread              bool                `json:"is_unread,omitempty"`
+	HasMedia              bool                `json:"has_media,omitempty"`
+	Attachments           []MessageAttachment `json:"attachments,omitempty"`
+	Reactions             []MessageReaction   `json:"reactions,omitempty"`
+	ReactionKeys          []string            `json:"reaction_keys,omitempty"`
+	DownloadedAttachments []string            `json:"downloaded_attachments,omitempty"`
+	// ReplyTo is the message LinkedMessageID points to, set by
+	// MessagesService.HydrateReplies.
+	ReplyTo *MessageReply `json:"reply_to,omitempty"`
+}
+
+// MessageAttachment is a file attached to a message.
+type MessageAttachment struct {
+	Type        string  `json:"type,omitempty"`
+	FileName    string  `json:"file_name,omitempty"`
+	FileSize    int64   `json:"file_size,omitempty"`
+	MimeType    string  `json:"mime_type,omitempty"`
+	SrcURL      string  `json:"src_url,omitempty"`
+	Duration    float64 `json:"duration,omitempty"`
+	IsGif       bool    `json:"is_gif,omitempty"`
+	IsSticker   bool    `json:"is_sticker,omitempty"`
+	IsVoiceNote bool    `json:"is_voice_note,omitempty"`
+	PosterImg   string  `json:"poster_img,omitempty"`
+	Width       int     `json:"width,omitempty"`
+	Height      int     `json:"height,omitempty"`
+}
+
+// Kind classifies the attachment as gif, sticker, voice, image, video,
+// audio, or file.
+func (a MessageAttachment) Kind() string { return beeperapi.MessageAttachment(a).Kind() }
+
+// MessageReaction is a reaction to a message.
+type MessageReaction struct {
+	ID            string `json:"id,omitempty"`
+	ParticipantID string `json:"participant_id,omitempty"`
+	ReactionKey   string `json:"reaction_key,omitempty"`
+	Emoji         bool   `json:"emoji,omitempty"`
+	ImgURL        string `json:"img_url,omitempty"`
+}
+
+// SendParams describes a message to send.
+type SendParams struct {
+	Text             string
+	ReplyToMessageID string
+	Attachment       *SendAttachmentParams
+}
+
+// SendAttachmentParams attaches an uploaded file to a message.
+type SendAttachmentParams struct {
+	UploadID string
+	FileName string
+	MimeType string
+	Type     string
+	Duration *float64
+	Width    *float64
+	Height   *float64
+}
+
+// SendResult identifies a sent message while it is pending.
+type SendResult struct {
+	ChatID           string `json:"chat_id"`
+	PendingMessageID string `json:"pending_message_id"`
+}
+
+// EditParams is the new content of an edited message.
+type EditParams struct {
+	Text string
+}
+
+// EditResult reports the outcome of an edit.
+type EditResult struct {
+	ChatID    string `json:"chat_id"`
+	MessageID string `json:"message_id"`
+	Success   bool   `json:"success"`
+}
+
+// MessageReply summarizes the message another message replies to.
+type MessageReply struct {
+	ID         string `json:"id"`
+	SenderID   string `json:"sender_id,omitempty"`
+	SenderName string `json:"sender_name,omitempty"`
+	Text       string `json:"text,omitempty"`
+	Timestamp  string `json:"timestamp,omitempty"`
+	HasMedia   bool   `json:"has_media,omitempty"`
+}
+
+// Thread is the reply tree around a message.
+type Thread struct {
+	ChatID    string `json:"chat_id"`
+	MessageID string `json:"message_id"`
+	RootID    string `json:"root_id"`
+	Count     int    `json:"count"`
+	// MissingParentID is the message the root replies to when it was not
+	// found in the scanned history.
+	MissingParentID string        `json:"missing_parent_id,omitempty"`
+	Pages           int           `json:"pages"`
+	Root            ThreadMessage `json:"root"`
+}
+
+// ThreadMessage is a message in a thread with its replies.
+type ThreadMessage struct {
+	MessageItem
+	Replies []ThreadMessage `json:"replies,omitempty"`
+}
+
+// ThreadParams customizes MessagesService.Thread.
+type ThreadParams struct {
+	MaxPages int // history pages to scan (default DefaultThreadMaxPages)
+}
+
+// MessageContextParams selects the anchor of MessagesService.Context and
+// how many messages to return around it.
+type MessageContextParams struct {
+	SortKey   string
+	MessageID string
+	At        time.Time // newest message at or before this time
+	Before    int       // messages before the anchor
+	After     int       // messages after the anchor
+	MaxPages  int       // history pages to scan for MessageID/At (default DefaultContextMaxPages)
+}
+
+// MessageContext is an anchor message with the messages around it.
+type MessageContext struct {
+	ChatID  string        `json:"chat_id"`
+	SortKey string        `json:"sort_key"`
+	Anchor  *MessageItem  `json:"anchor"`
+	Before  []MessageItem `json:"before"`
+	After   []MessageItem `json:"after"`
+}
+
+// SharedParams filters MessagesService.Links and Media.
+type SharedParams struct {
+	Since    *time.Time // skip messages before this time; scanning stops there
+	Until    *time.Time // skip messages after this time
+	Dedupe   bool       // keep only the newest occurrence of each link or file
+	Limit    int        // stop after this many items (0 = no limit)
+	MaxPages int        // history pages to scan (default DefaultSharedMaxPages)
+	Domains  []string   // Links: only these domains and their subdomains
+	Kinds    []string   // Media: only these MessageAttachment.Kind values
+}
+
+// SharedLink is a URL shared in a chat.
+type SharedLink struct {
+	URL        string `json:"url"`
+	Domain     string `json:"domain,omitempty"`
+	MessageID  string `json:"message_id"`
+	ChatID     string `json:"chat_id"`
+	SenderID   string `json:"sender_id,omitempty"`
+	SenderName string `json:"sender_name,omitempty"`
+	Timestamp  string `json:"timestamp,omitempty"`
+	// Count is how many times the URL was shared, set with Dedupe.
+	Count int `json:"count,omitempty"`
+}
+
+// SharedMedia is an attachment shared in a chat.
+type SharedMedia struct {
+	Kind       string  `json:"kind"`
+	FileName   string  `json:"file_name,omitempty"`
+	MimeType   string  `json:"mime_type,omitempty"`
+	FileSize   int64   `json:"file_size,omitempty"`
+	SrcURL     string  `json:"src_url,omitempty"`
+	Width      int     `json:"width,omitempty"`
+	Height     int     `json:"height,omitempty"`
+	Duration   float64 `json:"duration,omitempty"`
+	MessageID  string  `json:"message_id"`
+	ChatID     string  `json:"chat_id"`
+	SenderID   string  `json:"sender_id,omitempty"`
+	SenderName string  `json:"sender_name,omitempty"`
+	Timestamp  string  `json:"timestamp,omitempty"`
+	// Count is how many times the file was shared, set with Dedupe.
+	Count int `json:"count,omitempty"`
+}
+
+// SharedLinksResult lists the links shared in a chat.
+type SharedLinksResult struct {
+	ChatID string       `json:"chat_id"`
+	Items  []SharedLink `json:"items"`
+	// Scanned is the number of messages read, over Pages pages.
+	Scanned int `json:"scanned"`
+	Pages   int `json:"pages"`
+	// Complete is false when MaxPages or Limit stopped the scan before
+	// Since or the start of the chat.
+	Complete bool `json:"complete"`
+}
+
+// SharedMediaResult lists the attachments shared in a chat.
+type SharedMediaResult struct {
+	ChatID   string        `json:"chat_id"`
+	Items    []SharedMedia `json:"items"`
+	Scanned  int           `json:"scanned"`
+	Pages    int           `json:"pages"`
+	Complete bool          `json:"complete"`
+}
+
+// SearchParams describes a global search.
+type SearchParams struct {
+	Query             string
+	MessagesCursor    string
+	MessagesDirection string // before|after
+	MessagesLimit     int
+}
+
+// SearchResult holds the chats, groups, and messages a global search found.
+type SearchResult struct {
+	Chats    []SearchChat   `json:"chats"`
+	InGroups []SearchChat   `json:"in_groups"`
+	Messages SearchMessages `json:"messages"`
+}
+
+// SearchChat is a chat in global search results.
+type SearchChat struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	DisplayName string `json:"display_name,omitempty"`
+	Type        string `json:"type"`
+	Network     string `json:"network"`
+	AccountID   string `json:"account_id"`
+	UnreadCount int64  `json:"unread_count"`
+}
+
+// SearchMessages is the message page of global search results.
+type SearchMessages struct {
+	Items        []MessageItem `json:"items"`
+	HasMore      bool          `json:"has_more"`
+	OldestCursor string        `json:"oldest_cursor,omitempty"`
+	NewestCursor string        `json:"newest_cursor,omitempty"`
+}
+
+// AssetUploadParams describes a local file to upload.
+type AssetUploadParams struct {
+	FilePath string
+	FileName string
+	MimeType string
+	// StripMetadata removes EXIF (including GPS location), XMP, IPTC, and
+	// comments from JPEG files before upload. Other files are uploaded
+	// unchanged.
+	StripMetadata bool
+}
+
+// AssetUploadBase64Params describes base64 content to upload.
+type AssetUploadBase64Params struct {
+	Content  string
+	FileName string
+	MimeType string
+}
+
+// AssetUploadResult identifies an uploaded file.
+type AssetUploadResult struct {
+	UploadID string  `json:"upload_id"`
+	SrcURL   string  `json:"src_url,omitempty"`
+	FileName string  `json:"file_name,omitempty"`
+	MimeType string  `json:"mime_type,omitempty"`
+	FileSize int64   `json:"file_size,omitempty"`
+	Duration float64 `json:"duration,omitempty"`
+	Width    int     `json:"width,omitempty"`
+	Height   int     `json:"height,omitempty"`
+}
+
+// AssetServeResult describes a streamed asset.
+type AssetServeResult struct {
+	ContentType   string `json:"content_type,omitempty"`
+	ContentLength int64  `json:"content_length,omitempty"`
+	BytesWritten  int64  `json:"bytes_written"`
+}
+
+// FocusParams selects what Client.Focus shows.
+type FocusParams struct {
+	ChatID              string
+	MessageID           string
+	DraftText           string
+	DraftAttachmentPath string
+}
+
+// FocusResult reports whether Beeper Desktop was focused.
+type FocusResult struct {
+	Success bool `json:"success"`
+}
+
+// ConnectInfo is the Connect metadata of a Desktop API server.
+type ConnectInfo struct {
+	Name      string            `json:"name,omitempty"`
+	Version   string            `json:"version,omitempty"`
+	Runtime   string            `json:"runtime,omitempty"`
+	Endpoints map[string]string `json:"endpoints,omitempty"`
+	Raw       map[string]any    `json:"raw,omitempty"`
+}
+
+// TokenIntrospection is the OAuth introspection result for a token.
+type TokenIntrospection struct {
+	Active    bool           `json:"active"`
+	Scope     string         `json:"scope,omitempty"`
+	ClientID  string         `json:"client_id,omitempty"`
+	Subject   string         `json:"subject,omitempty"`
+	Username  string         `json:"username,omitempty"`
+	TokenType string         `json:"token_type,omitempty"`
+	Issuer    string         `json:"issuer,omitempty"`
+	ExpiresAt int64          `json:"expires_at,omitempty"`
+	IssuedAt  int64          `json:"issued_at,omitempty"`
+	NotBefore int64          `json:"not_before,omitempty"`
+	Raw       map[string]any `json:"raw,omitempty"`
+}
+
+// Cache stores normalized responses between calls; see WithCache. Values
+// are JSON-encodable; resource names a kind of response, such as "chats".
+type Cache interface {
+	Get(resource, key string, v any) bool
+	Set(resource, key string, v any)
+	Invalidate(resource string)
+}